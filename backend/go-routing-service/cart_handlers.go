@@ -0,0 +1,516 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/cart"
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Cart handlers - carts are kept by the service and re-validated through the "retail" connector domain
+
+const defaultCartTTL = 72 * time.Hour
+
+// cartTTL reads how long an unchanged cart is kept from CART_TTL
+func cartTTL() time.Duration {
+	value := os.Getenv("CART_TTL")
+	if value == "" {
+		return defaultCartTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Warn().Str("value", value).Msg("Invalid CART_TTL, using default")
+		return defaultCartTTL
+	}
+	return ttl
+}
+
+// getRetailConnector resolves the retail connector for a store
+func (app *App) getRetailConnector(ctx context.Context, tenantID, storeID string) (ports.IRetailConnector, error) {
+	connector, err := app.connectorRegistry.GetConnector(ctx, tenantID, storeID, "retail")
+	if err != nil {
+		return nil, err
+	}
+
+	retailConnector, ok := connector.(ports.IRetailConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector %s does not implement IRetailConnector", connector.GetAdapterType())
+	}
+
+	return retailConnector, nil
+}
+
+// writeCartError maps cart service errors to HTTP responses
+func writeCartError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, cart.ErrInvalidQuantity):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, cart.ErrUnavailable), errors.Is(err, cart.ErrEmpty), errors.Is(err, ports.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusBadGateway)
+	}
+}
+
+// writeCart writes a cart response
+func writeCart(w http.ResponseWriter, correlationID string, customerCart *models.Cart) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(customerCart)
+}
+
+// logCartError logs cart errors that are not caused by the request
+func logCartError(err error, msg string) {
+	if errors.Is(err, ports.ErrNotFound) || errors.Is(err, cart.ErrInvalidQuantity) ||
+		errors.Is(err, cart.ErrUnavailable) || errors.Is(err, cart.ErrEmpty) {
+		return
+	}
+	log.Error().Err(err).Msg(msg)
+}
+
+// commerceGetCartHandler handles GET /api/v1/commerce/cart
+func (app *App) commerceGetCartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get query params
+	storeID := r.URL.Query().Get("storeId")
+	customerID := claims.Sub
+
+	if storeID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Msg("Get cart request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, err := app.carts.Get(ctx, retailConnector, claims.TenantID, storeID, customerID)
+	if err != nil {
+		logCartError(err, "Failed to get cart")
+		writeCartError(w, err, "get cart")
+		return
+	}
+
+	writeCart(w, correlationID, customerCart)
+}
+
+// commerceClearCartHandler handles DELETE /api/v1/commerce/cart
+func (app *App) commerceClearCartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get query params
+	storeID := r.URL.Query().Get("storeId")
+	customerID := claims.Sub
+
+	if storeID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Msg("Clear cart request")
+
+	if err := app.carts.Clear(ctx, claims.TenantID, storeID, customerID); err != nil {
+		logCartError(err, "Failed to clear cart")
+		writeCartError(w, err, "clear cart")
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commerceAddCartLineHandler handles POST /api/v1/commerce/cart/lines
+func (app *App) commerceAddCartLineHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req models.AddCartLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.StoreID == "" || req.ProductID == "" {
+		http.Error(w, "storeId and productId are required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Str("productId", req.ProductID).
+		Int("quantity", req.Quantity).
+		Msg("Add cart line request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, err := app.carts.AddLine(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub, req.ProductID, req.VariantID, req.Quantity)
+	if err != nil {
+		logCartError(err, "Failed to add cart line")
+		writeCartError(w, err, "add to cart")
+		return
+	}
+
+	writeCart(w, correlationID, customerCart)
+}
+
+// commerceUpdateCartLineHandler handles PATCH /api/v1/commerce/cart/lines/{lineId}
+func (app *App) commerceUpdateCartLineHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lineID := chi.URLParam(r, "lineId")
+
+	// Parse request body
+	var req models.UpdateCartLineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if lineID == "" || req.StoreID == "" {
+		http.Error(w, "lineId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Str("lineId", lineID).
+		Int("quantity", req.Quantity).
+		Msg("Update cart line request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, err := app.carts.UpdateLine(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub, lineID, req.Quantity)
+	if err != nil {
+		logCartError(err, "Failed to update cart line")
+		writeCartError(w, err, "update cart")
+		return
+	}
+
+	writeCart(w, correlationID, customerCart)
+}
+
+// commerceRemoveCartLineHandler handles DELETE /api/v1/commerce/cart/lines/{lineId}
+func (app *App) commerceRemoveCartLineHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params
+	lineID := chi.URLParam(r, "lineId")
+	storeID := r.URL.Query().Get("storeId")
+	customerID := claims.Sub
+
+	if lineID == "" || storeID == "" {
+		http.Error(w, "lineId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Str("lineId", lineID).
+		Msg("Remove cart line request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, err := app.carts.RemoveLine(ctx, retailConnector, claims.TenantID, storeID, customerID, lineID)
+	if err != nil {
+		logCartError(err, "Failed to remove cart line")
+		writeCartError(w, err, "update cart")
+		return
+	}
+
+	writeCart(w, correlationID, customerCart)
+}
+
+// writeCartNotReady reports a cart that cannot be converted, together with its validated lines
+func writeCartNotReady(w http.ResponseWriter, correlationID string, customerCart *models.Cart, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":       err.Error(),
+		"cart":        customerCart,
+		"unavailable": customerCart.UnavailableLines(),
+	})
+}
+
+// completeCart deletes a converted cart; a failure only leaves the cart in place
+func (app *App) completeCart(ctx context.Context, customerCart *models.Cart) {
+	if err := app.carts.Complete(ctx, customerCart); err != nil {
+		log.Warn().Err(err).Str("cartId", customerCart.ID).Msg("Converted cart was not removed")
+	}
+}
+
+// commerceCartCheckoutHandler handles POST /api/v1/commerce/cart/checkout
+func (app *App) commerceCartCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		StoreID   string             `json:"storeId"`
+		Discounts []checkoutDiscount `json:"discounts,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.StoreID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Msg("Cart checkout request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, changes, err := app.carts.Prepare(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub)
+	if errors.Is(err, cart.ErrUnavailable) || errors.Is(err, cart.ErrEmpty) {
+		writeCartNotReady(w, correlationID, customerCart, err)
+		return
+	}
+	if err != nil {
+		logCartError(err, "Failed to prepare cart for checkout")
+		writeCartError(w, err, "check out cart")
+		return
+	}
+
+	items := make([]checkout.Item, 0, len(customerCart.Lines))
+	for _, line := range customerCart.Lines {
+		items = append(items, checkout.Item{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			SKU:       line.SKU,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	session, err := app.createCheckoutSession(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub, items, discountCodes(req.Discounts))
+	if err != nil {
+		logCheckoutError(err)
+		writeCheckoutError(w, err)
+		return
+	}
+
+	app.completeCart(ctx, customerCart)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"session": session,
+		"changes": changes,
+	})
+}
+
+// commerceCartOrderHandler handles POST /api/v1/commerce/cart/order
+func (app *App) commerceCartOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		StoreID         string               `json:"storeId"`
+		BillingAddress  models.Address       `json:"billingAddress"`
+		ShippingAddress models.Address       `json:"shippingAddress"`
+		PaymentMethod   models.PaymentMethod `json:"paymentMethod"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.StoreID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Msg("Cart order request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	customerCart, changes, err := app.carts.Prepare(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub)
+	if errors.Is(err, cart.ErrUnavailable) || errors.Is(err, cart.ErrEmpty) {
+		writeCartNotReady(w, correlationID, customerCart, err)
+		return
+	}
+	if err != nil {
+		logCartError(err, "Failed to prepare cart for order")
+		writeCartError(w, err, "order cart")
+		return
+	}
+
+	orderReq := models.OrderRequest{
+		StoreID:         req.StoreID,
+		LineItems:       make([]models.OrderLineItem, 0, len(customerCart.Lines)),
+		BillingAddress:  req.BillingAddress,
+		ShippingAddress: req.ShippingAddress,
+		PaymentMethod:   req.PaymentMethod,
+		Metadata: map[string]interface{}{
+			"customerId": claims.Sub,
+			"cartId":     customerCart.ID,
+		},
+	}
+	for _, line := range customerCart.Lines {
+		orderReq.LineItems = append(orderReq.LineItems, line.OrderLineItem())
+	}
+
+	order, err := retailConnector.CreateOrder(ctx, orderReq)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create order via connector")
+		http.Error(w, fmt.Sprintf("Failed to create order: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	app.recordOrderCreated(ctx, claims.TenantID, req.StoreID, claims.Sub, order)
+
+	// Food lines are prepared by the store's kitchen
+	app.forwardToKitchen(ctx, claims.TenantID, req.StoreID, order.ID, orderReq.LineItems)
+
+	app.completeCart(ctx, customerCart)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"order":   order,
+		"changes": changes,
+	})
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("orderId", order.ID).
+		Str("cartId", customerCart.ID).
+		Msg("Cart converted to order")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/pubsub"
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// checkoutStreamHeartbeat is how often an idle stream sends a keep-alive and re-reads the session
+const checkoutStreamHeartbeat = 15 * time.Second
+
+// newEventBroker selects the broker for real-time events from EVENTS_BROKER
+// "redis" (the default) fans events out to every gateway replica; "memory" keeps them in this
+// process and only suits a single replica
+func newEventBroker(redisClient redis.UniversalClient) pubsub.Broker {
+	switch os.Getenv("EVENTS_BROKER") {
+	case "memory":
+		return pubsub.NewMemoryBroker()
+	case "", "redis":
+		return pubsub.NewRedisBroker(redisClient, "amicis:events:")
+	default:
+		log.Warn().Str("value", os.Getenv("EVENTS_BROKER")).Msg("Unknown EVENTS_BROKER, using redis")
+		return pubsub.NewRedisBroker(redisClient, "amicis:events:")
+	}
+}
+
+// checkoutTopic is the event topic of a checkout session
+func checkoutTopic(tenantID, sessionID string) string {
+	return fmt.Sprintf("checkout:%s:%s", tenantID, sessionID)
+}
+
+// checkoutSessionStatus is the status view of a session shared by the status endpoint and the event stream
+func checkoutSessionStatus(session *models.CheckoutSession) map[string]interface{} {
+	status := map[string]interface{}{
+		"sessionId": session.ID,
+		"status":    session.Status,
+		"total":     session.Total,
+		"qrToken":   session.QRToken,
+		"version":   session.Version,
+	}
+
+	// The app shows why an in-app payment was declined
+	if session.Payment != nil {
+		status["paymentStatus"] = session.Payment.Status
+		if session.Payment.DeclineReason != "" {
+			status["declineReason"] = session.Payment.DeclineReason
+		}
+	}
+
+	// The receipt screen shows the order number once the order is submitted
+	if session.Order != nil {
+		status["orderStatus"] = session.Order.Status
+		status["orderId"] = session.Order.OrderID
+		status["orderNumber"] = session.Order.OrderNumber
+	}
+	return status
+}
+
+// checkoutStreamDone reports whether a session in this state will not change any more
+func checkoutStreamDone(status, orderStatus string) bool {
+	switch status {
+	case models.CheckoutStatusCancelled, models.CheckoutStatusExpired:
+		return true
+	case models.CheckoutStatusCompleted:
+		return orderStatus == "" || orderStatus == models.CheckoutOrderSubmitted || orderStatus == models.CheckoutOrderFailed
+	}
+	return false
+}
+
+// publishCheckoutSession publishes a session change to the session's event stream
+// Publishing is best effort: streams also re-read the session on every heartbeat
+func (app *App) publishCheckoutSession(ctx context.Context, session *models.CheckoutSession) {
+	message, err := json.Marshal(checkoutSessionStatus(session))
+	if err != nil {
+		log.Error().Err(err).Str("sessionId", session.ID).Msg("Failed to encode checkout event")
+		return
+	}
+
+	// The change is stored, so the event must go out even if the request that made it is gone
+	if err := app.eventBroker.Publish(context.WithoutCancel(ctx), checkoutTopic(session.TenantID, session.ID), message); err != nil {
+		log.Warn().Err(err).Str("sessionId", session.ID).Msg("Failed to publish checkout event")
+	}
+}
+
+// checkoutSessionEventsHandler handles GET /api/v1/commerce/checkout/sessions/{sessionId}/events
+// It streams the session's status as Server-Sent Events. Event IDs are session versions, so a
+// client reconnecting with Last-Event-ID only gets the session again if it changed meanwhile.
+func (app *App) checkoutSessionEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		http.Error(w, "sessionId is required", http.StatusBadRequest)
+		return
+	}
+
+	// Browsers send Last-Event-ID on reconnect; other clients may pass it as a query parameter
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	var lastVersion int64
+	if lastEventID != "" {
+		version, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			http.Error(w, "Last-Event-ID must be an event ID from this stream", http.StatusBadRequest)
+			return
+		}
+		lastVersion = version
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("sessionId", sessionID).
+		Int64("lastVersion", lastVersion).
+		Msg("Checkout session stream request")
+
+	// Subscribe before reading the session, so that no change falls between the two
+	updates, err := app.eventBroker.Subscribe(ctx, checkoutTopic(claims.TenantID, sessionID))
+	if err != nil {
+		log.Error().Err(err).Str("broker", app.eventBroker.Name()).Msg("Failed to subscribe to checkout events")
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	session, err := app.getOwnCheckoutSession(ctx, claims, sessionID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to get checkout session")
+		}
+		writeCheckoutSessionError(w, err, "get session")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	done := false
+
+	// send writes a status event unless the client already has this version or a later one
+	send := func(message []byte) error {
+		var state struct {
+			Version     int64  `json:"version"`
+			Status      string `json:"status"`
+			OrderStatus string `json:"orderStatus"`
+		}
+		if err := json.Unmarshal(message, &state); err != nil {
+			return nil
+		}
+		done = checkoutStreamDone(state.Status, state.OrderStatus)
+		if state.Version <= lastVersion {
+			return nil
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: status\ndata: %s\n\n", state.Version, message); err != nil {
+			return err
+		}
+		lastVersion = state.Version
+		return rc.Flush()
+	}
+
+	// sendSession re-reads the session and sends it if it changed; this also expires it when due
+	sendSession := func() error {
+		session, err := app.checkoutSessions.Get(ctx, claims.TenantID, sessionID)
+		if err != nil {
+			return err
+		}
+		message, err := json.Marshal(checkoutSessionStatus(session))
+		if err != nil {
+			return err
+		}
+		return send(message)
+	}
+
+	message, err := json.Marshal(checkoutSessionStatus(session))
+	if err != nil || send(message) != nil || done {
+		return
+	}
+
+	heartbeat := time.NewTicker(checkoutStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	// Wake up when an unpaid session expires, since nothing else changes it then
+	var expiry <-chan time.Time
+	if models.IsOpenCheckoutStatus(session.Status) {
+		timer := time.NewTimer(time.Until(session.ExpiresAt))
+		defer timer.Stop()
+		expiry = timer.C
+	}
+
+	for !done {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-updates:
+			if !ok {
+				return
+			}
+			err = send(message)
+		case <-expiry:
+			expiry = nil
+			err = sendSession()
+		case <-heartbeat.C:
+			err = sendSession()
+			if err == nil {
+				if _, err = fmt.Fprint(w, ": ping\n\n"); err == nil {
+					err = rc.Flush()
+				}
+			}
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn().Err(err).Str("correlationId", correlationID).Str("sessionId", sessionID).Msg("Checkout session stream closed")
+			}
+			return
+		}
+	}
+}
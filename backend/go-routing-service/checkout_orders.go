@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultCheckoutOrderInterval is how often due order submissions are retried
+	defaultCheckoutOrderInterval = time.Minute
+
+	// checkoutOrderBatch bounds the sessions submitted per run
+	checkoutOrderBatch = 100
+
+	// checkoutOrderTimeout bounds a single submission to the retail backend
+	checkoutOrderTimeout = 30 * time.Second
+)
+
+// checkoutOrderInterval reads the retry interval from CHECKOUT_ORDER_RETRY_INTERVAL
+// A value of 0 disables the retry job; paid sessions are then only submitted once
+func checkoutOrderInterval() time.Duration {
+	value := os.Getenv("CHECKOUT_ORDER_RETRY_INTERVAL")
+	if value == "" {
+		return defaultCheckoutOrderInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warn().Str("value", value).Msg("Invalid CHECKOUT_ORDER_RETRY_INTERVAL, using default")
+		return defaultCheckoutOrderInterval
+	}
+	return interval
+}
+
+// submitCheckoutOrder submits a paid session's order in the background
+// Failures are left to the retry job
+func (app *App) submitCheckoutOrder(tenantID, sessionID, correlationID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), checkoutOrderTimeout)
+		defer cancel()
+
+		session, err := app.checkoutSessions.SubmitOrder(ctx, tenantID, sessionID, app.getRetailConnector)
+		if err != nil {
+			if !errors.Is(err, ports.ErrInvalidTransition) {
+				log.Warn().Err(err).Str("correlationId", correlationID).Str("sessionId", sessionID).Msg("Failed to submit checkout order, will retry")
+			}
+			return
+		}
+		if session.Order != nil && session.Order.Status == models.CheckoutOrderSubmitted {
+			log.Info().
+				Str("correlationId", correlationID).
+				Str("sessionId", sessionID).
+				Str("orderId", session.Order.OrderID).
+				Str("orderNumber", session.Order.OrderNumber).
+				Msg("Checkout session submitted as order")
+		}
+	}()
+}
+
+// startCheckoutOrderJob retries due order submissions every interval until ctx is cancelled
+func (app *App) startCheckoutOrderJob(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		log.Info().Msg("Checkout order retry job disabled")
+		return
+	}
+
+	log.Info().Dur("interval", interval).Msg("Starting checkout order retry job")
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				submitted, failed, err := app.checkoutSessions.SubmitDueOrders(ctx, app.getRetailConnector, checkoutOrderBatch)
+				if err != nil {
+					log.Error().Err(err).Msg("Checkout order retry job failed to list due orders")
+					continue
+				}
+				if submitted > 0 || failed > 0 {
+					log.Info().Int("submitted", submitted).Int("failed", failed).Msg("Checkout order retry job run")
+				}
+			}
+		}
+	}()
+}
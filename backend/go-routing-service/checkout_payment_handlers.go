@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// paymentWebhookMaxBytes bounds the size of a payment provider notification
+const paymentWebhookMaxBytes = 64 << 10
+
+// getPaymentConnector resolves the payment connector for a store
+func (app *App) getPaymentConnector(ctx context.Context, tenantID, storeID string) (ports.IPaymentConnector, error) {
+	connector, err := app.connectorRegistry.GetConnector(ctx, tenantID, storeID, "payment")
+	if err != nil {
+		return nil, err
+	}
+
+	paymentConnector, ok := connector.(ports.IPaymentConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector %s does not implement IPaymentConnector", connector.GetAdapterType())
+	}
+
+	return paymentConnector, nil
+}
+
+// releasePayment voids or refunds a payment that cannot pay its checkout session
+func (app *App) releasePayment(ctx context.Context, paymentConnector ports.IPaymentConnector, payment *models.Payment, correlationID string) {
+	var err error
+	switch payment.Status {
+	case models.PaymentStatusAuthorized:
+		_, err = paymentConnector.Void(ctx, payment.ID)
+	case models.PaymentStatusCaptured:
+		_, err = paymentConnector.Refund(ctx, payment.ID, models.RoundAmount(payment.CapturedAmount-payment.RefundedAmount))
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("correlationId", correlationID).
+			Str("paymentId", payment.ID).
+			Str("sessionId", payment.Reference).
+			Msg("Failed to release rejected payment")
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("paymentId", payment.ID).
+		Str("sessionId", payment.Reference).
+		Str("status", payment.Status).
+		Msg("Released rejected payment")
+}
+
+// checkoutPaymentHandler handles POST /api/v1/commerce/checkout/sessions/{sessionId}/payment
+// It pays the session in the app: the session total is authorized with the store's payment
+// connector and captured at once. A captured payment moves the session to paid.
+func (app *App) checkoutPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := chi.URLParam(r, "sessionId")
+	if sessionID == "" {
+		http.Error(w, "sessionId is required", http.StatusBadRequest)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		PaymentToken string `json:"paymentToken"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.PaymentToken == "" {
+		http.Error(w, "paymentToken is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("sessionId", sessionID).
+		Msg("Checkout payment request")
+
+	session, err := app.getOwnCheckoutSession(ctx, claims, sessionID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) && !errors.Is(err, ports.ErrInvalidTransition) {
+			log.Error().Err(err).Msg("Failed to get checkout session")
+		}
+		writeCheckoutSessionError(w, err, "get session")
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	// A retried payment of a paid session returns the session instead of charging again
+	if !models.IsOpenCheckoutStatus(session.Status) {
+		if session.Payment == nil || session.Payment.Status != models.PaymentStatusCaptured {
+			http.Error(w, fmt.Sprintf("checkout session %s is %s", session.ID, session.Status), http.StatusConflict)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(checkoutSessionStatus(session))
+		return
+	}
+
+	paymentConnector, err := app.getPaymentConnector(ctx, claims.TenantID, session.StoreID)
+	if err != nil {
+		log.Error().Err(err).Str("storeId", session.StoreID).Msg("Failed to get payment connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	payment, err := paymentConnector.Authorize(ctx, models.PaymentRequest{
+		Reference:    session.ID,
+		Amount:       session.Total,
+		Currency:     session.Currency,
+		PaymentToken: req.PaymentToken,
+		Metadata: map[string]string{
+			"tenantId":   claims.TenantID,
+			"storeId":    session.StoreID,
+			"customerId": session.CustomerID,
+		},
+	})
+	if err != nil {
+		log.Error().Err(err).Str("correlationId", correlationID).Str("sessionId", session.ID).Msg("Failed to authorize payment")
+		http.Error(w, "Failed to authorize payment", http.StatusBadGateway)
+		return
+	}
+
+	if payment.Status == models.PaymentStatusAuthorized {
+		captured, err := paymentConnector.Capture(ctx, payment.ID, payment.Amount)
+		if err != nil {
+			log.Error().Err(err).Str("correlationId", correlationID).Str("paymentId", payment.ID).Msg("Failed to capture payment")
+			app.releasePayment(ctx, paymentConnector, payment, correlationID)
+			http.Error(w, "Failed to capture payment", http.StatusBadGateway)
+			return
+		}
+		payment = captured
+	}
+
+	session, err = app.checkoutSessions.RecordPayment(ctx, claims.TenantID, session.StoreID, session.ID, payment)
+	if errors.Is(err, checkout.ErrPaymentRejected) {
+		log.Warn().Err(err).Str("correlationId", correlationID).Str("paymentId", payment.ID).Msg("Rejected checkout payment")
+		app.releasePayment(ctx, paymentConnector, payment, correlationID)
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		// The provider's webhook records the payment later
+		log.Error().Err(err).Str("correlationId", correlationID).Str("paymentId", payment.ID).Msg("Failed to record checkout payment")
+		writeCheckoutSessionError(w, err, "record payment")
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("sessionId", session.ID).
+		Str("paymentId", payment.ID).
+		Str("paymentStatus", payment.Status).
+		Msg("Checkout payment processed")
+
+	// A paid session becomes a backend order
+	if session.Status == models.CheckoutStatusPaid {
+		app.submitCheckoutOrder(claims.TenantID, session.ID, correlationID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch payment.Status {
+	case models.PaymentStatusDeclined:
+		w.WriteHeader(http.StatusPaymentRequired)
+	case models.PaymentStatusPending:
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(checkoutSessionStatus(session))
+}
+
+// paymentWebhookHandler handles POST /api/v1/webhooks/payments/{tenantId}/{storeId}
+// Payment providers call it without a JWT; the store's payment connector authenticates the
+// notification. Notifications are acknowledged once handled, or when they cannot be handled
+// by a retry, so that the provider stops resending them.
+func (app *App) paymentWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	tenantID := chi.URLParam(r, "tenantId")
+	storeID := chi.URLParam(r, "storeId")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, paymentWebhookMaxBytes))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	paymentConnector, err := app.getPaymentConnector(ctx, tenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Str("tenantId", tenantID).Str("storeId", storeID).Msg("Failed to get payment connector for webhook")
+		http.Error(w, "Connector not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	event, err := paymentConnector.ParseWebhook(ctx, r.Header, body)
+	if errors.Is(err, ports.ErrInvalidSignature) {
+		log.Warn().Err(err).Str("correlationId", correlationID).Str("tenantId", tenantID).Str("storeId", storeID).Msg("Rejected payment webhook")
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("correlationId", correlationID).Msg("Invalid payment webhook")
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	payment := &event.Payment
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", tenantID).
+		Str("storeId", storeID).
+		Str("eventId", event.ID).
+		Str("eventType", event.Type).
+		Str("paymentId", payment.ID).
+		Str("sessionId", payment.Reference).
+		Str("paymentStatus", payment.Status).
+		Msg("Payment webhook received")
+
+	session, err := app.checkoutSessions.RecordPayment(ctx, tenantID, storeID, payment.Reference, payment)
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		log.Warn().Str("correlationId", correlationID).Str("sessionId", payment.Reference).Msg("Payment webhook for unknown checkout session")
+	case errors.Is(err, checkout.ErrPaymentRejected):
+		log.Warn().Err(err).Str("correlationId", correlationID).Str("paymentId", payment.ID).Msg("Rejected checkout payment")
+		app.releasePayment(ctx, paymentConnector, payment, correlationID)
+	case err != nil:
+		log.Error().Err(err).Str("correlationId", correlationID).Str("sessionId", payment.Reference).Msg("Failed to record checkout payment")
+		http.Error(w, "Failed to record payment", http.StatusInternalServerError)
+		return
+	case session.Status == models.CheckoutStatusPaid:
+		app.submitCheckoutOrder(tenantID, session.ID, correlationID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]bool{"received": true})
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/signing"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// checkoutSessionTTL is how long a checkout session can be paid after it is created
+	checkoutSessionTTL = 15 * time.Minute
+
+	// checkoutSessionRetention is how long sessions are kept after they expire or are finished
+	checkoutSessionRetention = 7 * 24 * time.Hour
+
+	// checkoutQRGrace is how long a QR token stays valid after the session's payment deadline
+	checkoutQRGrace = 2 * time.Hour
+)
+
+// staffRoles are the JWT roles of store staff, e.g. to verify QR tokens at the exit gate or work the kitchen queue
+var staffRoles = []string{"staff", "admin"}
+
+// newCheckoutQRTokens creates the QR token issuer from CHECKOUT_QR_KEYS
+// The variable holds comma-separated kid:secret pairs; the first key signs new tokens and the
+// others are only used to verify tokens issued before a rotation
+func newCheckoutQRTokens() *checkout.QRTokens {
+	spec := os.Getenv("CHECKOUT_QR_KEYS")
+	if spec == "" {
+		spec = "dev:development-qr-secret-change-in-production"
+		log.Warn().Msg("Using default checkout QR key - set CHECKOUT_QR_KEYS environment variable")
+	}
+
+	activeID, keys, err := signing.ParseKeys(spec)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid CHECKOUT_QR_KEYS")
+	}
+	keyring, err := signing.NewKeyring(activeID, keys)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid CHECKOUT_QR_KEYS")
+	}
+
+	return checkout.NewQRTokens(keyring, checkoutQRGrace)
+}
+
+// writeCheckoutSessionError maps checkout session errors to HTTP responses
+func writeCheckoutSessionError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		http.Error(w, "Session not found", http.StatusNotFound)
+	case errors.Is(err, ports.ErrInvalidTransition), errors.Is(err, ports.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusInternalServerError)
+	}
+}
+
+// getOwnCheckoutSession returns a session of the customer in claims
+// Sessions of other customers are reported as not found, so their IDs cannot be probed
+func (app *App) getOwnCheckoutSession(ctx context.Context, claims *JWTClaims, sessionID string) (*models.CheckoutSession, error) {
+	session, err := app.checkoutSessions.Get(ctx, claims.TenantID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.CustomerID != claims.Sub {
+		return nil, fmt.Errorf("checkout session %s: %w", sessionID, ports.ErrNotFound)
+	}
+	return session, nil
+}
+
+// checkoutSessionTransitionHandler returns a handler that moves a checkout session to status
+// It serves POST /api/v1/commerce/checkout/sessions/{sessionId}/{scan,pay,complete,cancel}
+// Staff can move any session of the tenant; customers can only move their own
+func (app *App) checkoutSessionTransitionHandler(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		correlationID := GetCorrelationID(ctx)
+
+		// Get JWT claims
+		claims, ok := GetUserFromContext(ctx)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		sessionID := chi.URLParam(r, "sessionId")
+		if sessionID == "" {
+			http.Error(w, "sessionId is required", http.StatusBadRequest)
+			return
+		}
+
+		log.Info().
+			Str("correlationId", correlationID).
+			Str("tenantId", claims.TenantID).
+			Str("sessionId", sessionID).
+			Str("status", status).
+			Msg("Checkout session transition request")
+
+		if !claims.HasRole(staffRoles...) {
+			if _, err := app.getOwnCheckoutSession(ctx, claims, sessionID); err != nil {
+				if !errors.Is(err, ports.ErrNotFound) {
+					log.Error().Err(err).Str("sessionId", sessionID).Msg("Failed to get checkout session")
+				}
+				writeCheckoutSessionError(w, err, "update session")
+				return
+			}
+		}
+
+		session, err := app.checkoutSessions.Transition(ctx, claims.TenantID, sessionID, status)
+		if err != nil {
+			if !errors.Is(err, ports.ErrNotFound) && !errors.Is(err, ports.ErrInvalidTransition) {
+				log.Error().Err(err).Str("sessionId", sessionID).Msg("Failed to update checkout session")
+			}
+			writeCheckoutSessionError(w, err, "update session")
+			return
+		}
+
+		// A paid session becomes a backend order
+		if status == models.CheckoutStatusPaid {
+			app.submitCheckoutOrder(claims.TenantID, session.ID, correlationID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Correlation-ID", correlationID)
+		json.NewEncoder(w).Encode(session)
+	}
+}
+
+// verifyCheckoutHandler handles POST /api/v1/commerce/checkout/verify
+// Staff scan the customer's QR code at the exit gate; a paid session is completed, and a token
+// that was already verified is rejected so it cannot be used by a second customer
+func (app *App) verifyCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		Token   string `json:"token"`
+		StoreID string `json:"storeId,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("staffId", claims.Sub).
+		Msg("Verify checkout request")
+
+	session, err := app.checkoutSessions.Verify(ctx, claims.TenantID, req.StoreID, req.Token, claims.Sub)
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	switch {
+	case errors.Is(err, checkout.ErrInvalidQRToken):
+		log.Warn().Err(err).Str("correlationId", correlationID).Msg("Rejected checkout QR token")
+		http.Error(w, "Invalid QR token", http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, checkout.ErrQRTokenExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	case errors.Is(err, checkout.ErrAlreadyVerified):
+		log.Warn().Str("correlationId", correlationID).Str("sessionId", session.ID).Msg("Checkout QR token replayed")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      err.Error(),
+			"sessionId":  session.ID,
+			"verifiedAt": session.VerifiedAt,
+		})
+		return
+	case err != nil:
+		if !errors.Is(err, ports.ErrNotFound) && !errors.Is(err, ports.ErrInvalidTransition) {
+			log.Error().Err(err).Msg("Failed to verify checkout session")
+		}
+		writeCheckoutSessionError(w, err, "verify session")
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("sessionId", session.ID).
+		Str("staffId", claims.Sub).
+		Msg("Checkout verified at exit")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
@@ -69,6 +69,21 @@ func NewCircuitBreakerWrapper() *CircuitBreakerWrapper {
 	}
 }
 
+// BreakerRejectedError is returned when a circuit breaker rejects a call without making it
+// It wraps gobreaker.ErrOpenState or gobreaker.ErrTooManyRequests.
+type BreakerRejectedError struct {
+	message string
+	cause   error
+}
+
+func (e *BreakerRejectedError) Error() string {
+	return e.message
+}
+
+func (e *BreakerRejectedError) Unwrap() error {
+	return e.cause
+}
+
 // ExecuteWithBreaker executes a function with circuit breaker protection
 func (cbw *CircuitBreakerWrapper) ExecuteWithBreaker(
 	breaker *gobreaker.CircuitBreaker,
@@ -80,13 +95,19 @@ func (cbw *CircuitBreakerWrapper) ExecuteWithBreaker(
 			log.Error().
 				Str("circuit_breaker", breaker.Name()).
 				Msg("Circuit breaker is OPEN - rejecting request")
-			return nil, fmt.Errorf("service temporarily unavailable: %s circuit is open", breaker.Name())
+			return nil, &BreakerRejectedError{
+				message: fmt.Sprintf("service temporarily unavailable: %s circuit is open", breaker.Name()),
+				cause:   err,
+			}
 		}
 		if err == gobreaker.ErrTooManyRequests {
 			log.Warn().
 				Str("circuit_breaker", breaker.Name()).
 				Msg("Circuit breaker in half-open state - too many requests")
-			return nil, fmt.Errorf("service recovering: %s - too many requests", breaker.Name())
+			return nil, &BreakerRejectedError{
+				message: fmt.Sprintf("service recovering: %s - too many requests", breaker.Name()),
+				cause:   err,
+			}
 		}
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+// Command psp-standin serves the PSP payment stand-in for local development.
+//
+// Point a payment connector with adapter "PSPAdapter" at it. When PSP_WEBHOOK_URL is set, every
+// payment change is posted there, signed with PSP_WEBHOOK_SECRET:
+//
+//	PSP_API_KEY=local-psp-key \
+//	PSP_WEBHOOK_URL=http://localhost:8080/api/v1/webhooks/payments/ikea/ikea-seattle \
+//	PSP_WEBHOOK_SECRET=local-webhook-secret \
+//	go run ./cmd/psp-standin
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/amicis/go-routing-service/internal/adapters/psp"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout})
+
+	addr := os.Getenv("PSP_ADDR")
+	if addr == "" {
+		addr = ":8092"
+	}
+	apiKey := os.Getenv("PSP_API_KEY")
+	if apiKey == "" {
+		apiKey = "local-psp-key"
+	}
+
+	standIn := psp.NewStandIn(apiKey)
+	if webhookURL := os.Getenv("PSP_WEBHOOK_URL"); webhookURL != "" {
+		secret := os.Getenv("PSP_WEBHOOK_SECRET")
+		if secret == "" {
+			secret = "local-webhook-secret"
+		}
+		standIn.SendWebhooks(webhookURL, secret)
+		log.Info().Str("url", webhookURL).Msg("Sending payment webhooks")
+	}
+
+	log.Info().Str("address", addr).Msg("Starting PSP stand-in")
+	if err := http.ListenAndServe(addr, standIn); err != nil {
+		log.Fatal().Err(err).Msg("PSP stand-in failed to start")
+	}
+}
@@ -0,0 +1,33 @@
+// Command purekds-standin serves the PureKDS stand-in for local development.
+//
+// Point a kitchen connector with adapter "PureKDSAdapter" at it:
+//
+//	PUREKDS_API_KEY=local-kds-key go run ./cmd/purekds-standin
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/amicis/go-routing-service/internal/adapters/purekds"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stdout})
+
+	addr := os.Getenv("PUREKDS_ADDR")
+	if addr == "" {
+		addr = ":8091"
+	}
+	apiKey := os.Getenv("PUREKDS_API_KEY")
+	if apiKey == "" {
+		apiKey = "local-kds-key"
+	}
+
+	log.Info().Str("address", addr).Msg("Starting PureKDS stand-in")
+	if err := http.ListenAndServe(addr, purekds.NewStandIn(apiKey)); err != nil {
+		log.Fatal().Err(err).Msg("PureKDS stand-in failed to start")
+	}
+}
@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/amicis/go-routing-service/internal/adapters/d365"
+	"github.com/amicis/go-routing-service/internal/adapters/generic"
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/adapters/mongodb"
+	"github.com/amicis/go-routing-service/internal/adapters/psp"
+	"github.com/amicis/go-routing-service/internal/adapters/purekds"
+	"github.com/amicis/go-routing-service/internal/adapters/sap"
+	"github.com/amicis/go-routing-service/internal/checkout"
 	"github.com/amicis/go-routing-service/internal/domain/models"
 	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/promotions"
 	"github.com/amicis/go-routing-service/internal/registry"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Commerce handlers - implements the connector gateway pattern
@@ -206,6 +215,13 @@ func (app *App) commerceOrdersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Backends that place orders on behalf of a customer (e.g. SAP OCC) need the customer ID
+	// It always comes from the JWT; a customerId sent by the client is overwritten
+	if orderReq.Metadata == nil {
+		orderReq.Metadata = make(map[string]interface{})
+	}
+	orderReq.Metadata["customerId"] = claims.Sub
+
 	log.Info().
 		Str("correlationId", correlationID).
 		Str("tenantId", claims.TenantID).
@@ -236,6 +252,11 @@ func (app *App) commerceOrdersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	app.recordOrderCreated(ctx, claims.TenantID, orderReq.StoreID, claims.Sub, order)
+
+	// Food lines are prepared by the store's kitchen; categories come from the request
+	app.forwardToKitchen(ctx, claims.TenantID, orderReq.StoreID, order.ID, orderReq.LineItems)
+
 	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Correlation-ID", correlationID)
@@ -304,13 +325,47 @@ func (app *App) initializeConnectorRegistry(dbName string) error {
 	// Initialize registry
 	app.connectorRegistry = registry.NewConnectorRegistry(connectorsDB)
 	
-	// Register adapter factories
-	app.connectorRegistry.RegisterFactory("D365CommerceAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+	// Register adapter factories; retail adapters are wrapped with the product cache
+	app.connectorRegistry.RegisterFactory("D365CommerceAdapter", app.cachedRetailFactory(func(config ports.ConnectorConfig) (ports.IConnector, error) {
 		return d365.NewD365CommerceAdapter(config)
+	}))
+	
+	app.connectorRegistry.RegisterFactory("SAPCommerceAdapter", app.cachedRetailFactory(func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return sap.NewSAPCommerceAdapter(config)
+	}))
+	
+	app.connectorRegistry.RegisterFactory("GenericRESTAdapter", app.cachedRetailFactory(func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return generic.NewGenericRESTAdapter(config)
+	}))
+	
+	// Wishlist adapters
+	app.connectorRegistry.RegisterFactory("D365WishlistAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return d365.NewD365WishlistAdapter(config)
+	})
+	
+	app.connectorRegistry.RegisterFactory("MongoWishlistAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return mongodb.NewMongoWishlistAdapter(config, app.wishlistsDB)
+	})
+	
+	// Kitchen adapters
+	app.connectorRegistry.RegisterFactory("PureKDSAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return purekds.NewPureKDSAdapter(config)
+	})
+	
+	app.connectorRegistry.RegisterFactory("InMemoryKitchenAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return memory.NewInMemoryKitchenAdapter(config, app.kitchenStore)
+	})
+	
+	// Payment adapters
+	app.connectorRegistry.RegisterFactory("PSPAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return psp.NewPSPAdapter(config)
+	})
+	
+	app.connectorRegistry.RegisterFactory("FakePaymentAdapter", func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		return memory.NewFakePaymentAdapter(config, app.fakePayments)
 	})
 	
 	// Register more adapters here in the future:
-	// app.connectorRegistry.RegisterFactory("SAP CommerceAdapter", ...)
 	// app.connectorRegistry.RegisterFactory("ShopifyAdapter", ...)
 	
 	log.Info().Msg("Connector registry initialized with adapters")
@@ -318,7 +373,48 @@ func (app *App) initializeConnectorRegistry(dbName string) error {
 	return nil
 }
 
-// Wishlist handlers - using MongoDB for storage
+// Wishlist handlers - routed through the "wishlist" connector domain
+
+// getWishlistConnector resolves the wishlist connector for a store
+// Stores without a wishlist connector document use the service's own wishlists collection
+func (app *App) getWishlistConnector(ctx context.Context, tenantID, storeID string) (ports.IWishlistConnector, error) {
+	connector, err := app.connectorRegistry.GetConnector(ctx, tenantID, storeID, "wishlist")
+	if errors.Is(err, registry.ErrConnectorNotFound) {
+		connector, err = mongodb.NewMongoWishlistAdapter(ports.ConnectorConfig{
+			TenantID: tenantID,
+			StoreID:  storeID,
+			Domain:   "wishlist",
+			Adapter:  "MongoWishlistAdapter",
+			Enabled:  true,
+		}, app.wishlistsDB)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wishlistConnector, ok := connector.(ports.IWishlistConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector %s does not implement IWishlistConnector", connector.GetAdapterType())
+	}
+
+	return wishlistConnector, nil
+}
+
+// getDefaultWishlist returns the customer's default (oldest) wishlist, creating it when create is set
+// Returns nil without error when the customer has no wishlist and create is not set
+func getDefaultWishlist(ctx context.Context, connector ports.IWishlistConnector, customerID string, create bool) (*models.Wishlist, error) {
+	wishlists, err := connector.GetWishlists(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wishlists.Wishlists) > 0 {
+		return &wishlists.Wishlists[0], nil
+	}
+	if !create {
+		return nil, nil
+	}
+	return connector.CreateWishlist(ctx, customerID, mongodb.DefaultWishlistName, false)
+}
 
 // commerceWishlistHandler handles GET /api/v1/commerce/wishlist
 func (app *App) commerceWishlistHandler(w http.ResponseWriter, r *http.Request) {
@@ -332,12 +428,12 @@ func (app *App) commerceWishlistHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Get query params
+	// Get query params; the wishlist is always the caller's own
 	storeID := r.URL.Query().Get("storeId")
-	customerID := r.URL.Query().Get("customerId")
+	customerID := claims.Sub
 	
-	if storeID == "" || customerID == "" {
-		http.Error(w, "storeId and customerId query parameters are required", http.StatusBadRequest)
+	if storeID == "" {
+		http.Error(w, "storeId query parameter is required", http.StatusBadRequest)
 		return
 	}
 
@@ -348,34 +444,33 @@ func (app *App) commerceWishlistHandler(w http.ResponseWriter, r *http.Request)
 		Str("customerId", customerID).
 		Msg("Get wishlist request")
 
-	// Get wishlist from MongoDB
-	wishlistCollection := app.mongoClient.Database("amicis").Collection("wishlists")
-	
-	var wishlist models.Wishlist
-	err := wishlistCollection.FindOne(ctx, map[string]interface{}{
-		"tenantId":   claims.TenantID,
-		"storeId":    storeID,
-		"customerId": customerID,
-	}).Decode(&wishlist)
-	
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getDefaultWishlist(ctx, wishlistConnector, customerID, false)
 	if err != nil {
-		// Wishlist doesn't exist yet, return empty
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("X-Correlation-ID", correlationID)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"items": []interface{}{},
-			"count": 0,
-		})
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		http.Error(w, "Failed to get wishlist", http.StatusBadGateway)
 		return
 	}
 
-	// Return wishlist
+	response := map[string]interface{}{
+		"items": []models.WishlistItem{},
+		"count": 0,
+	}
+	if wishlist != nil {
+		response["wishlistId"] = wishlist.ID
+		response["items"] = wishlist.Items
+		response["count"] = len(wishlist.Items)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Correlation-ID", correlationID)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"items": wishlist.Items,
-		"count": len(wishlist.Items),
-	})
+	json.NewEncoder(w).Encode(response)
 }
 
 // commerceAddToWishlistHandler handles POST /api/v1/commerce/wishlist/items
@@ -392,9 +487,11 @@ func (app *App) commerceAddToWishlistHandler(w http.ResponseWriter, r *http.Requ
 
 	// Parse request body
 	var req struct {
-		StoreID    string `json:"storeId"`
-		CustomerID string `json:"customerId"`
-		ProductID  string `json:"productId"`
+		StoreID   string `json:"storeId"`
+		ProductID string `json:"productId"`
+		VariantID string `json:"variantId,omitempty"`
+		Quantity  int    `json:"quantity,omitempty"`
+		Notes     string `json:"notes,omitempty"`
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -402,16 +499,19 @@ func (app *App) commerceAddToWishlistHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if req.StoreID == "" || req.CustomerID == "" || req.ProductID == "" {
-		http.Error(w, "storeId, customerId, and productId are required", http.StatusBadRequest)
+	if req.StoreID == "" || req.ProductID == "" {
+		http.Error(w, "storeId and productId are required", http.StatusBadRequest)
 		return
 	}
 
+	// The wishlist is always the caller's own
+	customerID := claims.Sub
+
 	log.Info().
 		Str("correlationId", correlationID).
 		Str("tenantId", claims.TenantID).
 		Str("storeId", req.StoreID).
-		Str("customerId", req.CustomerID).
+		Str("customerId", customerID).
 		Str("productId", req.ProductID).
 		Msg("Add to wishlist request")
 
@@ -437,54 +537,37 @@ func (app *App) commerceAddToWishlistHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Create wishlist item
-	item := models.WishlistItem{
-		ID:        fmt.Sprintf("wishlist-item-%s-%d", req.ProductID, time.Now().Unix()),
-		ProductID: req.ProductID,
-		SKU:       product.SKU,
-		Name:      product.Name,
-		Price:     product.Price,
-		AddedAt:   time.Now(),
-	}
-
-	// Update wishlist in MongoDB
-	wishlistCollection := app.mongoClient.Database("amicis").Collection("wishlists")
-	
-	opts := options.Update().SetUpsert(true)
-	_, err = wishlistCollection.UpdateOne(
-		ctx,
-		map[string]interface{}{
-			"tenantId":   claims.TenantID,
-			"storeId":    req.StoreID,
-			"customerId": req.CustomerID,
-		},
-		map[string]interface{}{
-			"$push": map[string]interface{}{"items": item},
-			"$set": map[string]interface{}{
-				"tenantId":   claims.TenantID,
-				"storeId":    req.StoreID,
-				"customerId": req.CustomerID,
-				"updatedAt":  time.Now(),
-			},
-			"$setOnInsert": map[string]interface{}{
-				"createdAt": time.Now(),
-			},
-		},
-		opts,
-	)
-	
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getDefaultWishlist(ctx, wishlistConnector, customerID, true)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to add item to wishlist")
-		http.Error(w, "Failed to add to wishlist", http.StatusInternalServerError)
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get or create wishlist")
+		http.Error(w, "Failed to add to wishlist", http.StatusBadGateway)
 		return
 	}
 
+	addReq := newWishlistItemRequest(product, req.ProductID, req.VariantID, req.Quantity, req.Notes)
+
+	item, err := wishlistConnector.AddItem(ctx, wishlist.ID, addReq)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to add item to wishlist")
+		http.Error(w, "Failed to add to wishlist", http.StatusBadGateway)
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistItemAdded, claims.TenantID, wishlist, item)
+
 	// Return success
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Correlation-ID", correlationID)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"item": item,
+		"wishlistId": wishlist.ID,
+		"item":       item,
 	})
 }
 
@@ -500,13 +583,13 @@ func (app *App) commerceRemoveFromWishlistHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Get path params and query params
+	// Get path params and query params; the wishlist is always the caller's own
 	itemID := chi.URLParam(r, "itemId")
 	storeID := r.URL.Query().Get("storeId")
-	customerID := r.URL.Query().Get("customerId")
+	customerID := claims.Sub
 	
-	if itemID == "" || storeID == "" || customerID == "" {
-		http.Error(w, "itemId, storeId, and customerId are required", http.StatusBadRequest)
+	if itemID == "" || storeID == "" {
+		http.Error(w, "itemId and storeId are required", http.StatusBadRequest)
 		return
 	}
 
@@ -518,31 +601,27 @@ func (app *App) commerceRemoveFromWishlistHandler(w http.ResponseWriter, r *http
 		Str("itemId", itemID).
 		Msg("Remove from wishlist request")
 
-	// Remove item from wishlist in MongoDB
-	wishlistCollection := app.mongoClient.Database("amicis").Collection("wishlists")
-	
-	_, err := wishlistCollection.UpdateOne(
-		ctx,
-		map[string]interface{}{
-			"tenantId":   claims.TenantID,
-			"storeId":    storeID,
-			"customerId": customerID,
-		},
-		map[string]interface{}{
-			"$pull": map[string]interface{}{
-				"items": map[string]interface{}{"id": itemID},
-			},
-			"$set": map[string]interface{}{
-				"updatedAt": time.Now(),
-			},
-		},
-	)
-	
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to remove item from wishlist")
-		http.Error(w, "Failed to remove from wishlist", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getDefaultWishlist(ctx, wishlistConnector, customerID, false)
+	if err == nil && wishlist != nil {
+		err = wishlistConnector.RemoveItem(ctx, wishlist.ID, itemID)
+	}
+
+	// Removing an item that is already gone is not an error
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to remove item from wishlist")
+		http.Error(w, "Failed to remove from wishlist", http.StatusBadGateway)
 		return
 	}
+	if err == nil && wishlist != nil {
+		app.recordWishlistEvent(ctx, models.EventWishlistItemRemoved, claims.TenantID, wishlist, &models.WishlistItem{ID: itemID})
+	}
 
 	// Return success
 	w.Header().Set("Content-Type", "application/json")
@@ -550,8 +629,101 @@ func (app *App) commerceRemoveFromWishlistHandler(w http.ResponseWriter, r *http
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// commerceUpdateWishlistItemDefaultHandler handles PATCH /api/v1/commerce/wishlist/items/{itemId}
+func (app *App) commerceUpdateWishlistItemDefaultHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+	
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	itemID := chi.URLParam(r, "itemId")
+
+	// Parse request body
+	var req struct {
+		StoreID  string  `json:"storeId"`
+		Quantity *int    `json:"quantity,omitempty"`
+		Notes    *string `json:"notes,omitempty"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if itemID == "" || req.StoreID == "" {
+		http.Error(w, "itemId and storeId are required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity == nil && req.Notes == nil {
+		http.Error(w, "quantity or notes is required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		http.Error(w, "quantity must be positive", http.StatusBadRequest)
+		return
+	}
+
+	// The wishlist is always the caller's own
+	customerID := claims.Sub
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", customerID).
+		Str("itemId", itemID).
+		Msg("Update wishlist item request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getDefaultWishlist(ctx, wishlistConnector, customerID, false)
+	if err == nil && wishlist == nil {
+		err = fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+	var item *models.WishlistItem
+	if err == nil {
+		item, err = updateWishlistItem(ctx, wishlistConnector, wishlist, itemID, req.Quantity, req.Notes)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to update wishlist item")
+		}
+		writeWishlistError(w, err, "update wishlist item")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistItemUpdated, claims.TenantID, wishlist, item)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wishlistId": wishlist.ID,
+		"item":       item,
+	})
+}
+
 // IKEA Scan & Go Mobile App Handlers
 
+// StoreDetails is the store information shown by the mobile app
+type StoreDetails struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Status      string    `json:"status"` // "busy", "normal", "closingSoon"
+	ClosingTime time.Time `json:"closingTime"`
+	Address     string    `json:"address"`
+	City        string    `json:"city"`
+	State       string    `json:"state"`
+}
+
 // storeDetailsHandler handles GET /api/v1/stores/{storeId}
 func (app *App) storeDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -577,34 +749,32 @@ func (app *App) storeDetailsHandler(w http.ResponseWriter, r *http.Request) {
 		Str("storeId", storeID).
 		Msg("Get store details request")
 
-	// Get store from MongoDB
-	storesCollection := app.mongoClient.Database("amicis").Collection("stores")
-	
-	var store struct {
-		ID          string    `json:"id" bson:"storeId"`
-		Name        string    `json:"name" bson:"name"`
-		Status      string    `json:"status" bson:"status"` // "busy", "normal", "closingSoon"
-		ClosingTime time.Time `json:"closingTime" bson:"closingTime"`
-		Address     string    `json:"address" bson:"address"`
-		City        string    `json:"city" bson:"city"`
-		State       string    `json:"state" bson:"state"`
+	// Get store from the tenant's stores; a store of another tenant is not found
+	dbQueriesTotal.WithLabelValues("stores", "findOne").Inc()
+	store, err := app.stores.GetStore(ctx, claims.TenantID, storeID)
+	if errors.Is(err, ports.ErrNotFound) {
+		log.Warn().Err(err).Msg("Store not found")
+		http.Error(w, "Store not found", http.StatusNotFound)
+		return
 	}
-	
-	err := storesCollection.FindOne(ctx, map[string]interface{}{
-		"tenantId": claims.TenantID,
-		"storeId":  storeID,
-	}).Decode(&store)
-	
 	if err != nil {
-		log.Error().Err(err).Msg("Store not found")
-		http.Error(w, "Store not found", http.StatusNotFound)
+		log.Error().Err(err).Msg("Failed to get store")
+		http.Error(w, "Failed to get store", http.StatusServiceUnavailable)
 		return
 	}
 
 	// Return store details
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Correlation-ID", correlationID)
-	json.NewEncoder(w).Encode(store)
+	json.NewEncoder(w).Encode(StoreDetails{
+		ID:          store.StoreID,
+		Name:        store.Name,
+		Status:      store.Status,
+		ClosingTime: store.ClosingTime,
+		Address:     store.Address,
+		City:        store.City,
+		State:       store.State,
+	})
 }
 
 // productByArticleHandler handles GET /api/v1/commerce/products/by-article/{articleNumber}
@@ -833,7 +1003,44 @@ func (app *App) productPricingHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// checkoutDiscount is a discount code requested for a checkout session
+// Only the code is read; discount amounts sent by older clients are ignored
+type checkoutDiscount struct {
+	Code string `json:"code"`
+}
+
+// discountCodes returns the codes of the requested discounts
+func discountCodes(discounts []checkoutDiscount) []string {
+	codes := make([]string, 0, len(discounts))
+	for _, discount := range discounts {
+		codes = append(codes, discount.Code)
+	}
+	return codes
+}
+
+// writeCheckoutError maps checkout pricing errors to HTTP responses
+func writeCheckoutError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, checkout.ErrInvalidItem):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ports.ErrNotFound), errors.Is(err, promotions.ErrInvalidCode), errors.Is(err, checkout.ErrMixedCurrency):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, "Failed to create session", http.StatusBadGateway)
+	}
+}
+
+// logCheckoutError logs checkout errors that are not caused by the request
+func logCheckoutError(err error) {
+	if errors.Is(err, checkout.ErrInvalidItem) || errors.Is(err, ports.ErrNotFound) ||
+		errors.Is(err, promotions.ErrInvalidCode) || errors.Is(err, checkout.ErrMixedCurrency) {
+		return
+	}
+	log.Error().Err(err).Msg("Failed to create checkout session")
+}
+
 // createCheckoutSessionHandler handles POST /api/v1/commerce/checkout/sessions
+// Item prices sent by the client are ignored; every item is priced through the retail connector
 func (app *App) createCheckoutSessionHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	correlationID := GetCorrelationID(ctx)
@@ -847,13 +1054,9 @@ func (app *App) createCheckoutSessionHandler(w http.ResponseWriter, r *http.Requ
 
 	// Parse request body
 	var req struct {
-		StoreID    string                `json:"storeId"`
-		CustomerID string                `json:"customerId"`
-		Items      []models.WishlistItem `json:"items"`
-		Discounts  []struct {
-			Code   string  `json:"code"`
-			Amount float64 `json:"amount"`
-		} `json:"discounts"`
+		StoreID   string             `json:"storeId"`
+		Items     []checkout.Item    `json:"items"`
+		Discounts []checkoutDiscount `json:"discounts"`
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -861,8 +1064,13 @@ func (app *App) createCheckoutSessionHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if req.StoreID == "" || req.CustomerID == "" {
-		http.Error(w, "storeId and customerId are required", http.StatusBadRequest)
+	if req.StoreID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		http.Error(w, "items are required", http.StatusBadRequest)
 		return
 	}
 
@@ -870,48 +1078,22 @@ func (app *App) createCheckoutSessionHandler(w http.ResponseWriter, r *http.Requ
 		Str("correlationId", correlationID).
 		Str("tenantId", claims.TenantID).
 		Str("storeId", req.StoreID).
-		Str("customerId", req.CustomerID).
+		Str("customerId", claims.Sub).
 		Int("itemCount", len(req.Items)).
+		Int("discountCount", len(req.Discounts)).
 		Msg("Create checkout session request")
 
-	// Calculate totals
-	var subtotal float64
-	for _, item := range req.Items {
-		subtotal += item.Price.Amount
-	}
-
-	var discountTotal float64
-	for _, discount := range req.Discounts {
-		discountTotal += discount.Amount
-	}
-
-	total := subtotal - discountTotal
-
-	// Generate QR token (simple UUID + timestamp for demo)
-	qrToken := fmt.Sprintf("QR-%s-%d", claims.TenantID, time.Now().Unix())
-
-	// Create checkout session
-	session := map[string]interface{}{
-		"sessionId":  fmt.Sprintf("session-%d", time.Now().Unix()),
-		"tenantId":   claims.TenantID,
-		"storeId":    req.StoreID,
-		"customerId": req.CustomerID,
-		"items":      req.Items,
-		"discounts":  req.Discounts,
-		"subtotal":   subtotal,
-		"total":      total,
-		"qrToken":    qrToken,
-		"status":     "pending",
-		"createdAt":  time.Now(),
-		"expiresAt":  time.Now().Add(15 * time.Minute),
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
 	}
 
-	// Save to MongoDB
-	sessionsCollection := app.mongoClient.Database("amicis").Collection("checkout_sessions")
-	_, err := sessionsCollection.InsertOne(ctx, session)
+	session, err := app.createCheckoutSession(ctx, retailConnector, claims.TenantID, req.StoreID, claims.Sub, req.Items, discountCodes(req.Discounts))
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create checkout session")
-		http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		logCheckoutError(err)
+		writeCheckoutError(w, err)
 		return
 	}
 
@@ -922,6 +1104,17 @@ func (app *App) createCheckoutSessionHandler(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(session)
 }
 
+// createCheckoutSession prices the items on the server and stores a new pending checkout session
+// The session keeps the verified line breakdown and the discounts that were applied
+func (app *App) createCheckoutSession(ctx context.Context, retailConnector ports.IRetailConnector, tenantID, storeID, customerID string, items []checkout.Item, codes []string) (*models.CheckoutSession, error) {
+	quote, err := app.checkoutPricer.Quote(ctx, retailConnector, tenantID, storeID, items, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.checkoutSessions.Create(ctx, tenantID, storeID, customerID, quote)
+}
+
 // getCheckoutSessionStatusHandler handles GET /api/v1/commerce/checkout/sessions/{sessionId}/status
 func (app *App) getCheckoutSessionStatusHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -947,36 +1140,18 @@ func (app *App) getCheckoutSessionStatusHandler(w http.ResponseWriter, r *http.R
 		Str("sessionId", sessionID).
 		Msg("Get checkout session status request")
 
-	// Get session from MongoDB
-	sessionsCollection := app.mongoClient.Database("amicis").Collection("checkout_sessions")
-	
-	var session map[string]interface{}
-	err := sessionsCollection.FindOne(ctx, map[string]interface{}{
-		"sessionId": sessionID,
-		"tenantId":  claims.TenantID,
-	}).Decode(&session)
-	
+	session, err := app.getOwnCheckoutSession(ctx, claims, sessionID)
 	if err != nil {
-		log.Error().Err(err).Msg("Session not found")
-		http.Error(w, "Session not found", http.StatusNotFound)
-		return
-	}
-
-	// Check if expired
-	if expiresAt, ok := session["expiresAt"].(time.Time); ok {
-		if time.Now().After(expiresAt) {
-			session["status"] = "expired"
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to get checkout session")
 		}
+		writeCheckoutSessionError(w, err, "get session")
+		return
 	}
 
 	// Return status
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Correlation-ID", correlationID)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"sessionId": sessionID,
-		"status":    session["status"],
-		"total":     session["total"],
-		"qrToken":   session["qrToken"],
-	})
+	json.NewEncoder(w).Encode(checkoutSessionStatus(session))
 }
 
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/outbox"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultOutboxRelayInterval is how often the relay looks for pending events
+	defaultOutboxRelayInterval = time.Second
+
+	// outboxRetention is how long published events are kept in the outbox
+	outboxRetention = 7 * 24 * time.Hour
+
+	// eventStream and eventSubjectPrefix name the JetStream stream and subjects events are published to
+	eventStream        = "AMICIS_EVENTS"
+	eventSubjectPrefix = "amicis.events"
+)
+
+// outboxRelayInterval reads the relay interval from OUTBOX_RELAY_INTERVAL
+// A value of 0 disables the relay on this replica; events then stay in the outbox
+func outboxRelayInterval() time.Duration {
+	value := os.Getenv("OUTBOX_RELAY_INTERVAL")
+	if value == "" {
+		return defaultOutboxRelayInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warn().Str("value", value).Msg("Invalid OUTBOX_RELAY_INTERVAL, using default")
+		return defaultOutboxRelayInterval
+	}
+	return interval
+}
+
+// newEventBus selects the bus domain events are published to from EVENT_BUS
+// "memory" (the default) only delivers to handlers in this process; "nats" publishes to a
+// JetStream stream on NATS_URL. A configured bus that cannot be used is an error rather than a
+// reason to fall back to memory, which would mark events published that no consumer received.
+func newEventBus(ctx context.Context) (outbox.Bus, error) {
+	switch value := os.Getenv("EVENT_BUS"); value {
+	case "", "memory":
+		return outbox.NewMemoryBus(), nil
+	case "nats":
+		url := os.Getenv("NATS_URL")
+		if url == "" {
+			url = "nats://localhost:4222"
+		}
+		bus, err := outbox.NewNATSBus(ctx, url, eventStream, eventSubjectPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("event bus nats at %s: %w", url, err)
+		}
+		log.Info().Str("url", url).Str("stream", eventStream).Msg("Connected to NATS successfully")
+		return bus, nil
+	default:
+		return nil, fmt.Errorf("unknown EVENT_BUS %q", value)
+	}
+}
+
+// startOutboxRelay publishes pending outbox events every interval until ctx is cancelled
+// Every replica runs a relay; the outbox lease lets one of them publish at a time
+func (app *App) startOutboxRelay(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		log.Info().Msg("Outbox relay disabled")
+		return
+	}
+
+	owner, err := os.Hostname()
+	if err != nil || owner == "" {
+		owner = uuid.NewString()
+	}
+
+	log.Info().Dur("interval", interval).Str("bus", app.eventBus.Name()).Str("owner", owner).Msg("Starting outbox relay")
+	go outbox.NewRelay(app.outbox, app.eventBus, owner).Run(ctx, interval)
+}
+
+// recordEvent appends a domain event for a change already made in a retail or wishlist backend
+// The change cannot be undone, so a failure to record is logged and the request still succeeds
+func (app *App) recordEvent(ctx context.Context, eventType, aggregateType, aggregateID, tenantID, storeID string, data interface{}) {
+	event, err := models.NewDomainEvent(eventType, aggregateType, aggregateID, tenantID, storeID, data)
+	if err == nil {
+		// The change is made, so the event must be stored even if the request that made it is gone
+		err = app.outbox.Append(context.WithoutCancel(ctx), event)
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("correlationId", GetCorrelationID(ctx)).
+			Str("eventType", eventType).
+			Str("aggregateId", aggregateID).
+			Msg("Failed to record domain event")
+	}
+}
+
+// recordOrderCreated records the OrderCreated event of an order placed through the gateway
+func (app *App) recordOrderCreated(ctx context.Context, tenantID, storeID, customerID string, order *models.Order) {
+	app.recordEvent(ctx, models.EventOrderCreated, models.AggregateOrder, order.ID, tenantID, storeID, map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"customerId":  customerID,
+		"status":      order.Status,
+		"total":       order.Total,
+	})
+}
+
+// recordWishlistEvent records a wishlist event; item is nil for events about the list itself
+// The tenant comes from the JWT because not every wishlist backend stores it
+func (app *App) recordWishlistEvent(ctx context.Context, eventType, tenantID string, wishlist *models.Wishlist, item *models.WishlistItem) {
+	data := map[string]interface{}{
+		"wishlistId": wishlist.ID,
+		"customerId": wishlist.CustomerID,
+		"name":       wishlist.Name,
+		"isPublic":   wishlist.IsPublic,
+	}
+	if item != nil {
+		data["item"] = item
+	}
+	app.recordEvent(ctx, eventType, models.AggregateWishlist, wishlist.ID, tenantID, wishlist.StoreID, data)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/idempotency"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultIdempotencyTTL is how long responses are replayed for retries with the same Idempotency-Key
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyTTL returns the replay window from IDEMPOTENCY_TTL
+func idempotencyTTL() time.Duration {
+	value := os.Getenv("IDEMPOTENCY_TTL")
+	if value == "" {
+		return defaultIdempotencyTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Warn().Str("value", value).Msg("Invalid IDEMPOTENCY_TTL, using default")
+		return defaultIdempotencyTTL
+	}
+	return ttl
+}
+
+// newIdempotencyStore selects the idempotency store from IDEMPOTENCY_STORE
+// Redis shares keys across replicas; the in-process store only suits a single replica
+func newIdempotencyStore(redisClient redis.UniversalClient) idempotency.Store {
+	switch os.Getenv("IDEMPOTENCY_STORE") {
+	case "memory":
+		return idempotency.NewMemoryStore()
+	case "", "redis":
+		return idempotency.NewRedisStore(redisClient, "amicis:idempotency:")
+	default:
+		log.Warn().Str("value", os.Getenv("IDEMPOTENCY_STORE")).Msg("Unknown IDEMPOTENCY_STORE, using redis")
+		return idempotency.NewRedisStore(redisClient, "amicis:idempotency:")
+	}
+}
+
+// idempotencyScope namespaces idempotency keys by tenant and user from the JWT
+func idempotencyScope(r *http.Request) string {
+	claims, ok := GetUserFromContext(r.Context())
+	if !ok {
+		return "anonymous"
+	}
+	return claims.TenantID + ":" + claims.Sub
+}
@@ -0,0 +1,92 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// KitchenFixture describes what the kitchen conformance tests need
+type KitchenFixture struct {
+	// Config is passed to Initialize
+	Config ports.ConnectorConfig
+
+	// OrderPrefix makes submitted order IDs unique per run (defaults to "conformance")
+	OrderPrefix string
+}
+
+// RunKitchenConnectorTests verifies that a connector honours the IKitchenConnector contract
+func RunKitchenConnectorTests(t *testing.T, connector ports.IKitchenConnector, fixture KitchenFixture) {
+	t.Helper()
+	ctx := context.Background()
+
+	prefix := fixture.OrderPrefix
+	if prefix == "" {
+		prefix = "conformance"
+	}
+	orderID := fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+	items := []models.OrderLineItem{
+		{ID: "1", ProductID: "70001", SKU: "MEATBALLS-15", Name: "Swedish meatballs, 15 pcs", Quantity: 2, Category: "restaurant"},
+		{ID: "2", ProductID: "70002", SKU: "LINGONBERRY", Name: "Lingonberry drink", Quantity: 1, Category: "restaurant"},
+	}
+
+	t.Run("Identity", func(t *testing.T) {
+		assert.Equal(t, "kitchen", connector.GetDomain())
+		assert.NotEmpty(t, connector.GetAdapterType())
+	})
+
+	t.Run("InitializeAndHealthCheck", func(t *testing.T) {
+		require.NoError(t, connector.Initialize(ctx, fixture.Config))
+		assert.NoError(t, connector.HealthCheck(ctx))
+	})
+
+	t.Run("SubmitOrder", func(t *testing.T) {
+		require.NoError(t, connector.SubmitOrder(ctx, orderID, items))
+		assert.NoError(t, connector.SubmitOrder(ctx, orderID, items), "resubmitting an order must be harmless")
+
+		status, err := connector.GetOrderStatus(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, models.KitchenStatusReceived, status)
+
+		assert.Error(t, connector.SubmitOrder(ctx, orderID+"-empty", nil))
+	})
+
+	t.Run("GetActiveOrders", func(t *testing.T) {
+		active, err := connector.GetActiveOrders(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, active, orderID)
+	})
+
+	t.Run("UpdateOrderStatus", func(t *testing.T) {
+		for _, status := range []string{models.KitchenStatusPreparing, models.KitchenStatusReady} {
+			require.NoError(t, connector.UpdateOrderStatus(ctx, orderID, status))
+			got, err := connector.GetOrderStatus(ctx, orderID)
+			require.NoError(t, err)
+			assert.Equal(t, status, got)
+		}
+
+		err := connector.UpdateOrderStatus(ctx, orderID, models.KitchenStatusReceived)
+		assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+		assert.Error(t, connector.UpdateOrderStatus(ctx, orderID, "burnt"))
+
+		require.NoError(t, connector.UpdateOrderStatus(ctx, orderID, models.KitchenStatusServed))
+		active, err := connector.GetActiveOrders(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, active, orderID, "served orders are no longer active")
+	})
+
+	t.Run("UnknownOrder", func(t *testing.T) {
+		_, err := connector.GetOrderStatus(ctx, orderID+"-missing")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		err = connector.UpdateOrderStatus(ctx, orderID+"-missing", models.KitchenStatusPreparing)
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	})
+}
@@ -0,0 +1,156 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// PaymentFixture describes what the payment conformance tests need
+type PaymentFixture struct {
+	// Config is passed to Initialize
+	Config ports.ConnectorConfig
+
+	// PaymentToken is authorized by the backend
+	PaymentToken string
+
+	// DeclinedToken is declined by the backend
+	DeclinedToken string
+
+	// Webhook builds a notification the connector accepts for the payment's current state
+	Webhook func(payment models.Payment) (http.Header, []byte)
+}
+
+// RunPaymentConnectorTests verifies that a connector honours the IPaymentConnector contract
+func RunPaymentConnectorTests(t *testing.T, connector ports.IPaymentConnector, fixture PaymentFixture) {
+	t.Helper()
+	ctx := context.Background()
+
+	reference := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	request := func(suffix string, amount float64, token string) models.PaymentRequest {
+		return models.PaymentRequest{Reference: reference + suffix, Amount: amount, Currency: "EUR", PaymentToken: token}
+	}
+
+	t.Run("Identity", func(t *testing.T) {
+		assert.Equal(t, "payment", connector.GetDomain())
+		assert.NotEmpty(t, connector.GetAdapterType())
+	})
+
+	t.Run("InitializeAndHealthCheck", func(t *testing.T) {
+		require.NoError(t, connector.Initialize(ctx, fixture.Config))
+		assert.NoError(t, connector.HealthCheck(ctx))
+	})
+
+	t.Run("AuthorizeCaptureRefund", func(t *testing.T) {
+		payment, err := connector.Authorize(ctx, request("-capture", 143.98, fixture.PaymentToken))
+		require.NoError(t, err)
+		assert.NotEmpty(t, payment.ID)
+		assert.Equal(t, reference+"-capture", payment.Reference)
+		assert.Equal(t, models.PaymentStatusAuthorized, payment.Status)
+		assert.Equal(t, 143.98, payment.Amount)
+		assert.Equal(t, "EUR", payment.Currency)
+
+		again, err := connector.Authorize(ctx, request("-capture", 143.98, fixture.PaymentToken))
+		require.NoError(t, err)
+		assert.Equal(t, payment.ID, again.ID, "a retried authorization must not create a second payment")
+
+		captured, err := connector.Capture(ctx, payment.ID, 143.98)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCaptured, captured.Status)
+		assert.Equal(t, 143.98, captured.CapturedAmount)
+
+		captured, err = connector.Capture(ctx, payment.ID, 143.98)
+		require.NoError(t, err, "capturing again must be harmless")
+		assert.Equal(t, models.PaymentStatusCaptured, captured.Status)
+
+		_, err = connector.Void(ctx, payment.ID)
+		assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+		refunded, err := connector.Refund(ctx, payment.ID, 43.98)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusCaptured, refunded.Status, "a partial refund keeps the payment captured")
+		assert.Equal(t, 43.98, refunded.RefundedAmount)
+
+		_, err = connector.Refund(ctx, payment.ID, 100.01)
+		assert.Error(t, err, "refunds must not exceed the captured amount")
+
+		refunded, err = connector.Refund(ctx, payment.ID, 100)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusRefunded, refunded.Status)
+
+		read, err := connector.GetPayment(ctx, payment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusRefunded, read.Status)
+		assert.Equal(t, 143.98, read.RefundedAmount)
+	})
+
+	t.Run("Void", func(t *testing.T) {
+		payment, err := connector.Authorize(ctx, request("-void", 10, fixture.PaymentToken))
+		require.NoError(t, err)
+
+		voided, err := connector.Void(ctx, payment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PaymentStatusVoided, voided.Status)
+
+		_, err = connector.Capture(ctx, payment.ID, 10)
+		assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+	})
+
+	t.Run("Decline", func(t *testing.T) {
+		payment, err := connector.Authorize(ctx, request("-decline", 10, fixture.DeclinedToken))
+		require.NoError(t, err, "a decline is an answer, not an error")
+		assert.Equal(t, models.PaymentStatusDeclined, payment.Status)
+		assert.NotEmpty(t, payment.DeclineReason)
+
+		_, err = connector.Capture(ctx, payment.ID, 10)
+		assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+	})
+
+	t.Run("InvalidAmounts", func(t *testing.T) {
+		_, err := connector.Authorize(ctx, request("-zero", 0, fixture.PaymentToken))
+		assert.Error(t, err)
+
+		payment, err := connector.Authorize(ctx, request("-over", 10, fixture.PaymentToken))
+		require.NoError(t, err)
+		_, err = connector.Capture(ctx, payment.ID, 10.01)
+		assert.Error(t, err, "captures must not exceed the authorized amount")
+	})
+
+	t.Run("UnknownPayment", func(t *testing.T) {
+		_, err := connector.GetPayment(ctx, "pay_missing")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+
+		_, err = connector.Capture(ctx, "pay_missing", 10)
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	})
+
+	t.Run("ParseWebhook", func(t *testing.T) {
+		if fixture.Webhook == nil {
+			t.Skip("no webhook fixture")
+		}
+
+		payment, err := connector.Authorize(ctx, request("-webhook", 25, fixture.PaymentToken))
+		require.NoError(t, err)
+		captured, err := connector.Capture(ctx, payment.ID, 25)
+		require.NoError(t, err)
+
+		header, body := fixture.Webhook(*captured)
+		event, err := connector.ParseWebhook(ctx, header, body)
+		require.NoError(t, err)
+		assert.NotEmpty(t, event.ID)
+		assert.Equal(t, payment.ID, event.Payment.ID)
+		assert.Equal(t, reference+"-webhook", event.Payment.Reference)
+		assert.Equal(t, models.PaymentStatusCaptured, event.Payment.Status)
+		assert.Equal(t, 25.0, event.Payment.CapturedAmount)
+
+		_, err = connector.ParseWebhook(ctx, http.Header{}, []byte("{}"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+// Package conformance provides shared behavioural tests that every connector
+// implementation must pass, regardless of the backend it talks to.
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RetailFixture describes the backend data the retail conformance tests rely on
+type RetailFixture struct {
+	// Config is passed to Initialize
+	Config ports.ConnectorConfig
+
+	// ProductID is a product that exists in the backend
+	ProductID string
+
+	// SKU is a SKU that exists in the backend
+	SKU string
+
+	// MissingProductID is a product that does not exist (skipped when empty)
+	MissingProductID string
+
+	// SearchTerm returns at least one product from GetProducts
+	SearchTerm string
+
+	// CustomerID has at least one order in the backend
+	CustomerID string
+
+	// OrderRequest is submitted through CreateOrder
+	OrderRequest models.OrderRequest
+}
+
+// RunRetailConnectorTests verifies that a connector honours the IRetailConnector contract
+func RunRetailConnectorTests(t *testing.T, connector ports.IRetailConnector, fixture RetailFixture) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("Identity", func(t *testing.T) {
+		assert.Equal(t, "retail", connector.GetDomain())
+		assert.NotEmpty(t, connector.GetAdapterType())
+	})
+
+	t.Run("InitializeAndHealthCheck", func(t *testing.T) {
+		require.NoError(t, connector.Initialize(ctx, fixture.Config))
+		assert.NoError(t, connector.HealthCheck(ctx))
+	})
+
+	t.Run("GetProducts", func(t *testing.T) {
+		list, err := connector.GetProducts(ctx, models.ProductFilters{
+			SearchTerm: fixture.SearchTerm,
+			Limit:      5,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, list)
+		require.NotEmpty(t, list.Products)
+		assert.LessOrEqual(t, len(list.Products), 5)
+		assert.GreaterOrEqual(t, list.Total, len(list.Products))
+
+		for _, product := range list.Products {
+			assertCanonicalProduct(t, product)
+		}
+	})
+
+	t.Run("GetProductsBySKU", func(t *testing.T) {
+		list, err := connector.GetProducts(ctx, models.ProductFilters{
+			SKUs: []string{fixture.SKU},
+		})
+		require.NoError(t, err)
+		require.Len(t, list.Products, 1)
+		assert.Equal(t, fixture.SKU, list.Products[0].SKU)
+	})
+
+	t.Run("GetProduct", func(t *testing.T) {
+		product, err := connector.GetProduct(ctx, fixture.ProductID)
+		require.NoError(t, err)
+		require.NotNil(t, product)
+		assert.Equal(t, fixture.ProductID, product.ID)
+		assertCanonicalProduct(t, *product)
+		assert.NotNil(t, product.Inventory, "product details must include inventory")
+	})
+
+	t.Run("GetProductNotFound", func(t *testing.T) {
+		if fixture.MissingProductID == "" {
+			t.Skip("no missing product configured")
+		}
+		product, err := connector.GetProduct(ctx, fixture.MissingProductID)
+		assert.Error(t, err)
+		assert.Nil(t, product)
+	})
+
+	t.Run("GetProductBySKU", func(t *testing.T) {
+		product, err := connector.GetProductBySKU(ctx, fixture.SKU)
+		require.NoError(t, err)
+		require.NotNil(t, product)
+		assert.Equal(t, fixture.SKU, product.SKU)
+	})
+
+	t.Run("CreateAndGetOrder", func(t *testing.T) {
+		order, err := connector.CreateOrder(ctx, fixture.OrderRequest)
+		require.NoError(t, err)
+		require.NotNil(t, order)
+		assert.NotEmpty(t, order.ID)
+		assert.NotEmpty(t, order.OrderNumber)
+		assert.NotEmpty(t, order.Status)
+		assert.Len(t, order.LineItems, len(fixture.OrderRequest.LineItems))
+		assert.Greater(t, order.Total.Amount, 0.0)
+		assert.NotEmpty(t, order.Total.Currency)
+
+		fetched, err := connector.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.ID, fetched.ID)
+		assert.Equal(t, order.OrderNumber, fetched.OrderNumber)
+	})
+
+	t.Run("GetOrders", func(t *testing.T) {
+		list, err := connector.GetOrders(ctx, fixture.CustomerID, 10, 0)
+		require.NoError(t, err)
+		require.NotNil(t, list)
+		assert.NotEmpty(t, list.Orders)
+		assert.LessOrEqual(t, len(list.Orders), 10)
+		assert.Equal(t, 10, list.Limit)
+		assert.Equal(t, 0, list.Offset)
+
+		for _, order := range list.Orders {
+			assert.NotEmpty(t, order.ID)
+			assert.NotEmpty(t, order.Status)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		assert.NoError(t, connector.Close())
+	})
+}
+
+func assertCanonicalProduct(t *testing.T, product models.Product) {
+	t.Helper()
+	assert.NotEmpty(t, product.ID, "product id")
+	assert.NotEmpty(t, product.SKU, "product sku")
+	assert.NotEmpty(t, product.Name, "product name")
+	assert.NotEmpty(t, product.Price.Currency, "product currency")
+	assert.GreaterOrEqual(t, product.Price.Amount, 0.0, "product price")
+}
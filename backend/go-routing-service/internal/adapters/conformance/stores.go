@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// StoreRepositoryFixture is the registry the store repository conformance tests expect
+// Two tenants share the storeId "shared" with different backends; each also has a store of its own.
+var StoreRepositoryFixture = []models.Store{
+	{TenantID: "tenant-a", StoreID: "shared", Name: "A shared", BackendURL: "https://a.example.com"},
+	{TenantID: "tenant-a", StoreID: "a-only", Name: "A only", BackendURL: "https://a-only.example.com"},
+	{TenantID: "tenant-b", StoreID: "shared", Name: "B shared", BackendURL: "https://b.example.com"},
+	{TenantID: "tenant-b", StoreID: "b-only", Name: "B only", BackendURL: "https://b-only.example.com"},
+}
+
+// RunStoreRepositoryTests verifies that a repository holding StoreRepositoryFixture honours the
+// IStoreRepository contract, above all that no lookup returns another tenant's store
+func RunStoreRepositoryTests(t *testing.T, repository ports.IStoreRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("GetStore", func(t *testing.T) {
+		store, err := repository.GetStore(ctx, "tenant-a", "a-only")
+		require.NoError(t, err)
+		assert.Equal(t, "tenant-a", store.TenantID)
+		assert.Equal(t, "https://a-only.example.com", store.BackendURL)
+	})
+
+	t.Run("SameStoreIDResolvesPerTenant", func(t *testing.T) {
+		a, err := repository.GetStore(ctx, "tenant-a", "shared")
+		require.NoError(t, err)
+		b, err := repository.GetStore(ctx, "tenant-b", "shared")
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://a.example.com", a.BackendURL)
+		assert.Equal(t, "https://b.example.com", b.BackendURL)
+	})
+
+	t.Run("OtherTenantsStoreIsNotFound", func(t *testing.T) {
+		_, err := repository.GetStore(ctx, "tenant-a", "b-only")
+		assert.True(t, errors.Is(err, ports.ErrNotFound), "expected ErrNotFound, got %v", err)
+
+		_, err = repository.GetStore(ctx, "tenant-c", "shared")
+		assert.True(t, errors.Is(err, ports.ErrNotFound), "expected ErrNotFound, got %v", err)
+	})
+
+	t.Run("ListStoresOnlyReturnsTenantsStores", func(t *testing.T) {
+		stores, err := repository.ListStores(ctx, "tenant-b")
+		require.NoError(t, err)
+		require.Len(t, stores, 2)
+		for _, store := range stores {
+			assert.Equal(t, "tenant-b", store.TenantID)
+		}
+
+		stores, err = repository.ListStores(ctx, "tenant-c")
+		require.NoError(t, err)
+		assert.Empty(t, stores)
+	})
+
+	t.Run("TenantIsRequired", func(t *testing.T) {
+		_, err := repository.GetStore(ctx, "", "shared")
+		assert.ErrorIs(t, err, ports.ErrTenantRequired)
+
+		_, err = repository.ListStores(ctx, "")
+		assert.ErrorIs(t, err, ports.ErrTenantRequired)
+	})
+}
@@ -0,0 +1,209 @@
+package d365
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// BusinessEventSignatureHeader carries the signature of a business event notification
+// The value has the form "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">", keyed with
+// the connector's webhookSecret. D365 business events reach the gateway through an HTTPS
+// endpoint relay (e.g. a Logic App) that adds it.
+const BusinessEventSignatureHeader = "X-D365-Signature"
+
+// businessEventTolerance is how old a notification may be before it is rejected as a possible replay
+const businessEventTolerance = 5 * time.Minute
+
+// Business events handled by the gateway; others are ignored
+const (
+	salesOrderStatusChangedEvent = "SalesOrderStatusChangedBusinessEvent"
+	productPriceChangedEvent     = "ProductPriceChangedBusinessEvent"
+	inventoryChangedEvent        = "InventoryOnHandChangedBusinessEvent"
+)
+
+// D365BusinessEvent is a D365 business event with the fields of the events handled by the gateway
+type D365BusinessEvent struct {
+	BusinessEventID  string `json:"BusinessEventId"`
+	EventID          string `json:"EventId"`
+	EventTime        string `json:"EventTime"`
+	SalesOrderRecID  int64  `json:"SalesOrderRecId,omitempty"`
+	SalesOrderNumber string `json:"SalesOrderNumber,omitempty"`
+	SalesOrderStatus string `json:"SalesOrderStatus,omitempty"`
+	ItemNumber       string `json:"ItemNumber,omitempty"`
+	ProductRecID     int64  `json:"ProductRecId,omitempty"`
+}
+
+// processedEvents remembers the IDs of business events that were handled
+// A signed notification can be replayed until its timestamp leaves the tolerance window, so an ID
+// is kept until then. The IDs are held by the connector instance, i.e. per replica.
+type processedEvents struct {
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+func newProcessedEvents() *processedEvents {
+	return &processedEvents{ids: make(map[string]time.Time)}
+}
+
+// claim records id until the given time and reports whether it was not recorded yet
+func (p *processedEvents) claim(id string, until, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for seen, expires := range p.ids {
+		if !expires.After(now) {
+			delete(p.ids, seen)
+		}
+	}
+	if _, ok := p.ids[id]; ok {
+		return false
+	}
+	p.ids[id] = until
+	return true
+}
+
+// SignBusinessEvent returns the signature header value of a notification body sent at the given time
+func SignBusinessEvent(secret string, body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, businessEventMAC(secret, timestamp, body))
+}
+
+// ParseChangeNotification authenticates a business event notification and maps the sales
+// order status, price and inventory events it carries to retail changes
+// A notification carries one event or an array of events. Events whose EventId was already
+// handled are dropped, so a replayed notification reports no changes.
+func (a *D365CommerceAdapter) ParseChangeNotification(ctx context.Context, header http.Header, body []byte) ([]models.RetailChange, error) {
+	secret, _ := a.config.Config["webhookSecret"].(string)
+	if secret == "" {
+		return nil, fmt.Errorf("webhookSecret is not configured for D365 adapter: %w", ports.ErrInvalidSignature)
+	}
+	now := time.Now()
+	signedAt, err := verifyBusinessEvent(secret, header.Get(BusinessEventSignatureHeader), body, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []D365BusinessEvent
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &events); err != nil {
+			return nil, fmt.Errorf("failed to decode business events: %w", err)
+		}
+	} else {
+		var event D365BusinessEvent
+		if err := json.Unmarshal(trimmed, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode business event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	changes := make([]models.RetailChange, 0, len(events))
+	for _, event := range events {
+		change := models.RetailChange{
+			ID:         event.EventID,
+			SKU:        event.ItemNumber,
+			OccurredAt: parseBusinessEventTime(event.EventTime),
+		}
+		if event.ProductRecID != 0 {
+			change.ProductID = strconv.FormatInt(event.ProductRecID, 10)
+		}
+
+		switch event.BusinessEventID {
+		case salesOrderStatusChangedEvent:
+			// Orders are identified by their record ID, as in transformOrder
+			if event.SalesOrderRecID == 0 || event.SalesOrderStatus == "" {
+				return nil, fmt.Errorf("business event %s has no sales order record ID or status", event.EventID)
+			}
+			change.Type = models.RetailChangeOrderStatus
+			change.OrderID = strconv.FormatInt(event.SalesOrderRecID, 10)
+			change.OrderNumber = event.SalesOrderNumber
+			change.OrderStatus = a.mapD365OrderStatus(event.SalesOrderStatus)
+		case productPriceChangedEvent, inventoryChangedEvent:
+			if event.ItemNumber == "" {
+				return nil, fmt.Errorf("business event %s has no item number", event.EventID)
+			}
+			change.Type = models.RetailChangePrice
+			if event.BusinessEventID == inventoryChangedEvent {
+				change.Type = models.RetailChangeInventory
+			}
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	// Events are only recorded once the whole notification was understood
+	fresh := changes[:0]
+	for _, change := range changes {
+		if change.ID == "" || a.processedEvents.claim(change.ID, signedAt.Add(businessEventTolerance), now) {
+			fresh = append(fresh, change)
+		}
+	}
+	return fresh, nil
+}
+
+// parseBusinessEventTime reads the OData "/Date(<unix ms>)/" or RFC 3339 event time
+// Notifications without a readable time are taken as current.
+func parseBusinessEventTime(value string) time.Time {
+	if millis, ok := strings.CutPrefix(value, "/Date("); ok {
+		if ms, err := strconv.ParseInt(strings.TrimSuffix(millis, ")/"), 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC()
+		}
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// verifyBusinessEvent checks a signature header against the body and returns the time it was signed
+func verifyBusinessEvent(secret, header string, body []byte, now time.Time) (time.Time, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return time.Time{}, fmt.Errorf("malformed %s header: %w", BusinessEventSignatureHeader, ports.ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed %s timestamp: %w", BusinessEventSignatureHeader, ports.ErrInvalidSignature)
+	}
+	signedAt := time.Unix(seconds, 0)
+	if age := now.Sub(signedAt); age > businessEventTolerance || age < -businessEventTolerance {
+		return time.Time{}, fmt.Errorf("business event timestamp is outside the tolerance of %s: %w", businessEventTolerance, ports.ErrInvalidSignature)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(businessEventMAC(secret, timestamp, body))) {
+		return time.Time{}, fmt.Errorf("business event signature does not match: %w", ports.ErrInvalidSignature)
+	}
+	return signedAt, nil
+}
+
+func businessEventMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
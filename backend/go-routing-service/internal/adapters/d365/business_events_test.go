@@ -0,0 +1,121 @@
+package d365
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCommerceAdapter(t *testing.T, config map[string]interface{}) *D365CommerceAdapter {
+	connector, err := NewD365CommerceAdapter(ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  "ikea-seattle",
+		Domain:   "retail",
+		Adapter:  "D365CommerceAdapter",
+		Config:   config,
+		Timeout:  5000,
+	})
+	require.NoError(t, err)
+	return connector.(*D365CommerceAdapter)
+}
+
+func signedHeader(secret string, body []byte, at time.Time) http.Header {
+	header := http.Header{}
+	header.Set(BusinessEventSignatureHeader, SignBusinessEvent(secret, body, at))
+	return header
+}
+
+func TestParseChangeNotification_MapsBusinessEvents(t *testing.T) {
+	adapter := newTestCommerceAdapter(t, map[string]interface{}{"webhookSecret": "d365-secret"})
+	body := []byte(`[
+		{"BusinessEventId":"SalesOrderStatusChangedBusinessEvent","EventId":"evt-1","EventTime":"/Date(1760000000000)/","SalesOrderRecId":5637144576,"SalesOrderNumber":"SO-001","SalesOrderStatus":"ReadyForPickup"},
+		{"BusinessEventId":"ProductPriceChangedBusinessEvent","EventId":"evt-2","EventTime":"2026-10-01T12:00:00Z","ItemNumber":"0001","ProductRecId":68719476737},
+		{"BusinessEventId":"InventoryOnHandChangedBusinessEvent","EventId":"evt-3","ItemNumber":"0002"},
+		{"BusinessEventId":"VendorCreatedBusinessEvent","EventId":"evt-4"}
+	]`)
+
+	changes, err := adapter.ParseChangeNotification(context.Background(), signedHeader("d365-secret", body, time.Now()), body)
+	require.NoError(t, err)
+	require.Len(t, changes, 3, "unhandled business events are ignored")
+
+	assert.Equal(t, models.RetailChangeOrderStatus, changes[0].Type)
+	assert.Equal(t, "5637144576", changes[0].OrderID)
+	assert.Equal(t, "SO-001", changes[0].OrderNumber)
+	assert.Equal(t, models.OrderStatusReadyForPickup, changes[0].OrderStatus)
+	assert.Equal(t, time.UnixMilli(1760000000000).UTC(), changes[0].OccurredAt)
+
+	assert.Equal(t, models.RetailChangePrice, changes[1].Type)
+	assert.Equal(t, "0001", changes[1].SKU)
+	assert.Equal(t, "68719476737", changes[1].ProductID)
+	assert.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), changes[1].OccurredAt)
+
+	assert.Equal(t, models.RetailChangeInventory, changes[2].Type)
+	assert.Equal(t, "0002", changes[2].SKU)
+}
+
+func TestParseChangeNotification_AcceptsSingleEvent(t *testing.T) {
+	adapter := newTestCommerceAdapter(t, map[string]interface{}{"webhookSecret": "d365-secret"})
+	body := []byte(`{"BusinessEventId":"SalesOrderStatusChangedBusinessEvent","EventId":"evt-1","SalesOrderRecId":5637144576,"SalesOrderNumber":"SO-001","SalesOrderStatus":"Delivered"}`)
+
+	changes, err := adapter.ParseChangeNotification(context.Background(), signedHeader("d365-secret", body, time.Now()), body)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, models.OrderStatusDelivered, changes[0].OrderStatus)
+}
+
+func TestParseChangeNotification_RejectsUnsignedNotifications(t *testing.T) {
+	body := []byte(`{"BusinessEventId":"SalesOrderStatusChangedBusinessEvent","EventId":"evt-1","SalesOrderRecId":5637144576,"SalesOrderNumber":"SO-001","SalesOrderStatus":"Invoiced"}`)
+	ctx := context.Background()
+
+	adapter := newTestCommerceAdapter(t, map[string]interface{}{"webhookSecret": "d365-secret"})
+	_, err := adapter.ParseChangeNotification(ctx, signedHeader("other-secret", body, time.Now()), body)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+	_, err = adapter.ParseChangeNotification(ctx, signedHeader("d365-secret", body, time.Now().Add(-10*time.Minute)), body)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature, "stale notifications are rejected")
+	_, err = adapter.ParseChangeNotification(ctx, http.Header{}, body)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+
+	unconfigured := newTestCommerceAdapter(t, map[string]interface{}{})
+	_, err = unconfigured.ParseChangeNotification(ctx, signedHeader("", body, time.Now()), body)
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature, "notifications are refused without a webhookSecret")
+}
+
+func TestParseChangeNotification_DropsReplayedEvents(t *testing.T) {
+	adapter := newTestCommerceAdapter(t, map[string]interface{}{"webhookSecret": "d365-secret"})
+	ctx := context.Background()
+	body := []byte(`{"BusinessEventId":"SalesOrderStatusChangedBusinessEvent","EventId":"evt-1","SalesOrderRecId":5637144576,"SalesOrderNumber":"SO-001","SalesOrderStatus":"Delivered"}`)
+	header := signedHeader("d365-secret", body, time.Now())
+
+	changes, err := adapter.ParseChangeNotification(ctx, header, body)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	changes, err = adapter.ParseChangeNotification(ctx, header, body)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "a replayed notification reports no changes")
+
+	batch := []byte(`[
+		{"BusinessEventId":"SalesOrderStatusChangedBusinessEvent","EventId":"evt-1","SalesOrderRecId":5637144576,"SalesOrderNumber":"SO-001","SalesOrderStatus":"Delivered"},
+		{"BusinessEventId":"InventoryOnHandChangedBusinessEvent","EventId":"evt-2","ItemNumber":"0002"}
+	]`)
+	changes, err = adapter.ParseChangeNotification(ctx, signedHeader("d365-secret", batch, time.Now()), batch)
+	require.NoError(t, err)
+	require.Len(t, changes, 1, "only the events already handled are dropped")
+	assert.Equal(t, "evt-2", changes[0].ID)
+}
+
+func TestProcessedEvents_ForgetsExpiredEvents(t *testing.T) {
+	events := newProcessedEvents()
+	now := time.Now()
+
+	assert.True(t, events.claim("evt-1", now.Add(businessEventTolerance), now))
+	assert.False(t, events.claim("evt-1", now.Add(businessEventTolerance), now.Add(time.Minute)))
+	assert.True(t, events.claim("evt-1", now.Add(2*businessEventTolerance), now.Add(businessEventTolerance)),
+		"an event is forgotten once its notification can no longer be replayed")
+}
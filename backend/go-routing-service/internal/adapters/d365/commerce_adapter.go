@@ -26,6 +26,9 @@ type D365CommerceAdapter struct {
 	apiKey         string
 	tenantID       string
 	demoMode       bool
+
+	// processedEvents holds the IDs of business events already handled, to drop replays
+	processedEvents *processedEvents
 }
 
 // NewD365CommerceAdapter creates a new D365 Commerce adapter
@@ -37,7 +40,8 @@ func NewD365CommerceAdapter(config ports.ConnectorConfig) (ports.IConnector, err
 		httpClient: &http.Client{
 			Timeout: time.Duration(config.Timeout) * time.Millisecond,
 		},
-		demoMode: false,
+		demoMode:        false,
+		processedEvents: newProcessedEvents(),
 	}
 
 	// Check for demo mode
@@ -205,7 +209,7 @@ func (a *D365CommerceAdapter) GetProduct(ctx context.Context, productID string)
 		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("product not found: %s", productID)
+			return nil, fmt.Errorf("product not found: %s: %w", productID, ports.ErrNotFound)
 		}
 
 		if resp.StatusCode != http.StatusOK {
@@ -234,7 +238,11 @@ func (a *D365CommerceAdapter) GetProductBySKU(ctx context.Context, sku string) (
 		return a.getDemoProduct(sku), nil
 	}
 
-	endpoint := fmt.Sprintf("%s/api/commerce/v1/Products?$filter=ItemId eq '%s'&$expand=Variants,Images", a.baseURL, sku)
+	query := url.Values{}
+	query.Set("$filter", fmt.Sprintf("ItemId eq '%s'", escapeODataString(sku)))
+	query.Set("$expand", "Variants,Images")
+
+	endpoint := fmt.Sprintf("%s/api/commerce/v1/Products?%s", a.baseURL, query.Encode())
 
 	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
 		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
@@ -261,7 +269,7 @@ func (a *D365CommerceAdapter) GetProductBySKU(ctx context.Context, sku string) (
 		}
 
 		if len(odataResp.Value) == 0 {
-			return nil, fmt.Errorf("product not found with SKU: %s", sku)
+			return nil, fmt.Errorf("product not found with SKU: %s: %w", sku, ports.ErrNotFound)
 		}
 
 		return a.transformProduct(odataResp.Value[0]), nil
@@ -298,6 +306,9 @@ func (a *D365CommerceAdapter) CreateOrder(ctx context.Context, orderReq models.O
 
 		a.addAuthHeaders(req)
 		req.Header.Set("Content-Type", "application/json")
+		if key := orderReq.IdempotencyKey(); key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
 
 		resp, err := a.httpClient.Do(req)
 		if err != nil {
@@ -348,7 +359,7 @@ func (a *D365CommerceAdapter) GetOrder(ctx context.Context, orderID string) (*mo
 		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, fmt.Errorf("order not found: %s", orderID)
+			return nil, fmt.Errorf("order not found: %s: %w", orderID, ports.ErrNotFound)
 		}
 
 		if resp.StatusCode != http.StatusOK {
@@ -524,13 +535,14 @@ func (a *D365CommerceAdapter) buildODataQuery(filters models.ProductFilters) str
 func (a *D365CommerceAdapter) mapOrderStatus(status models.OrderStatus) string {
 	// Map domain status to D365 status values
 	statusMap := map[models.OrderStatus]string{
-		models.OrderStatusPending:    "Created",
-		models.OrderStatusProcessing: "Processing",
-		models.OrderStatusPaid:       "Confirmed",
-		models.OrderStatusShipped:    "Shipped",
-		models.OrderStatusDelivered:  "Delivered",
-		models.OrderStatusCancelled:  "Cancelled",
-		models.OrderStatusRefunded:   "Returned",
+		models.OrderStatusPending:        "Created",
+		models.OrderStatusProcessing:     "Processing",
+		models.OrderStatusPaid:           "Confirmed",
+		models.OrderStatusShipped:        "Shipped",
+		models.OrderStatusReadyForPickup: "ReadyForPickup",
+		models.OrderStatusDelivered:      "Delivered",
+		models.OrderStatusCancelled:      "Cancelled",
+		models.OrderStatusRefunded:       "Returned",
 	}
 
 	if d365Status, ok := statusMap[status]; ok {
@@ -0,0 +1,74 @@
+package d365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHTTPCommerceAdapter creates an adapter that calls handler as its D365 backend
+func newHTTPCommerceAdapter(t *testing.T, handler http.HandlerFunc) *D365CommerceAdapter {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  "ikea-seattle",
+		Domain:   "retail",
+		URL:      server.URL,
+		Adapter:  "D365CommerceAdapter",
+		Config:   map[string]interface{}{"apiKey": "d365-key"},
+		Timeout:  5000,
+	}
+	connector, err := NewD365CommerceAdapter(config)
+	require.NoError(t, err)
+	require.NoError(t, connector.Initialize(context.Background(), config))
+	return connector.(*D365CommerceAdapter)
+}
+
+func TestD365CommerceAdapter_UnknownProductsAreNotFound(t *testing.T) {
+	adapter := newHTTPCommerceAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/commerce/v1/Products":
+			assert.Equal(t, "ItemId eq 'UNKNOWN SKU'", r.URL.Query().Get("$filter"))
+			json.NewEncoder(w).Encode(D365ProductListResponse{Value: []D365Product{}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	_, err := adapter.GetProduct(context.Background(), "99999")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	_, err = adapter.GetProductBySKU(context.Background(), "UNKNOWN SKU")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
+
+func TestD365CommerceAdapter_CreateOrderSendsIdempotencyKey(t *testing.T) {
+	var key string
+	var body map[string]interface{}
+	adapter := newHTTPCommerceAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(D365Order{SalesID: "SO-1001"})
+	})
+
+	_, err := adapter.CreateOrder(context.Background(), models.OrderRequest{
+		StoreID:   "ikea-seattle",
+		LineItems: []models.OrderLineItem{{SKU: "BILLY-WHITE-001", Quantity: 1}},
+		Metadata:  map[string]interface{}{"idempotencyKey": "session-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", key)
+	assert.Equal(t, "session-1", body["ChannelReferenceId"])
+}
@@ -285,6 +285,9 @@ func (a *D365CommerceAdapter) getDemoOrders(customerID string, limit, offset int
 		*a.getDemoOrderByID("1003"),
 	}
 
+	if offset > len(orders) {
+		offset = len(orders)
+	}
 	end := offset + limit
 	if end > len(orders) {
 		end = len(orders)
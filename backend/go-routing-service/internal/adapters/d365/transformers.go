@@ -285,13 +285,14 @@ func (a *D365CommerceAdapter) transformAddress(d365Addr D365Address) *models.Add
 func (a *D365CommerceAdapter) mapD365OrderStatus(d365Status string) models.OrderStatus {
 	// Map D365 status to domain status
 	statusMap := map[string]models.OrderStatus{
-		"Created":    models.OrderStatusPending,
-		"Processing": models.OrderStatusProcessing,
-		"Confirmed":  models.OrderStatusPaid,
-		"Shipped":    models.OrderStatusShipped,
-		"Delivered":  models.OrderStatusDelivered,
-		"Cancelled":  models.OrderStatusCancelled,
-		"Returned":   models.OrderStatusRefunded,
+		"Created":        models.OrderStatusPending,
+		"Processing":     models.OrderStatusProcessing,
+		"Confirmed":      models.OrderStatusPaid,
+		"Shipped":        models.OrderStatusShipped,
+		"ReadyForPickup": models.OrderStatusReadyForPickup,
+		"Delivered":      models.OrderStatusDelivered,
+		"Cancelled":      models.OrderStatusCancelled,
+		"Returned":       models.OrderStatusRefunded,
 	}
 
 	if status, ok := statusMap[d365Status]; ok {
@@ -311,6 +312,11 @@ func (a *D365CommerceAdapter) transformOrderRequest(orderReq models.OrderRequest
 		"Lines":           a.transformOrderLines(orderReq.LineItems),
 	}
 
+	// The channel reference lets D365 and support staff match resubmissions to the first order
+	if key := orderReq.IdempotencyKey(); key != "" {
+		d365Order["ChannelReferenceId"] = key
+	}
+
 	// Add delivery address
 	if orderReq.ShippingAddress.Address1 != "" {
 		d365Order["DeliveryAddress"] = map[string]interface{}{
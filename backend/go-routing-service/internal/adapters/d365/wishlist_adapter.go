@@ -0,0 +1,643 @@
+package d365
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// D365WishlistAdapter implements IWishlistConnector on D365 Commerce customer wishlists (CommerceLists)
+type D365WishlistAdapter struct {
+	config         ports.ConnectorConfig
+	baseURL        string
+	httpClient     *http.Client
+	circuitBreaker *gobreaker.CircuitBreaker
+	apiKey         string
+	demoMode       bool
+	demo           *demoCommerceLists
+}
+
+// NewD365WishlistAdapter creates a new D365 wishlist adapter
+func NewD365WishlistAdapter(config ports.ConnectorConfig) (ports.IConnector, error) {
+	adapter := &D365WishlistAdapter{
+		config:  config,
+		baseURL: strings.TrimRight(config.URL, "/"),
+		httpClient: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Millisecond,
+		},
+		demoMode: false,
+	}
+
+	// Check for demo mode
+	if demoMode, ok := config.Config["demoMode"].(bool); ok {
+		adapter.demoMode = demoMode
+	}
+	if adapter.demoMode {
+		adapter.demo = newDemoCommerceLists()
+	}
+
+	// Extract API key from config
+	if apiKey, ok := config.Config["apiKey"].(string); ok {
+		adapter.apiKey = apiKey
+	}
+
+	// Initialize circuit breaker
+	cbSettings := gobreaker.Settings{
+		Name:        fmt.Sprintf("d365-wishlist-%s", config.StoreID),
+		MaxRequests: 3,
+		Interval:    10 * time.Second,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= 5 && failureRatio >= 0.5
+		},
+		IsSuccessful: func(err error) bool {
+			// A missing wishlist or line is a valid answer, not a backend failure
+			return err == nil || errors.Is(err, ports.ErrNotFound)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from_state", from.String()).
+				Str("to_state", to.String()).
+				Msg("D365 wishlist circuit breaker state changed")
+		},
+	}
+	adapter.circuitBreaker = gobreaker.NewCircuitBreaker(cbSettings)
+
+	return adapter, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *D365WishlistAdapter) GetDomain() string {
+	return "wishlist"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *D365WishlistAdapter) GetAdapterType() string {
+	return "D365WishlistAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *D365WishlistAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("storeId", config.StoreID).
+		Str("url", config.URL).
+		Bool("demoMode", a.demoMode).
+		Msg("Initializing D365 wishlist adapter")
+
+	if !a.demoMode && a.apiKey == "" {
+		return fmt.Errorf("apiKey is required for D365 wishlist adapter (non-demo mode)")
+	}
+
+	return nil
+}
+
+// HealthCheck verifies the connector can communicate with its backend
+func (a *D365WishlistAdapter) HealthCheck(ctx context.Context) error {
+	if a.demoMode {
+		return nil // Demo mode always healthy
+	}
+
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", a.baseURL+"/api/health", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 500 {
+			return nil, fmt.Errorf("health check failed with status %d", resp.StatusCode)
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// Close gracefully shuts down the connector
+func (a *D365WishlistAdapter) Close() error {
+	log.Info().Str("storeId", a.config.StoreID).Msg("Closing D365 wishlist adapter")
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// GetWishlists retrieves all wishlists for a customer
+func (a *D365WishlistAdapter) GetWishlists(ctx context.Context, customerID string) (*models.WishlistList, error) {
+	var lists []D365CommerceList
+
+	if a.demoMode {
+		lists = a.demo.byCustomer(customerID)
+	} else {
+		path := fmt.Sprintf("/CommerceLists/GetByCustomer(customerId='%s')", url.PathEscape(escapeODataString(customerID)))
+		result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+			var resp D365CommerceListResponse
+			if err := a.doRequest(ctx, "GET", path, nil, &resp); err != nil {
+				return nil, err
+			}
+			return resp.Value, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wishlists from D365: %w", err)
+		}
+		lists = result.([]D365CommerceList)
+	}
+
+	wishlists := make([]models.Wishlist, 0, len(lists))
+	for _, list := range lists {
+		wishlists = append(wishlists, *a.transformCommerceList(list))
+	}
+
+	return &models.WishlistList{
+		Wishlists: wishlists,
+		Total:     len(wishlists),
+		Limit:     len(wishlists),
+		Offset:    0,
+		HasMore:   false,
+	}, nil
+}
+
+// GetWishlist retrieves a specific wishlist
+func (a *D365WishlistAdapter) GetWishlist(ctx context.Context, wishlistID string) (*models.Wishlist, error) {
+	list, err := a.getCommerceList(ctx, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist from D365: %w", err)
+	}
+	return a.transformCommerceList(*list), nil
+}
+
+// CreateWishlist creates a new wishlist
+func (a *D365WishlistAdapter) CreateWishlist(ctx context.Context, customerID, name string, isPublic bool) (*models.Wishlist, error) {
+	list := D365CommerceList{
+		Name:       name,
+		CustomerID: customerID,
+		IsPrivate:  !isPublic,
+		Lines:      []D365CommerceListLine{},
+	}
+
+	created, err := a.execute(ctx, "POST", "/CommerceLists", list, func() (*D365CommerceList, error) {
+		return a.demo.create(list), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wishlist in D365: %w", err)
+	}
+
+	return a.transformCommerceList(*created), nil
+}
+
+// UpdateWishlist renames a wishlist or changes its visibility
+func (a *D365WishlistAdapter) UpdateWishlist(ctx context.Context, wishlistID string, update models.WishlistUpdate) (*models.Wishlist, error) {
+	list, err := a.getCommerceList(ctx, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wishlist in D365: %w", err)
+	}
+
+	if update.Name != nil {
+		list.Name = *update.Name
+	}
+	if update.IsPublic != nil {
+		list.IsPrivate = !*update.IsPublic
+	}
+
+	// Lines are managed through AddLines/RemoveLines/UpdateLines, so only the header fields are sent
+	payload := map[string]interface{}{"Name": list.Name, "IsPrivate": list.IsPrivate}
+
+	updated, err := a.execute(ctx, "PATCH", fmt.Sprintf("/CommerceLists(%d)", list.ID), payload, func() (*D365CommerceList, error) {
+		return a.demo.update(*list)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wishlist in D365: %w", err)
+	}
+
+	// Retail Server may answer PATCH with 204 No Content
+	if updated.ID == 0 {
+		updated = list
+	}
+
+	return a.transformCommerceList(*updated), nil
+}
+
+// AddItem adds a line to a wishlist
+func (a *D365WishlistAdapter) AddItem(ctx context.Context, wishlistID string, req models.AddWishlistItemRequest) (*models.WishlistItem, error) {
+	listID, err := parseCommerceListID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := strconv.ParseInt(req.ProductID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("D365 wishlist lines require a numeric product record ID, got %q", req.ProductID)
+	}
+
+	list, err := a.getCommerceList(ctx, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add item to D365 wishlist: %w", err)
+	}
+
+	// Merge with an existing line for the same product and variant
+	if existing := findProductLine(list, productID, req.VariantID); existing != nil {
+		quantity := req.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		notes := extensionMap(existing.ExtensionProperties)[extNotes].StringValue
+		if req.Notes != "" {
+			notes = req.Notes
+		}
+		return a.updateLine(ctx, listID, *existing, int(existing.Quantity)+quantity, notes)
+	}
+
+	line := a.transformAddItemRequest(listID, productID, list.CustomerID, req)
+	payload := map[string]interface{}{"commerceListLines": []D365CommerceListLine{line}}
+
+	updated, err := a.execute(ctx, "POST", fmt.Sprintf("/CommerceLists(%d)/AddLines", listID), payload, func() (*D365CommerceList, error) {
+		return a.demo.addLine(listID, line)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add item to D365 wishlist: %w", err)
+	}
+
+	// D365 assigns line IDs; the new line has the highest one
+	var added *D365CommerceListLine
+	for i := range updated.Lines {
+		if added == nil || updated.Lines[i].LineID > added.LineID {
+			added = &updated.Lines[i]
+		}
+	}
+	if added == nil {
+		return nil, fmt.Errorf("failed to add item to D365 wishlist: no line returned")
+	}
+
+	item := a.transformCommerceListLine(*added)
+	return &item, nil
+}
+
+// RemoveItem removes a line from a wishlist
+func (a *D365WishlistAdapter) RemoveItem(ctx context.Context, wishlistID, itemID string) error {
+	listID, err := parseCommerceListID(wishlistID)
+	if err != nil {
+		return err
+	}
+	lineID, err := strconv.ParseInt(itemID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+
+	list, err := a.getCommerceList(ctx, wishlistID)
+	if err != nil {
+		return fmt.Errorf("failed to remove item from D365 wishlist: %w", err)
+	}
+	if findLine(list, lineID) == nil {
+		return fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+
+	payload := map[string]interface{}{
+		"commerceListLines": []D365CommerceListLine{{CommerceListID: listID, LineID: lineID}},
+	}
+	_, err = a.execute(ctx, "POST", fmt.Sprintf("/CommerceLists(%d)/RemoveLines", listID), payload, func() (*D365CommerceList, error) {
+		return a.demo.removeLine(listID, lineID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove item from D365 wishlist: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateItem updates the quantity and notes of a wishlist line
+func (a *D365WishlistAdapter) UpdateItem(ctx context.Context, wishlistID, itemID string, quantity int, notes string) (*models.WishlistItem, error) {
+	listID, err := parseCommerceListID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	lineID, err := strconv.ParseInt(itemID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+
+	list, err := a.getCommerceList(ctx, wishlistID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update D365 wishlist item: %w", err)
+	}
+	line := findLine(list, lineID)
+	if line == nil {
+		return nil, fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+
+	return a.updateLine(ctx, listID, *line, quantity, notes)
+}
+
+// DeleteWishlist deletes a wishlist
+func (a *D365WishlistAdapter) DeleteWishlist(ctx context.Context, wishlistID string) error {
+	listID, err := parseCommerceListID(wishlistID)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.execute(ctx, "DELETE", fmt.Sprintf("/CommerceLists(%d)", listID), nil, func() (*D365CommerceList, error) {
+		return nil, a.demo.delete(listID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete wishlist in D365: %w", err)
+	}
+
+	return nil
+}
+
+// Private helper methods
+
+func (a *D365WishlistAdapter) getCommerceList(ctx context.Context, wishlistID string) (*D365CommerceList, error) {
+	listID, err := parseCommerceListID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.execute(ctx, "GET", fmt.Sprintf("/CommerceLists(%d)", listID), nil, func() (*D365CommerceList, error) {
+		return a.demo.get(listID)
+	})
+}
+
+// updateLine sets the quantity and notes of an existing line through UpdateLines
+func (a *D365WishlistAdapter) updateLine(ctx context.Context, listID int64, line D365CommerceListLine, quantity int, notes string) (*models.WishlistItem, error) {
+	changed := line
+	changed.Quantity = float64(quantity)
+	changed.ExtensionProperties = setExtension(append([]D365CommerceProperty(nil), line.ExtensionProperties...), extNotes, D365CommercePropertyValue{StringValue: notes})
+
+	payload := map[string]interface{}{"commerceListLines": []D365CommerceListLine{changed}}
+	updated, err := a.execute(ctx, "POST", fmt.Sprintf("/CommerceLists(%d)/UpdateLines", listID), payload, func() (*D365CommerceList, error) {
+		return a.demo.updateLine(listID, changed)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update D365 wishlist item: %w", err)
+	}
+
+	result := findLine(updated, line.LineID)
+	if result == nil {
+		return nil, fmt.Errorf("wishlist item %d: %w", line.LineID, ports.ErrNotFound)
+	}
+	item := a.transformCommerceListLine(*result)
+	return &item, nil
+}
+
+// execute runs a CommerceLists call against D365, or against the in-memory lists in demo mode
+// Calls that return a list decode it; DELETE returns nil
+func (a *D365WishlistAdapter) execute(ctx context.Context, method, path string, body interface{}, demo func() (*D365CommerceList, error)) (*D365CommerceList, error) {
+	if a.demoMode {
+		return demo()
+	}
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		if method == "DELETE" {
+			return (*D365CommerceList)(nil), a.doRequest(ctx, method, path, body, nil)
+		}
+		var list D365CommerceList
+		if err := a.doRequest(ctx, method, path, body, &list); err != nil {
+			return nil, err
+		}
+		return &list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*D365CommerceList), nil
+}
+
+func (a *D365WishlistAdapter) doRequest(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	endpoint := a.baseURL + "/api/commerce/v1" + path
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	if a.apiKey != "" {
+		req.Header.Set("Api-Key", a.apiKey)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("D365 commerce list: %w", ports.ErrNotFound)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("D365 API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func parseCommerceListID(wishlistID string) (int64, error) {
+	id, err := strconv.ParseInt(wishlistID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+	return id, nil
+}
+
+func findLine(list *D365CommerceList, lineID int64) *D365CommerceListLine {
+	for i := range list.Lines {
+		if list.Lines[i].LineID == lineID {
+			return &list.Lines[i]
+		}
+	}
+	return nil
+}
+
+// escapeODataString escapes single quotes in OData string literals
+// findProductLine returns the line for a product and variant, or nil
+func findProductLine(list *D365CommerceList, productID int64, variantID string) *D365CommerceListLine {
+	for i := range list.Lines {
+		line := &list.Lines[i]
+		if line.ProductID == productID && extensionMap(line.ExtensionProperties)[extVariantID].StringValue == variantID {
+			return line
+		}
+	}
+	return nil
+}
+
+func escapeODataString(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// demoCommerceLists is an in-memory stand-in for CommerceLists used in demo mode
+type demoCommerceLists struct {
+	mu         sync.Mutex
+	lists      map[int64]*D365CommerceList
+	nextListID int64
+	nextLineID int64
+}
+
+func newDemoCommerceLists() *demoCommerceLists {
+	return &demoCommerceLists{
+		lists:      make(map[int64]*D365CommerceList),
+		nextListID: 68719476736,
+		nextLineID: 1,
+	}
+}
+
+func (d *demoCommerceLists) byCustomer(customerID string) []D365CommerceList {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var lists []D365CommerceList
+	for id := int64(68719476736); id < d.nextListID; id++ {
+		if list, ok := d.lists[id]; ok && list.CustomerID == customerID {
+			lists = append(lists, copyCommerceList(list))
+		}
+	}
+	return lists
+}
+
+func (d *demoCommerceLists) get(listID int64) (*D365CommerceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list, ok := d.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("D365 commerce list %d: %w", listID, ports.ErrNotFound)
+	}
+	copied := copyCommerceList(list)
+	return &copied, nil
+}
+
+func (d *demoCommerceLists) create(list D365CommerceList) *D365CommerceList {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list.ID = d.nextListID
+	d.nextListID++
+	d.lists[list.ID] = &list
+
+	copied := copyCommerceList(&list)
+	return &copied
+}
+
+func (d *demoCommerceLists) update(header D365CommerceList) (*D365CommerceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list, ok := d.lists[header.ID]
+	if !ok {
+		return nil, fmt.Errorf("D365 commerce list %d: %w", header.ID, ports.ErrNotFound)
+	}
+	list.Name = header.Name
+	list.IsPrivate = header.IsPrivate
+
+	copied := copyCommerceList(list)
+	return &copied, nil
+}
+
+func (d *demoCommerceLists) addLine(listID int64, line D365CommerceListLine) (*D365CommerceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list, ok := d.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("D365 commerce list %d: %w", listID, ports.ErrNotFound)
+	}
+	line.LineID = d.nextLineID
+	d.nextLineID++
+	list.Lines = append(list.Lines, line)
+
+	copied := copyCommerceList(list)
+	return &copied, nil
+}
+
+func (d *demoCommerceLists) removeLine(listID, lineID int64) (*D365CommerceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list, ok := d.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("D365 commerce list %d: %w", listID, ports.ErrNotFound)
+	}
+	lines := list.Lines[:0]
+	for _, line := range list.Lines {
+		if line.LineID != lineID {
+			lines = append(lines, line)
+		}
+	}
+	list.Lines = lines
+
+	copied := copyCommerceList(list)
+	return &copied, nil
+}
+
+func (d *demoCommerceLists) updateLine(listID int64, changed D365CommerceListLine) (*D365CommerceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list, ok := d.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("D365 commerce list %d: %w", listID, ports.ErrNotFound)
+	}
+	for i := range list.Lines {
+		if list.Lines[i].LineID == changed.LineID {
+			list.Lines[i] = changed
+		}
+	}
+
+	copied := copyCommerceList(list)
+	return &copied, nil
+}
+
+func (d *demoCommerceLists) delete(listID int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.lists[listID]; !ok {
+		return fmt.Errorf("D365 commerce list %d: %w", listID, ports.ErrNotFound)
+	}
+	delete(d.lists, listID)
+	return nil
+}
+
+func copyCommerceList(list *D365CommerceList) D365CommerceList {
+	copied := *list
+	copied.Lines = append([]D365CommerceListLine(nil), list.Lines...)
+	return copied
+}
@@ -0,0 +1,172 @@
+package d365
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWishlistAdapter(t *testing.T, url string, config map[string]interface{}) *D365WishlistAdapter {
+	connectorConfig := ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  "ikea-seattle",
+		Domain:   "wishlist",
+		URL:      url,
+		Adapter:  "D365WishlistAdapter",
+		Config:   config,
+		Timeout:  5000,
+	}
+
+	connector, err := NewD365WishlistAdapter(connectorConfig)
+	require.NoError(t, err)
+	require.NoError(t, connector.Initialize(context.Background(), connectorConfig))
+
+	return connector.(*D365WishlistAdapter)
+}
+
+func TestD365WishlistAdapter_DemoMode_Lifecycle(t *testing.T) {
+	adapter := newTestWishlistAdapter(t, "", map[string]interface{}{"demoMode": true})
+	ctx := context.Background()
+
+	var _ ports.IWishlistConnector = adapter
+
+	wishlist, err := adapter.CreateWishlist(ctx, "cust-1", "Living room", false)
+	require.NoError(t, err)
+	assert.Equal(t, "Living room", wishlist.Name)
+	assert.Equal(t, "ikea-seattle", wishlist.StoreID)
+
+	name, public := "Living room ideas", true
+	renamed, err := adapter.UpdateWishlist(ctx, wishlist.ID, models.WishlistUpdate{Name: &name, IsPublic: &public})
+	require.NoError(t, err)
+	assert.Equal(t, "Living room ideas", renamed.Name)
+	assert.True(t, renamed.IsPublic)
+
+	item, err := adapter.AddItem(ctx, wishlist.ID, models.AddWishlistItemRequest{
+		ProductID: "1001",
+		SKU:       "BILLY-WHITE-001",
+		Name:      "BILLY Bookcase, white",
+		Price:     models.Price{Amount: 79.99, Currency: "USD"},
+		Notes:     "for the hallway",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1001", item.ProductID)
+	assert.Equal(t, 1, item.Quantity)
+	assert.Equal(t, 79.99, item.Price.Amount)
+	assert.False(t, item.AddedAt.IsZero())
+
+	updated, err := adapter.UpdateItem(ctx, wishlist.ID, item.ID, 3, "two for the office")
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.Quantity)
+	assert.Equal(t, "two for the office", updated.Notes)
+	assert.Equal(t, "BILLY Bookcase, white", updated.Name, "snapshot survives updates")
+
+	merged, err := adapter.AddItem(ctx, wishlist.ID, models.AddWishlistItemRequest{ProductID: "1001", Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, item.ID, merged.ID, "same product is merged")
+	assert.Equal(t, 5, merged.Quantity)
+	assert.Equal(t, "two for the office", merged.Notes, "empty notes keep the existing ones")
+
+	variant, err := adapter.AddItem(ctx, wishlist.ID, models.AddWishlistItemRequest{ProductID: "1001", VariantID: "1001-BLACK"})
+	require.NoError(t, err)
+	assert.NotEqual(t, item.ID, variant.ID, "another variant is a separate item")
+	require.NoError(t, adapter.RemoveItem(ctx, wishlist.ID, variant.ID))
+
+	wishlists, err := adapter.GetWishlists(ctx, "cust-1")
+	require.NoError(t, err)
+	require.Len(t, wishlists.Wishlists, 1)
+	require.Len(t, wishlists.Wishlists[0].Items, 1)
+
+	require.NoError(t, adapter.RemoveItem(ctx, wishlist.ID, item.ID))
+	assert.ErrorIs(t, adapter.RemoveItem(ctx, wishlist.ID, item.ID), ports.ErrNotFound)
+
+	require.NoError(t, adapter.DeleteWishlist(ctx, wishlist.ID))
+	_, err = adapter.GetWishlist(ctx, wishlist.ID)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	_, err = adapter.AddItem(ctx, wishlist.ID, models.AddWishlistItemRequest{ProductID: "BILLY"})
+	assert.Error(t, err)
+}
+
+func TestD365WishlistAdapter_CommerceListsAPI(t *testing.T) {
+	var addLinesBody struct {
+		CommerceListLines []D365CommerceListLine `json:"commerceListLines"`
+	}
+
+	list := D365CommerceList{ID: 5637144576, Name: "Wishlist", CustomerID: "004021", IsPrivate: true}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Api-Key") != "d365-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/commerce/v1/CommerceLists/GetByCustomer(customerId='004021')":
+			json.NewEncoder(w).Encode(D365CommerceListResponse{Value: []D365CommerceList{list}})
+		case "GET /api/commerce/v1/CommerceLists(5637144576)":
+			json.NewEncoder(w).Encode(list)
+		case "PATCH /api/commerce/v1/CommerceLists(5637144576)":
+			var header map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&header))
+			assert.NotContains(t, header, "CommerceListLines", "lines are not part of a header update")
+			list.Name = header["Name"].(string)
+			list.IsPrivate = header["IsPrivate"].(bool)
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /api/commerce/v1/CommerceLists(5637144576)/AddLines":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&addLinesBody))
+			line := addLinesBody.CommerceListLines[0]
+			line.LineID = 22565421970
+			list.Lines = append(list.Lines, line)
+			json.NewEncoder(w).Encode(list)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adapter := newTestWishlistAdapter(t, server.URL, map[string]interface{}{"apiKey": "d365-key"})
+	ctx := context.Background()
+
+	wishlists, err := adapter.GetWishlists(ctx, "004021")
+	require.NoError(t, err)
+	require.Len(t, wishlists.Wishlists, 1)
+	assert.Equal(t, "5637144576", wishlists.Wishlists[0].ID)
+	assert.False(t, wishlists.Wishlists[0].IsPublic)
+
+	item, err := adapter.AddItem(ctx, "5637144576", models.AddWishlistItemRequest{
+		ProductID: "68719478279",
+		Quantity:  2,
+		SKU:       "0001",
+		Name:      "KALLAX Shelf unit",
+		Price:     models.Price{Amount: 59.99, Currency: "USD"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, addLinesBody.CommerceListLines, 1)
+	sent := addLinesBody.CommerceListLines[0]
+	assert.Equal(t, int64(68719478279), sent.ProductID)
+	assert.Equal(t, "004021", sent.CustomerID)
+	assert.Equal(t, 2.0, sent.Quantity)
+
+	assert.Equal(t, "22565421970", item.ID)
+	assert.Equal(t, "KALLAX Shelf unit", item.Name)
+	assert.Equal(t, models.Price{Amount: 59.99, Currency: "USD"}, item.Price)
+
+	name := "Kids room"
+	renamed, err := adapter.UpdateWishlist(ctx, "5637144576", models.WishlistUpdate{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Kids room", renamed.Name)
+	assert.Equal(t, "Kids room", list.Name)
+	assert.False(t, renamed.IsPublic, "visibility is left unchanged")
+
+	_, err = adapter.GetWishlist(ctx, "5637144999")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,156 @@
+package d365
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// D365 CommerceLists structures (customer wishlists)
+
+// D365CommerceListResponse represents the OData collection response for commerce lists
+type D365CommerceListResponse struct {
+	Context string             `json:"@odata.context,omitempty"`
+	Value   []D365CommerceList `json:"value"`
+}
+
+// D365CommerceList represents a customer wishlist in D365 Commerce
+type D365CommerceList struct {
+	ID                  int64                  `json:"Id"`
+	Name                string                 `json:"Name"`
+	CustomerID          string                 `json:"CustomerId"`
+	IsFavorite          bool                   `json:"IsFavorite"`
+	IsPrivate           bool                   `json:"IsPrivate"`
+	IsCollaborative     bool                   `json:"IsCollaborative"`
+	Lines               []D365CommerceListLine `json:"CommerceListLines"`
+	ExtensionProperties []D365CommerceProperty `json:"ExtensionProperties,omitempty"`
+}
+
+// D365CommerceListLine represents a line of a commerce list
+type D365CommerceListLine struct {
+	CommerceListID      int64                  `json:"CommerceListId"`
+	LineID              int64                  `json:"LineId"`
+	CustomerID          string                 `json:"CustomerId,omitempty"`
+	ProductID           int64                  `json:"ProductId"`
+	Quantity            float64                `json:"Quantity"`
+	UnitOfMeasure       string                 `json:"UnitOfMeasure,omitempty"`
+	IsFavorite          bool                   `json:"IsFavorite"`
+	ExtensionProperties []D365CommerceProperty `json:"ExtensionProperties,omitempty"`
+}
+
+// D365CommerceProperty is a D365 extension property
+type D365CommerceProperty struct {
+	Key   string                    `json:"Key"`
+	Value D365CommercePropertyValue `json:"Value"`
+}
+
+// D365CommercePropertyValue holds the typed value of an extension property
+type D365CommercePropertyValue struct {
+	StringValue  string   `json:"StringValue,omitempty"`
+	DecimalValue *float64 `json:"DecimalValue,omitempty"`
+}
+
+// Extension properties used to keep a snapshot of the product on each list line,
+// since CommerceListLines only reference the product record ID
+const (
+	extSKU       = "AmicisSku"
+	extName      = "AmicisName"
+	extPrice     = "AmicisPrice"
+	extCurrency  = "AmicisCurrency"
+	extImageURL  = "AmicisImageUrl"
+	extNotes     = "AmicisNotes"
+	extVariantID = "AmicisVariantId"
+	extAddedAt   = "AmicisAddedAt"
+)
+
+// Transformation methods: D365 → Domain models
+
+func (a *D365WishlistAdapter) transformCommerceList(list D365CommerceList) *models.Wishlist {
+	items := make([]models.WishlistItem, 0, len(list.Lines))
+	for _, line := range list.Lines {
+		items = append(items, a.transformCommerceListLine(line))
+	}
+
+	return &models.Wishlist{
+		ID:         strconv.FormatInt(list.ID, 10),
+		CustomerID: list.CustomerID,
+		TenantID:   a.config.TenantID,
+		StoreID:    a.config.StoreID,
+		Name:       list.Name,
+		Items:      items,
+		IsPublic:   !list.IsPrivate,
+	}
+}
+
+func (a *D365WishlistAdapter) transformCommerceListLine(line D365CommerceListLine) models.WishlistItem {
+	props := extensionMap(line.ExtensionProperties)
+
+	item := models.WishlistItem{
+		ID:        strconv.FormatInt(line.LineID, 10),
+		ProductID: strconv.FormatInt(line.ProductID, 10),
+		VariantID: props[extVariantID].StringValue,
+		SKU:       props[extSKU].StringValue,
+		Name:      props[extName].StringValue,
+		ImageURL:  props[extImageURL].StringValue,
+		Notes:     props[extNotes].StringValue,
+		Quantity:  int(line.Quantity),
+		Price: models.Price{
+			Currency: props[extCurrency].StringValue,
+		},
+	}
+	if price := props[extPrice].DecimalValue; price != nil {
+		item.Price.Amount = *price
+	}
+	if addedAt, err := time.Parse(time.RFC3339, props[extAddedAt].StringValue); err == nil {
+		item.AddedAt = addedAt
+	}
+
+	return item
+}
+
+// Transformation methods: Domain models → D365
+
+func (a *D365WishlistAdapter) transformAddItemRequest(listID, productID int64, customerID string, req models.AddWishlistItemRequest) D365CommerceListLine {
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	price := req.Price.Amount
+	return D365CommerceListLine{
+		CommerceListID: listID,
+		CustomerID:     customerID,
+		ProductID:      productID,
+		Quantity:       float64(quantity),
+		ExtensionProperties: []D365CommerceProperty{
+			{Key: extSKU, Value: D365CommercePropertyValue{StringValue: req.SKU}},
+			{Key: extName, Value: D365CommercePropertyValue{StringValue: req.Name}},
+			{Key: extPrice, Value: D365CommercePropertyValue{DecimalValue: &price}},
+			{Key: extCurrency, Value: D365CommercePropertyValue{StringValue: req.Price.Currency}},
+			{Key: extImageURL, Value: D365CommercePropertyValue{StringValue: req.ImageURL}},
+			{Key: extNotes, Value: D365CommercePropertyValue{StringValue: req.Notes}},
+			{Key: extVariantID, Value: D365CommercePropertyValue{StringValue: req.VariantID}},
+			{Key: extAddedAt, Value: D365CommercePropertyValue{StringValue: time.Now().UTC().Format(time.RFC3339)}},
+		},
+	}
+}
+
+// setExtension replaces or appends an extension property
+func setExtension(props []D365CommerceProperty, key string, value D365CommercePropertyValue) []D365CommerceProperty {
+	for i := range props {
+		if props[i].Key == key {
+			props[i].Value = value
+			return props
+		}
+	}
+	return append(props, D365CommerceProperty{Key: key, Value: value})
+}
+
+func extensionMap(props []D365CommerceProperty) map[string]D365CommercePropertyValue {
+	values := make(map[string]D365CommercePropertyValue, len(props))
+	for _, prop := range props {
+		values[prop.Key] = prop.Value
+	}
+	return values
+}
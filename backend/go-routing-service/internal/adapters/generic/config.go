@@ -0,0 +1,212 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// restConfig is the declarative description of a REST backend, decoded from ConnectorConfig.Config
+//
+// Example connector document config:
+//
+//	{
+//	  "auth": {"type": "apiKey", "header": "X-Api-Key", "key": "..."},
+//	  "pagination": {"style": "page", "pageParam": "page", "limitParam": "per_page", "firstPage": 1},
+//	  "endpoints": {
+//	    "searchProducts": {"path": "/items", "query": {"q": "{searchTerm}"}, "items": "$.data[*]", "total": "$.meta.total"},
+//	    "getProduct": {"path": "/items/{id}", "result": "$.data"}
+//	  },
+//	  "mappings": {
+//	    "product": ["id <- $.itemCode", "name <- $.title", "price.amount <- $.pricing.current", "price.currency <- 'EUR'"]
+//	  },
+//	  "statusMap": {"OPEN": "pending", "PAID": "paid"}
+//	}
+type restConfig struct {
+	Auth       authConfig                `json:"auth"`
+	Pagination paginationConfig          `json:"pagination"`
+	Endpoints  map[string]endpointConfig `json:"endpoints"`
+	Mappings   map[string][]string       `json:"mappings"`
+	StatusMap  map[string]string         `json:"statusMap"`
+}
+
+// authConfig describes how requests are authenticated
+type authConfig struct {
+	Type string `json:"type"` // none, apiKey, bearer, basic, oauth2
+
+	// apiKey
+	Header     string `json:"header,omitempty"`
+	QueryParam string `json:"queryParam,omitempty"`
+	Key        string `json:"key,omitempty"`
+
+	// bearer
+	Token string `json:"token,omitempty"`
+
+	// basic
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// oauth2 client credentials
+	TokenURL     string `json:"tokenUrl,omitempty"`
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// paginationConfig describes how list endpoints are paged
+type paginationConfig struct {
+	Style       string `json:"style"` // none, offset, page, cursor
+	LimitParam  string `json:"limitParam,omitempty"`
+	OffsetParam string `json:"offsetParam,omitempty"`
+	PageParam   string `json:"pageParam,omitempty"`
+	FirstPage   int    `json:"firstPage,omitempty"`
+	CursorParam string `json:"cursorParam,omitempty"`
+	NextCursor  string `json:"nextCursor,omitempty"` // expression yielding the next cursor from a response
+	MaxPages    int    `json:"maxPages,omitempty"`
+}
+
+// endpointConfig describes a single backend operation
+// Path and query values may contain placeholders such as {id}, {sku}, {customerId},
+// {searchTerm}, {category}, {limit} and {offset}; query parameters that resolve to
+// an empty string are omitted.
+type endpointConfig struct {
+	Method string            `json:"method,omitempty"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query,omitempty"`
+	Items  string            `json:"items,omitempty"`  // expression selecting the list of records
+	Result string            `json:"result,omitempty"` // expression selecting a single record
+	Total  string            `json:"total,omitempty"`  // expression selecting the total count
+}
+
+// Endpoint names understood by the adapter
+const (
+	endpointHealth            = "health"
+	endpointSearchProducts    = "searchProducts"
+	endpointGetProduct        = "getProduct"
+	endpointGetProductBySKU   = "getProductBySku"
+	endpointCreateOrder       = "createOrder"
+	endpointGetOrder          = "getOrder"
+	endpointGetOrders         = "getOrders"
+	endpointUpdateOrderStatus = "updateOrderStatus"
+)
+
+// Mapping names understood by the adapter
+const (
+	mappingProduct           = "product"
+	mappingOrder             = "order"
+	mappingOrderRequest      = "orderRequest"
+	mappingOrderStatusUpdate = "orderStatusUpdate"
+)
+
+var knownEndpoints = map[string]bool{
+	endpointHealth:            true,
+	endpointSearchProducts:    true,
+	endpointGetProduct:        true,
+	endpointGetProductBySKU:   true,
+	endpointCreateOrder:       true,
+	endpointGetOrder:          true,
+	endpointGetOrders:         true,
+	endpointUpdateOrderStatus: true,
+}
+
+// parseRESTConfig decodes the raw connector config into a restConfig
+func parseRESTConfig(raw map[string]interface{}) (*restConfig, error) {
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var cfg restConfig
+	if err := json.Unmarshal(payload, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	if cfg.Auth.Type == "" {
+		cfg.Auth.Type = "none"
+	}
+	if cfg.Pagination.Style == "" {
+		cfg.Pagination.Style = "none"
+	}
+	if cfg.Pagination.MaxPages <= 0 {
+		cfg.Pagination.MaxPages = 10
+	}
+
+	return &cfg, nil
+}
+
+// validate checks the parts of the config that do not depend on mappings
+func (c *restConfig) validate() []error {
+	var errs []error
+
+	switch c.Auth.Type {
+	case "none":
+	case "apiKey":
+		if c.Auth.Key == "" {
+			errs = append(errs, fmt.Errorf("auth.key is required for apiKey auth"))
+		}
+		if c.Auth.Header == "" && c.Auth.QueryParam == "" {
+			errs = append(errs, fmt.Errorf("auth.header or auth.queryParam is required for apiKey auth"))
+		}
+	case "bearer":
+		if c.Auth.Token == "" {
+			errs = append(errs, fmt.Errorf("auth.token is required for bearer auth"))
+		}
+	case "basic":
+		if c.Auth.Username == "" {
+			errs = append(errs, fmt.Errorf("auth.username is required for basic auth"))
+		}
+	case "oauth2":
+		if c.Auth.TokenURL == "" || c.Auth.ClientID == "" || c.Auth.ClientSecret == "" {
+			errs = append(errs, fmt.Errorf("auth.tokenUrl, auth.clientId and auth.clientSecret are required for oauth2 auth"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported auth.type %q", c.Auth.Type))
+	}
+
+	switch c.Pagination.Style {
+	case "none":
+	case "offset":
+		if c.Pagination.LimitParam == "" || c.Pagination.OffsetParam == "" {
+			errs = append(errs, fmt.Errorf("pagination.limitParam and pagination.offsetParam are required for offset pagination"))
+		}
+	case "page":
+		if c.Pagination.PageParam == "" || c.Pagination.LimitParam == "" {
+			errs = append(errs, fmt.Errorf("pagination.pageParam and pagination.limitParam are required for page pagination"))
+		}
+	case "cursor":
+		if c.Pagination.CursorParam == "" || c.Pagination.NextCursor == "" {
+			errs = append(errs, fmt.Errorf("pagination.cursorParam and pagination.nextCursor are required for cursor pagination"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported pagination.style %q", c.Pagination.Style))
+	}
+
+	for name, endpoint := range c.Endpoints {
+		if !knownEndpoints[name] {
+			errs = append(errs, fmt.Errorf("unknown endpoint %q", name))
+			continue
+		}
+		if endpoint.Path == "" {
+			errs = append(errs, fmt.Errorf("endpoints.%s.path is required", name))
+		}
+		switch strings.ToUpper(endpoint.Method) {
+		case "", "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			errs = append(errs, fmt.Errorf("endpoints.%s.method %q is not supported", name, endpoint.Method))
+		}
+	}
+
+	for _, name := range []string{endpointSearchProducts, endpointGetProduct} {
+		if _, ok := c.Endpoints[name]; !ok {
+			errs = append(errs, fmt.Errorf("endpoints.%s is required", name))
+		}
+	}
+	if _, ok := c.Endpoints[endpointSearchProducts]; ok && c.Endpoints[endpointSearchProducts].Items == "" {
+		errs = append(errs, fmt.Errorf("endpoints.%s.items is required", endpointSearchProducts))
+	}
+	if _, ok := c.Endpoints[endpointGetOrders]; ok && c.Endpoints[endpointGetOrders].Items == "" {
+		errs = append(errs, fmt.Errorf("endpoints.%s.items is required", endpointGetOrders))
+	}
+
+	return errs
+}
@@ -0,0 +1,148 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression.
+// The supported subset covers what field mappings need: $, .name, ['name'], [n], [*] and .*
+type jsonPath struct {
+	raw   string
+	steps []pathStep
+	multi bool // true when the path contains a wildcard and always yields a list
+}
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+)
+
+type pathStep struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+// compileJSONPath parses a JSONPath expression
+func compileJSONPath(expr string) (*jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+
+	p := &jsonPath{raw: expr}
+	rest := expr[1:]
+
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return nil, fmt.Errorf("JSONPath %q: recursive descent is not supported", expr)
+
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q: empty field name", expr)
+			}
+			if name == "*" {
+				p.steps = append(p.steps, pathStep{kind: stepWildcard})
+				p.multi = true
+			} else {
+				p.steps = append(p.steps, pathStep{kind: stepKey, key: name})
+			}
+			rest = rest[end:]
+
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("JSONPath %q: unterminated bracket", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, pathStep{kind: stepWildcard})
+				p.multi = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, pathStep{kind: stepKey, key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSONPath %q: unsupported selector [%s]", expr, inner)
+				}
+				p.steps = append(p.steps, pathStep{kind: stepIndex, index: index})
+			}
+
+		default:
+			return nil, fmt.Errorf("JSONPath %q: unexpected %q", expr, rest[:1])
+		}
+	}
+
+	return p, nil
+}
+
+// Search evaluates the path against a decoded JSON document
+func (p *jsonPath) Search(data interface{}) (interface{}, error) {
+	nodes := []interface{}{data}
+
+	for _, step := range p.steps {
+		next := make([]interface{}, 0, len(nodes))
+
+		for _, node := range nodes {
+			switch step.kind {
+			case stepKey:
+				if obj, ok := node.(map[string]interface{}); ok {
+					if value, exists := obj[step.key]; exists {
+						next = append(next, value)
+					}
+				}
+
+			case stepIndex:
+				if list, ok := node.([]interface{}); ok {
+					index := step.index
+					if index < 0 {
+						index += len(list)
+					}
+					if index >= 0 && index < len(list) {
+						next = append(next, list[index])
+					}
+				}
+
+			case stepWildcard:
+				switch v := node.(type) {
+				case []interface{}:
+					next = append(next, v...)
+				case map[string]interface{}:
+					keys := make([]string, 0, len(v))
+					for key := range v {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, v[key])
+					}
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	if p.multi {
+		return nodes, nil
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	return nodes[0], nil
+}
@@ -0,0 +1,365 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+// expression selects a value from a decoded JSON document
+type expression interface {
+	Search(data interface{}) (interface{}, error)
+}
+
+// compileExpression compiles a source expression
+// Expressions starting with $ are JSONPath, anything else is JMESPath
+// (which also covers literals such as 'EUR' or `1`)
+func compileExpression(expr string) (expression, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	if strings.HasPrefix(expr, "$") {
+		return compileJSONPath(expr)
+	}
+	compiled, err := jmespath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("JMESPath %q: %w", expr, err)
+	}
+	return compiled, nil
+}
+
+// targetSegment is one dotted component of a mapping target, e.g. "lineItems[]"
+type targetSegment struct {
+	name  string
+	array bool
+}
+
+// mappingRule maps one source expression onto one target field
+type mappingRule struct {
+	raw     string
+	target  string
+	path    []targetSegment
+	arrayAt int          // index of the [] segment, -1 if none
+	leaf    reflect.Type // nil for free-form (outbound) targets
+	source  expression
+}
+
+// fieldMapping is a compiled list of mapping rules
+type fieldMapping struct {
+	rules []mappingRule
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// compileMapping parses "target <- source" rules
+// When model is set, every target must resolve to a field of the model by its json name
+func compileMapping(name string, rules []string, model reflect.Type) (*fieldMapping, []error) {
+	mapping := &fieldMapping{}
+	var errs []error
+
+	for _, raw := range rules {
+		rule, err := compileRule(raw, model)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("mappings.%s: %w", name, err))
+			continue
+		}
+		mapping.rules = append(mapping.rules, *rule)
+	}
+
+	return mapping, errs
+}
+
+func compileRule(raw string, model reflect.Type) (*mappingRule, error) {
+	parts := strings.SplitN(raw, "<-", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("rule %q must have the form \"target <- source\"", raw)
+	}
+
+	rule := &mappingRule{raw: raw, target: strings.TrimSpace(parts[0]), arrayAt: -1}
+	if rule.target == "" {
+		return nil, fmt.Errorf("rule %q has an empty target", raw)
+	}
+
+	for i, name := range strings.Split(rule.target, ".") {
+		segment := targetSegment{name: name}
+		if strings.HasSuffix(name, "[]") {
+			segment.name = strings.TrimSuffix(name, "[]")
+			segment.array = true
+			if rule.arrayAt >= 0 {
+				return nil, fmt.Errorf("rule %q: only one [] segment is supported", raw)
+			}
+			rule.arrayAt = i
+		}
+		if segment.name == "" {
+			return nil, fmt.Errorf("rule %q: empty target segment", raw)
+		}
+		rule.path = append(rule.path, segment)
+	}
+	if rule.arrayAt == len(rule.path)-1 {
+		return nil, fmt.Errorf("rule %q: a field must follow the [] segment", raw)
+	}
+
+	if model != nil {
+		leaf, err := resolveTarget(model, rule.path)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", raw, err)
+		}
+		rule.leaf = leaf
+	}
+
+	source, err := compileExpression(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", raw, err)
+	}
+	rule.source = source
+
+	return rule, nil
+}
+
+// resolveTarget walks the model type along the target path and returns the leaf type
+func resolveTarget(model reflect.Type, path []targetSegment) (reflect.Type, error) {
+	current := model
+	parent := model.Name()
+
+	for _, segment := range path {
+		for current.Kind() == reflect.Ptr {
+			current = current.Elem()
+		}
+
+		switch {
+		case current.Kind() == reflect.Interface:
+			// Free-form values such as metadata entries accept any nested path
+			return current, nil
+		case current.Kind() == reflect.Struct && current != timeType:
+			field, ok := jsonField(current, segment.name)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q on %s", segment.name, current.Name())
+			}
+			current = field.Type
+		case current.Kind() == reflect.Map:
+			current = current.Elem()
+		default:
+			return nil, fmt.Errorf("%q is not an object", parent)
+		}
+		parent = segment.name
+
+		if segment.array {
+			for current.Kind() == reflect.Ptr {
+				current = current.Elem()
+			}
+			if current.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("%q is not a list", segment.name)
+			}
+			current = current.Elem()
+		}
+	}
+
+	return current, nil
+}
+
+// jsonField finds a struct field by its json tag name
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tagName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// hasTarget reports whether any rule writes the given target
+func (m *fieldMapping) hasTarget(target string) bool {
+	for _, rule := range m.rules {
+		if rule.target == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply evaluates all rules against a decoded JSON document
+func (m *fieldMapping) Apply(doc interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+
+	for _, rule := range m.rules {
+		value, err := rule.source.Search(doc)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %q: %w", rule.raw, err)
+		}
+		if value == nil {
+			continue
+		}
+
+		if rule.arrayAt < 0 {
+			coerced, err := coerce(value, rule.leaf)
+			if err != nil {
+				return nil, fmt.Errorf("mapping %q: %w", rule.raw, err)
+			}
+			setPath(out, rule.path, coerced)
+			continue
+		}
+
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("mapping %q: source must yield a list, got %T", rule.raw, value)
+		}
+		items := ensureList(out, rule.path[:rule.arrayAt+1], len(list))
+		for i, elem := range list {
+			if elem == nil {
+				continue
+			}
+			coerced, err := coerce(elem, rule.leaf)
+			if err != nil {
+				return nil, fmt.Errorf("mapping %q: item %d: %w", rule.raw, i, err)
+			}
+			setPath(items[i].(map[string]interface{}), rule.path[rule.arrayAt+1:], coerced)
+		}
+	}
+
+	return out, nil
+}
+
+// ApplyInto evaluates all rules and decodes the result into target
+func (m *fieldMapping) ApplyInto(doc interface{}, target interface{}) error {
+	mapped, err := m.Apply(doc)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(mapped)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, target)
+}
+
+func setPath(out map[string]interface{}, path []targetSegment, value interface{}) {
+	current := out
+	for _, segment := range path[:len(path)-1] {
+		next, ok := current[segment.name].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[segment.name] = next
+		}
+		current = next
+	}
+	current[path[len(path)-1].name] = value
+}
+
+// ensureList returns the list at path, grown to at least n object elements
+func ensureList(out map[string]interface{}, path []targetSegment, n int) []interface{} {
+	parent := out
+	for _, segment := range path[:len(path)-1] {
+		next, ok := parent[segment.name].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			parent[segment.name] = next
+		}
+		parent = next
+	}
+
+	key := path[len(path)-1].name
+	list, _ := parent[key].([]interface{})
+	for len(list) < n {
+		list = append(list, make(map[string]interface{}))
+	}
+	parent[key] = list
+	return list
+}
+
+// coerce converts a JSON value to the kind expected by the target field
+func coerce(value interface{}, leaf reflect.Type) (interface{}, error) {
+	if leaf == nil {
+		return value, nil
+	}
+	if leaf == timeType {
+		return parseTime(value)
+	}
+
+	switch leaf.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+		return nil, fmt.Errorf("cannot convert %T to string", value)
+
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to number", v)
+			}
+			return f, nil
+		}
+		return nil, fmt.Errorf("cannot convert %T to number", value)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := value.(type) {
+		case float64:
+			return int64(v), nil
+		case string:
+			i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to integer", v)
+			}
+			return i, nil
+		}
+		return nil, fmt.Errorf("cannot convert %T to integer", value)
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("cannot convert %q to boolean", v)
+			}
+			return b, nil
+		case float64:
+			return v != 0, nil
+		}
+		return nil, fmt.Errorf("cannot convert %T to boolean", value)
+	}
+
+	// Objects, lists and interface{} fields are passed through and decoded as JSON
+	return value, nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseTime accepts common timestamp strings and unix seconds
+func parseTime(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot parse %q as time", v)
+	case float64:
+		return time.Unix(int64(v), 0).UTC(), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to time", value)
+}
@@ -0,0 +1,144 @@
+package generic
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeDocument(t *testing.T, raw string) interface{} {
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &doc))
+	return doc
+}
+
+func TestJSONPath_Search(t *testing.T) {
+	doc := decodeDocument(t, `{
+		"store": {"name": "Kungens Kurva", "opening hours": "10-20"},
+		"items": [{"code": "A", "tags": ["x"]}, {"code": "B"}, {"code": "C"}]
+	}`)
+
+	tests := []struct {
+		expr string
+		want interface{}
+	}{
+		{"$", doc},
+		{"$.store.name", "Kungens Kurva"},
+		{"$['store']['opening hours']", "10-20"},
+		{"$.items[1].code", "B"},
+		{"$.items[-1].code", "C"},
+		{"$.items[*].code", []interface{}{"A", "B", "C"}},
+		{"$.items[*].tags[0]", []interface{}{"x"}},
+		{"$.store.*", []interface{}{"Kungens Kurva", "10-20"}},
+		{"$.missing.field", nil},
+		{"$.items[7]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			path, err := compileJSONPath(tt.expr)
+			require.NoError(t, err)
+			got, err := path.Search(doc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONPath_CompileErrors(t *testing.T) {
+	for _, expr := range []string{"store.name", "$..name", "$.items[", "$.items[?(@.code)]", "$."} {
+		_, err := compileJSONPath(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestFieldMapping_Apply(t *testing.T) {
+	mapping, errs := compileMapping("product", []string{
+		"id <- $.code",
+		"name <- title",
+		"price.amount <- $.pricing.current",
+		"price.currency <- 'EUR'",
+		"inventory.quantity <- $.stock",
+		"inventory.available <- to_number(stock) > `0`",
+		"variants[].sku <- $.variants[*].code",
+		"variants[].attributes.size <- variants[*].size",
+		"createdAt <- $.created",
+		"metadata.raw <- $.extra",
+	}, reflect.TypeOf(models.Product{}))
+	require.Empty(t, errs)
+
+	var product models.Product
+	err := mapping.ApplyInto(decodeDocument(t, `{
+		"code": 1001,
+		"title": "LACK",
+		"pricing": {"current": "9.99"},
+		"stock": "4",
+		"variants": [{"code": "1001-W", "size": "S"}, {"code": "1001-B"}],
+		"created": 1767225600,
+		"extra": {"supplier": 7}
+	}`), &product)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1001", product.ID)
+	assert.Equal(t, "LACK", product.Name)
+	assert.Equal(t, models.Price{Amount: 9.99, Currency: "EUR"}, product.Price)
+	assert.Equal(t, &models.InventoryInfo{Available: true, Quantity: 4}, product.Inventory)
+	require.Len(t, product.Variants, 2)
+	assert.Equal(t, "1001-B", product.Variants[1].SKU)
+	assert.Equal(t, map[string]string{"size": "S"}, product.Variants[0].Attributes)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), product.CreatedAt.UTC())
+	assert.Equal(t, map[string]interface{}{"supplier": 7.0}, product.Metadata["raw"])
+}
+
+func TestFieldMapping_Apply_CoercionErrors(t *testing.T) {
+	mapping, errs := compileMapping("product", []string{"price.amount <- $.price"}, reflect.TypeOf(models.Product{}))
+	require.Empty(t, errs)
+
+	_, err := mapping.Apply(decodeDocument(t, `{"price": "n/a"}`))
+	assert.ErrorContains(t, err, `cannot convert "n/a" to number`)
+
+	mapping, errs = compileMapping("product", []string{"variants[].sku <- $.code"}, reflect.TypeOf(models.Product{}))
+	require.Empty(t, errs)
+
+	_, err = mapping.Apply(decodeDocument(t, `{"code": "A"}`))
+	assert.ErrorContains(t, err, "source must yield a list")
+}
+
+func TestCompileMapping_ValidatesTargets(t *testing.T) {
+	_, errs := compileMapping("order", []string{
+		"id <- $.id",
+		"lineItems.sku <- $.sku",
+		"lineItems[].sku[] <- $.sku",
+		"total[].amount <- $.total",
+		"lineItems[] <- $.rows",
+		"shippingAddress.zip <- $.zip",
+		"metadata.anything.nested <- $.x",
+	}, reflect.TypeOf(models.Order{}))
+
+	require.Len(t, errs, 5, "nested metadata keys are free-form")
+	assert.ErrorContains(t, errs[0], `"lineItems" is not an object`)
+	assert.ErrorContains(t, errs[1], "only one [] segment")
+	assert.ErrorContains(t, errs[2], `"total" is not a list`)
+	assert.ErrorContains(t, errs[3], "a field must follow the [] segment")
+	assert.ErrorContains(t, errs[4], `unknown field "zip" on Address`)
+}
+
+func TestCompileMapping_FreeFormTargets(t *testing.T) {
+	mapping, errs := compileMapping("orderRequest", []string{
+		"header.customer <- metadata.customerId",
+		"lines[].item <- lineItems[*].sku",
+	}, nil)
+	require.Empty(t, errs)
+
+	out, err := mapping.Apply(decodeDocument(t, `{"metadata": {"customerId": "c1"}, "lineItems": [{"sku": "A"}, {"sku": "B"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"header": map[string]interface{}{"customer": "c1"},
+		"lines":  []interface{}{map[string]interface{}{"item": "A"}, map[string]interface{}{"item": "B"}},
+	}, out)
+}
@@ -0,0 +1,971 @@
+// Package generic provides a configuration-driven REST adapter so that simple
+// backends can be onboarded by inserting a connector document instead of writing Go code.
+package generic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/adapters/oauth"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// errNotFound is returned by doRequest when the backend responds with 404
+// It is ports.ErrNotFound so that handlers can tell a missing entity from a failing backend
+var errNotFound = ports.ErrNotFound
+
+// placeholderPattern matches {name} placeholders in endpoint paths and query values
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+var knownPlaceholders = map[string]bool{
+	"id": true, "sku": true, "customerId": true, "searchTerm": true, "category": true,
+	"limit": true, "offset": true, "storeId": true, "tenantId": true,
+}
+
+var defaultMethods = map[string]string{
+	endpointCreateOrder:       "POST",
+	endpointUpdateOrderStatus: "PATCH",
+}
+
+// Targets every mapping of the given kind must populate
+var requiredTargets = map[string][]string{
+	mappingProduct: {"id", "name", "price.amount"},
+	mappingOrder:   {"id"},
+}
+
+// compiledEndpoint is an endpoint with its selector expressions compiled
+type compiledEndpoint struct {
+	endpointConfig
+	items  expression
+	result expression
+	total  expression
+}
+
+// GenericRESTAdapter implements IRetailConnector for any JSON REST backend described by
+// endpoints, auth, pagination and field mappings in ConnectorConfig.Config
+type GenericRESTAdapter struct {
+	config         ports.ConnectorConfig
+	baseURL        string
+	rest           *restConfig
+	endpoints      map[string]*compiledEndpoint
+	mappings       map[string]*fieldMapping
+	nextCursor     expression
+	httpClient     *http.Client
+	circuitBreaker *gobreaker.CircuitBreaker
+	tokens         *oauth.ClientCredentials
+}
+
+// NewGenericRESTAdapter creates a new generic REST adapter
+// The declarative configuration is validated by Initialize
+func NewGenericRESTAdapter(config ports.ConnectorConfig) (ports.IConnector, error) {
+	timeout := time.Duration(config.Timeout) * time.Millisecond
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	rest, err := parseRESTConfig(config.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GenericRESTAdapter configuration: %w", err)
+	}
+
+	adapter := &GenericRESTAdapter{
+		config:  config,
+		baseURL: strings.TrimRight(config.URL, "/"),
+		rest:    rest,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+
+	if rest.Auth.Type == "oauth2" {
+		adapter.tokens = oauth.NewClientCredentials(rest.Auth.TokenURL, rest.Auth.ClientID, rest.Auth.ClientSecret, adapter.httpClient)
+		adapter.tokens.Scope = rest.Auth.Scope
+	}
+
+	// Initialize circuit breaker
+	cbSettings := gobreaker.Settings{
+		Name:        fmt.Sprintf("generic-rest-%s-%s", config.StoreID, config.Domain),
+		MaxRequests: 3,
+		Interval:    10 * time.Second,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= 5 && failureRatio >= 0.5
+		},
+		IsSuccessful: func(err error) bool {
+			// A missing product or order is a valid answer, not a backend failure
+			return err == nil || errors.Is(err, errNotFound)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from_state", from.String()).
+				Str("to_state", to.String()).
+				Msg("Generic REST circuit breaker state changed")
+		},
+	}
+	adapter.circuitBreaker = gobreaker.NewCircuitBreaker(cbSettings)
+
+	return adapter, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *GenericRESTAdapter) GetDomain() string {
+	return "retail"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *GenericRESTAdapter) GetAdapterType() string {
+	return "GenericRESTAdapter"
+}
+
+// Initialize validates the endpoints, auth, pagination and mappings and compiles all expressions
+// Every problem found is reported, so integrators can fix a connector document in one pass
+func (a *GenericRESTAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("storeId", a.config.StoreID).
+		Str("url", a.baseURL).
+		Str("auth", a.rest.Auth.Type).
+		Str("pagination", a.rest.Pagination.Style).
+		Msg("Initializing generic REST adapter")
+
+	var errs []error
+	if a.baseURL == "" {
+		errs = append(errs, fmt.Errorf("url is required"))
+	}
+	errs = append(errs, a.rest.validate()...)
+
+	// Endpoints
+	a.endpoints = make(map[string]*compiledEndpoint, len(a.rest.Endpoints))
+	for name, endpoint := range a.rest.Endpoints {
+		compiled := &compiledEndpoint{endpointConfig: endpoint}
+		if compiled.Method == "" {
+			compiled.Method = defaultMethods[name]
+			if compiled.Method == "" {
+				compiled.Method = "GET"
+			}
+		}
+		compiled.Method = strings.ToUpper(compiled.Method)
+
+		templates := []string{endpoint.Path}
+		for _, value := range endpoint.Query {
+			templates = append(templates, value)
+		}
+		for _, template := range templates {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+				if !knownPlaceholders[match[1]] {
+					errs = append(errs, fmt.Errorf("endpoints.%s: unknown placeholder {%s}", name, match[1]))
+				}
+			}
+		}
+
+		var err error
+		for _, selector := range []struct {
+			field string
+			expr  string
+			dest  *expression
+		}{
+			{"items", endpoint.Items, &compiled.items},
+			{"result", endpoint.Result, &compiled.result},
+			{"total", endpoint.Total, &compiled.total},
+		} {
+			if selector.expr == "" {
+				continue
+			}
+			if *selector.dest, err = compileExpression(selector.expr); err != nil {
+				errs = append(errs, fmt.Errorf("endpoints.%s.%s: %w", name, selector.field, err))
+			}
+		}
+
+		a.endpoints[name] = compiled
+	}
+
+	if a.rest.Pagination.NextCursor != "" {
+		var err error
+		if a.nextCursor, err = compileExpression(a.rest.Pagination.NextCursor); err != nil {
+			errs = append(errs, fmt.Errorf("pagination.nextCursor: %w", err))
+		}
+	}
+
+	// Mappings
+	mappingModels := map[string]reflect.Type{
+		mappingProduct:           reflect.TypeOf(models.Product{}),
+		mappingOrder:             reflect.TypeOf(models.Order{}),
+		mappingOrderRequest:      nil,
+		mappingOrderStatusUpdate: nil,
+	}
+	a.mappings = make(map[string]*fieldMapping, len(a.rest.Mappings))
+	for name, rules := range a.rest.Mappings {
+		model, known := mappingModels[name]
+		if !known {
+			errs = append(errs, fmt.Errorf("unknown mapping %q", name))
+			continue
+		}
+		mapping, mappingErrs := compileMapping(name, rules, model)
+		errs = append(errs, mappingErrs...)
+		a.mappings[name] = mapping
+	}
+
+	if _, ok := a.mappings[mappingProduct]; !ok {
+		errs = append(errs, fmt.Errorf("mappings.%s is required", mappingProduct))
+	}
+	if a.hasOrderEndpoints() {
+		if _, ok := a.mappings[mappingOrder]; !ok {
+			errs = append(errs, fmt.Errorf("mappings.%s is required when order endpoints are configured", mappingOrder))
+		}
+	}
+	for name, targets := range requiredTargets {
+		mapping, ok := a.mappings[name]
+		if !ok {
+			continue
+		}
+		for _, target := range targets {
+			if !mapping.hasTarget(target) {
+				errs = append(errs, fmt.Errorf("mappings.%s must map %q", name, target))
+			}
+		}
+	}
+
+	for backendStatus, status := range a.rest.StatusMap {
+		if !isCanonicalStatus(models.OrderStatus(status)) {
+			errs = append(errs, fmt.Errorf("statusMap.%s: %q is not an order status", backendStatus, status))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid GenericRESTAdapter configuration: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// HealthCheck calls the health endpoint, or performs a one-item product search when none is configured
+func (a *GenericRESTAdapter) HealthCheck(ctx context.Context) error {
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		if endpoint, ok := a.endpoints[endpointHealth]; ok {
+			return a.call(ctx, endpoint, a.vars(nil), nil)
+		}
+		vars := a.vars(map[string]string{"limit": "1", "offset": "0"})
+		return a.call(ctx, a.endpoints[endpointSearchProducts], vars, nil)
+	})
+	return err
+}
+
+// Close gracefully shuts down the connector
+func (a *GenericRESTAdapter) Close() error {
+	log.Info().Str("storeId", a.config.StoreID).Msg("Closing generic REST adapter")
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// GetProducts retrieves products based on filters
+// Price and stock filters are applied to the mapped products, since few backends support them natively
+func (a *GenericRESTAdapter) GetProducts(ctx context.Context, filters models.ProductFilters) (*models.ProductList, error) {
+	if len(filters.SKUs) > 0 {
+		return a.getProductsBySKUs(ctx, filters.SKUs)
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	vars := a.vars(map[string]string{
+		"searchTerm": filters.SearchTerm,
+		"category":   filters.Category,
+	})
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		page, err := a.list(ctx, a.endpoints[endpointSearchProducts], vars, limit, filters.Offset)
+		if err != nil {
+			return nil, err
+		}
+
+		products := make([]models.Product, 0, len(page.records))
+		for _, record := range page.records {
+			product, err := a.mapProduct(record)
+			if err != nil {
+				return nil, err
+			}
+			if !matchesFilters(*product, filters) {
+				continue
+			}
+			products = append(products, *product)
+		}
+
+		return &models.ProductList{
+			Products: products,
+			Total:    page.total,
+			Limit:    limit,
+			Offset:   filters.Offset,
+			HasMore:  page.hasMore,
+		}, nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products from REST backend: %w", err)
+	}
+
+	return result.(*models.ProductList), nil
+}
+
+// GetProduct retrieves a single product by ID
+func (a *GenericRESTAdapter) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	product, err := a.getProduct(ctx, endpointGetProduct, map[string]string{"id": productID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product from REST backend: %w", err)
+	}
+	return product, nil
+}
+
+// GetProductBySKU retrieves a product by SKU, falling back to the product endpoint when
+// the backend has no dedicated SKU lookup
+func (a *GenericRESTAdapter) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	if _, ok := a.endpoints[endpointGetProductBySKU]; !ok {
+		return a.GetProduct(ctx, sku)
+	}
+
+	product, err := a.getProduct(ctx, endpointGetProductBySKU, map[string]string{"sku": sku, "id": sku})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product by SKU from REST backend: %w", err)
+	}
+	return product, nil
+}
+
+// CreateOrder submits an order, translating it with the orderRequest mapping when configured
+func (a *GenericRESTAdapter) CreateOrder(ctx context.Context, orderReq models.OrderRequest) (*models.Order, error) {
+	endpoint, err := a.endpoint(endpointCreateOrder)
+	if err != nil {
+		return nil, err
+	}
+	if len(orderReq.LineItems) == 0 {
+		return nil, fmt.Errorf("order must contain at least one line item")
+	}
+
+	customerID, _ := orderReq.Metadata["customerId"].(string)
+
+	canonical, err := toDocument(orderReq)
+	if err != nil {
+		return nil, err
+	}
+	var payload interface{} = canonical
+	if mapping, ok := a.mappings[mappingOrderRequest]; ok {
+		if payload, err = mapping.Apply(canonical); err != nil {
+			return nil, fmt.Errorf("failed to map order request: %w", err)
+		}
+	}
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		doc, err := a.call(ctx, endpoint, a.vars(map[string]string{"customerId": customerID, "idempotencyKey": orderReq.IdempotencyKey()}), payload)
+		if err != nil {
+			return nil, err
+		}
+		return a.mapOrder(endpoint, doc)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order in REST backend: %w", err)
+	}
+
+	order := result.(*models.Order)
+	if order.CustomerID == "" {
+		order.CustomerID = customerID
+	}
+
+	return order, nil
+}
+
+// GetOrder retrieves an order by ID
+func (a *GenericRESTAdapter) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	endpoint, err := a.endpoint(endpointGetOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		doc, err := a.call(ctx, endpoint, a.vars(map[string]string{"id": orderID}), nil)
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("order not found: %s: %w", orderID, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return a.mapOrder(endpoint, doc)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order from REST backend: %w", err)
+	}
+
+	return result.(*models.Order), nil
+}
+
+// GetOrders retrieves orders for a customer
+func (a *GenericRESTAdapter) GetOrders(ctx context.Context, customerID string, limit, offset int) (*models.OrderList, error) {
+	endpoint, err := a.endpoint(endpointGetOrders)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		page, err := a.list(ctx, endpoint, a.vars(map[string]string{"customerId": customerID}), limit, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		orders := make([]models.Order, 0, len(page.records))
+		for _, record := range page.records {
+			order, err := a.mapOrderRecord(record)
+			if err != nil {
+				return nil, err
+			}
+			if order.CustomerID == "" {
+				order.CustomerID = customerID
+			}
+			orders = append(orders, *order)
+		}
+
+		return &models.OrderList{
+			Orders:  orders,
+			Total:   page.total,
+			Limit:   limit,
+			Offset:  offset,
+			HasMore: page.hasMore,
+		}, nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders from REST backend: %w", err)
+	}
+
+	return result.(*models.OrderList), nil
+}
+
+// UpdateOrderStatus updates the order status
+// The canonical status is translated back through statusMap; the request body comes from
+// the orderStatusUpdate mapping (evaluated against {orderId, status, canonicalStatus})
+// or defaults to {"status": <backend status>}
+func (a *GenericRESTAdapter) UpdateOrderStatus(ctx context.Context, orderID string, status models.OrderStatus) error {
+	endpoint, err := a.endpoint(endpointUpdateOrderStatus)
+	if err != nil {
+		return err
+	}
+
+	backendStatus := a.backendStatus(status)
+
+	var payload interface{} = map[string]interface{}{"status": backendStatus}
+	if mapping, ok := a.mappings[mappingOrderStatusUpdate]; ok {
+		payload, err = mapping.Apply(map[string]interface{}{
+			"orderId":         orderID,
+			"status":          backendStatus,
+			"canonicalStatus": string(status),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to map order status update: %w", err)
+		}
+	}
+
+	_, err = a.circuitBreaker.Execute(func() (interface{}, error) {
+		return a.call(ctx, endpoint, a.vars(map[string]string{"id": orderID}), payload)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update order status in REST backend: %w", err)
+	}
+
+	return nil
+}
+
+// Private helper methods
+
+// listPage is one window of records from a list endpoint
+type listPage struct {
+	records []interface{}
+	total   int
+	hasMore bool
+}
+
+// list fetches the records in [offset, offset+limit) using the configured pagination style
+func (a *GenericRESTAdapter) list(ctx context.Context, endpoint *compiledEndpoint, vars map[string]string, limit, offset int) (*listPage, error) {
+	pagination := a.rest.Pagination
+	vars["limit"] = strconv.Itoa(limit)
+	vars["offset"] = strconv.Itoa(offset)
+
+	switch pagination.Style {
+	case "offset", "page":
+		query := url.Values{}
+		query.Set(pagination.LimitParam, strconv.Itoa(limit))
+		if pagination.Style == "offset" {
+			query.Set(pagination.OffsetParam, strconv.Itoa(offset))
+		} else {
+			query.Set(pagination.PageParam, strconv.Itoa(pagination.FirstPage+offset/limit))
+		}
+
+		doc, err := a.call(ctx, endpoint, vars, nil, query)
+		if err != nil {
+			return nil, err
+		}
+		records, total, err := a.selectRecords(endpoint, doc)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > limit {
+			records = records[:limit]
+		}
+
+		page := &listPage{records: records, total: total}
+		if total >= 0 {
+			page.hasMore = offset+len(records) < total
+		} else {
+			page.hasMore = len(records) == limit
+			page.total = offset + len(records)
+		}
+		return page, nil
+
+	case "cursor":
+		var collected []interface{}
+		total := -1
+		cursor := ""
+		exhausted := false
+
+		for pageNum := 0; pageNum < pagination.MaxPages && len(collected) < offset+limit; pageNum++ {
+			query := url.Values{}
+			if pagination.LimitParam != "" {
+				query.Set(pagination.LimitParam, strconv.Itoa(limit))
+			}
+			if cursor != "" {
+				query.Set(pagination.CursorParam, cursor)
+			}
+
+			doc, err := a.call(ctx, endpoint, vars, nil, query)
+			if err != nil {
+				return nil, err
+			}
+			records, pageTotal, err := a.selectRecords(endpoint, doc)
+			if err != nil {
+				return nil, err
+			}
+			collected = append(collected, records...)
+			total = pageTotal
+
+			next, err := a.nextCursor.Search(doc)
+			if err != nil {
+				return nil, fmt.Errorf("pagination.nextCursor: %w", err)
+			}
+			cursor = stringValue(next)
+			if cursor == "" {
+				exhausted = true
+				break
+			}
+		}
+
+		return window(collected, limit, offset, total, !exhausted), nil
+
+	default:
+		doc, err := a.call(ctx, endpoint, vars, nil)
+		if err != nil {
+			return nil, err
+		}
+		records, total, err := a.selectRecords(endpoint, doc)
+		if err != nil {
+			return nil, err
+		}
+		return window(records, limit, offset, total, false), nil
+	}
+}
+
+// window slices records fetched from the start of a list to [offset, offset+limit)
+func window(records []interface{}, limit, offset, total int, more bool) *listPage {
+	page := &listPage{}
+	if offset < len(records) {
+		end := offset + limit
+		if end > len(records) {
+			end = len(records)
+		}
+		page.records = records[offset:end]
+	}
+
+	if total < 0 {
+		total = len(records)
+	}
+	page.total = total
+	page.hasMore = more || offset+len(page.records) < len(records) || offset+len(page.records) < total
+	return page
+}
+
+// selectRecords applies the endpoint's items and total expressions; total is -1 when unknown
+func (a *GenericRESTAdapter) selectRecords(endpoint *compiledEndpoint, doc interface{}) ([]interface{}, int, error) {
+	value, err := endpoint.items.Search(doc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("items: %w", err)
+	}
+	records, ok := value.([]interface{})
+	if value != nil && !ok {
+		return nil, 0, fmt.Errorf("items must select a list, got %T", value)
+	}
+
+	total := -1
+	if endpoint.total != nil {
+		value, err := endpoint.total.Search(doc)
+		if err != nil {
+			return nil, 0, fmt.Errorf("total: %w", err)
+		}
+		if number, err := coerce(value, reflect.TypeOf(0)); err == nil && value != nil {
+			total = int(number.(int64))
+		}
+	}
+
+	return records, total, nil
+}
+
+// selectResult applies the endpoint's result expression, if any
+func (a *GenericRESTAdapter) selectResult(endpoint *compiledEndpoint, doc interface{}) (interface{}, error) {
+	if endpoint.result == nil {
+		return doc, nil
+	}
+	value, err := endpoint.result.Search(doc)
+	if err != nil {
+		return nil, fmt.Errorf("result: %w", err)
+	}
+	return value, nil
+}
+
+func (a *GenericRESTAdapter) getProduct(ctx context.Context, name string, vars map[string]string) (*models.Product, error) {
+	endpoint := a.endpoints[name]
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		doc, err := a.call(ctx, endpoint, a.vars(vars), nil)
+		if err == nil {
+			doc, err = a.selectResult(endpoint, doc)
+			if err == nil && doc == nil {
+				err = errNotFound
+			}
+		}
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("product not found: %s: %w", vars["id"], err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return a.mapProduct(doc)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*models.Product), nil
+}
+
+func (a *GenericRESTAdapter) getProductsBySKUs(ctx context.Context, skus []string) (*models.ProductList, error) {
+	products := make([]models.Product, 0, len(skus))
+
+	for _, sku := range skus {
+		product, err := a.GetProductBySKU(ctx, sku)
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	return &models.ProductList{
+		Products: products,
+		Total:    len(products),
+		Limit:    len(skus),
+		Offset:   0,
+		HasMore:  false,
+	}, nil
+}
+
+func (a *GenericRESTAdapter) mapProduct(record interface{}) (*models.Product, error) {
+	var product models.Product
+	if err := a.mappings[mappingProduct].ApplyInto(record, &product); err != nil {
+		return nil, err
+	}
+	if product.SKU == "" {
+		product.SKU = product.ID
+	}
+	if product.Inventory != nil && product.Inventory.StoreID == "" {
+		product.Inventory.StoreID = a.config.StoreID
+	}
+	return &product, nil
+}
+
+func (a *GenericRESTAdapter) mapOrder(endpoint *compiledEndpoint, doc interface{}) (*models.Order, error) {
+	record, err := a.selectResult(endpoint, doc)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errNotFound
+	}
+	return a.mapOrderRecord(record)
+}
+
+func (a *GenericRESTAdapter) mapOrderRecord(record interface{}) (*models.Order, error) {
+	var order models.Order
+	if err := a.mappings[mappingOrder].ApplyInto(record, &order); err != nil {
+		return nil, err
+	}
+	if order.OrderNumber == "" {
+		order.OrderNumber = order.ID
+	}
+	if order.StoreID == "" {
+		order.StoreID = a.config.StoreID
+	}
+	order.Status = a.mapOrderStatus(string(order.Status))
+	return &order, nil
+}
+
+// mapOrderStatus maps a backend status through statusMap
+// Canonical statuses pass through; anything unknown is treated as pending
+func (a *GenericRESTAdapter) mapOrderStatus(backendStatus string) models.OrderStatus {
+	if status, ok := a.rest.StatusMap[backendStatus]; ok {
+		return models.OrderStatus(status)
+	}
+	if isCanonicalStatus(models.OrderStatus(strings.ToLower(backendStatus))) {
+		return models.OrderStatus(strings.ToLower(backendStatus))
+	}
+	return models.OrderStatusPending
+}
+
+// backendStatus finds the backend status for a canonical status, preferring the
+// alphabetically first when several backend statuses map to it
+func (a *GenericRESTAdapter) backendStatus(status models.OrderStatus) string {
+	keys := make([]string, 0, len(a.rest.StatusMap))
+	for backendStatus, canonical := range a.rest.StatusMap {
+		if models.OrderStatus(canonical) == status {
+			keys = append(keys, backendStatus)
+		}
+	}
+	if len(keys) == 0 {
+		return string(status)
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+func (a *GenericRESTAdapter) hasOrderEndpoints() bool {
+	for _, name := range []string{endpointCreateOrder, endpointGetOrder, endpointGetOrders, endpointUpdateOrderStatus} {
+		if _, ok := a.rest.Endpoints[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *GenericRESTAdapter) endpoint(name string) (*compiledEndpoint, error) {
+	endpoint, ok := a.endpoints[name]
+	if !ok {
+		return nil, fmt.Errorf("GenericRESTAdapter for store %s has no %s endpoint configured", a.config.StoreID, name)
+	}
+	return endpoint, nil
+}
+
+// vars returns placeholder values for a request, including the connector's store and tenant
+func (a *GenericRESTAdapter) vars(values map[string]string) map[string]string {
+	vars := map[string]string{
+		"storeId":  a.config.StoreID,
+		"tenantId": a.config.TenantID,
+	}
+	for key, value := range values {
+		vars[key] = value
+	}
+	return vars
+}
+
+// call expands the endpoint templates and performs the request
+// Extra query values (pagination) are merged over the endpoint's configured query. An
+// idempotencyKey variable is also sent as the Idempotency-Key header.
+func (a *GenericRESTAdapter) call(ctx context.Context, endpoint *compiledEndpoint, vars map[string]string, body interface{}, extra ...url.Values) (interface{}, error) {
+	path := placeholderPattern.ReplaceAllStringFunc(endpoint.Path, func(match string) string {
+		return url.PathEscape(vars[match[1:len(match)-1]])
+	})
+
+	query := url.Values{}
+	for key, template := range endpoint.Query {
+		value := placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+			return vars[match[1:len(match)-1]]
+		})
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	for _, values := range extra {
+		for key := range values {
+			query.Set(key, values.Get(key))
+		}
+	}
+
+	header := http.Header{}
+	if key := vars["idempotencyKey"]; key != "" {
+		header.Set("Idempotency-Key", key)
+	}
+
+	return a.doRequest(ctx, endpoint.Method, path, query, header, body)
+}
+
+// doRequest performs an authenticated request and decodes the JSON response
+// With oauth2 auth a 401 response invalidates the cached token and retries the request once
+func (a *GenericRESTAdapter) doRequest(ctx context.Context, method, path string, query url.Values, header http.Header, body interface{}) (interface{}, error) {
+	auth := a.rest.Auth
+	if auth.Type == "apiKey" && auth.QueryParam != "" {
+		query.Set(auth.QueryParam, auth.Key)
+	}
+
+	endpoint := a.baseURL + "/" + strings.TrimLeft(path, "/")
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("Accept", "application/json")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		switch auth.Type {
+		case "apiKey":
+			if auth.Header != "" {
+				req.Header.Set(auth.Header, auth.Key)
+			}
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		case "basic":
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case "oauth2":
+			token, err := a.tokens.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get OAuth token: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && auth.Type == "oauth2" && attempt == 0 {
+			resp.Body.Close()
+			a.tokens.Invalidate()
+			continue
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, errNotFound
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("REST backend error: status=%d, body=%s", resp.StatusCode, string(respBody))
+		}
+
+		if len(bytes.TrimSpace(respBody)) == 0 {
+			return nil, nil
+		}
+
+		var doc interface{}
+		if err := json.Unmarshal(respBody, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode REST backend response: %w", err)
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("REST backend error: unauthorized")
+}
+
+// toDocument converts a canonical model into a generic JSON document for mapping
+func toDocument(value interface{}) (interface{}, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func matchesFilters(product models.Product, filters models.ProductFilters) bool {
+	if filters.MinPrice != nil && product.Price.Amount < *filters.MinPrice {
+		return false
+	}
+	if filters.MaxPrice != nil && product.Price.Amount > *filters.MaxPrice {
+		return false
+	}
+	if filters.InStock != nil && *filters.InStock && (product.Inventory == nil || !product.Inventory.Available) {
+		return false
+	}
+	return true
+}
+
+func isCanonicalStatus(status models.OrderStatus) bool {
+	switch status {
+	case models.OrderStatusPending, models.OrderStatusProcessing, models.OrderStatusPaid,
+		models.OrderStatusShipped, models.OrderStatusDelivered, models.OrderStatusCancelled,
+		models.OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
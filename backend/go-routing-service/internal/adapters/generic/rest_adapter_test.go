@@ -0,0 +1,327 @@
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// posStandIn replays responses of a small regional POS REST API from testdata/pos
+type posStandIn struct {
+	t *testing.T
+
+	mu          sync.Mutex
+	lastQuery   map[string]string
+	saleBody    map[string]interface{}
+	saleKey     string
+	stateUpdate map[string]interface{}
+}
+
+func (s *posStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Api-Key") != "pos-test-key" {
+		http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	s.lastQuery = map[string]string{}
+	for key := range r.URL.Query() {
+		s.lastQuery[key] = r.URL.Query().Get(key)
+	}
+	s.mu.Unlock()
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /v1/health":
+		w.Write([]byte(`{"status":"ok"}`))
+	case "GET /v1/articles":
+		switch r.URL.Query().Get("ean") {
+		case "":
+			s.replay(w, http.StatusOK, "articles_search.json")
+		case "7318580134012":
+			s.replay(w, http.StatusOK, "articles_by_ean.json")
+		default:
+			w.Write([]byte(`{"data":[],"meta":{"total":0}}`))
+		}
+	case "GET /v1/articles/40263848":
+		s.replay(w, http.StatusOK, "article_40263848.json")
+	case "POST /v1/sales":
+		s.mu.Lock()
+		json.NewDecoder(r.Body).Decode(&s.saleBody)
+		s.saleKey = r.Header.Get("Idempotency-Key")
+		s.mu.Unlock()
+		s.replay(w, http.StatusCreated, "sale_S-1001.json")
+	case "GET /v1/sales/S-1001":
+		s.replay(w, http.StatusOK, "sale_S-1001.json")
+	case "GET /v1/customers/cust-42/sales":
+		s.replay(w, http.StatusOK, "customer_sales.json")
+	case "PUT /v1/sales/S-1001/state":
+		s.mu.Lock()
+		json.NewDecoder(r.Body).Decode(&s.stateUpdate)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	}
+}
+
+func (s *posStandIn) replay(w http.ResponseWriter, status int, fixture string) {
+	body, err := os.ReadFile(filepath.Join("testdata", "pos", fixture))
+	require.NoError(s.t, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// loadConnectorDocument reads the connector config an integrator would insert into MongoDB
+func loadConnectorDocument(t *testing.T) map[string]interface{} {
+	body, err := os.ReadFile(filepath.Join("testdata", "pos", "connector.json"))
+	require.NoError(t, err)
+
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &config))
+	return config
+}
+
+func newTestAdapter(t *testing.T, config map[string]interface{}) (*GenericRESTAdapter, *posStandIn, ports.ConnectorConfig) {
+	standIn := &posStandIn{t: t}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	connectorConfig := ports.ConnectorConfig{
+		TenantID: "ikea-nordics",
+		StoreID:  "ikea-kungens-kurva",
+		Domain:   "retail",
+		URL:      server.URL,
+		Adapter:  "GenericRESTAdapter",
+		Config:   config,
+		Enabled:  true,
+		Timeout:  5000,
+	}
+
+	connector, err := NewGenericRESTAdapter(connectorConfig)
+	require.NoError(t, err)
+
+	return connector.(*GenericRESTAdapter), standIn, connectorConfig
+}
+
+func newInitializedAdapter(t *testing.T) (*GenericRESTAdapter, *posStandIn) {
+	adapter, standIn, config := newTestAdapter(t, loadConnectorDocument(t))
+	require.NoError(t, adapter.Initialize(context.Background(), config))
+	return adapter, standIn
+}
+
+func TestGenericRESTAdapter_RetailConformance(t *testing.T) {
+	adapter, _, config := newTestAdapter(t, loadConnectorDocument(t))
+
+	conformance.RunRetailConnectorTests(t, adapter, conformance.RetailFixture{
+		Config:           config,
+		ProductID:        "40263848",
+		SKU:              "7318580134012",
+		MissingProductID: "99999999",
+		SearchTerm:       "billy",
+		CustomerID:       "cust-42",
+		OrderRequest: models.OrderRequest{
+			StoreID: "ikea-kungens-kurva",
+			LineItems: []models.OrderLineItem{
+				{ProductID: "40263848", SKU: "7318580134012", Quantity: 2},
+			},
+			Metadata: map[string]interface{}{"customerId": "cust-42"},
+		},
+	})
+}
+
+func TestGenericRESTAdapter_GetProduct_AppliesMappings(t *testing.T) {
+	adapter, _ := newInitializedAdapter(t)
+
+	product, err := adapter.GetProduct(context.Background(), "40263848")
+	require.NoError(t, err)
+
+	assert.Equal(t, "7318580134012", product.SKU)
+	assert.Equal(t, "bookcases", product.Category)
+	assert.Equal(t, 699.0, product.Price.Amount, "string prices are coerced to numbers")
+	assert.Equal(t, "SEK", product.Price.Currency)
+	require.NotNil(t, product.Price.CompareAtPrice)
+	assert.Equal(t, 749.0, *product.Price.CompareAtPrice)
+
+	require.NotNil(t, product.Inventory)
+	assert.True(t, product.Inventory.Available)
+	assert.Equal(t, 7, product.Inventory.Quantity)
+	assert.Equal(t, "ikea-kungens-kurva", product.Inventory.StoreID)
+
+	require.Len(t, product.Images, 2)
+	assert.True(t, product.Images[0].IsPrimary)
+	assert.False(t, product.Images[1].IsPrimary)
+	assert.Equal(t, "7318580134012", product.Metadata["ean"])
+	assert.Equal(t, 2026, product.UpdatedAt.Year())
+}
+
+func TestGenericRESTAdapter_GetProducts_PagePagination(t *testing.T) {
+	adapter, standIn := newInitializedAdapter(t)
+
+	inStock := true
+	list, err := adapter.GetProducts(context.Background(), models.ProductFilters{
+		SearchTerm: "billy",
+		Limit:      5,
+		Offset:     10,
+		InStock:    &inStock,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"q":        "billy",
+		"shop":     "ikea-kungens-kurva",
+		"page":     "3",
+		"per_page": "5",
+	}, standIn.lastQuery, "empty placeholders are omitted and pages start at firstPage")
+
+	require.Len(t, list.Products, 1, "out of stock products are filtered")
+	assert.Equal(t, "40263848", list.Products[0].ID)
+	assert.Equal(t, 2, list.Total)
+}
+
+func TestGenericRESTAdapter_GetProducts_CursorPagination(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("after")
+		requests = append(requests, cursor)
+		switch cursor {
+		case "":
+			w.Write([]byte(`{"items":[{"sku":"A","name":"A","price":1},{"sku":"B","name":"B","price":2}],"next":"c2"}`))
+		case "c2":
+			w.Write([]byte(`{"items":[{"sku":"C","name":"C","price":3}],"next":null}`))
+		}
+	}))
+	defer server.Close()
+
+	config := ports.ConnectorConfig{
+		StoreID: "store-1",
+		URL:     server.URL,
+		Config: map[string]interface{}{
+			"pagination": map[string]interface{}{"style": "cursor", "cursorParam": "after", "nextCursor": "next"},
+			"endpoints": map[string]interface{}{
+				"searchProducts": map[string]interface{}{"path": "/items", "items": "items"},
+				"getProduct":     map[string]interface{}{"path": "/items/{id}"},
+			},
+			"mappings": map[string]interface{}{
+				"product": []interface{}{"id <- sku", "name <- name", "price.amount <- price", "price.currency <- 'NOK'"},
+			},
+		},
+	}
+	connector, err := NewGenericRESTAdapter(config)
+	require.NoError(t, err)
+	require.NoError(t, connector.Initialize(context.Background(), config))
+
+	list, err := connector.(*GenericRESTAdapter).GetProducts(context.Background(), models.ProductFilters{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "c2"}, requests)
+	require.Len(t, list.Products, 2)
+	assert.Equal(t, "B", list.Products[0].ID)
+	assert.Equal(t, "C", list.Products[1].SKU, "sku defaults to id")
+	assert.Equal(t, "NOK", list.Products[1].Price.Currency)
+	assert.Equal(t, 3, list.Total)
+	assert.False(t, list.HasMore)
+}
+
+func TestGenericRESTAdapter_CreateOrder_MapsRequestAndResponse(t *testing.T) {
+	adapter, standIn := newInitializedAdapter(t)
+
+	order, err := adapter.CreateOrder(context.Background(), models.OrderRequest{
+		StoreID:   "ikea-kungens-kurva",
+		LineItems: []models.OrderLineItem{{ProductID: "40263848", Quantity: 2}},
+		Metadata:  map[string]interface{}{"customerId": "cust-42", "idempotencyKey": "session-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", standIn.saleKey, "the idempotency key is sent as a header")
+
+	assert.Equal(t, map[string]interface{}{
+		"customer": "cust-42",
+		"shop":     "ikea-kungens-kurva",
+		"channel":  "amicis",
+		"rows": []interface{}{
+			map[string]interface{}{"articleNo": "40263848", "qty": 2.0},
+		},
+	}, standIn.saleBody)
+
+	assert.Equal(t, "S-1001", order.ID)
+	assert.Equal(t, "R-2026-000417", order.OrderNumber)
+	assert.Equal(t, models.OrderStatusPending, order.Status)
+	assert.Equal(t, "ikea-kungens-kurva", order.StoreID)
+	require.Len(t, order.LineItems, 1)
+	assert.Equal(t, 2, order.LineItems[0].Quantity)
+	assert.Equal(t, 1398.0, order.Total.Amount)
+	assert.Equal(t, 279.6, order.Tax.Amount)
+	assert.Equal(t, 9, order.CreatedAt.Hour())
+}
+
+func TestGenericRESTAdapter_GetOrders_MapsStatuses(t *testing.T) {
+	adapter, _ := newInitializedAdapter(t)
+
+	list, err := adapter.GetOrders(context.Background(), "cust-42", 10, 0)
+	require.NoError(t, err)
+
+	require.Len(t, list.Orders, 2)
+	assert.Equal(t, models.OrderStatusPending, list.Orders[0].Status)
+	assert.Equal(t, models.OrderStatusDelivered, list.Orders[1].Status)
+	assert.False(t, list.HasMore)
+}
+
+func TestGenericRESTAdapter_UpdateOrderStatus_UsesStatusMap(t *testing.T) {
+	adapter, standIn := newInitializedAdapter(t)
+
+	require.NoError(t, adapter.UpdateOrderStatus(context.Background(), "S-1001", models.OrderStatusCancelled))
+	assert.Equal(t, map[string]interface{}{"state": "VOID"}, standIn.stateUpdate)
+}
+
+func TestGenericRESTAdapter_Initialize_ReportsAllProblems(t *testing.T) {
+	config := loadConnectorDocument(t)
+	config["auth"] = map[string]interface{}{"type": "apiKey", "header": "X-Api-Key"}
+	config["statusMap"] = map[string]interface{}{"OPEN": "open"}
+
+	mappings := config["mappings"].(map[string]interface{})
+	mappings["product"] = []interface{}{
+		"id <- $.articleNo",
+		"title <- $.title",
+		"price.amount $.pricing.current",
+		"category <- group.[",
+	}
+	endpoints := config["endpoints"].(map[string]interface{})
+	endpoints["getProduct"] = map[string]interface{}{"path": "/v1/articles/{articleId}"}
+
+	adapter, _, connectorConfig := newTestAdapter(t, config)
+	err := adapter.Initialize(context.Background(), connectorConfig)
+	require.Error(t, err)
+
+	for _, problem := range []string{
+		"auth.key is required",
+		`unknown field "title" on Product`,
+		`must have the form "target <- source"`,
+		"JMESPath",
+		`endpoints.getProduct: unknown placeholder {articleId}`,
+		`mappings.product must map "name"`,
+		`mappings.product must map "price.amount"`,
+		`statusMap.OPEN: "open" is not an order status`,
+	} {
+		assert.ErrorContains(t, err, problem)
+	}
+}
+
+func TestGenericRESTAdapter_Initialize_RequiresOrderMappingForOrderEndpoints(t *testing.T) {
+	config := loadConnectorDocument(t)
+	delete(config["mappings"].(map[string]interface{}), "order")
+
+	adapter, _, connectorConfig := newTestAdapter(t, config)
+	err := adapter.Initialize(context.Background(), connectorConfig)
+	assert.ErrorContains(t, err, "mappings.order is required")
+}
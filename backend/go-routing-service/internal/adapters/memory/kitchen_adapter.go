@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// InMemoryKitchenAdapter implements IKitchenConnector on a KitchenStore
+// Tickets are scoped to the connector's tenant and store
+type InMemoryKitchenAdapter struct {
+	config ports.ConnectorConfig
+	store  *KitchenStore
+}
+
+// NewInMemoryKitchenAdapter creates a new in-memory kitchen adapter
+func NewInMemoryKitchenAdapter(config ports.ConnectorConfig, store *KitchenStore) (ports.IConnector, error) {
+	if store == nil {
+		return nil, fmt.Errorf("kitchen store is required for in-memory kitchen adapter")
+	}
+
+	return &InMemoryKitchenAdapter{
+		config: config,
+		store:  store,
+	}, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *InMemoryKitchenAdapter) GetDomain() string {
+	return "kitchen"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *InMemoryKitchenAdapter) GetAdapterType() string {
+	return "InMemoryKitchenAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *InMemoryKitchenAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("tenantId", config.TenantID).
+		Str("storeId", config.StoreID).
+		Msg("Initializing in-memory kitchen adapter")
+
+	if a.config.TenantID == "" || a.config.StoreID == "" {
+		return fmt.Errorf("tenantId and storeId are required for in-memory kitchen adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck always succeeds; there is no backend to reach
+func (a *InMemoryKitchenAdapter) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// Close gracefully shuts down the connector
+// Tickets stay in the shared store
+func (a *InMemoryKitchenAdapter) Close() error {
+	return nil
+}
+
+// SubmitOrder creates a kitchen ticket; resubmitting an order is a no-op
+func (a *InMemoryKitchenAdapter) SubmitOrder(ctx context.Context, orderID string, items []models.OrderLineItem) error {
+	if orderID == "" {
+		return fmt.Errorf("orderId is required")
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("kitchen order %s has no items", orderID)
+	}
+
+	a.store.Submit(a.config.TenantID, a.config.StoreID, orderID, items)
+	return nil
+}
+
+// GetOrderStatus retrieves the kitchen status of an order
+func (a *InMemoryKitchenAdapter) GetOrderStatus(ctx context.Context, orderID string) (string, error) {
+	ticket, err := a.store.Get(a.config.TenantID, a.config.StoreID, orderID)
+	if err != nil {
+		return "", err
+	}
+	return ticket.Status, nil
+}
+
+// UpdateOrderStatus updates the kitchen status
+func (a *InMemoryKitchenAdapter) UpdateOrderStatus(ctx context.Context, orderID string, status string) error {
+	if !models.IsValidKitchenStatus(status) {
+		return fmt.Errorf("unknown kitchen status %q", status)
+	}
+
+	_, err := a.store.UpdateStatus(a.config.TenantID, a.config.StoreID, orderID, status)
+	return err
+}
+
+// GetActiveOrders retrieves the IDs of all active orders in the kitchen, oldest first
+func (a *InMemoryKitchenAdapter) GetActiveOrders(ctx context.Context) ([]string, error) {
+	tickets := a.store.Active(a.config.TenantID, a.config.StoreID)
+
+	orderIDs := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		orderIDs = append(orderIDs, ticket.OrderID)
+	}
+	return orderIDs, nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKitchenAdapter(t *testing.T, store *KitchenStore, storeID string) ports.IKitchenConnector {
+	config := ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  storeID,
+		Domain:   "kitchen",
+		Adapter:  "InMemoryKitchenAdapter",
+		Enabled:  true,
+	}
+
+	connector, err := NewInMemoryKitchenAdapter(config, store)
+	require.NoError(t, err)
+	require.NoError(t, connector.Initialize(context.Background(), config))
+
+	return connector.(ports.IKitchenConnector)
+}
+
+func TestInMemoryKitchenAdapter_Conformance(t *testing.T) {
+	config := ports.ConnectorConfig{TenantID: "ikea", StoreID: "ikea-seattle", Domain: "kitchen"}
+	connector, err := NewInMemoryKitchenAdapter(config, NewKitchenStore())
+	require.NoError(t, err)
+
+	conformance.RunKitchenConnectorTests(t, connector.(ports.IKitchenConnector), conformance.KitchenFixture{
+		Config: config,
+	})
+}
+
+func TestInMemoryKitchenAdapter_ScopedToStore(t *testing.T) {
+	store := NewKitchenStore()
+	seattle := newTestKitchenAdapter(t, store, "ikea-seattle")
+	tacoma := newTestKitchenAdapter(t, store, "ikea-tacoma")
+	ctx := context.Background()
+
+	items := []models.OrderLineItem{{SKU: "HOTDOG", Quantity: 1, Category: "bistro"}}
+	require.NoError(t, seattle.SubmitOrder(ctx, "ORD-1", items))
+
+	_, err := tacoma.GetOrderStatus(ctx, "ORD-1")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	active, err := tacoma.GetActiveOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	// A new adapter instance on the same store sees existing tickets
+	again := newTestKitchenAdapter(t, store, "ikea-seattle")
+	status, err := again.GetOrderStatus(ctx, "ORD-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.KitchenStatusReceived, status)
+}
@@ -0,0 +1,135 @@
+// Package memory provides connectors that keep their state in process memory.
+// They suit pilots and local development; state is lost when the service restarts.
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// finishedTicketRetention is how long served and cancelled tickets are kept
+const finishedTicketRetention = 24 * time.Hour
+
+// KitchenStore holds kitchen tickets for any number of tenants and stores
+// It is shared by all InMemoryKitchenAdapter instances so tickets survive connector cache eviction
+type KitchenStore struct {
+	mu      sync.RWMutex
+	tickets map[string]*models.KitchenTicket // key: "tenantId:storeId:orderId"
+	now     func() time.Time
+}
+
+// NewKitchenStore creates an empty kitchen store
+func NewKitchenStore() *KitchenStore {
+	return &KitchenStore{
+		tickets: make(map[string]*models.KitchenTicket),
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Submit creates a ticket in the received status
+// Submitting an order that already has a ticket returns the existing ticket and created=false,
+// so a retried submission is never prepared twice
+func (s *KitchenStore) Submit(tenantID, storeID, orderID string, items []models.OrderLineItem) (ticket models.KitchenTicket, created bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := ticketKey(tenantID, storeID, orderID)
+	if existing, ok := s.tickets[key]; ok {
+		return copyTicket(existing), false
+	}
+
+	s.pruneLocked()
+
+	now := s.now()
+	t := &models.KitchenTicket{
+		OrderID:   orderID,
+		StoreID:   storeID,
+		Status:    models.KitchenStatusReceived,
+		Items:     append([]models.OrderLineItem(nil), items...),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.tickets[key] = t
+
+	return copyTicket(t), true
+}
+
+// Get retrieves a ticket
+func (s *KitchenStore) Get(tenantID, storeID, orderID string) (models.KitchenTicket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.tickets[ticketKey(tenantID, storeID, orderID)]
+	if !ok {
+		return models.KitchenTicket{}, fmt.Errorf("kitchen order %s: %w", orderID, ports.ErrNotFound)
+	}
+	return copyTicket(t), nil
+}
+
+// UpdateStatus moves a ticket to a new status
+func (s *KitchenStore) UpdateStatus(tenantID, storeID, orderID, status string) (models.KitchenTicket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[ticketKey(tenantID, storeID, orderID)]
+	if !ok {
+		return models.KitchenTicket{}, fmt.Errorf("kitchen order %s: %w", orderID, ports.ErrNotFound)
+	}
+	if !models.CanTransitionKitchenStatus(t.Status, status) {
+		return models.KitchenTicket{}, fmt.Errorf("kitchen order %s cannot move from %s to %s: %w", orderID, t.Status, status, ports.ErrInvalidTransition)
+	}
+
+	if t.Status != status {
+		t.Status = status
+		t.UpdatedAt = s.now()
+	}
+	return copyTicket(t), nil
+}
+
+// Active returns the active tickets of a store, oldest first
+func (s *KitchenStore) Active(tenantID, storeID string) []models.KitchenTicket {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefix := tenantID + ":" + storeID + ":"
+	tickets := []models.KitchenTicket{}
+	for key, t := range s.tickets {
+		if strings.HasPrefix(key, prefix) && models.IsActiveKitchenStatus(t.Status) {
+			tickets = append(tickets, copyTicket(t))
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		if tickets[i].CreatedAt.Equal(tickets[j].CreatedAt) {
+			return tickets[i].OrderID < tickets[j].OrderID
+		}
+		return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+	})
+	return tickets
+}
+
+// pruneLocked drops finished tickets older than the retention period
+func (s *KitchenStore) pruneLocked() {
+	cutoff := s.now().Add(-finishedTicketRetention)
+	for key, t := range s.tickets {
+		if !models.IsActiveKitchenStatus(t.Status) && t.UpdatedAt.Before(cutoff) {
+			delete(s.tickets, key)
+		}
+	}
+}
+
+func ticketKey(tenantID, storeID, orderID string) string {
+	return fmt.Sprintf("%s:%s:%s", tenantID, storeID, orderID)
+}
+
+func copyTicket(t *models.KitchenTicket) models.KitchenTicket {
+	c := *t
+	c.Items = append([]models.OrderLineItem(nil), t.Items...)
+	return c
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+)
+
+// FakePaymentAdapter implements IPaymentConnector on a PaymentStore for demos and local development
+// No money moves: every payment token is authorized except DeclinedPaymentToken.
+// Payments are scoped to the connector's tenant and store.
+type FakePaymentAdapter struct {
+	config ports.ConnectorConfig
+	store  *PaymentStore
+}
+
+// fakeWebhook is the body of a fake payment notification
+type fakeWebhook struct {
+	PaymentID string `json:"paymentId"`
+}
+
+// NewFakePaymentAdapter creates a new fake payment adapter
+func NewFakePaymentAdapter(config ports.ConnectorConfig, store *PaymentStore) (ports.IConnector, error) {
+	if store == nil {
+		return nil, fmt.Errorf("payment store is required for fake payment adapter")
+	}
+
+	return &FakePaymentAdapter{
+		config: config,
+		store:  store,
+	}, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *FakePaymentAdapter) GetDomain() string {
+	return "payment"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *FakePaymentAdapter) GetAdapterType() string {
+	return "FakePaymentAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *FakePaymentAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("tenantId", config.TenantID).
+		Str("storeId", config.StoreID).
+		Msg("Initializing fake payment adapter")
+
+	if a.config.TenantID == "" || a.config.StoreID == "" {
+		return fmt.Errorf("tenantId and storeId are required for fake payment adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck always succeeds; there is no backend to reach
+func (a *FakePaymentAdapter) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// Close gracefully shuts down the connector
+// Payments stay in the shared store
+func (a *FakePaymentAdapter) Close() error {
+	return nil
+}
+
+// Authorize reserves an amount; DeclinedPaymentToken is declined
+func (a *FakePaymentAdapter) Authorize(ctx context.Context, req models.PaymentRequest) (*models.Payment, error) {
+	payment, err := a.store.Authorize(a.config.TenantID, a.config.StoreID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Capture collects up to the authorized amount
+func (a *FakePaymentAdapter) Capture(ctx context.Context, paymentID string, amount float64) (*models.Payment, error) {
+	payment, err := a.store.Capture(a.config.TenantID, a.config.StoreID, paymentID, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Void releases an authorization that was not captured
+func (a *FakePaymentAdapter) Void(ctx context.Context, paymentID string) (*models.Payment, error) {
+	payment, err := a.store.Void(a.config.TenantID, a.config.StoreID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Refund returns part or all of the captured amount
+func (a *FakePaymentAdapter) Refund(ctx context.Context, paymentID string, amount float64) (*models.Payment, error) {
+	payment, err := a.store.Refund(a.config.TenantID, a.config.StoreID, paymentID, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetPayment retrieves a payment and its current status
+func (a *FakePaymentAdapter) GetPayment(ctx context.Context, paymentID string) (*models.Payment, error) {
+	payment, err := a.store.Get(a.config.TenantID, a.config.StoreID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// ParseWebhook decodes a fake notification, {"paymentId": "..."}, for replaying webhooks in demos
+// The payment is reported as the store holds it, so a notification cannot fake a payment's status
+// and needs no signature
+func (a *FakePaymentAdapter) ParseWebhook(ctx context.Context, header http.Header, body []byte) (*models.PaymentEvent, error) {
+	var webhook fakeWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil || webhook.PaymentID == "" {
+		return nil, fmt.Errorf("fake payment webhook must carry a paymentId")
+	}
+
+	payment, err := a.store.Get(a.config.TenantID, a.config.StoreID, webhook.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaymentEvent{
+		ID:      fmt.Sprintf("evt_%s_%d", payment.ID, payment.UpdatedAt.UnixNano()),
+		Type:    "payment." + payment.Status,
+		Payment: payment,
+	}, nil
+}
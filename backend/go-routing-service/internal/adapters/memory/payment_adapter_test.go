@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPaymentAdapter(t *testing.T, store *PaymentStore, storeID string) ports.IPaymentConnector {
+	config := ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  storeID,
+		Domain:   "payment",
+		Adapter:  "FakePaymentAdapter",
+		Enabled:  true,
+	}
+
+	connector, err := NewFakePaymentAdapter(config, store)
+	require.NoError(t, err)
+	require.NoError(t, connector.Initialize(context.Background(), config))
+
+	return connector.(ports.IPaymentConnector)
+}
+
+func TestFakePaymentAdapter_Conformance(t *testing.T) {
+	config := ports.ConnectorConfig{TenantID: "ikea", StoreID: "ikea-seattle", Domain: "payment"}
+	connector, err := NewFakePaymentAdapter(config, NewPaymentStore())
+	require.NoError(t, err)
+
+	conformance.RunPaymentConnectorTests(t, connector.(ports.IPaymentConnector), conformance.PaymentFixture{
+		Config:        config,
+		PaymentToken:  "tok_visa",
+		DeclinedToken: DeclinedPaymentToken,
+		Webhook: func(payment models.Payment) (http.Header, []byte) {
+			return http.Header{}, []byte(fmt.Sprintf(`{"paymentId": %q}`, payment.ID))
+		},
+	})
+}
+
+func TestFakePaymentAdapter_ScopedToStore(t *testing.T) {
+	store := NewPaymentStore()
+	seattle := newTestPaymentAdapter(t, store, "ikea-seattle")
+	tacoma := newTestPaymentAdapter(t, store, "ikea-tacoma")
+	ctx := context.Background()
+
+	payment, err := seattle.Authorize(ctx, models.PaymentRequest{Reference: "session-1", Amount: 10, Currency: "usd", PaymentToken: "tok_visa"})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", payment.Currency)
+
+	_, err = tacoma.GetPayment(ctx, payment.ID)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	_, err = tacoma.ParseWebhook(ctx, http.Header{}, []byte(fmt.Sprintf(`{"paymentId": %q}`, payment.ID)))
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+)
+
+// DeclinedPaymentToken is the payment token that the in-memory payment store declines
+// Any other non-empty token is authorized
+const DeclinedPaymentToken = "tok_declined"
+
+// ErrInvalidPaymentAmount is returned when an amount is not positive or exceeds what the payment allows
+var ErrInvalidPaymentAmount = errors.New("invalid payment amount")
+
+// PaymentStore holds payments for any number of tenants and stores
+// It is shared by all FakePaymentAdapter instances so payments survive connector cache eviction
+type PaymentStore struct {
+	mu       sync.Mutex
+	payments map[string]*models.Payment // key: "tenantId:storeId:paymentId"
+	now      func() time.Time
+}
+
+// NewPaymentStore creates an empty payment store
+func NewPaymentStore() *PaymentStore {
+	return &PaymentStore{
+		payments: make(map[string]*models.Payment),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Authorize creates an authorized payment, or a declined one for DeclinedPaymentToken
+// While a reference has an open payment for the same amount, authorizing it again returns that
+// payment, so a retried authorization never charges twice
+func (s *PaymentStore) Authorize(tenantID, storeID string, req models.PaymentRequest) (models.Payment, error) {
+	if req.Reference == "" || req.Currency == "" || req.PaymentToken == "" {
+		return models.Payment{}, fmt.Errorf("reference, currency and paymentToken are required")
+	}
+	if req.Amount <= 0 {
+		return models.Payment{}, fmt.Errorf("amount %.2f: %w", req.Amount, ErrInvalidPaymentAmount)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := tenantID + ":" + storeID + ":"
+	for key, p := range s.payments {
+		if strings.HasPrefix(key, prefix) && p.Reference == req.Reference && p.Amount == models.RoundAmount(req.Amount) &&
+			(p.Status == models.PaymentStatusAuthorized || p.Status == models.PaymentStatusCaptured) {
+			return *p, nil
+		}
+	}
+
+	now := s.now()
+	p := &models.Payment{
+		ID:        "pay_" + uuid.New().String(),
+		Reference: req.Reference,
+		Status:    models.PaymentStatusAuthorized,
+		Amount:    models.RoundAmount(req.Amount),
+		Currency:  strings.ToUpper(req.Currency),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.PaymentToken == DeclinedPaymentToken {
+		p.Status = models.PaymentStatusDeclined
+		p.DeclineReason = "card_declined"
+	}
+	s.payments[paymentKey(tenantID, storeID, p.ID)] = p
+
+	return *p, nil
+}
+
+// Get retrieves a payment
+func (s *PaymentStore) Get(tenantID, storeID, paymentID string) (models.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.getLocked(tenantID, storeID, paymentID)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	return *p, nil
+}
+
+// Capture collects up to the authorized amount
+func (s *PaymentStore) Capture(tenantID, storeID, paymentID string, amount float64) (models.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.transitionLocked(tenantID, storeID, paymentID, models.PaymentStatusCaptured)
+	if err != nil || p.Status == models.PaymentStatusCaptured {
+		return derefPayment(p), err
+	}
+
+	amount = models.RoundAmount(amount)
+	if amount <= 0 || amount > p.Amount {
+		return models.Payment{}, fmt.Errorf("capture of %.2f on payment %s of %.2f: %w", amount, paymentID, p.Amount, ErrInvalidPaymentAmount)
+	}
+
+	p.Status = models.PaymentStatusCaptured
+	p.CapturedAmount = amount
+	p.UpdatedAt = s.now()
+	return *p, nil
+}
+
+// Void releases an authorization
+func (s *PaymentStore) Void(tenantID, storeID, paymentID string) (models.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.transitionLocked(tenantID, storeID, paymentID, models.PaymentStatusVoided)
+	if err != nil || p.Status == models.PaymentStatusVoided {
+		return derefPayment(p), err
+	}
+
+	p.Status = models.PaymentStatusVoided
+	p.UpdatedAt = s.now()
+	return *p, nil
+}
+
+// Refund returns part or all of the captured amount that was not refunded yet
+func (s *PaymentStore) Refund(tenantID, storeID, paymentID string, amount float64) (models.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.getLocked(tenantID, storeID, paymentID)
+	if err != nil {
+		return models.Payment{}, err
+	}
+	if p.Status != models.PaymentStatusCaptured {
+		return models.Payment{}, fmt.Errorf("payment %s is %s, not captured: %w", paymentID, p.Status, ports.ErrInvalidTransition)
+	}
+
+	amount = models.RoundAmount(amount)
+	refundable := models.RoundAmount(p.CapturedAmount - p.RefundedAmount)
+	if amount <= 0 || amount > refundable {
+		return models.Payment{}, fmt.Errorf("refund of %.2f on payment %s with %.2f refundable: %w", amount, paymentID, refundable, ErrInvalidPaymentAmount)
+	}
+
+	p.RefundedAmount = models.RoundAmount(p.RefundedAmount + amount)
+	if p.RefundedAmount == p.CapturedAmount {
+		p.Status = models.PaymentStatusRefunded
+	}
+	p.UpdatedAt = s.now()
+	return *p, nil
+}
+
+func (s *PaymentStore) getLocked(tenantID, storeID, paymentID string) (*models.Payment, error) {
+	p, ok := s.payments[paymentKey(tenantID, storeID, paymentID)]
+	if !ok {
+		return nil, fmt.Errorf("payment %s: %w", paymentID, ports.ErrNotFound)
+	}
+	return p, nil
+}
+
+// transitionLocked returns the payment if it may move to status
+func (s *PaymentStore) transitionLocked(tenantID, storeID, paymentID, status string) (*models.Payment, error) {
+	p, err := s.getLocked(tenantID, storeID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionPaymentStatus(p.Status, status) {
+		return nil, fmt.Errorf("payment %s cannot move from %s to %s: %w", paymentID, p.Status, status, ports.ErrInvalidTransition)
+	}
+	return p, nil
+}
+
+func paymentKey(tenantID, storeID, paymentID string) string {
+	return fmt.Sprintf("%s:%s:%s", tenantID, storeID, paymentID)
+}
+
+func derefPayment(p *models.Payment) models.Payment {
+	if p == nil {
+		return models.Payment{}
+	}
+	return *p
+}
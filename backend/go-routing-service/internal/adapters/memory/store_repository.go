@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// StoreRepository keeps a store registry in process; it suits tests and local development
+type StoreRepository struct {
+	mu     sync.RWMutex
+	stores map[string]models.Store // key: "tenantId:storeId"
+}
+
+// NewStoreRepository creates a store registry holding the given stores
+func NewStoreRepository(stores ...models.Store) *StoreRepository {
+	r := &StoreRepository{stores: make(map[string]models.Store)}
+	for _, store := range stores {
+		r.Put(store)
+	}
+	return r
+}
+
+// Put adds or replaces a store
+func (r *StoreRepository) Put(store models.Store) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stores[store.TenantID+":"+store.StoreID] = store
+}
+
+// GetStore returns a store of the tenant
+func (r *StoreRepository) GetStore(ctx context.Context, tenantID, storeID string) (*models.Store, error) {
+	if tenantID == "" {
+		return nil, ports.ErrTenantRequired
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	store, ok := r.stores[tenantID+":"+storeID]
+	if !ok {
+		return nil, fmt.Errorf("store %s: %w", storeID, ports.ErrNotFound)
+	}
+	return &store, nil
+}
+
+// ListStores returns all stores of the tenant ordered by storeId
+func (r *StoreRepository) ListStores(ctx context.Context, tenantID string) ([]models.Store, error) {
+	if tenantID == "" {
+		return nil, ports.ErrTenantRequired
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stores := make([]models.Store, 0)
+	for _, store := range r.stores {
+		if store.TenantID == tenantID {
+			stores = append(stores, store)
+		}
+	}
+	sort.Slice(stores, func(i, j int) bool { return stores[i].StoreID < stores[j].StoreID })
+	return stores, nil
+}
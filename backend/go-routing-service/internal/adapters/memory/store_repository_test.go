@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+)
+
+func TestStoreRepository_Conformance(t *testing.T) {
+	conformance.RunStoreRepositoryTests(t, NewStoreRepository(conformance.StoreRepositoryFixture...))
+}
@@ -0,0 +1,165 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cartDocument is the stored form of a cart
+// The document ID is the (tenant, store, customer) key, so a customer has at most one cart per store
+type cartDocument struct {
+	Key         string            `bson:"_id"`
+	CartID      string            `bson:"cartId"`
+	TenantID    string            `bson:"tenantId"`
+	StoreID     string            `bson:"storeId"`
+	CustomerID  string            `bson:"customerId"`
+	Lines       []models.CartLine `bson:"lines"`
+	Version     int64             `bson:"version"`
+	CreatedAt   time.Time         `bson:"createdAt"`
+	UpdatedAt   time.Time         `bson:"updatedAt"`
+	ValidatedAt time.Time         `bson:"validatedAt"`
+	ExpiresAt   time.Time         `bson:"expiresAt"`
+}
+
+// CartStore keeps carts in the carts collection
+// Carts are removed by a TTL index on expiresAt; expired carts that the TTL monitor has not
+// removed yet are treated as missing
+type CartStore struct {
+	collection *mongo.Collection
+}
+
+// NewCartStore creates a cart store on the given collection
+func NewCartStore(collection *mongo.Collection) *CartStore {
+	return &CartStore{collection: collection}
+}
+
+// EnsureIndexes creates the TTL index that expires carts
+func (s *CartStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cart TTL index: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves the customer's cart in a store
+func (s *CartStore) Get(ctx context.Context, tenantID, storeID, customerID string) (*models.Cart, error) {
+	filter := bson.M{
+		"_id":       cartKey(tenantID, storeID, customerID),
+		"expiresAt": bson.M{"$gt": time.Now().UTC()},
+	}
+
+	var doc cartDocument
+	err := s.collection.FindOne(ctx, filter).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("cart: %w", ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// Save stores the cart if it has not changed since it was read and increments its version
+// A new cart (version 0) replaces an expired one but never a live one
+func (s *CartStore) Save(ctx context.Context, cart *models.Cart) error {
+	key := cartKey(cart.TenantID, cart.StoreID, cart.CustomerID)
+	doc := cartDocument{
+		Key:         key,
+		CartID:      cart.ID,
+		TenantID:    cart.TenantID,
+		StoreID:     cart.StoreID,
+		CustomerID:  cart.CustomerID,
+		Lines:       cart.Lines,
+		Version:     cart.Version + 1,
+		CreatedAt:   cart.CreatedAt,
+		UpdatedAt:   cart.UpdatedAt,
+		ValidatedAt: cart.ValidatedAt,
+		ExpiresAt:   cart.ExpiresAt,
+	}
+
+	var filter bson.M
+	opts := options.Replace()
+	if cart.Version == 0 {
+		filter = bson.M{"_id": key, "expiresAt": bson.M{"$lte": time.Now().UTC()}}
+		opts.SetUpsert(true)
+	} else {
+		filter = bson.M{"_id": key, "version": cart.Version}
+	}
+
+	result, err := s.collection.ReplaceOne(ctx, filter, doc, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		// A live cart already exists for this customer
+		return fmt.Errorf("cart: %w", ports.ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save cart: %w", err)
+	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return fmt.Errorf("cart: %w", ports.ErrConflict)
+	}
+
+	cart.Version = doc.Version
+	return nil
+}
+
+// Delete removes the cart if it has not changed since it was read
+func (s *CartStore) Delete(ctx context.Context, cart *models.Cart) error {
+	key := cartKey(cart.TenantID, cart.StoreID, cart.CustomerID)
+
+	result, err := s.collection.DeleteOne(ctx, bson.M{"_id": key, "version": cart.Version})
+	if err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	if result.DeletedCount == 1 {
+		return nil
+	}
+
+	count, err := s.collection.CountDocuments(ctx, bson.M{"_id": key})
+	if err != nil {
+		return fmt.Errorf("failed to delete cart: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("cart: %w", ports.ErrNotFound)
+	}
+	return fmt.Errorf("cart: %w", ports.ErrConflict)
+}
+
+// cartKey builds the document ID of a customer's cart in a store
+func cartKey(tenantID, storeID, customerID string) string {
+	return fmt.Sprintf("%s:%s:%s", tenantID, storeID, customerID)
+}
+
+func (d cartDocument) toModel() *models.Cart {
+	lines := d.Lines
+	if lines == nil {
+		lines = []models.CartLine{}
+	}
+
+	cart := &models.Cart{
+		ID:          d.CartID,
+		TenantID:    d.TenantID,
+		StoreID:     d.StoreID,
+		CustomerID:  d.CustomerID,
+		Lines:       lines,
+		Version:     d.Version,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+		ValidatedAt: d.ValidatedAt,
+		ExpiresAt:   d.ExpiresAt,
+	}
+	cart.Recalculate()
+	return cart
+}
@@ -0,0 +1,311 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// checkoutSessionDocument is the stored form of a checkout session
+type checkoutSessionDocument struct {
+	SessionID     string                   `bson:"sessionId"`
+	TenantID      string                   `bson:"tenantId"`
+	StoreID       string                   `bson:"storeId"`
+	CustomerID    string                   `bson:"customerId"`
+	Lines         []models.CheckoutLine    `bson:"lines"`
+	Discounts     []models.AppliedDiscount `bson:"discounts"`
+	Subtotal      float64                  `bson:"subtotal"`
+	DiscountTotal float64                  `bson:"discountTotal"`
+	Total         float64                  `bson:"total"`
+	Currency      string                   `bson:"currency"`
+	QRToken       string                   `bson:"qrToken"`
+	Status        string                   `bson:"status"`
+	Version       int64                    `bson:"version"`
+	CreatedAt     time.Time                `bson:"createdAt"`
+	UpdatedAt     time.Time                `bson:"updatedAt"`
+	ExpiresAt     time.Time                `bson:"expiresAt"`
+	ScannedAt     *time.Time               `bson:"scannedAt,omitempty"`
+	PaidAt        *time.Time               `bson:"paidAt,omitempty"`
+	CompletedAt   *time.Time               `bson:"completedAt,omitempty"`
+	CancelledAt   *time.Time               `bson:"cancelledAt,omitempty"`
+	VerifiedAt    *time.Time               `bson:"verifiedAt,omitempty"`
+	VerifiedBy    string                   `bson:"verifiedBy,omitempty"`
+	Payment       *checkoutPaymentDocument `bson:"payment,omitempty"`
+	Order         *checkoutOrderDocument   `bson:"order,omitempty"`
+	PurgeAt       time.Time                `bson:"purgeAt"`
+}
+
+// checkoutPaymentDocument is the stored in-app payment of a session
+type checkoutPaymentDocument struct {
+	PaymentID      string    `bson:"paymentId"`
+	Status         string    `bson:"status"`
+	Amount         float64   `bson:"amount"`
+	RefundedAmount float64   `bson:"refundedAmount,omitempty"`
+	Currency       string    `bson:"currency"`
+	DeclineReason  string    `bson:"declineReason,omitempty"`
+	UpdatedAt      time.Time `bson:"updatedAt"`
+}
+
+// checkoutOrderDocument is the stored order submission state of a paid session
+type checkoutOrderDocument struct {
+	Status        string     `bson:"status"`
+	OrderID       string     `bson:"orderId,omitempty"`
+	OrderNumber   string     `bson:"orderNumber,omitempty"`
+	Attempts      int        `bson:"attempts"`
+	LastError     string     `bson:"lastError,omitempty"`
+	NextAttemptAt time.Time  `bson:"nextAttemptAt"`
+	SubmittedAt   *time.Time `bson:"submittedAt,omitempty"`
+}
+
+// CheckoutSessionStore keeps checkout sessions in the checkout_sessions collection
+// Sessions are removed by a TTL index on purgeAt, which lies after the session expires or
+// reaches a final status
+type CheckoutSessionStore struct {
+	collection *mongo.Collection
+}
+
+// NewCheckoutSessionStore creates a checkout session store on the given collection
+func NewCheckoutSessionStore(collection *mongo.Collection) *CheckoutSessionStore {
+	return &CheckoutSessionStore{collection: collection}
+}
+
+// EnsureIndexes creates the session lookup index, the index used to find due order submissions
+// and the TTL index that removes old sessions
+func (s *CheckoutSessionStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "sessionId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "purgeAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: "order.status", Value: 1}, {Key: "order.nextAttemptAt", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create checkout session indexes: %w", err)
+	}
+	return nil
+}
+
+// Create stores a new session at version 1
+func (s *CheckoutSessionStore) Create(ctx context.Context, session *models.CheckoutSession) error {
+	doc := newCheckoutSessionDocument(session)
+	doc.Version = 1
+
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	session.Version = doc.Version
+	return nil
+}
+
+// Get retrieves a tenant's session
+func (s *CheckoutSessionStore) Get(ctx context.Context, tenantID, sessionID string) (*models.CheckoutSession, error) {
+	var doc checkoutSessionDocument
+	err := s.collection.FindOne(ctx, bson.M{"sessionId": sessionID, "tenantId": tenantID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("checkout session %s: %w", sessionID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkout session: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// Update stores the session if it has not changed since it was read and increments its version
+func (s *CheckoutSessionStore) Update(ctx context.Context, session *models.CheckoutSession) error {
+	doc := newCheckoutSessionDocument(session)
+	doc.Version = session.Version + 1
+
+	filter := bson.M{"sessionId": session.ID, "tenantId": session.TenantID, "version": session.Version}
+	result, err := s.collection.ReplaceOne(ctx, filter, doc)
+	if err != nil {
+		return fmt.Errorf("failed to update checkout session: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := s.collection.CountDocuments(ctx, bson.M{"sessionId": session.ID, "tenantId": session.TenantID})
+		if err != nil {
+			return fmt.Errorf("failed to update checkout session: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("checkout session %s: %w", session.ID, ports.ErrNotFound)
+		}
+		return fmt.Errorf("checkout session %s: %w", session.ID, ports.ErrConflict)
+	}
+
+	session.Version = doc.Version
+	return nil
+}
+
+// ListDueOrders returns sessions of any tenant whose order submission is due, oldest first
+// A submission is due when it is pending, or submitting with a lapsed claim, and its next
+// attempt time has passed
+func (s *CheckoutSessionStore) ListDueOrders(ctx context.Context, now time.Time, limit int) ([]*models.CheckoutSession, error) {
+	filter := bson.M{
+		"order.status":        bson.M{"$in": []string{models.CheckoutOrderPending, models.CheckoutOrderSubmitting}},
+		"order.nextAttemptAt": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "order.nextAttemptAt", Value: 1}}).SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due checkout orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*models.CheckoutSession{}
+	for cursor.Next(ctx) {
+		var doc checkoutSessionDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode checkout session: %w", err)
+		}
+		sessions = append(sessions, doc.toModel())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list due checkout orders: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func newCheckoutSessionDocument(session *models.CheckoutSession) checkoutSessionDocument {
+	return checkoutSessionDocument{
+		SessionID:     session.ID,
+		TenantID:      session.TenantID,
+		StoreID:       session.StoreID,
+		CustomerID:    session.CustomerID,
+		Lines:         session.Lines,
+		Discounts:     session.Discounts,
+		Subtotal:      session.Subtotal,
+		DiscountTotal: session.DiscountTotal,
+		Total:         session.Total,
+		Currency:      session.Currency,
+		QRToken:       session.QRToken,
+		Status:        session.Status,
+		Version:       session.Version,
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
+		ExpiresAt:     session.ExpiresAt,
+		ScannedAt:     session.ScannedAt,
+		PaidAt:        session.PaidAt,
+		CompletedAt:   session.CompletedAt,
+		CancelledAt:   session.CancelledAt,
+		VerifiedAt:    session.VerifiedAt,
+		VerifiedBy:    session.VerifiedBy,
+		Payment:       newCheckoutPaymentDocument(session.Payment),
+		Order:         newCheckoutOrderDocument(session.Order),
+		PurgeAt:       session.PurgeAt,
+	}
+}
+
+func newCheckoutPaymentDocument(payment *models.CheckoutPayment) *checkoutPaymentDocument {
+	if payment == nil {
+		return nil
+	}
+	return &checkoutPaymentDocument{
+		PaymentID:      payment.PaymentID,
+		Status:         payment.Status,
+		Amount:         payment.Amount,
+		RefundedAmount: payment.RefundedAmount,
+		Currency:       payment.Currency,
+		DeclineReason:  payment.DeclineReason,
+		UpdatedAt:      payment.UpdatedAt,
+	}
+}
+
+func newCheckoutOrderDocument(order *models.CheckoutOrder) *checkoutOrderDocument {
+	if order == nil {
+		return nil
+	}
+	return &checkoutOrderDocument{
+		Status:        order.Status,
+		OrderID:       order.OrderID,
+		OrderNumber:   order.OrderNumber,
+		Attempts:      order.Attempts,
+		LastError:     order.LastError,
+		NextAttemptAt: order.NextAttemptAt,
+		SubmittedAt:   order.SubmittedAt,
+	}
+}
+
+func (d checkoutSessionDocument) toModel() *models.CheckoutSession {
+	lines := d.Lines
+	if lines == nil {
+		lines = []models.CheckoutLine{}
+	}
+	discounts := d.Discounts
+	if discounts == nil {
+		discounts = []models.AppliedDiscount{}
+	}
+
+	return &models.CheckoutSession{
+		ID:            d.SessionID,
+		TenantID:      d.TenantID,
+		StoreID:       d.StoreID,
+		CustomerID:    d.CustomerID,
+		Lines:         lines,
+		Discounts:     discounts,
+		Subtotal:      d.Subtotal,
+		DiscountTotal: d.DiscountTotal,
+		Total:         d.Total,
+		Currency:      d.Currency,
+		QRToken:       d.QRToken,
+		Status:        d.Status,
+		Version:       d.Version,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+		ExpiresAt:     d.ExpiresAt,
+		ScannedAt:     d.ScannedAt,
+		PaidAt:        d.PaidAt,
+		CompletedAt:   d.CompletedAt,
+		CancelledAt:   d.CancelledAt,
+		VerifiedAt:    d.VerifiedAt,
+		VerifiedBy:    d.VerifiedBy,
+		Payment:       d.Payment.toModel(),
+		Order:         d.Order.toModel(),
+		PurgeAt:       d.PurgeAt,
+	}
+}
+
+func (d *checkoutPaymentDocument) toModel() *models.CheckoutPayment {
+	if d == nil {
+		return nil
+	}
+	return &models.CheckoutPayment{
+		PaymentID:      d.PaymentID,
+		Status:         d.Status,
+		Amount:         d.Amount,
+		RefundedAmount: d.RefundedAmount,
+		Currency:       d.Currency,
+		DeclineReason:  d.DeclineReason,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+func (d *checkoutOrderDocument) toModel() *models.CheckoutOrder {
+	if d == nil {
+		return nil
+	}
+	return &models.CheckoutOrder{
+		Status:        d.Status,
+		OrderID:       d.OrderID,
+		OrderNumber:   d.OrderNumber,
+		Attempts:      d.Attempts,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		SubmittedAt:   d.SubmittedAt,
+	}
+}
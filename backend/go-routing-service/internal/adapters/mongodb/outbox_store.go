@@ -0,0 +1,220 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/outbox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// outboxRelayLease is the ID of the lease document held by the publishing relay
+const outboxRelayLease = "outbox-relay"
+
+// outboxEventDocument is the stored form of a domain event
+type outboxEventDocument struct {
+	ID            string             `bson:"_id"`
+	Seq           primitive.ObjectID `bson:"seq"`
+	Type          string             `bson:"type"`
+	AggregateType string             `bson:"aggregateType"`
+	AggregateID   string             `bson:"aggregateId"`
+	TenantID      string             `bson:"tenantId"`
+	StoreID       string             `bson:"storeId,omitempty"`
+	OccurredAt    time.Time          `bson:"occurredAt"`
+	Data          string             `bson:"data,omitempty"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"lastError,omitempty"`
+	PublishedAt   *time.Time         `bson:"publishedAt,omitempty"`
+}
+
+// OutboxStore keeps domain events in the outbox collection until they are published, and the
+// relay lease in the outbox_leases collection
+// Published events are removed by a TTL index once retention has passed. Atomically uses a
+// transaction when the deployment supports them (replica sets, sharded clusters and Cosmos DB);
+// on a standalone server the writes are made one after the other.
+type OutboxStore struct {
+	events    *mongo.Collection
+	leases    *mongo.Collection
+	retention time.Duration
+
+	transactions bool
+}
+
+// NewOutboxStore creates an outbox store on the given collections
+func NewOutboxStore(events, leases *mongo.Collection, retention time.Duration) *OutboxStore {
+	return &OutboxStore{events: events, leases: leases, retention: retention}
+}
+
+// EnsureIndexes creates the index used to read pending events in order and the TTL index that
+// removes published events
+func (s *OutboxStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.events.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "occurredAt", Value: 1}, {Key: "seq", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(s.retention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+	return nil
+}
+
+// Append inserts events in order
+func (s *OutboxStore) Append(ctx context.Context, events ...models.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		docs = append(docs, outboxEventDocument{
+			ID:            event.ID,
+			Seq:           primitive.NewObjectID(),
+			Type:          event.Type,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			TenantID:      event.TenantID,
+			StoreID:       event.StoreID,
+			OccurredAt:    event.OccurredAt,
+			Data:          string(event.Data),
+		})
+	}
+
+	if _, err := s.events.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to append outbox events: %w", err)
+	}
+	return nil
+}
+
+// Atomically runs fn in a transaction when the deployment supports them
+// Stores whose collections are on the same deployment join the transaction through ctx.
+func (s *OutboxStore) Atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !s.transactions {
+		return fn(ctx)
+	}
+
+	session, err := s.events.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start outbox transaction: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// DetectTransactions checks whether the server is a replica set member or a mongos and makes
+// Atomically use transactions if so; it is called once at startup
+func (s *OutboxStore) DetectTransactions(ctx context.Context) error {
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := s.events.Database().RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("failed to detect MongoDB transaction support: %w", err)
+	}
+	s.transactions = result.SetName != "" || result.Msg == "isdbgrid"
+	return nil
+}
+
+// SupportsTransactions reports whether Atomically uses transactions
+func (s *OutboxStore) SupportsTransactions() bool {
+	return s.transactions
+}
+
+// ListPending returns unpublished events, oldest first
+func (s *OutboxStore) ListPending(ctx context.Context, limit int) ([]outbox.Entry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "occurredAt", Value: 1}, {Key: "seq", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.events.Find(ctx, bson.M{"publishedAt": nil}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending outbox events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []outbox.Entry{}
+	for cursor.Next(ctx) {
+		var doc outboxEventDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox event: %w", err)
+		}
+		entries = append(entries, outbox.Entry{
+			Event: models.DomainEvent{
+				ID:            doc.ID,
+				Type:          doc.Type,
+				AggregateType: doc.AggregateType,
+				AggregateID:   doc.AggregateID,
+				TenantID:      doc.TenantID,
+				StoreID:       doc.StoreID,
+				OccurredAt:    doc.OccurredAt,
+				Data:          json.RawMessage(doc.Data),
+			},
+			Attempts:  doc.Attempts,
+			LastError: doc.LastError,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list pending outbox events: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkPublished sets the event's publication time, which starts its retention
+func (s *OutboxStore) MarkPublished(ctx context.Context, eventID string, at time.Time) error {
+	_, err := s.events.UpdateByID(ctx, eventID, bson.M{"$set": bson.M{"publishedAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed counts a failed attempt and keeps its error
+func (s *OutboxStore) MarkFailed(ctx context.Context, eventID string, cause error) error {
+	update := bson.M{
+		"$inc": bson.M{"attempts": 1},
+		"$set": bson.M{"lastError": cause.Error()},
+	}
+	if _, err := s.events.UpdateByID(ctx, eventID, update); err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+// AcquireLease takes or renews the relay lease
+// The upsert only matches a lease owned by owner or expired; when another owner holds it the
+// insert fails on the lease ID.
+func (s *OutboxStore) AcquireLease(ctx context.Context, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	filter := bson.M{
+		"_id": outboxRelayLease,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(ttl)}}
+
+	_, err := s.leases.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire outbox relay lease: %w", err)
+	}
+	return true, nil
+}
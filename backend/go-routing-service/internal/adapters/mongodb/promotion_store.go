@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// promotionDocument is the stored form of a promotion
+// Codes are stored upper-case; see promotions.NormalizeCode
+type promotionDocument struct {
+	Code        string     `bson:"code"`
+	TenantID    string     `bson:"tenantId"`
+	StoreID     string     `bson:"storeId,omitempty"`
+	Description string     `bson:"description,omitempty"`
+	Type        string     `bson:"type"`
+	Value       float64    `bson:"value"`
+	Currency    string     `bson:"currency,omitempty"`
+	MinSubtotal float64    `bson:"minSubtotal,omitempty"`
+	StartsAt    *time.Time `bson:"startsAt,omitempty"`
+	EndsAt      *time.Time `bson:"endsAt,omitempty"`
+	Active      bool       `bson:"active"`
+}
+
+// PromotionStore reads tenant promotions from the promotions collection
+type PromotionStore struct {
+	collection *mongo.Collection
+}
+
+// NewPromotionStore creates a promotion store on the given collection
+func NewPromotionStore(collection *mongo.Collection) *PromotionStore {
+	return &PromotionStore{collection: collection}
+}
+
+// FindPromotion retrieves a tenant's promotion by code
+func (s *PromotionStore) FindPromotion(ctx context.Context, tenantID, code string) (*models.Promotion, error) {
+	var doc promotionDocument
+	err := s.collection.FindOne(ctx, bson.M{"tenantId": tenantID, "code": code}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("promotion %s: %w", code, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	return &models.Promotion{
+		Code:        doc.Code,
+		TenantID:    doc.TenantID,
+		StoreID:     doc.StoreID,
+		Description: doc.Description,
+		Type:        doc.Type,
+		Value:       doc.Value,
+		Currency:    doc.Currency,
+		MinSubtotal: doc.MinSubtotal,
+		StartsAt:    doc.StartsAt,
+		EndsAt:      doc.EndsAt,
+		Active:      doc.Active,
+	}, nil
+}
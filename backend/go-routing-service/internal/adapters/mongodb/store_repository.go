@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// storeDocument is the stored form of a store
+type storeDocument struct {
+	StoreID        string                 `bson:"storeId"`
+	TenantID       string                 `bson:"tenantId"`
+	Name           string                 `bson:"name"`
+	BackendURL     string                 `bson:"backendUrl"`
+	BackendContext map[string]interface{} `bson:"backendContext"`
+	Location       *models.StoreLocation  `bson:"location,omitempty"`
+	Status         string                 `bson:"status,omitempty"`
+	ClosingTime    time.Time              `bson:"closingTime,omitempty"`
+	Address        string                 `bson:"address,omitempty"`
+	City           string                 `bson:"city,omitempty"`
+	State          string                 `bson:"state,omitempty"`
+}
+
+func (d *storeDocument) toModel() models.Store {
+	return models.Store{
+		StoreID:        d.StoreID,
+		TenantID:       d.TenantID,
+		Name:           d.Name,
+		BackendURL:     d.BackendURL,
+		BackendContext: d.BackendContext,
+		Location:       d.Location,
+		Status:         d.Status,
+		ClosingTime:    d.ClosingTime,
+		Address:        d.Address,
+		City:           d.City,
+		State:          d.State,
+	}
+}
+
+// StoreRepository reads the store registry from the stores collection
+// Every query it runs is filtered by tenantId.
+type StoreRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStoreRepository creates a store repository on the given collection
+func NewStoreRepository(collection *mongo.Collection) *StoreRepository {
+	return &StoreRepository{collection: collection}
+}
+
+// GetStore returns a store of the tenant
+func (r *StoreRepository) GetStore(ctx context.Context, tenantID, storeID string) (*models.Store, error) {
+	if tenantID == "" {
+		return nil, ports.ErrTenantRequired
+	}
+
+	var doc storeDocument
+	err := r.collection.FindOne(ctx, bson.M{"tenantId": tenantID, "storeId": storeID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("store %s: %w", storeID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store: %w", err)
+	}
+
+	store := doc.toModel()
+	return &store, nil
+}
+
+// ListStores returns all stores of the tenant
+func (r *StoreRepository) ListStores(ctx context.Context, tenantID string) ([]models.Store, error) {
+	if tenantID == "" {
+		return nil, ports.ErrTenantRequired
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"tenantId": tenantID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stores: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []storeDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode stores: %w", err)
+	}
+
+	stores := make([]models.Store, 0, len(docs))
+	for i := range docs {
+		stores = append(stores, docs[i].toModel())
+	}
+	return stores, nil
+}
@@ -0,0 +1,309 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/webhooks"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// webhookSubscriptionDocument is the stored form of a webhook subscription
+type webhookSubscriptionDocument struct {
+	ID         string    `bson:"_id"`
+	TenantID   string    `bson:"tenantId"`
+	URL        string    `bson:"url"`
+	EventTypes []string  `bson:"eventTypes"`
+	Secret     string    `bson:"secret"`
+	Active     bool      `bson:"active"`
+	CreatedAt  time.Time `bson:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt"`
+}
+
+// webhookDeliveryDocument is the stored form of a webhook delivery
+type webhookDeliveryDocument struct {
+	ID             string     `bson:"_id"`
+	SubscriptionID string     `bson:"subscriptionId"`
+	TenantID       string     `bson:"tenantId"`
+	EventID        string     `bson:"eventId"`
+	EventType      string     `bson:"eventType"`
+	Payload        string     `bson:"payload"`
+	Status         string     `bson:"status"`
+	Attempts       int        `bson:"attempts"`
+	ResponseStatus int        `bson:"responseStatus,omitempty"`
+	LastError      string     `bson:"lastError,omitempty"`
+	CreatedAt      time.Time  `bson:"createdAt"`
+	UpdatedAt      time.Time  `bson:"updatedAt"`
+	DeliveredAt    *time.Time `bson:"deliveredAt,omitempty"`
+	NextAttemptAt  time.Time  `bson:"nextAttemptAt"`
+}
+
+// WebhookStore keeps webhook subscriptions in the webhook_subscriptions collection and their
+// deliveries in the webhook_deliveries collection
+// Deliveries are removed by a TTL index once retention has passed since they were created.
+type WebhookStore struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+	retention     time.Duration
+}
+
+// NewWebhookStore creates a webhook store on the given collections
+func NewWebhookStore(subscriptions, deliveries *mongo.Collection, retention time.Duration) *WebhookStore {
+	return &WebhookStore{subscriptions: subscriptions, deliveries: deliveries, retention: retention}
+}
+
+// EnsureIndexes creates the indexes used to find a tenant's subscriptions, to claim due
+// deliveries and to read delivery logs, and the TTL index that removes old deliveries
+func (s *WebhookStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.subscriptions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "createdAt", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription indexes: %w", err)
+	}
+
+	_, err = s.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "subscriptionId", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(s.retention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery indexes: %w", err)
+	}
+	return nil
+}
+
+// CreateSubscription stores a new subscription
+func (s *WebhookStore) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	if _, err := s.subscriptions.InsertOne(ctx, newWebhookSubscriptionDocument(subscription)); err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// GetSubscription retrieves a subscription of the tenant
+func (s *WebhookStore) GetSubscription(ctx context.Context, tenantID, subscriptionID string) (*models.WebhookSubscription, error) {
+	var doc webhookSubscriptionDocument
+	err := s.subscriptions.FindOne(ctx, bson.M{"_id": subscriptionID, "tenantId": tenantID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("webhook subscription %s: %w", subscriptionID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return doc.toModel(), nil
+}
+
+// ListSubscriptions retrieves the subscriptions of the tenant, oldest first
+func (s *WebhookStore) ListSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := s.subscriptions.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []webhookSubscriptionDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook subscriptions: %w", err)
+	}
+
+	subscriptions := make([]models.WebhookSubscription, 0, len(docs))
+	for _, doc := range docs {
+		subscriptions = append(subscriptions, *doc.toModel())
+	}
+	return subscriptions, nil
+}
+
+// UpdateSubscription replaces a subscription of the tenant
+func (s *WebhookStore) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	filter := bson.M{"_id": subscription.ID, "tenantId": subscription.TenantID}
+	result, err := s.subscriptions.ReplaceOne(ctx, filter, newWebhookSubscriptionDocument(subscription))
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook subscription %s: %w", subscription.ID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteSubscription removes a subscription of the tenant; its deliveries are kept
+func (s *WebhookStore) DeleteSubscription(ctx context.Context, tenantID, subscriptionID string) error {
+	result, err := s.subscriptions.DeleteOne(ctx, bson.M{"_id": subscriptionID, "tenantId": tenantID})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("webhook subscription %s: %w", subscriptionID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// EnqueueDelivery stores a delivery unless one with its ID exists
+func (s *WebhookStore) EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	_, err := s.deliveries.InsertOne(ctx, newWebhookDeliveryDocument(delivery))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ClaimDelivery claims the longest-due pending delivery of any tenant
+func (s *WebhookStore) ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":        models.WebhookDeliveryPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var doc webhookDeliveryDocument
+	err := s.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return doc.toModel(), nil
+}
+
+// SaveDelivery replaces a delivery
+func (s *WebhookStore) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	result, err := s.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, newWebhookDeliveryDocument(delivery))
+	if err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("webhook delivery %s: %w", delivery.ID, ports.ErrNotFound)
+	}
+	return nil
+}
+
+// GetDelivery retrieves a delivery of the tenant
+func (s *WebhookStore) GetDelivery(ctx context.Context, tenantID, deliveryID string) (*models.WebhookDelivery, error) {
+	var doc webhookDeliveryDocument
+	err := s.deliveries.FindOne(ctx, bson.M{"_id": deliveryID, "tenantId": tenantID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("webhook delivery %s: %w", deliveryID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return doc.toModel(), nil
+}
+
+// ListDeliveries retrieves matching deliveries, newest first
+func (s *WebhookStore) ListDeliveries(ctx context.Context, filter webhooks.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := bson.M{"tenantId": filter.TenantID}
+	if filter.SubscriptionID != "" {
+		query["subscriptionId"] = filter.SubscriptionID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := s.deliveries.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []webhookDeliveryDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(docs))
+	for _, doc := range docs {
+		deliveries = append(deliveries, *doc.toModel())
+	}
+	return deliveries, nil
+}
+
+func newWebhookSubscriptionDocument(subscription *models.WebhookSubscription) webhookSubscriptionDocument {
+	return webhookSubscriptionDocument{
+		ID:         subscription.ID,
+		TenantID:   subscription.TenantID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Secret:     subscription.Secret,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func (d webhookSubscriptionDocument) toModel() *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:         d.ID,
+		TenantID:   d.TenantID,
+		URL:        d.URL,
+		EventTypes: d.EventTypes,
+		Secret:     d.Secret,
+		Active:     d.Active,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+func newWebhookDeliveryDocument(delivery *models.WebhookDelivery) webhookDeliveryDocument {
+	return webhookDeliveryDocument{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		TenantID:       delivery.TenantID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        string(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		NextAttemptAt:  delivery.NextAttemptAt,
+	}
+}
+
+func (d webhookDeliveryDocument) toModel() *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		TenantID:       d.TenantID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        []byte(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+		DeliveredAt:    d.DeliveredAt,
+		NextAttemptAt:  d.NextAttemptAt,
+	}
+}
@@ -0,0 +1,488 @@
+// Package mongodb provides connectors and stores backed by the service's own MongoDB/Cosmos DB database.
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultWishlistName is used for wishlists created implicitly and for legacy documents without a name
+const DefaultWishlistName = "My Wishlist"
+
+// wishlistDocument is the stored form of a wishlist
+// Items are stored as models.WishlistItem to stay compatible with documents
+// written before wishlists went through the connector framework
+type wishlistDocument struct {
+	ID         primitive.ObjectID    `bson:"_id,omitempty"`
+	TenantID   string                `bson:"tenantId"`
+	StoreID    string                `bson:"storeId"`
+	CustomerID string                `bson:"customerId"`
+	Name       string                `bson:"name,omitempty"`
+	IsPublic   bool                  `bson:"isPublic"`
+	Items      []models.WishlistItem `bson:"items"`
+	CreatedAt  time.Time             `bson:"createdAt"`
+	UpdatedAt  time.Time             `bson:"updatedAt"`
+}
+
+// MongoWishlistAdapter implements IWishlistConnector on the wishlists collection
+// Every query is scoped to the connector's tenant and store
+type MongoWishlistAdapter struct {
+	config     ports.ConnectorConfig
+	collection *mongo.Collection
+}
+
+// NewMongoWishlistAdapter creates a new MongoDB wishlist adapter
+func NewMongoWishlistAdapter(config ports.ConnectorConfig, collection *mongo.Collection) (ports.IConnector, error) {
+	if collection == nil {
+		return nil, fmt.Errorf("wishlists collection is required for MongoDB wishlist adapter")
+	}
+
+	return &MongoWishlistAdapter{
+		config:     config,
+		collection: collection,
+	}, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *MongoWishlistAdapter) GetDomain() string {
+	return "wishlist"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *MongoWishlistAdapter) GetAdapterType() string {
+	return "MongoWishlistAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *MongoWishlistAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("tenantId", config.TenantID).
+		Str("storeId", config.StoreID).
+		Str("collection", a.collection.Name()).
+		Msg("Initializing MongoDB wishlist adapter")
+
+	if a.config.TenantID == "" || a.config.StoreID == "" {
+		return fmt.Errorf("tenantId and storeId are required for MongoDB wishlist adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck verifies the database is reachable
+func (a *MongoWishlistAdapter) HealthCheck(ctx context.Context) error {
+	return a.collection.Database().Client().Ping(ctx, nil)
+}
+
+// Close gracefully shuts down the connector
+// The Mongo client is shared with the rest of the service and is not disconnected here
+func (a *MongoWishlistAdapter) Close() error {
+	return nil
+}
+
+// GetWishlists retrieves all wishlists for a customer, oldest first
+func (a *MongoWishlistAdapter) GetWishlists(ctx context.Context, customerID string) (*models.WishlistList, error) {
+	filter := a.scope(bson.M{"customerId": customerID})
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := a.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlists: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []wishlistDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode wishlists: %w", err)
+	}
+
+	wishlists := make([]models.Wishlist, 0, len(docs))
+	for _, doc := range docs {
+		wishlists = append(wishlists, *doc.toModel())
+	}
+
+	return &models.WishlistList{
+		Wishlists: wishlists,
+		Total:     len(wishlists),
+		Limit:     len(wishlists),
+		Offset:    0,
+		HasMore:   false,
+	}, nil
+}
+
+// GetWishlist retrieves a specific wishlist
+func (a *MongoWishlistAdapter) GetWishlist(ctx context.Context, wishlistID string) (*models.Wishlist, error) {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc wishlistDocument
+	err = a.collection.FindOne(ctx, a.scope(bson.M{"_id": id})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// CreateWishlist creates a new wishlist
+func (a *MongoWishlistAdapter) CreateWishlist(ctx context.Context, customerID, name string, isPublic bool) (*models.Wishlist, error) {
+	if name == "" {
+		name = DefaultWishlistName
+	}
+
+	now := time.Now().UTC()
+	doc := wishlistDocument{
+		TenantID:   a.config.TenantID,
+		StoreID:    a.config.StoreID,
+		CustomerID: customerID,
+		Name:       name,
+		IsPublic:   isPublic,
+		Items:      []models.WishlistItem{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	result, err := a.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wishlist: %w", err)
+	}
+	doc.ID = result.InsertedID.(primitive.ObjectID)
+
+	return doc.toModel(), nil
+}
+
+// UpdateWishlist renames a wishlist or changes its visibility
+func (a *MongoWishlistAdapter) UpdateWishlist(ctx context.Context, wishlistID string, update models.WishlistUpdate) (*models.Wishlist, error) {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"updatedAt": time.Now().UTC()}
+	if update.Name != nil {
+		set["name"] = *update.Name
+	}
+	if update.IsPublic != nil {
+		set["isPublic"] = *update.IsPublic
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc wishlistDocument
+	err = a.collection.FindOneAndUpdate(ctx, a.scope(bson.M{"_id": id}), bson.M{"$set": set}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wishlist: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// AddItem adds an item to a wishlist
+// An item with the same product and variant is merged by adding to its quantity;
+// non-empty notes replace the existing ones
+func (a *MongoWishlistAdapter) AddItem(ctx context.Context, wishlistID string, req models.AddWishlistItemRequest) (*models.WishlistItem, error) {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	// Items are stored without bson tags, so their keys are the lowercased field names
+	sameProduct := bson.M{"productid": req.ProductID, "variantid": req.VariantID}
+
+	// Two attempts cover a concurrent add of the same product between the merge and the push
+	for attempt := 0; attempt < 2; attempt++ {
+		merged, err := a.mergeItem(ctx, id, sameProduct, quantity, req.Notes)
+		if err != nil {
+			return nil, err
+		}
+		if merged != nil {
+			return merged, nil
+		}
+
+		now := time.Now().UTC()
+		item := models.WishlistItem{
+			ID:        uuid.NewString(),
+			ProductID: req.ProductID,
+			VariantID: req.VariantID,
+			SKU:       req.SKU,
+			Name:      req.Name,
+			Price:     req.Price,
+			ImageURL:  req.ImageURL,
+			Notes:     req.Notes,
+			Quantity:  quantity,
+			AddedAt:   now,
+		}
+
+		// Only push when no item for the product exists, so concurrent adds cannot create duplicates
+		filter := a.scope(bson.M{"_id": id, "items": bson.M{"$not": bson.M{"$elemMatch": sameProduct}}})
+		result, err := a.collection.UpdateOne(ctx, filter, bson.M{
+			"$push": bson.M{"items": item},
+			"$set":  bson.M{"updatedAt": now},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add item to wishlist: %w", err)
+		}
+		if result.MatchedCount == 1 {
+			return &item, nil
+		}
+
+		// Either the wishlist does not exist or the product was added concurrently
+		if exists, err := a.collection.CountDocuments(ctx, a.scope(bson.M{"_id": id})); err != nil {
+			return nil, fmt.Errorf("failed to add item to wishlist: %w", err)
+		} else if exists == 0 {
+			return nil, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to add item to wishlist: concurrent update")
+}
+
+// mergeItem adds quantity to the item matching sameProduct; returns nil when there is no such item
+func (a *MongoWishlistAdapter) mergeItem(ctx context.Context, id primitive.ObjectID, sameProduct bson.M, quantity int, notes string) (*models.WishlistItem, error) {
+	set := bson.M{"updatedAt": time.Now().UTC()}
+	if notes != "" {
+		set["items.$.notes"] = notes
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc wishlistDocument
+	err := a.collection.FindOneAndUpdate(ctx,
+		a.scope(bson.M{"_id": id, "items": bson.M{"$elemMatch": sameProduct}}),
+		bson.M{
+			"$inc": bson.M{"items.$.quantity": quantity},
+			"$set": set,
+		},
+		opts,
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge wishlist item: %w", err)
+	}
+
+	for _, item := range doc.Items {
+		if item.ProductID == sameProduct["productid"] && item.VariantID == sameProduct["variantid"] {
+			return &item, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to merge wishlist item: item missing after update")
+}
+
+// RemoveItem removes an item from a wishlist
+func (a *MongoWishlistAdapter) RemoveItem(ctx context.Context, wishlistID, itemID string) error {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.collection.UpdateOne(ctx,
+		a.scope(bson.M{"_id": id, "items.id": itemID}),
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"id": itemID}},
+			"$set":  bson.M{"updatedAt": time.Now().UTC()},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove item from wishlist: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+
+	return nil
+}
+
+// UpdateItem updates the quantity and notes of an item in a wishlist
+func (a *MongoWishlistAdapter) UpdateItem(ctx context.Context, wishlistID, itemID string, quantity int, notes string) (*models.WishlistItem, error) {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc wishlistDocument
+	err = a.collection.FindOneAndUpdate(ctx,
+		a.scope(bson.M{"_id": id, "items.id": itemID}),
+		bson.M{"$set": bson.M{
+			"items.$.quantity": quantity,
+			"items.$.notes":    notes,
+			"updatedAt":        time.Now().UTC(),
+		}},
+		opts,
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update wishlist item: %w", err)
+	}
+
+	for _, item := range doc.Items {
+		if item.ID == itemID {
+			return &item, nil
+		}
+	}
+	return nil, fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+}
+
+// DeleteWishlist deletes a wishlist
+func (a *MongoWishlistAdapter) DeleteWishlist(ctx context.Context, wishlistID string) error {
+	id, err := parseWishlistID(wishlistID)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.collection.DeleteOne(ctx, a.scope(bson.M{"_id": id}))
+	if err != nil {
+		return fmt.Errorf("failed to delete wishlist: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+
+	return nil
+}
+
+// MigrateLegacyWishlists turns wishlist documents written before named wishlists existed
+// into default lists. Those documents were upserted per (tenant, store, customer) and have
+// no name or visibility. The migration is idempotent and returns the number of documents changed.
+func MigrateLegacyWishlists(ctx context.Context, collection *mongo.Collection) (int64, error) {
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"name": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"name":     DefaultWishlistName,
+			"isPublic": false,
+		}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to migrate legacy wishlists: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// WishlistStore identifies a store that has wishlists in the wishlists collection
+type WishlistStore struct {
+	TenantID string `bson:"tenantId"`
+	StoreID  string `bson:"storeId"`
+}
+
+// ListWishlistStores returns the stores that have at least one non-empty wishlist
+func ListWishlistStores(ctx context.Context, collection *mongo.Collection) ([]WishlistStore, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"items.0": bson.M{"$exists": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"tenantId": "$tenantId", "storeId": "$storeId"}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wishlist stores: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Store WishlistStore `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode wishlist stores: %w", err)
+	}
+
+	stores := make([]WishlistStore, 0, len(groups))
+	for _, group := range groups {
+		stores = append(stores, group.Store)
+	}
+	return stores, nil
+}
+
+// ScanStoreWishlists calls fn for every non-empty wishlist of a store
+// Scanning stops at the first error returned by fn
+func ScanStoreWishlists(ctx context.Context, collection *mongo.Collection, tenantID, storeID string, fn func(*models.Wishlist) error) error {
+	filter := bson.M{"tenantId": tenantID, "storeId": storeID, "items.0": bson.M{"$exists": true}}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to query store wishlists: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc wishlistDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode wishlist: %w", err)
+		}
+		if err := fn(doc.toModel()); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Private helper methods
+
+// scope restricts a filter to the connector's tenant and store
+func (a *MongoWishlistAdapter) scope(filter bson.M) bson.M {
+	filter["tenantId"] = a.config.TenantID
+	filter["storeId"] = a.config.StoreID
+	return filter
+}
+
+// parseWishlistID converts a wishlist ID to its ObjectID; malformed IDs are reported as not found
+func parseWishlistID(wishlistID string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(wishlistID)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+	return id, nil
+}
+
+func (d wishlistDocument) toModel() *models.Wishlist {
+	name := d.Name
+	if name == "" {
+		name = DefaultWishlistName
+	}
+	items := d.Items
+	if items == nil {
+		items = []models.WishlistItem{}
+	}
+
+	return &models.Wishlist{
+		ID:         d.ID.Hex(),
+		CustomerID: d.CustomerID,
+		TenantID:   d.TenantID,
+		StoreID:    d.StoreID,
+		Name:       name,
+		Items:      items,
+		IsPublic:   d.IsPublic,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// wishlistAlertDocument is the stored form of a wishlist alert
+type wishlistAlertDocument struct {
+	ID            string        `bson:"_id"`
+	Type          string        `bson:"type"`
+	TenantID      string        `bson:"tenantId"`
+	StoreID       string        `bson:"storeId"`
+	CustomerID    string        `bson:"customerId"`
+	WishlistID    string        `bson:"wishlistId"`
+	ItemID        string        `bson:"itemId"`
+	ProductID     string        `bson:"productId"`
+	VariantID     string        `bson:"variantId,omitempty"`
+	Name          string        `bson:"name"`
+	Price         models.Price  `bson:"price"`
+	PreviousPrice *models.Price `bson:"previousPrice,omitempty"`
+	CreatedAt     time.Time     `bson:"createdAt"`
+}
+
+// wishlistItemStateDocument is the last seen price and stock of one wishlist item
+type wishlistItemStateDocument struct {
+	ID        string       `bson:"_id"`
+	Price     models.Price `bson:"price"`
+	Available *bool        `bson:"available,omitempty"`
+	CheckedAt time.Time    `bson:"checkedAt"`
+}
+
+// WishlistAlertStore keeps wishlist alerts in the wishlist_alerts collection and the
+// last seen state of each watched item in the wishlist_item_states collection
+type WishlistAlertStore struct {
+	alerts *mongo.Collection
+	states *mongo.Collection
+}
+
+// NewWishlistAlertStore creates an alert store on the given collections
+func NewWishlistAlertStore(alerts, states *mongo.Collection) *WishlistAlertStore {
+	return &WishlistAlertStore{alerts: alerts, states: states}
+}
+
+// Record stores a new alert and returns it with its generated ID
+func (s *WishlistAlertStore) Record(ctx context.Context, alert models.WishlistAlert) (*models.WishlistAlert, error) {
+	doc := wishlistAlertDocument{
+		ID:            uuid.NewString(),
+		Type:          alert.Type,
+		TenantID:      alert.TenantID,
+		StoreID:       alert.StoreID,
+		CustomerID:    alert.CustomerID,
+		WishlistID:    alert.WishlistID,
+		ItemID:        alert.ItemID,
+		ProductID:     alert.ProductID,
+		VariantID:     alert.VariantID,
+		Name:          alert.Name,
+		Price:         alert.Price,
+		PreviousPrice: alert.PreviousPrice,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if _, err := s.alerts.InsertOne(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to record wishlist alert: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// List retrieves a customer's alerts in a store, newest first
+// Only alerts created after since are returned when since is not zero
+func (s *WishlistAlertStore) List(ctx context.Context, tenantID, storeID, customerID string, since time.Time, limit int) ([]models.WishlistAlert, error) {
+	filter := bson.M{"tenantId": tenantID, "storeId": storeID, "customerId": customerID}
+	if !since.IsZero() {
+		filter["createdAt"] = bson.M{"$gt": since.UTC()}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.alerts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlist alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []wishlistAlertDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode wishlist alerts: %w", err)
+	}
+
+	alerts := make([]models.WishlistAlert, 0, len(docs))
+	for _, doc := range docs {
+		alerts = append(alerts, *doc.toModel())
+	}
+	return alerts, nil
+}
+
+// GetItemState retrieves the last seen state of a wishlist item
+// Returns nil without error when the item has not been checked yet
+func (s *WishlistAlertStore) GetItemState(ctx context.Context, tenantID, storeID, wishlistID, itemID string) (*models.WishlistItemState, error) {
+	var doc wishlistItemStateDocument
+	err := s.states.FindOne(ctx, bson.M{"_id": itemStateKey(tenantID, storeID, wishlistID, itemID)}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist item state: %w", err)
+	}
+
+	return &models.WishlistItemState{
+		Price:     doc.Price,
+		Available: doc.Available,
+		CheckedAt: doc.CheckedAt,
+	}, nil
+}
+
+// SaveItemState stores the last seen state of a wishlist item
+func (s *WishlistAlertStore) SaveItemState(ctx context.Context, tenantID, storeID, wishlistID, itemID string, state models.WishlistItemState) error {
+	key := itemStateKey(tenantID, storeID, wishlistID, itemID)
+	doc := wishlistItemStateDocument{
+		ID:        key,
+		Price:     state.Price,
+		Available: state.Available,
+		CheckedAt: state.CheckedAt.UTC(),
+	}
+
+	_, err := s.states.ReplaceOne(ctx, bson.M{"_id": key}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save wishlist item state: %w", err)
+	}
+	return nil
+}
+
+// itemStateKey builds the state document ID of a wishlist item
+func itemStateKey(tenantID, storeID, wishlistID, itemID string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", tenantID, storeID, wishlistID, itemID)
+}
+
+func (d wishlistAlertDocument) toModel() *models.WishlistAlert {
+	return &models.WishlistAlert{
+		ID:            d.ID,
+		Type:          d.Type,
+		TenantID:      d.TenantID,
+		StoreID:       d.StoreID,
+		CustomerID:    d.CustomerID,
+		WishlistID:    d.WishlistID,
+		ItemID:        d.ItemID,
+		ProductID:     d.ProductID,
+		VariantID:     d.VariantID,
+		Name:          d.Name,
+		Price:         d.Price,
+		PreviousPrice: d.PreviousPrice,
+		CreatedAt:     d.CreatedAt,
+	}
+}
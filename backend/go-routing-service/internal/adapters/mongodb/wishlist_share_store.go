@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// wishlistShareDocument is the stored form of a wishlist share link
+type wishlistShareDocument struct {
+	ID         string     `bson:"_id"`
+	TenantID   string     `bson:"tenantId"`
+	StoreID    string     `bson:"storeId"`
+	WishlistID string     `bson:"wishlistId"`
+	CustomerID string     `bson:"customerId"`
+	CreatedAt  time.Time  `bson:"createdAt"`
+	ExpiresAt  time.Time  `bson:"expiresAt"`
+	RevokedAt  *time.Time `bson:"revokedAt,omitempty"`
+}
+
+// WishlistShareStore keeps wishlist share links in the wishlist_shares collection
+// A share link is valid only while its document exists and is not revoked
+type WishlistShareStore struct {
+	collection *mongo.Collection
+}
+
+// NewWishlistShareStore creates a share store on the given collection
+func NewWishlistShareStore(collection *mongo.Collection) *WishlistShareStore {
+	return &WishlistShareStore{collection: collection}
+}
+
+// Create stores a new share link and returns it with its generated ID
+func (s *WishlistShareStore) Create(ctx context.Context, tenantID, storeID, wishlistID, customerID string, expiresAt time.Time) (*models.WishlistShare, error) {
+	doc := wishlistShareDocument{
+		ID:         uuid.NewString(),
+		TenantID:   tenantID,
+		StoreID:    storeID,
+		WishlistID: wishlistID,
+		CustomerID: customerID,
+		CreatedAt:  time.Now().UTC(),
+		ExpiresAt:  expiresAt.UTC(),
+	}
+
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return nil, fmt.Errorf("failed to create wishlist share: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// Get retrieves a share link by ID
+func (s *WishlistShareStore) Get(ctx context.Context, shareID string) (*models.WishlistShare, error) {
+	var doc wishlistShareDocument
+	err := s.collection.FindOne(ctx, bson.M{"_id": shareID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("wishlist share %s: %w", shareID, ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist share: %w", err)
+	}
+
+	return doc.toModel(), nil
+}
+
+// List retrieves the share links of a wishlist, newest first
+func (s *WishlistShareStore) List(ctx context.Context, tenantID, storeID, wishlistID string) ([]models.WishlistShare, error) {
+	filter := bson.M{"tenantId": tenantID, "storeId": storeID, "wishlistId": wishlistID}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wishlist shares: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []wishlistShareDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode wishlist shares: %w", err)
+	}
+
+	shares := make([]models.WishlistShare, 0, len(docs))
+	for _, doc := range docs {
+		shares = append(shares, *doc.toModel())
+	}
+	return shares, nil
+}
+
+// Revoke marks a share link of a wishlist as revoked
+// Revoking an already revoked link keeps the original revocation time
+func (s *WishlistShareStore) Revoke(ctx context.Context, tenantID, storeID, wishlistID, shareID string) error {
+	filter := bson.M{"_id": shareID, "tenantId": tenantID, "storeId": storeID, "wishlistId": wishlistID}
+
+	active := bson.M{"revokedAt": bson.M{"$exists": false}}
+	for key, value := range filter {
+		active[key] = value
+	}
+
+	result, err := s.collection.UpdateOne(ctx, active, bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}})
+	if err != nil {
+		return fmt.Errorf("failed to revoke wishlist share: %w", err)
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	// Not active: either already revoked or unknown
+	count, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to revoke wishlist share: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("wishlist share %s: %w", shareID, ports.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (d wishlistShareDocument) toModel() *models.WishlistShare {
+	return &models.WishlistShare{
+		ID:         d.ID,
+		TenantID:   d.TenantID,
+		StoreID:    d.StoreID,
+		WishlistID: d.WishlistID,
+		CustomerID: d.CustomerID,
+		CreatedAt:  d.CreatedAt,
+		ExpiresAt:  d.ExpiresAt,
+		RevokedAt:  d.RevokedAt,
+	}
+}
@@ -0,0 +1,136 @@
+// Package oauth provides OAuth2 helpers shared by HTTP-based adapters.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenResponse represents an OAuth2 token endpoint response
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// ClientCredentials acquires and caches tokens using the OAuth2 client credentials grant
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+
+	httpClient *http.Client
+
+	// Thread-safe token storage
+	mu          sync.RWMutex
+	accessToken string
+	expiresAt   time.Time
+
+	// Refresh token if it expires within this duration
+	expiryBuffer time.Duration
+}
+
+// NewClientCredentials creates a token source for the given token endpoint
+func NewClientCredentials(tokenURL, clientID, clientSecret string, httpClient *http.Client) *ClientCredentials {
+	return &ClientCredentials{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		httpClient:   httpClient,
+		expiryBuffer: 1 * time.Minute,
+	}
+}
+
+// Token returns a valid access token, refreshing if necessary
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.RLock()
+	if c.isTokenValid() {
+		token := c.accessToken
+		c.mu.RUnlock()
+		return token, nil
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Double-check after acquiring write lock (another goroutine might have refreshed)
+	if c.isTokenValid() {
+		return c.accessToken, nil
+	}
+
+	return c.refreshToken(ctx)
+}
+
+// Invalidate clears the cached token so the next call fetches a new one
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.accessToken = ""
+	c.expiresAt = time.Time{}
+}
+
+// isTokenValid checks if the current token is valid (must be called with lock held)
+func (c *ClientCredentials) isTokenValid() bool {
+	if c.accessToken == "" {
+		return false
+	}
+	return time.Now().Add(c.expiryBuffer).Before(c.expiresAt)
+}
+
+// refreshToken requests a new token (must be called with write lock held)
+func (c *ClientCredentials) refreshToken(ctx context.Context) (string, error) {
+	formData := url.Values{}
+	formData.Set("grant_type", "client_credentials")
+	formData.Set("client_id", c.ClientID)
+	formData.Set("client_secret", c.ClientSecret)
+	if c.Scope != "" {
+		formData.Set("scope", c.Scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.TokenURL, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("received empty access token")
+	}
+
+	c.accessToken = tokenResp.AccessToken
+	c.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+
+	return c.accessToken, nil
+}
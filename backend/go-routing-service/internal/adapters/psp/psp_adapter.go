@@ -0,0 +1,292 @@
+// Package psp provides a connector for card payment service providers with an Adyen or
+// Stripe style REST API: payments in minor units, manual capture, and HMAC-signed webhooks.
+package psp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// errRejected is returned by doRequest when the PSP rejects a request as invalid
+var errRejected = errors.New("rejected")
+
+// PSPAdapter implements IPaymentConnector for the PSP payment REST API
+type PSPAdapter struct {
+	config          ports.ConnectorConfig
+	baseURL         string
+	merchantAccount string
+	apiKey          string
+	webhookSecret   string
+	httpClient      *http.Client
+	circuitBreaker  *gobreaker.CircuitBreaker
+	now             func() time.Time
+}
+
+// NewPSPAdapter creates a new PSP adapter
+func NewPSPAdapter(config ports.ConnectorConfig) (ports.IConnector, error) {
+	timeout := time.Duration(config.Timeout) * time.Millisecond
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	adapter := &PSPAdapter{
+		config:          config,
+		baseURL:         strings.TrimRight(config.URL, "/"),
+		merchantAccount: config.StoreID,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		now: time.Now,
+	}
+
+	if apiKey, ok := config.Config["apiKey"].(string); ok {
+		adapter.apiKey = apiKey
+	}
+	if merchantAccount, ok := config.Config["merchantAccount"].(string); ok && merchantAccount != "" {
+		adapter.merchantAccount = merchantAccount
+	}
+	if webhookSecret, ok := config.Config["webhookSecret"].(string); ok {
+		adapter.webhookSecret = webhookSecret
+	}
+
+	// Initialize circuit breaker
+	cbSettings := gobreaker.Settings{
+		Name:        fmt.Sprintf("psp-%s", config.StoreID),
+		MaxRequests: 3,
+		Interval:    10 * time.Second,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= 5 && failureRatio >= 0.5
+		},
+		IsSuccessful: func(err error) bool {
+			// Unknown payments and rejected requests are valid answers, not provider failures
+			return err == nil || errors.Is(err, ports.ErrNotFound) || errors.Is(err, ports.ErrInvalidTransition) || errors.Is(err, errRejected)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from_state", from.String()).
+				Str("to_state", to.String()).
+				Msg("PSP circuit breaker state changed")
+		},
+	}
+	adapter.circuitBreaker = gobreaker.NewCircuitBreaker(cbSettings)
+
+	return adapter, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *PSPAdapter) GetDomain() string {
+	return "payment"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *PSPAdapter) GetAdapterType() string {
+	return "PSPAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *PSPAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("storeId", config.StoreID).
+		Str("url", config.URL).
+		Str("merchantAccount", a.merchantAccount).
+		Bool("webhooks", a.webhookSecret != "").
+		Msg("Initializing PSP adapter")
+
+	if a.baseURL == "" {
+		return fmt.Errorf("url is required for PSP adapter")
+	}
+	if a.apiKey == "" {
+		return fmt.Errorf("apiKey is required for PSP adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck verifies the PSP API is reachable
+func (a *PSPAdapter) HealthCheck(ctx context.Context) error {
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		return nil, a.doRequest(ctx, "GET", "/v1/health", "", nil, nil)
+	})
+	return err
+}
+
+// Close gracefully shuts down the connector
+func (a *PSPAdapter) Close() error {
+	log.Info().Str("storeId", a.config.StoreID).Msg("Closing PSP adapter")
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// Authorize reserves an amount on the customer's payment method
+// The request carries an idempotency key derived from the reference, amount and token, so a
+// retried authorization is answered with the original payment
+func (a *PSPAdapter) Authorize(ctx context.Context, req models.PaymentRequest) (*models.Payment, error) {
+	if req.Reference == "" || req.Currency == "" || req.PaymentToken == "" {
+		return nil, fmt.Errorf("reference, currency and paymentToken are required")
+	}
+
+	body := transformPaymentRequest(req)
+	key := idempotencyKey("authorize", body.MerchantReference, fmt.Sprint(body.Amount.Value), body.Amount.Currency, req.PaymentToken)
+
+	return a.paymentRequest(ctx, "POST", a.paymentsPath(), key, body, "authorize payment")
+}
+
+// Capture collects up to the authorized amount
+func (a *PSPAdapter) Capture(ctx context.Context, paymentID string, amount float64) (*models.Payment, error) {
+	payment, err := a.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == models.PaymentStatusCaptured {
+		return payment, nil
+	}
+
+	body := PSPAmountRequest{Amount: PSPAmount{Value: toMinorUnits(amount, payment.Currency), Currency: payment.Currency}}
+	return a.paymentRequest(ctx, "POST", a.paymentPath(paymentID)+"/captures", "", body, "capture payment")
+}
+
+// Void releases an authorization that was not captured
+func (a *PSPAdapter) Void(ctx context.Context, paymentID string) (*models.Payment, error) {
+	return a.paymentRequest(ctx, "POST", a.paymentPath(paymentID)+"/cancels", "", nil, "void payment")
+}
+
+// Refund returns part or all of the captured amount
+func (a *PSPAdapter) Refund(ctx context.Context, paymentID string, amount float64) (*models.Payment, error) {
+	payment, err := a.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := PSPAmountRequest{Amount: PSPAmount{Value: toMinorUnits(amount, payment.Currency), Currency: payment.Currency}}
+	return a.paymentRequest(ctx, "POST", a.paymentPath(paymentID)+"/refunds", "", body, "refund payment")
+}
+
+// GetPayment retrieves a payment and its current status
+func (a *PSPAdapter) GetPayment(ctx context.Context, paymentID string) (*models.Payment, error) {
+	return a.paymentRequest(ctx, "GET", a.paymentPath(paymentID), "", nil, "get payment")
+}
+
+// ParseWebhook verifies the notification's Psp-Signature header and decodes the payment it reports
+func (a *PSPAdapter) ParseWebhook(ctx context.Context, header http.Header, body []byte) (*models.PaymentEvent, error) {
+	if a.webhookSecret == "" {
+		return nil, fmt.Errorf("webhookSecret is not configured for PSP adapter: %w", ports.ErrInvalidSignature)
+	}
+	if err := verifyWebhook(a.webhookSecret, header.Get(SignatureHeader), body, a.now()); err != nil {
+		return nil, err
+	}
+
+	var event PSPEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode PSP webhook: %w", err)
+	}
+	if event.ID == "" || event.Data.ID == "" {
+		return nil, fmt.Errorf("PSP webhook has no event or payment ID")
+	}
+
+	return &models.PaymentEvent{
+		ID:      event.ID,
+		Type:    event.Type,
+		Payment: *transformPayment(event.Data),
+	}, nil
+}
+
+// Private helper methods
+
+func (a *PSPAdapter) paymentsPath() string {
+	return fmt.Sprintf("/v1/merchants/%s/payments", url.PathEscape(a.merchantAccount))
+}
+
+func (a *PSPAdapter) paymentPath(paymentID string) string {
+	return a.paymentsPath() + "/" + url.PathEscape(paymentID)
+}
+
+// paymentRequest calls an endpoint that responds with a payment
+func (a *PSPAdapter) paymentRequest(ctx context.Context, method, path, idempotencyKey string, body interface{}, action string) (*models.Payment, error) {
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var payment PSPPayment
+		if err := a.doRequest(ctx, method, path, idempotencyKey, body, &payment); err != nil {
+			return nil, err
+		}
+		return payment, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s with PSP: %w", action, err)
+	}
+
+	return transformPayment(result.(PSPPayment)), nil
+}
+
+// doRequest calls the PSP API
+// 404 is reported as ports.ErrNotFound, 409 as ports.ErrInvalidTransition, and 400 and 422 as errRejected
+func (a *PSPAdapter) doRequest(ctx context.Context, method, path, idempotencyKey string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("PSP payment: %w", ports.ErrNotFound)
+	case resp.StatusCode == http.StatusConflict:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PSP rejected request: %s: %w", strings.TrimSpace(string(respBody)), ports.ErrInvalidTransition)
+	case resp.StatusCode == http.StatusBadRequest, resp.StatusCode == http.StatusUnprocessableEntity:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PSP rejected request: %s: %w", strings.TrimSpace(string(respBody)), errRejected)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PSP API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// idempotencyKey derives a stable key from the parts of a request
+func idempotencyKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
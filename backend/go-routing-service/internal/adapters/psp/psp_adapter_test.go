@@ -0,0 +1,175 @@
+package psp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(url string) ports.ConnectorConfig {
+	return ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  "ikea-seattle",
+		Domain:   "payment",
+		URL:      url,
+		Adapter:  "PSPAdapter",
+		Config:   map[string]interface{}{"apiKey": "psp-key", "merchantAccount": "IKEA_US_SEA", "webhookSecret": "whsec-test"},
+		Timeout:  5000,
+	}
+}
+
+func newTestAdapter(t *testing.T, url string) *PSPAdapter {
+	connector, err := NewPSPAdapter(newTestConfig(url))
+	require.NoError(t, err)
+	return connector.(*PSPAdapter)
+}
+
+func TestPSPAdapter_Conformance(t *testing.T) {
+	server := httptest.NewServer(NewStandIn("psp-key"))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	connector, err := NewPSPAdapter(config)
+	require.NoError(t, err)
+
+	conformance.RunPaymentConnectorTests(t, connector.(ports.IPaymentConnector), conformance.PaymentFixture{
+		Config:        config,
+		PaymentToken:  "tok_visa",
+		DeclinedToken: memory.DeclinedPaymentToken,
+		Webhook: func(payment models.Payment) (http.Header, []byte) {
+			body, _ := json.Marshal(PSPEvent{ID: "evt_1", Type: "payment.captured", Data: toPSPPayment(payment)})
+			header := http.Header{}
+			header.Set(SignatureHeader, SignWebhook("whsec-test", body, time.Now()))
+			return header, body
+		},
+	})
+}
+
+func TestPSPAdapter_RequestFormat(t *testing.T) {
+	var received PSPPaymentRequest
+	var key string
+	standIn := NewStandIn("psp-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/merchants/IKEA_US_SEA/payments" {
+			body, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(body, &received))
+			key = r.Header.Get("Idempotency-Key")
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		standIn.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server.URL)
+	payment, err := adapter.Authorize(context.Background(), models.PaymentRequest{
+		Reference: "session-1", Amount: 143.98, Currency: "eur", PaymentToken: "tok_visa",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "session-1", received.MerchantReference)
+	assert.Equal(t, PSPAmount{Value: 14398, Currency: "EUR"}, received.Amount)
+	assert.Equal(t, "manual", received.CaptureMethod)
+	assert.NotEmpty(t, key)
+	assert.Equal(t, 143.98, payment.Amount)
+}
+
+func TestPSPAdapter_ZeroDecimalCurrency(t *testing.T) {
+	assert.Equal(t, int64(1500), toMinorUnits(1500, "JPY"))
+	assert.Equal(t, 1500.0, fromMinorUnits(1500, "jpy"))
+	assert.Equal(t, int64(1999), toMinorUnits(19.99, "USD"))
+	assert.Equal(t, 19.99, fromMinorUnits(1999, "USD"))
+}
+
+func TestPSPAdapter_WebhooksFromStandIn(t *testing.T) {
+	standIn := NewStandIn("psp-key")
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server.URL)
+	events := make(chan *models.PaymentEvent, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event, err := adapter.ParseWebhook(r.Context(), r.Header, body)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		events <- event
+	}))
+	defer receiver.Close()
+	standIn.SendWebhooks(receiver.URL, "whsec-test")
+
+	ctx := context.Background()
+	payment, err := adapter.Authorize(ctx, models.PaymentRequest{Reference: "session-2", Amount: 20, Currency: "EUR", PaymentToken: "tok_visa"})
+	require.NoError(t, err)
+	_, err = adapter.Capture(ctx, payment.ID, 20)
+	require.NoError(t, err)
+
+	statuses := map[string]bool{}
+	for len(statuses) < 2 {
+		select {
+		case event := <-events:
+			assert.Equal(t, payment.ID, event.Payment.ID)
+			assert.Equal(t, "session-2", event.Payment.Reference)
+			statuses[event.Payment.Status] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("missing webhooks, got %v", statuses)
+		}
+	}
+	assert.True(t, statuses[models.PaymentStatusAuthorized])
+	assert.True(t, statuses[models.PaymentStatusCaptured])
+}
+
+func TestPSPAdapter_RejectsForgedWebhooks(t *testing.T) {
+	adapter := newTestAdapter(t, "http://psp.invalid")
+	body := []byte(`{"id":"evt_1","type":"payment.captured","data":{"id":"pay_1","status":"CAPTURED"}}`)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{"missing", ""},
+		{"wrong secret", SignWebhook("another-secret", body, now)},
+		{"stale", SignWebhook("whsec-test", body, now.Add(-10*time.Minute))},
+		{"malformed", "v1=abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set(SignatureHeader, tt.signature)
+			_, err := adapter.ParseWebhook(context.Background(), header, body)
+			assert.ErrorIs(t, err, ports.ErrInvalidSignature)
+		})
+	}
+
+	header := http.Header{}
+	header.Set(SignatureHeader, SignWebhook("whsec-test", body, now))
+	_, err := adapter.ParseWebhook(context.Background(), header, append(body, ' '))
+	assert.ErrorIs(t, err, ports.ErrInvalidSignature, "a modified body must not verify")
+
+	event, err := adapter.ParseWebhook(context.Background(), header, body)
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentStatusCaptured, event.Payment.Status)
+}
+
+func TestPSPAdapter_RejectsWrongAPIKey(t *testing.T) {
+	server := httptest.NewServer(NewStandIn("another-key"))
+	defer server.Close()
+
+	err := newTestAdapter(t, server.URL).HealthCheck(context.Background())
+	assert.ErrorContains(t, err, "status=401")
+}
@@ -0,0 +1,203 @@
+package psp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// standInTenant scopes stand-in payments in the payment store; the PSP itself has no tenants
+const standInTenant = "psp"
+
+// StandIn is a local stand-in for the PSP payment API
+// It implements the subset of endpoints used by PSPAdapter, so the adapter can be exercised in
+// tests and local development without a provider account. Payment token memory.DeclinedPaymentToken
+// is refused; every other token is authorised.
+type StandIn struct {
+	apiKey   string
+	payments *memory.PaymentStore
+	mux      *http.ServeMux
+
+	mu            sync.RWMutex
+	webhookURL    string
+	webhookSecret string
+	client        *http.Client
+}
+
+// NewStandIn creates a PSP stand-in that accepts requests carrying apiKey
+func NewStandIn(apiKey string) *StandIn {
+	s := &StandIn{
+		apiKey:   apiKey,
+		payments: memory.NewPaymentStore(),
+		mux:      http.NewServeMux(),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+
+	s.mux.HandleFunc("GET /v1/health", s.health)
+	s.mux.HandleFunc("POST /v1/merchants/{merchant}/payments", s.authorize)
+	s.mux.HandleFunc("GET /v1/merchants/{merchant}/payments/{paymentId}", s.getPayment)
+	s.mux.HandleFunc("POST /v1/merchants/{merchant}/payments/{paymentId}/captures", s.capture)
+	s.mux.HandleFunc("POST /v1/merchants/{merchant}/payments/{paymentId}/cancels", s.cancel)
+	s.mux.HandleFunc("POST /v1/merchants/{merchant}/payments/{paymentId}/refunds", s.refund)
+
+	return s
+}
+
+// SendWebhooks makes the stand-in notify url of every payment change, signed with secret
+func (s *StandIn) SendWebhooks(url, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookURL = url
+	s.webhookSecret = secret
+}
+
+// ServeHTTP implements http.Handler
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeStandInError(w, http.StatusUnauthorized, "invalid api key")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *StandIn) health(w http.ResponseWriter, r *http.Request) {
+	writeStandInJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+}
+
+func (s *StandIn) authorize(w http.ResponseWriter, r *http.Request) {
+	var req PSPPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.CaptureMethod != "manual" {
+		writeStandInError(w, http.StatusBadRequest, "only captureMethod manual is supported")
+		return
+	}
+
+	payment, err := s.payments.Authorize(standInTenant, r.PathValue("merchant"), models.PaymentRequest{
+		Reference:    req.MerchantReference,
+		Amount:       fromMinorUnits(req.Amount.Value, req.Amount.Currency),
+		Currency:     req.Amount.Currency,
+		PaymentToken: req.PaymentMethod.Token,
+		Metadata:     req.Metadata,
+	})
+	s.respond(w, http.StatusCreated, payment, err)
+}
+
+func (s *StandIn) getPayment(w http.ResponseWriter, r *http.Request) {
+	payment, err := s.payments.Get(standInTenant, r.PathValue("merchant"), r.PathValue("paymentId"))
+	if err != nil {
+		writeStandInStoreError(w, err)
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, toPSPPayment(payment))
+}
+
+func (s *StandIn) capture(w http.ResponseWriter, r *http.Request) {
+	var req PSPAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payment, err := s.payments.Capture(standInTenant, r.PathValue("merchant"), r.PathValue("paymentId"), fromMinorUnits(req.Amount.Value, req.Amount.Currency))
+	s.respond(w, http.StatusOK, payment, err)
+}
+
+func (s *StandIn) cancel(w http.ResponseWriter, r *http.Request) {
+	payment, err := s.payments.Void(standInTenant, r.PathValue("merchant"), r.PathValue("paymentId"))
+	s.respond(w, http.StatusOK, payment, err)
+}
+
+func (s *StandIn) refund(w http.ResponseWriter, r *http.Request) {
+	var req PSPAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payment, err := s.payments.Refund(standInTenant, r.PathValue("merchant"), r.PathValue("paymentId"), fromMinorUnits(req.Amount.Value, req.Amount.Currency))
+	s.respond(w, http.StatusOK, payment, err)
+}
+
+// respond writes the payment of a change and sends its webhook
+func (s *StandIn) respond(w http.ResponseWriter, status int, payment models.Payment, err error) {
+	if err != nil {
+		writeStandInStoreError(w, err)
+		return
+	}
+
+	pspPayment := toPSPPayment(payment)
+	go s.notify(pspPayment)
+	writeStandInJSON(w, status, pspPayment)
+}
+
+// notify posts a signed webhook for the payment, if webhooks are configured
+func (s *StandIn) notify(payment PSPPayment) {
+	s.mu.RLock()
+	url, secret := s.webhookURL, s.webhookSecret
+	s.mu.RUnlock()
+	if url == "" {
+		return
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(PSPEvent{
+		ID:      "evt_" + uuid.New().String(),
+		Type:    "payment." + strings.ToLower(payment.Status),
+		Created: now.Unix(),
+		Data:    payment,
+	})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, SignWebhook(secret, body, now))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Warn().Err(err).Str("paymentId", payment.ID).Msg("PSP stand-in failed to send webhook")
+		return
+	}
+	resp.Body.Close()
+}
+
+func writeStandInStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		writeStandInError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ports.ErrInvalidTransition):
+		writeStandInError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, memory.ErrInvalidPaymentAmount):
+		writeStandInError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func writeStandInError(w http.ResponseWriter, status int, message string) {
+	writeStandInJSON(w, status, map[string]string{"error": message})
+}
+
+func writeStandInJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,158 @@
+package psp
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// PSP payment API structures
+
+// PSPAmount is an amount in the currency's minor units, e.g. cents
+type PSPAmount struct {
+	Value    int64  `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// PSPPaymentMethod is a tokenized payment method from the provider's client SDK
+type PSPPaymentMethod struct {
+	Token string `json:"token"`
+}
+
+// PSPPaymentRequest is the body of a payment authorization
+type PSPPaymentRequest struct {
+	MerchantReference string            `json:"merchantReference"`
+	Amount            PSPAmount         `json:"amount"`
+	PaymentMethod     PSPPaymentMethod  `json:"paymentMethod"`
+	CaptureMethod     string            `json:"captureMethod"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// PSPAmountRequest is the body of a capture or refund
+type PSPAmountRequest struct {
+	Amount PSPAmount `json:"amount"`
+}
+
+// PSPPayment is a payment in the PSP API
+type PSPPayment struct {
+	ID                string    `json:"id"`
+	MerchantReference string    `json:"merchantReference"`
+	Status            string    `json:"status"`
+	Amount            PSPAmount `json:"amount"`
+	AmountCaptured    int64     `json:"amountCaptured"`
+	AmountRefunded    int64     `json:"amountRefunded"`
+	RefusalReason     string    `json:"refusalReason,omitempty"`
+	Created           time.Time `json:"created"`
+	Updated           time.Time `json:"updated"`
+}
+
+// PSPEvent is a webhook notification
+type PSPEvent struct {
+	ID      string     `json:"id"`
+	Type    string     `json:"type"`
+	Created int64      `json:"created"`
+	Data    PSPPayment `json:"data"`
+}
+
+// PSP payment statuses
+const (
+	statusPending    = "PENDING"
+	statusAuthorised = "AUTHORISED"
+	statusCaptured   = "CAPTURED"
+	statusRefunded   = "REFUNDED"
+	statusCancelled  = "CANCELLED"
+	statusRefused    = "REFUSED"
+)
+
+var statusToModel = map[string]string{
+	statusPending:    models.PaymentStatusPending,
+	statusAuthorised: models.PaymentStatusAuthorized,
+	statusCaptured:   models.PaymentStatusCaptured,
+	statusRefunded:   models.PaymentStatusRefunded,
+	statusCancelled:  models.PaymentStatusVoided,
+	statusRefused:    models.PaymentStatusDeclined,
+}
+
+var modelToStatus = map[string]string{
+	models.PaymentStatusPending:    statusPending,
+	models.PaymentStatusAuthorized: statusAuthorised,
+	models.PaymentStatusCaptured:   statusCaptured,
+	models.PaymentStatusRefunded:   statusRefunded,
+	models.PaymentStatusVoided:     statusCancelled,
+	models.PaymentStatusDeclined:   statusRefused,
+}
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = map[string]bool{
+	"ISK": true,
+	"JPY": true,
+	"KRW": true,
+}
+
+// toMinorUnits converts an amount to the currency's minor units
+func toMinorUnits(amount float64, currency string) int64 {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return int64(math.Round(amount))
+	}
+	return int64(math.Round(amount * 100))
+}
+
+// fromMinorUnits converts an amount in the currency's minor units to a decimal amount
+func fromMinorUnits(value int64, currency string) float64 {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return float64(value)
+	}
+	return models.RoundAmount(float64(value) / 100)
+}
+
+// transformPaymentRequest converts an authorization request to the PSP format
+// Payments are authorized only; the service captures them once it has checked the result
+func transformPaymentRequest(req models.PaymentRequest) PSPPaymentRequest {
+	currency := strings.ToUpper(req.Currency)
+	return PSPPaymentRequest{
+		MerchantReference: req.Reference,
+		Amount:            PSPAmount{Value: toMinorUnits(req.Amount, currency), Currency: currency},
+		PaymentMethod:     PSPPaymentMethod{Token: req.PaymentToken},
+		CaptureMethod:     "manual",
+		Metadata:          req.Metadata,
+	}
+}
+
+// transformPayment converts a PSP payment to the domain model
+func transformPayment(p PSPPayment) *models.Payment {
+	status, ok := statusToModel[strings.ToUpper(p.Status)]
+	if !ok {
+		status = models.PaymentStatusPending
+	}
+
+	currency := p.Amount.Currency
+	return &models.Payment{
+		ID:             p.ID,
+		Reference:      p.MerchantReference,
+		Status:         status,
+		Amount:         fromMinorUnits(p.Amount.Value, currency),
+		CapturedAmount: fromMinorUnits(p.AmountCaptured, currency),
+		RefundedAmount: fromMinorUnits(p.AmountRefunded, currency),
+		Currency:       currency,
+		DeclineReason:  p.RefusalReason,
+		CreatedAt:      p.Created,
+		UpdatedAt:      p.Updated,
+	}
+}
+
+// toPSPPayment converts a domain payment to the PSP format
+func toPSPPayment(p models.Payment) PSPPayment {
+	return PSPPayment{
+		ID:                p.ID,
+		MerchantReference: p.Reference,
+		Status:            modelToStatus[p.Status],
+		Amount:            PSPAmount{Value: toMinorUnits(p.Amount, p.Currency), Currency: p.Currency},
+		AmountCaptured:    toMinorUnits(p.CapturedAmount, p.Currency),
+		AmountRefunded:    toMinorUnits(p.RefundedAmount, p.Currency),
+		RefusalReason:     p.DeclineReason,
+		Created:           p.CreatedAt,
+		Updated:           p.UpdatedAt,
+	}
+}
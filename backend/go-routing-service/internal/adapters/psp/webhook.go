@@ -0,0 +1,69 @@
+package psp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// SignatureHeader carries the signature of a webhook notification
+const SignatureHeader = "Psp-Signature"
+
+// webhookTolerance is how old a notification may be before it is rejected as a possible replay
+const webhookTolerance = 5 * time.Minute
+
+// SignWebhook returns the signature header value of a notification body sent at the given time
+// The value has the form "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+func SignWebhook(secret string, body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, webhookMAC(secret, timestamp, body))
+}
+
+// verifyWebhook checks a signature header against the body
+// The header may carry several v1 signatures while the provider rotates its secret
+func verifyWebhook(secret, header string, body []byte, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("malformed %s header: %w", SignatureHeader, ports.ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed %s timestamp: %w", SignatureHeader, ports.ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return fmt.Errorf("webhook timestamp is outside the tolerance of %s: %w", webhookTolerance, ports.ErrInvalidSignature)
+	}
+
+	expected := webhookMAC(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook signature does not match: %w", ports.ErrInvalidSignature)
+}
+
+func webhookMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
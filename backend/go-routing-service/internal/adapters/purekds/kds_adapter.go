@@ -0,0 +1,271 @@
+// Package purekds provides the PureKDS kitchen display system connector.
+package purekds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// errConflict is returned by doRequest when PureKDS responds with 409
+var errConflict = errors.New("conflict")
+
+// PureKDSAdapter implements IKitchenConnector for the PureKDS ticket REST API
+type PureKDSAdapter struct {
+	config         ports.ConnectorConfig
+	baseURL        string
+	storeCode      string
+	apiKey         string
+	httpClient     *http.Client
+	circuitBreaker *gobreaker.CircuitBreaker
+}
+
+// NewPureKDSAdapter creates a new PureKDS adapter
+func NewPureKDSAdapter(config ports.ConnectorConfig) (ports.IConnector, error) {
+	timeout := time.Duration(config.Timeout) * time.Millisecond
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	adapter := &PureKDSAdapter{
+		config:    config,
+		baseURL:   strings.TrimRight(config.URL, "/"),
+		storeCode: config.StoreID,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+
+	if apiKey, ok := config.Config["apiKey"].(string); ok {
+		adapter.apiKey = apiKey
+	}
+	if storeCode, ok := config.Config["storeCode"].(string); ok && storeCode != "" {
+		adapter.storeCode = storeCode
+	}
+
+	// Initialize circuit breaker
+	cbSettings := gobreaker.Settings{
+		Name:        fmt.Sprintf("purekds-%s", config.StoreID),
+		MaxRequests: 3,
+		Interval:    10 * time.Second,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= 5 && failureRatio >= 0.5
+		},
+		IsSuccessful: func(err error) bool {
+			// Unknown tickets and rejected state changes are valid answers, not backend failures
+			return err == nil || errors.Is(err, ports.ErrNotFound) || errors.Is(err, errConflict) || errors.Is(err, ports.ErrInvalidTransition)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from_state", from.String()).
+				Str("to_state", to.String()).
+				Msg("PureKDS circuit breaker state changed")
+		},
+	}
+	adapter.circuitBreaker = gobreaker.NewCircuitBreaker(cbSettings)
+
+	return adapter, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *PureKDSAdapter) GetDomain() string {
+	return "kitchen"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *PureKDSAdapter) GetAdapterType() string {
+	return "PureKDSAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *PureKDSAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("storeId", config.StoreID).
+		Str("url", config.URL).
+		Str("storeCode", a.storeCode).
+		Msg("Initializing PureKDS adapter")
+
+	if a.baseURL == "" {
+		return fmt.Errorf("url is required for PureKDS adapter")
+	}
+	if a.apiKey == "" {
+		return fmt.Errorf("apiKey is required for PureKDS adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck verifies the PureKDS API is reachable
+func (a *PureKDSAdapter) HealthCheck(ctx context.Context) error {
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		return nil, a.doRequest(ctx, "GET", "/api/v1/health", nil, nil)
+	})
+	return err
+}
+
+// Close gracefully shuts down the connector
+func (a *PureKDSAdapter) Close() error {
+	log.Info().Str("storeId", a.config.StoreID).Msg("Closing PureKDS adapter")
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// SubmitOrder creates a kitchen ticket for the order
+// PureKDS rejects a second ticket for the same order with 409, which is treated as success
+func (a *PureKDSAdapter) SubmitOrder(ctx context.Context, orderID string, items []models.OrderLineItem) error {
+	if orderID == "" {
+		return fmt.Errorf("orderId is required")
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("kitchen order %s has no items", orderID)
+	}
+
+	ticket := transformOrder(a.storeCode, orderID, items)
+
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		err := a.doRequest(ctx, "POST", a.ticketsPath(), ticket, nil)
+		if errors.Is(err, errConflict) {
+			log.Info().
+				Str("storeId", a.config.StoreID).
+				Str("orderId", orderID).
+				Msg("PureKDS ticket already exists")
+			return nil, nil
+		}
+		return nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit order to PureKDS: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrderStatus retrieves the kitchen status of an order
+func (a *PureKDSAdapter) GetOrderStatus(ctx context.Context, orderID string) (string, error) {
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var ticket KDSTicket
+		if err := a.doRequest(ctx, "GET", a.ticketPath(orderID), nil, &ticket); err != nil {
+			return nil, err
+		}
+		return ticket, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get kitchen order from PureKDS: %w", err)
+	}
+
+	return transformState(result.(KDSTicket).State), nil
+}
+
+// UpdateOrderStatus updates the kitchen status
+func (a *PureKDSAdapter) UpdateOrderStatus(ctx context.Context, orderID string, status string) error {
+	state, ok := statusToState[status]
+	if !ok {
+		return fmt.Errorf("unknown kitchen status %q", status)
+	}
+
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		err := a.doRequest(ctx, "POST", a.ticketPath(orderID)+"/state", KDSStateChange{State: state}, nil)
+		if errors.Is(err, errConflict) {
+			return nil, fmt.Errorf("%v: %w", err, ports.ErrInvalidTransition)
+		}
+		return nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update kitchen order in PureKDS: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveOrders retrieves the IDs of all open tickets, oldest first
+func (a *PureKDSAdapter) GetActiveOrders(ctx context.Context) ([]string, error) {
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var list KDSTicketList
+		if err := a.doRequest(ctx, "GET", a.ticketsPath()+"?open=true", nil, &list); err != nil {
+			return nil, err
+		}
+		return list.Tickets, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active kitchen orders from PureKDS: %w", err)
+	}
+
+	tickets := result.([]KDSTicket)
+	orderIDs := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		if models.IsActiveKitchenStatus(transformState(ticket.State)) {
+			orderIDs = append(orderIDs, ticket.OrderRef)
+		}
+	}
+	return orderIDs, nil
+}
+
+// Private helper methods
+
+func (a *PureKDSAdapter) ticketsPath() string {
+	return fmt.Sprintf("/api/v1/stores/%s/tickets", url.PathEscape(a.storeCode))
+}
+
+func (a *PureKDSAdapter) ticketPath(orderID string) string {
+	return a.ticketsPath() + "/" + url.PathEscape(orderID)
+}
+
+// doRequest calls the PureKDS API
+// 404 is reported as ports.ErrNotFound and 409 as errConflict
+func (a *PureKDSAdapter) doRequest(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", a.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("PureKDS ticket: %w", ports.ErrNotFound)
+	case resp.StatusCode == http.StatusConflict:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PureKDS rejected request: %s: %w", strings.TrimSpace(string(respBody)), errConflict)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PureKDS API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
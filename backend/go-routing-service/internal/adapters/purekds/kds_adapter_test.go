@@ -0,0 +1,81 @@
+package purekds
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(url string) ports.ConnectorConfig {
+	return ports.ConnectorConfig{
+		TenantID: "ikea",
+		StoreID:  "ikea-seattle",
+		Domain:   "kitchen",
+		URL:      url,
+		Adapter:  "PureKDSAdapter",
+		Config:   map[string]interface{}{"apiKey": "kds-key", "storeCode": "SEA-RESTAURANT"},
+		Timeout:  5000,
+	}
+}
+
+func TestPureKDSAdapter_Conformance(t *testing.T) {
+	server := httptest.NewServer(NewStandIn("kds-key"))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	connector, err := NewPureKDSAdapter(config)
+	require.NoError(t, err)
+
+	conformance.RunKitchenConnectorTests(t, connector.(ports.IKitchenConnector), conformance.KitchenFixture{
+		Config: config,
+	})
+}
+
+func TestPureKDSAdapter_TicketFormat(t *testing.T) {
+	var received KDSTicket
+	standIn := NewStandIn("kds-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/api/v1/stores/SEA-RESTAURANT/tickets" {
+			var ticket KDSTicket
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ticket))
+			received = ticket
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		standIn.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	connector, err := NewPureKDSAdapter(newTestConfig(server.URL))
+	require.NoError(t, err)
+	adapter := connector.(*PureKDSAdapter)
+
+	err = adapter.SubmitOrder(context.Background(), "ORD-1001", []models.OrderLineItem{
+		{ID: "L1", ProductID: "70001", Name: "Veggie balls", Quantity: 0, Category: "restaurant"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ORD-1001", received.OrderRef)
+	assert.Equal(t, "SEA-RESTAURANT", received.StoreCode)
+	assert.Equal(t, []KDSTicketLine{{LineRef: "L1", ItemCode: "70001", Label: "Veggie balls", Qty: 1}}, received.Lines)
+}
+
+func TestPureKDSAdapter_RejectsWrongAPIKey(t *testing.T) {
+	server := httptest.NewServer(NewStandIn("another-key"))
+	defer server.Close()
+
+	connector, err := NewPureKDSAdapter(newTestConfig(server.URL))
+	require.NoError(t, err)
+
+	err = connector.HealthCheck(context.Background())
+	assert.ErrorContains(t, err, "status=401")
+}
@@ -0,0 +1,153 @@
+package purekds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// standInTenant scopes stand-in tickets in the kitchen store; PureKDS itself has no tenants
+const standInTenant = "purekds"
+
+// StandIn is a local stand-in for the PureKDS ticket API
+// It implements the subset of endpoints used by PureKDSAdapter, so the adapter can be
+// exercised in tests and local development without a kitchen installation
+type StandIn struct {
+	apiKey  string
+	tickets *memory.KitchenStore
+	mux     *http.ServeMux
+}
+
+// NewStandIn creates a PureKDS stand-in that accepts requests carrying apiKey
+func NewStandIn(apiKey string) *StandIn {
+	s := &StandIn{
+		apiKey:  apiKey,
+		tickets: memory.NewKitchenStore(),
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /api/v1/health", s.health)
+	s.mux.HandleFunc("POST /api/v1/stores/{storeCode}/tickets", s.createTicket)
+	s.mux.HandleFunc("GET /api/v1/stores/{storeCode}/tickets", s.listTickets)
+	s.mux.HandleFunc("GET /api/v1/stores/{storeCode}/tickets/{orderRef}", s.getTicket)
+	s.mux.HandleFunc("POST /api/v1/stores/{storeCode}/tickets/{orderRef}/state", s.changeState)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Api-Key") != s.apiKey {
+		writeStandInError(w, http.StatusUnauthorized, "invalid api key")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *StandIn) health(w http.ResponseWriter, r *http.Request) {
+	writeStandInJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+}
+
+func (s *StandIn) createTicket(w http.ResponseWriter, r *http.Request) {
+	storeCode := r.PathValue("storeCode")
+
+	var ticket KDSTicket
+	if err := json.NewDecoder(r.Body).Decode(&ticket); err != nil {
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if ticket.OrderRef == "" || len(ticket.Lines) == 0 {
+		writeStandInError(w, http.StatusBadRequest, "orderRef and lines are required")
+		return
+	}
+
+	created, ok := s.tickets.Submit(standInTenant, storeCode, ticket.OrderRef, transformTicketLines(ticket.Lines))
+	if !ok {
+		writeStandInError(w, http.StatusConflict, fmt.Sprintf("ticket for order %s already exists", ticket.OrderRef))
+		return
+	}
+
+	writeStandInJSON(w, http.StatusCreated, toKDSTicket(created))
+}
+
+func (s *StandIn) listTickets(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("open") != "true" {
+		writeStandInError(w, http.StatusBadRequest, "only open=true is supported")
+		return
+	}
+
+	active := s.tickets.Active(standInTenant, r.PathValue("storeCode"))
+	list := KDSTicketList{Tickets: make([]KDSTicket, 0, len(active))}
+	for _, ticket := range active {
+		list.Tickets = append(list.Tickets, toKDSTicket(ticket))
+	}
+
+	writeStandInJSON(w, http.StatusOK, list)
+}
+
+func (s *StandIn) getTicket(w http.ResponseWriter, r *http.Request) {
+	ticket, err := s.tickets.Get(standInTenant, r.PathValue("storeCode"), r.PathValue("orderRef"))
+	if err != nil {
+		writeStandInStoreError(w, err)
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, toKDSTicket(ticket))
+}
+
+func (s *StandIn) changeState(w http.ResponseWriter, r *http.Request) {
+	var change KDSStateChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		writeStandInError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status, ok := stateToStatus[strings.ToUpper(change.State)]
+	if !ok {
+		writeStandInError(w, http.StatusBadRequest, fmt.Sprintf("unknown state %q", change.State))
+		return
+	}
+
+	ticket, err := s.tickets.UpdateStatus(standInTenant, r.PathValue("storeCode"), r.PathValue("orderRef"), status)
+	if err != nil {
+		writeStandInStoreError(w, err)
+		return
+	}
+
+	writeStandInJSON(w, http.StatusOK, toKDSTicket(ticket))
+}
+
+func toKDSTicket(ticket models.KitchenTicket) KDSTicket {
+	kds := transformOrder(ticket.StoreID, ticket.OrderID, ticket.Items)
+	kds.TicketID = "T-" + ticket.OrderID
+	kds.State = statusToState[ticket.Status]
+	kds.CreatedAt = ticket.CreatedAt
+	kds.UpdatedAt = ticket.UpdatedAt
+	return kds
+}
+
+func writeStandInStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		writeStandInError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ports.ErrInvalidTransition):
+		writeStandInError(w, http.StatusConflict, err.Error())
+	default:
+		writeStandInError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func writeStandInError(w http.ResponseWriter, status int, message string) {
+	writeStandInJSON(w, status, map[string]string{"error": message})
+}
+
+func writeStandInJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,116 @@
+package purekds
+
+import (
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// PureKDS ticket API structures
+
+// KDSTicket represents a kitchen ticket in PureKDS
+type KDSTicket struct {
+	TicketID  string          `json:"ticketId,omitempty"`
+	OrderRef  string          `json:"orderRef"`
+	StoreCode string          `json:"storeCode"`
+	State     string          `json:"state,omitempty"`
+	Lines     []KDSTicketLine `json:"lines"`
+	CreatedAt time.Time       `json:"createdAt,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt,omitempty"`
+}
+
+// KDSTicketLine represents a dish on a kitchen ticket
+type KDSTicketLine struct {
+	LineRef  string `json:"lineRef,omitempty"`
+	ItemCode string `json:"itemCode"`
+	Label    string `json:"label"`
+	Qty      int    `json:"qty"`
+}
+
+// KDSTicketList is the response of the ticket listing endpoint
+type KDSTicketList struct {
+	Tickets []KDSTicket `json:"tickets"`
+}
+
+// KDSStateChange is the body of a ticket state update
+type KDSStateChange struct {
+	State string `json:"state"`
+}
+
+// PureKDS ticket states
+const (
+	stateNew        = "NEW"
+	stateInProgress = "IN_PROGRESS"
+	stateReady      = "READY"
+	stateBumped     = "BUMPED"
+	stateVoided     = "VOIDED"
+)
+
+var stateToStatus = map[string]string{
+	stateNew:        models.KitchenStatusReceived,
+	stateInProgress: models.KitchenStatusPreparing,
+	stateReady:      models.KitchenStatusReady,
+	stateBumped:     models.KitchenStatusServed,
+	stateVoided:     models.KitchenStatusCancelled,
+}
+
+var statusToState = map[string]string{
+	models.KitchenStatusReceived:  stateNew,
+	models.KitchenStatusPreparing: stateInProgress,
+	models.KitchenStatusReady:     stateReady,
+	models.KitchenStatusServed:    stateBumped,
+	models.KitchenStatusCancelled: stateVoided,
+}
+
+// Transformation methods: PureKDS → Domain models
+
+// transformState maps a PureKDS state to a kitchen status
+// Unknown states are reported as received so the ticket stays visible to staff
+func transformState(state string) string {
+	if status, ok := stateToStatus[strings.ToUpper(state)]; ok {
+		return status
+	}
+	return models.KitchenStatusReceived
+}
+
+func transformTicketLines(lines []KDSTicketLine) []models.OrderLineItem {
+	items := make([]models.OrderLineItem, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, models.OrderLineItem{
+			ID:       line.LineRef,
+			SKU:      line.ItemCode,
+			Name:     line.Label,
+			Quantity: line.Qty,
+		})
+	}
+	return items
+}
+
+// Transformation methods: Domain models → PureKDS
+
+func transformOrder(storeCode, orderID string, items []models.OrderLineItem) KDSTicket {
+	lines := make([]KDSTicketLine, 0, len(items))
+	for _, item := range items {
+		code := item.SKU
+		if code == "" {
+			code = item.ProductID
+		}
+		qty := item.Quantity
+		if qty <= 0 {
+			qty = 1
+		}
+		lines = append(lines, KDSTicketLine{
+			LineRef:  item.ID,
+			ItemCode: code,
+			Label:    item.Name,
+			Qty:      qty,
+		})
+	}
+
+	return KDSTicket{
+		OrderRef:  orderID,
+		StoreCode: storeCode,
+		Lines:     lines,
+	}
+}
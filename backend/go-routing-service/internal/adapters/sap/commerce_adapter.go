@@ -0,0 +1,564 @@
+package sap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/adapters/oauth"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// errNotFound is returned by doRequest when OCC responds with 404
+// It is ports.ErrNotFound so that handlers can tell a missing entity from a failing backend
+var errNotFound = ports.ErrNotFound
+
+// SAPCommerceAdapter implements IRetailConnector for SAP Commerce Cloud via the OCC v2 REST API
+type SAPCommerceAdapter struct {
+	config         ports.ConnectorConfig
+	baseURL        string
+	occPath        string
+	baseSite       string
+	pointOfService string
+	defaultUserID  string
+	currency       string
+	language       string
+	httpClient     *http.Client
+	circuitBreaker *gobreaker.CircuitBreaker
+	tokens         *oauth.ClientCredentials
+}
+
+// NewSAPCommerceAdapter creates a new SAP Commerce Cloud adapter
+func NewSAPCommerceAdapter(config ports.ConnectorConfig) (ports.IConnector, error) {
+	timeout := time.Duration(config.Timeout) * time.Millisecond
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	adapter := &SAPCommerceAdapter{
+		config:         config,
+		baseURL:        strings.TrimRight(config.URL, "/"),
+		occPath:        "/occ/v2",
+		pointOfService: config.StoreID,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+
+	if baseSite, ok := config.Config["baseSite"].(string); ok {
+		adapter.baseSite = baseSite
+	}
+	if occPath, ok := config.Config["occPath"].(string); ok && occPath != "" {
+		adapter.occPath = "/" + strings.Trim(occPath, "/")
+	}
+	if pos, ok := config.Config["pointOfService"].(string); ok && pos != "" {
+		adapter.pointOfService = pos
+	}
+	if userID, ok := config.Config["defaultUserId"].(string); ok {
+		adapter.defaultUserID = userID
+	}
+	if currency, ok := config.Config["currency"].(string); ok {
+		adapter.currency = currency
+	}
+	if language, ok := config.Config["language"].(string); ok {
+		adapter.language = language
+	}
+
+	// OAuth2 client credentials
+	clientID, _ := config.Config["clientId"].(string)
+	clientSecret, _ := config.Config["clientSecret"].(string)
+	tokenURL, _ := config.Config["tokenUrl"].(string)
+	if tokenURL == "" {
+		tokenURL = adapter.baseURL + "/authorizationserver/oauth/token"
+	}
+	adapter.tokens = oauth.NewClientCredentials(tokenURL, clientID, clientSecret, adapter.httpClient)
+
+	// Initialize circuit breaker
+	cbSettings := gobreaker.Settings{
+		Name:        fmt.Sprintf("sap-commerce-%s", config.StoreID),
+		MaxRequests: 3,
+		Interval:    10 * time.Second,
+		Timeout:     30 * time.Second,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
+			return counts.Requests >= 5 && failureRatio >= 0.5
+		},
+		IsSuccessful: func(err error) bool {
+			// A missing product or order is a valid answer, not a backend failure
+			return err == nil || errors.Is(err, errNotFound)
+		},
+		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+			log.Warn().
+				Str("circuit_breaker", name).
+				Str("from_state", from.String()).
+				Str("to_state", to.String()).
+				Msg("SAP Commerce circuit breaker state changed")
+		},
+	}
+	adapter.circuitBreaker = gobreaker.NewCircuitBreaker(cbSettings)
+
+	return adapter, nil
+}
+
+// GetDomain returns the domain this connector handles
+func (a *SAPCommerceAdapter) GetDomain() string {
+	return "retail"
+}
+
+// GetAdapterType returns the adapter implementation type
+func (a *SAPCommerceAdapter) GetAdapterType() string {
+	return "SAPCommerceAdapter"
+}
+
+// Initialize sets up the connector with configuration
+func (a *SAPCommerceAdapter) Initialize(ctx context.Context, config ports.ConnectorConfig) error {
+	log.Info().
+		Str("storeId", config.StoreID).
+		Str("url", config.URL).
+		Str("baseSite", a.baseSite).
+		Msg("Initializing SAP Commerce adapter")
+
+	if a.baseURL == "" {
+		return fmt.Errorf("url is required for SAP Commerce adapter")
+	}
+	if a.baseSite == "" {
+		return fmt.Errorf("baseSite is required for SAP Commerce adapter")
+	}
+	if a.tokens.ClientID == "" || a.tokens.ClientSecret == "" {
+		return fmt.Errorf("clientId and clientSecret are required for SAP Commerce adapter")
+	}
+
+	return nil
+}
+
+// HealthCheck verifies the connector can obtain a token and that the base site exists
+func (a *SAPCommerceAdapter) HealthCheck(ctx context.Context) error {
+	_, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var sites struct {
+			BaseSites []struct {
+				UID string `json:"uid"`
+			} `json:"baseSites"`
+		}
+		if err := a.doRequest(ctx, "GET", "/basesites", nil, nil, &sites); err != nil {
+			return nil, err
+		}
+		for _, site := range sites.BaseSites {
+			if site.UID == a.baseSite {
+				return nil, nil
+			}
+		}
+		return nil, fmt.Errorf("base site %s not found", a.baseSite)
+	})
+	return err
+}
+
+// Close gracefully shuts down the connector
+func (a *SAPCommerceAdapter) Close() error {
+	log.Info().Str("storeId", a.config.StoreID).Msg("Closing SAP Commerce adapter")
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// GetProducts retrieves products based on filters using the OCC product search
+func (a *SAPCommerceAdapter) GetProducts(ctx context.Context, filters models.ProductFilters) (*models.ProductList, error) {
+	// OCC search cannot filter by a list of codes, so resolve explicit SKUs one by one
+	if len(filters.SKUs) > 0 {
+		return a.getProductsBySKUs(ctx, filters.SKUs)
+	}
+
+	pageSize := filters.Limit
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	query := url.Values{}
+	query.Set("query", a.buildSearchQuery(filters))
+	query.Set("currentPage", strconv.Itoa(filters.Offset/pageSize))
+	query.Set("pageSize", strconv.Itoa(pageSize))
+	query.Set("fields", "FULL")
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var page OCCProductSearchPage
+		if err := a.doRequest(ctx, "GET", "/products/search", query, nil, &page); err != nil {
+			return nil, err
+		}
+		return a.transformProductSearchPage(page, filters), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products from SAP Commerce: %w", err)
+	}
+
+	return result.(*models.ProductList), nil
+}
+
+// GetProduct retrieves a single product by ID, including stock at the store's point of service
+func (a *SAPCommerceAdapter) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	query := url.Values{}
+	query.Set("fields", "FULL")
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var occProduct OCCProduct
+		err := a.doRequest(ctx, "GET", "/products/"+url.PathEscape(productID), query, nil, &occProduct)
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("product not found: %s: %w", productID, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return a.transformProduct(occProduct), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product from SAP Commerce: %w", err)
+	}
+
+	product := result.(*models.Product)
+	a.applyStoreStock(ctx, product)
+
+	return product, nil
+}
+
+// GetProductBySKU retrieves a product by SKU (OCC product codes are SKUs)
+func (a *SAPCommerceAdapter) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return a.GetProduct(ctx, sku)
+}
+
+// GetStoreStock retrieves stock for a product at a specific point of service
+func (a *SAPCommerceAdapter) GetStoreStock(ctx context.Context, productCode, pointOfService string) (*models.InventoryInfo, error) {
+	path := fmt.Sprintf("/products/%s/stock/%s", url.PathEscape(productCode), url.PathEscape(pointOfService))
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var stock OCCStock
+		if err := a.doRequest(ctx, "GET", path, nil, nil, &stock); err != nil {
+			return nil, err
+		}
+		inventory := a.transformStock(stock)
+		inventory.StoreID = pointOfService
+		return inventory, nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store stock from SAP Commerce: %w", err)
+	}
+
+	return result.(*models.InventoryInfo), nil
+}
+
+// CreateOrder creates a cart, adds the line items and places the order
+func (a *SAPCommerceAdapter) CreateOrder(ctx context.Context, orderReq models.OrderRequest) (*models.Order, error) {
+	userID := a.resolveUserID(orderReq)
+	if userID == "" {
+		return nil, fmt.Errorf("customerId is required in order metadata for SAP Commerce orders")
+	}
+	if len(orderReq.LineItems) == 0 {
+		return nil, fmt.Errorf("order must contain at least one line item")
+	}
+
+	userPath := "/users/" + url.PathEscape(userID)
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		// Step 1: create cart
+		var cart OCCCart
+		if err := a.doRequest(ctx, "POST", userPath+"/carts", nil, nil, &cart); err != nil {
+			return nil, fmt.Errorf("create cart: %w", err)
+		}
+
+		// Anonymous carts are addressed by GUID, customer carts by code
+		cartID := cart.Code
+		if userID == "anonymous" {
+			cartID = cart.GUID
+		}
+		cartPath := userPath + "/carts/" + url.PathEscape(cartID)
+
+		// Step 2: add entries
+		for _, item := range orderReq.LineItems {
+			if err := a.doRequest(ctx, "POST", cartPath+"/entries", nil, a.transformCartEntry(item), nil); err != nil {
+				return nil, fmt.Errorf("add cart entry %s: %w", item.SKU, err)
+			}
+		}
+
+		// Step 3: place order
+		// OCC itself has no idempotency keys; the header is for gateways in front of it that honour them
+		query := url.Values{}
+		query.Set("cartId", cartID)
+		query.Set("termsChecked", "true")
+		query.Set("fields", "FULL")
+
+		header := http.Header{}
+		if key := orderReq.IdempotencyKey(); key != "" {
+			header.Set("Idempotency-Key", key)
+		}
+
+		var occOrder OCCOrder
+		if err := a.doRequestWithHeader(ctx, "POST", userPath+"/orders", query, header, nil, &occOrder); err != nil {
+			return nil, fmt.Errorf("place order: %w", err)
+		}
+
+		return a.transformOrder(occOrder), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order in SAP Commerce: %w", err)
+	}
+
+	return result.(*models.Order), nil
+}
+
+// GetOrder retrieves an order by code
+func (a *SAPCommerceAdapter) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	occOrder, err := a.getOCCOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order from SAP Commerce: %w", err)
+	}
+	return a.transformOrder(*occOrder), nil
+}
+
+// GetOrders retrieves the order history for a customer
+func (a *SAPCommerceAdapter) GetOrders(ctx context.Context, customerID string, limit, offset int) (*models.OrderList, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	query := url.Values{}
+	query.Set("currentPage", strconv.Itoa(offset/limit))
+	query.Set("pageSize", strconv.Itoa(limit))
+	query.Set("fields", "DEFAULT")
+
+	path := "/users/" + url.PathEscape(customerID) + "/orders"
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var history OCCOrderHistoryList
+		if err := a.doRequest(ctx, "GET", path, query, nil, &history); err != nil {
+			return nil, err
+		}
+		return a.transformOrderHistoryList(history, customerID, limit, offset), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders from SAP Commerce: %w", err)
+	}
+
+	return result.(*models.OrderList), nil
+}
+
+// UpdateOrderStatus updates the order status
+// OCC only exposes customer-initiated cancellation; other transitions are driven by the backend
+func (a *SAPCommerceAdapter) UpdateOrderStatus(ctx context.Context, orderID string, status models.OrderStatus) error {
+	if status != models.OrderStatusCancelled {
+		return fmt.Errorf("SAP Commerce adapter does not support setting order status %q", status)
+	}
+
+	occOrder, err := a.getOCCOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to update order status in SAP Commerce: %w", err)
+	}
+	if occOrder.User == nil || occOrder.User.UID == "" {
+		return fmt.Errorf("failed to update order status in SAP Commerce: order %s has no owner", orderID)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(occOrder.Entries))
+	for _, entry := range occOrder.Entries {
+		entries = append(entries, map[string]interface{}{
+			"orderEntryNumber": entry.EntryNumber,
+			"quantity":         entry.Quantity,
+		})
+	}
+	payload := map[string]interface{}{
+		"cancellationRequestEntryInputs": entries,
+	}
+
+	path := fmt.Sprintf("/users/%s/orders/%s/cancellation", url.PathEscape(occOrder.User.UID), url.PathEscape(orderID))
+
+	_, err = a.circuitBreaker.Execute(func() (interface{}, error) {
+		return nil, a.doRequest(ctx, "POST", path, nil, payload, nil)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update order status in SAP Commerce: %w", err)
+	}
+
+	return nil
+}
+
+// Private helper methods
+
+func (a *SAPCommerceAdapter) getOCCOrder(ctx context.Context, orderID string) (*OCCOrder, error) {
+	query := url.Values{}
+	query.Set("fields", "FULL")
+
+	result, err := a.circuitBreaker.Execute(func() (interface{}, error) {
+		var occOrder OCCOrder
+		err := a.doRequest(ctx, "GET", "/orders/"+url.PathEscape(orderID), query, nil, &occOrder)
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("order not found: %s: %w", orderID, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &occOrder, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*OCCOrder), nil
+}
+
+func (a *SAPCommerceAdapter) getProductsBySKUs(ctx context.Context, skus []string) (*models.ProductList, error) {
+	products := make([]models.Product, 0, len(skus))
+
+	for _, sku := range skus {
+		product, err := a.GetProductBySKU(ctx, sku)
+		if err != nil {
+			if errors.Is(err, errNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		products = append(products, *product)
+	}
+
+	return &models.ProductList{
+		Products: products,
+		Total:    len(products),
+		Limit:    len(skus),
+		Offset:   0,
+		HasMore:  false,
+	}, nil
+}
+
+// applyStoreStock replaces the online stock with the stock at the store's point of service
+func (a *SAPCommerceAdapter) applyStoreStock(ctx context.Context, product *models.Product) {
+	if a.pointOfService == "" {
+		return
+	}
+
+	inventory, err := a.GetStoreStock(ctx, product.SKU, a.pointOfService)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("productId", product.ID).
+			Str("pointOfService", a.pointOfService).
+			Msg("Failed to get store stock, keeping online stock")
+		return
+	}
+
+	product.Inventory = inventory
+}
+
+// buildSearchQuery builds an OCC search query in the form "<text>:<sort>:<facet>:<value>"
+func (a *SAPCommerceAdapter) buildSearchQuery(filters models.ProductFilters) string {
+	query := filters.SearchTerm + ":relevance"
+	if filters.Category != "" {
+		query += ":category:" + filters.Category
+	}
+	return query
+}
+
+func (a *SAPCommerceAdapter) resolveUserID(orderReq models.OrderRequest) string {
+	if customerID, ok := orderReq.Metadata["customerId"].(string); ok && customerID != "" {
+		return customerID
+	}
+	return a.defaultUserID
+}
+
+// doRequest performs an authenticated OCC request relative to the base site
+// A 401 response invalidates the cached token and retries the request once
+func (a *SAPCommerceAdapter) doRequest(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	return a.doRequestWithHeader(ctx, method, path, query, nil, body, out)
+}
+
+// doRequestWithHeader is doRequest with extra request headers
+func (a *SAPCommerceAdapter) doRequestWithHeader(ctx context.Context, method, path string, query url.Values, header http.Header, body interface{}, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if a.currency != "" {
+		query.Set("curr", a.currency)
+	}
+	if a.language != "" {
+		query.Set("lang", a.language)
+	}
+
+	endpoint := fmt.Sprintf("%s%s/%s%s", a.baseURL, a.occPath, url.PathEscape(a.baseSite), path)
+	if path == "/basesites" {
+		endpoint = a.baseURL + a.occPath + path
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		token, err := a.tokens.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get OAuth token: %w", err)
+		}
+
+		var reader io.Reader
+		if payload != nil {
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/json")
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			a.tokens.Invalidate()
+			continue
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return errNotFound
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("SAP OCC API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+		}
+
+		if out == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+
+	return fmt.Errorf("SAP OCC API error: unauthorized")
+}
@@ -0,0 +1,227 @@
+package sap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const standInToken = "Vx1cQvY2xW0pAp4JpcV3n0Sx3nE"
+
+// occStandIn replays recorded OCC v2 responses from testdata/occ
+type occStandIn struct {
+	t            *testing.T
+	tokenCalls   int32
+	placedOrders int32
+	cancelled    int32
+	orderKey     atomic.Value
+}
+
+func (s *occStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/authorizationserver/oauth/token" {
+		atomic.AddInt32(&s.tokenCalls, 1)
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("client_id") != "amicis" || r.FormValue("client_secret") != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		s.replay(w, http.StatusOK, "token.json")
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+standInToken {
+		http.Error(w, `{"errors":[{"type":"InvalidTokenError"}]}`, http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /occ/v2/basesites":
+		s.replay(w, http.StatusOK, "basesites.json")
+	case "GET /occ/v2/ikea-de/products/search":
+		s.replay(w, http.StatusOK, "products_search.json")
+	case "GET /occ/v2/ikea-de/products/40263848":
+		s.replay(w, http.StatusOK, "product_40263848.json")
+	case "GET /occ/v2/ikea-de/products/99999999":
+		s.replay(w, http.StatusNotFound, "error_unknown_product.json")
+	case "GET /occ/v2/ikea-de/products/40263848/stock/ikea-berlin":
+		s.replay(w, http.StatusOK, "stock_40263848_ikea-berlin.json")
+	case "POST /occ/v2/ikea-de/users/anna.schmidt@example.com/carts":
+		s.replay(w, http.StatusCreated, "cart_created.json")
+	case "POST /occ/v2/ikea-de/users/anna.schmidt@example.com/carts/00001042/entries":
+		s.replay(w, http.StatusOK, "cart_entry_added.json")
+	case "POST /occ/v2/ikea-de/users/anna.schmidt@example.com/orders":
+		if r.URL.Query().Get("cartId") != "00001042" {
+			http.Error(w, `{"errors":[{"type":"CartError"}]}`, http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&s.placedOrders, 1)
+		s.orderKey.Store(r.Header.Get("Idempotency-Key"))
+		s.replay(w, http.StatusCreated, "order_placed.json")
+	case "GET /occ/v2/ikea-de/orders/00005001":
+		s.replay(w, http.StatusOK, "order_placed.json")
+	case "GET /occ/v2/ikea-de/users/anna.schmidt@example.com/orders":
+		s.replay(w, http.StatusOK, "order_history.json")
+	case "POST /occ/v2/ikea-de/users/anna.schmidt@example.com/orders/00005001/cancellation":
+		atomic.AddInt32(&s.cancelled, 1)
+		w.WriteHeader(http.StatusOK)
+	default:
+		s.t.Logf("unexpected OCC request: %s %s", r.Method, r.URL.String())
+		http.NotFound(w, r)
+	}
+}
+
+func (s *occStandIn) replay(w http.ResponseWriter, status int, fixture string) {
+	body, err := os.ReadFile(filepath.Join("testdata", "occ", fixture))
+	require.NoError(s.t, err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func newTestAdapter(t *testing.T) (*SAPCommerceAdapter, *occStandIn, ports.ConnectorConfig) {
+	standIn := &occStandIn{t: t}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	config := ports.ConnectorConfig{
+		TenantID: "ikea-eu",
+		StoreID:  "ikea-berlin",
+		Domain:   "retail",
+		URL:      server.URL,
+		Adapter:  "SAPCommerceAdapter",
+		Config: map[string]interface{}{
+			"baseSite":     "ikea-de",
+			"clientId":     "amicis",
+			"clientSecret": "secret",
+			"currency":     "EUR",
+		},
+		Enabled: true,
+		Timeout: 5000,
+	}
+
+	connector, err := NewSAPCommerceAdapter(config)
+	require.NoError(t, err)
+
+	return connector.(*SAPCommerceAdapter), standIn, config
+}
+
+func TestSAPCommerceAdapter_RetailConformance(t *testing.T) {
+	adapter, _, config := newTestAdapter(t)
+
+	conformance.RunRetailConnectorTests(t, adapter, conformance.RetailFixture{
+		Config:           config,
+		ProductID:        "40263848",
+		SKU:              "40263848",
+		MissingProductID: "99999999",
+		SearchTerm:       "billy",
+		CustomerID:       "anna.schmidt@example.com",
+		OrderRequest: models.OrderRequest{
+			StoreID: "ikea-berlin",
+			LineItems: []models.OrderLineItem{
+				{ProductID: "40263848", SKU: "40263848", Quantity: 2},
+			},
+			Metadata: map[string]interface{}{"customerId": "anna.schmidt@example.com"},
+		},
+	})
+}
+
+func TestSAPCommerceAdapter_Initialize_RequiresCredentials(t *testing.T) {
+	connector, err := NewSAPCommerceAdapter(ports.ConnectorConfig{
+		StoreID: "ikea-berlin",
+		URL:     "https://api.example.com",
+		Config:  map[string]interface{}{"baseSite": "ikea-de"},
+	})
+	require.NoError(t, err)
+
+	err = connector.Initialize(context.Background(), ports.ConnectorConfig{})
+	assert.ErrorContains(t, err, "clientId and clientSecret are required")
+}
+
+func TestSAPCommerceAdapter_GetProduct_UsesStoreStock(t *testing.T) {
+	adapter, standIn, _ := newTestAdapter(t)
+
+	product, err := adapter.GetProduct(context.Background(), "40263848")
+	require.NoError(t, err)
+
+	require.NotNil(t, product.Inventory)
+	assert.Equal(t, 7, product.Inventory.Quantity)
+	assert.True(t, product.Inventory.Available)
+	assert.Equal(t, "ikea-berlin", product.Inventory.StoreID)
+
+	assert.Equal(t, "bookcases", product.Category)
+	assert.Equal(t, models.Price{Amount: 69.99, Currency: "EUR"}, product.Price)
+	require.Len(t, product.Images, 2, "only the product format should be kept")
+	assert.True(t, product.Images[0].IsPrimary)
+	assert.Contains(t, product.Images[0].URL, adapter.baseURL+"/medias/")
+
+	require.Len(t, product.Variants, 2)
+	assert.Equal(t, "40x28x202 cm", product.Variants[1].Attributes["size"])
+	assert.False(t, product.Variants[1].Inventory.Available)
+
+	// Token is cached across calls
+	_, err = adapter.GetProduct(context.Background(), "40263848")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&standIn.tokenCalls))
+}
+
+func TestSAPCommerceAdapter_CreateOrder_RequiresCustomer(t *testing.T) {
+	adapter, standIn, _ := newTestAdapter(t)
+
+	_, err := adapter.CreateOrder(context.Background(), models.OrderRequest{
+		StoreID:   "ikea-berlin",
+		LineItems: []models.OrderLineItem{{SKU: "40263848", Quantity: 1}},
+	})
+	assert.ErrorContains(t, err, "customerId is required")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&standIn.placedOrders))
+}
+
+func TestSAPCommerceAdapter_CreateOrder_MapsOrder(t *testing.T) {
+	adapter, standIn, _ := newTestAdapter(t)
+
+	order, err := adapter.CreateOrder(context.Background(), models.OrderRequest{
+		StoreID:   "ikea-berlin",
+		LineItems: []models.OrderLineItem{{SKU: "40263848", Quantity: 2}},
+		Metadata:  map[string]interface{}{"customerId": "anna.schmidt@example.com", "idempotencyKey": "session-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", standIn.orderKey.Load(), "the idempotency key is sent when the order is placed")
+
+	assert.Equal(t, "00005001", order.OrderNumber)
+	assert.Equal(t, models.OrderStatusPending, order.Status)
+	assert.Equal(t, "anna.schmidt@example.com", order.CustomerID)
+	assert.Equal(t, 139.98, order.Total.Amount)
+	assert.Equal(t, 22.35, order.Tax.Amount)
+	assert.Equal(t, 2026, order.CreatedAt.Year())
+	require.NotNil(t, order.ShippingAddress)
+	assert.Equal(t, "DE", order.ShippingAddress.Country)
+}
+
+func TestSAPCommerceAdapter_UpdateOrderStatus(t *testing.T) {
+	adapter, standIn, _ := newTestAdapter(t)
+	ctx := context.Background()
+
+	require.NoError(t, adapter.UpdateOrderStatus(ctx, "00005001", models.OrderStatusCancelled))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&standIn.cancelled))
+
+	err := adapter.UpdateOrderStatus(ctx, "00005001", models.OrderStatusShipped)
+	assert.ErrorContains(t, err, "does not support")
+}
+
+func TestSAPCommerceAdapter_MapOCCOrderStatus(t *testing.T) {
+	adapter := &SAPCommerceAdapter{}
+
+	assert.Equal(t, models.OrderStatusPending, adapter.mapOCCOrderStatus("CREATED"))
+	assert.Equal(t, models.OrderStatusPaid, adapter.mapOCCOrderStatus("READY_FOR_PICKUP"))
+	assert.Equal(t, models.OrderStatusDelivered, adapter.mapOCCOrderStatus("completed"))
+	assert.Equal(t, models.OrderStatusCancelled, adapter.mapOCCOrderStatus("CANCELLED"))
+	assert.Equal(t, models.OrderStatusPending, adapter.mapOCCOrderStatus("SOMETHING_NEW"))
+}
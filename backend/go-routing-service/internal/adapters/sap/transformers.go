@@ -0,0 +1,435 @@
+package sap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// OCC v2 response structures
+
+// OCCPrice represents a price in OCC format
+type OCCPrice struct {
+	CurrencyISO    string  `json:"currencyIso"`
+	Value          float64 `json:"value"`
+	FormattedValue string  `json:"formattedValue,omitempty"`
+	PriceType      string  `json:"priceType,omitempty"`
+}
+
+// OCCImage represents a product image in OCC format
+type OCCImage struct {
+	URL          string `json:"url"`
+	AltText      string `json:"altText,omitempty"`
+	Format       string `json:"format,omitempty"`    // "product", "thumbnail", "zoom"
+	ImageType    string `json:"imageType,omitempty"` // "PRIMARY", "GALLERY"
+	GalleryIndex int    `json:"galleryIndex,omitempty"`
+}
+
+// OCCStock represents stock information in OCC format
+type OCCStock struct {
+	StockLevelStatus string `json:"stockLevelStatus"` // "inStock", "lowStock", "outOfStock"
+	StockLevel       *int   `json:"stockLevel,omitempty"`
+}
+
+// OCCCategory represents a category reference in OCC format
+type OCCCategory struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+// OCCVariantQualifier represents a single variant attribute
+type OCCVariantQualifier struct {
+	Qualifier string `json:"qualifier"`
+	Name      string `json:"name,omitempty"`
+	Value     string `json:"value"`
+}
+
+// OCCVariantOption represents a purchasable variant of a base product
+type OCCVariantOption struct {
+	Code                    string                `json:"code"`
+	PriceData               *OCCPrice             `json:"priceData,omitempty"`
+	Stock                   *OCCStock             `json:"stock,omitempty"`
+	VariantOptionQualifiers []OCCVariantQualifier `json:"variantOptionQualifiers,omitempty"`
+}
+
+// OCCProduct represents a product in OCC format
+type OCCProduct struct {
+	Code           string             `json:"code"`
+	Name           string             `json:"name"`
+	Summary        string             `json:"summary,omitempty"`
+	Description    string             `json:"description,omitempty"`
+	Price          *OCCPrice          `json:"price,omitempty"`
+	Images         []OCCImage         `json:"images,omitempty"`
+	Categories     []OCCCategory      `json:"categories,omitempty"`
+	Stock          *OCCStock          `json:"stock,omitempty"`
+	VariantOptions []OCCVariantOption `json:"variantOptions,omitempty"`
+	Purchasable    bool               `json:"purchasable"`
+}
+
+// OCCPagination represents OCC paging metadata
+type OCCPagination struct {
+	CurrentPage  int `json:"currentPage"`
+	PageSize     int `json:"pageSize"`
+	TotalPages   int `json:"totalPages"`
+	TotalResults int `json:"totalResults"`
+}
+
+// OCCProductSearchPage represents the response of the product search endpoint
+type OCCProductSearchPage struct {
+	FreeTextSearch string        `json:"freeTextSearch,omitempty"`
+	Products       []OCCProduct  `json:"products"`
+	Pagination     OCCPagination `json:"pagination"`
+}
+
+// OCCCart represents the subset of a cart returned on creation
+type OCCCart struct {
+	Code string `json:"code"`
+	GUID string `json:"guid"`
+}
+
+// OCCOrderEntry represents an order line in OCC format
+type OCCOrderEntry struct {
+	EntryNumber int        `json:"entryNumber"`
+	Product     OCCProduct `json:"product"`
+	Quantity    int        `json:"quantity"`
+	BasePrice   OCCPrice   `json:"basePrice"`
+	TotalPrice  OCCPrice   `json:"totalPrice"`
+}
+
+// OCCCountry represents a country reference
+type OCCCountry struct {
+	ISOCode string `json:"isocode"`
+}
+
+// OCCRegion represents a region reference
+type OCCRegion struct {
+	ISOCodeShort string `json:"isocodeShort"`
+}
+
+// OCCAddress represents an address in OCC format
+type OCCAddress struct {
+	FirstName   string      `json:"firstName,omitempty"`
+	LastName    string      `json:"lastName,omitempty"`
+	CompanyName string      `json:"companyName,omitempty"`
+	Line1       string      `json:"line1"`
+	Line2       string      `json:"line2,omitempty"`
+	Town        string      `json:"town"`
+	PostalCode  string      `json:"postalCode"`
+	Phone       string      `json:"phone,omitempty"`
+	Country     *OCCCountry `json:"country,omitempty"`
+	Region      *OCCRegion  `json:"region,omitempty"`
+}
+
+// OCCUser represents the order owner
+type OCCUser struct {
+	UID  string `json:"uid"`
+	Name string `json:"name,omitempty"`
+}
+
+// OCCOrder represents an order in OCC format
+type OCCOrder struct {
+	Code              string          `json:"code"`
+	GUID              string          `json:"guid,omitempty"`
+	Status            string          `json:"status"`
+	Created           string          `json:"created"`
+	Entries           []OCCOrderEntry `json:"entries"`
+	SubTotal          *OCCPrice       `json:"subTotal,omitempty"`
+	TotalTax          *OCCPrice       `json:"totalTax,omitempty"`
+	DeliveryCost      *OCCPrice       `json:"deliveryCost,omitempty"`
+	TotalPriceWithTax *OCCPrice       `json:"totalPriceWithTax,omitempty"`
+	DeliveryAddress   *OCCAddress     `json:"deliveryAddress,omitempty"`
+	User              *OCCUser        `json:"user,omitempty"`
+	Store             string          `json:"store,omitempty"`
+}
+
+// OCCOrderHistory represents a summarized order in the order history list
+type OCCOrderHistory struct {
+	Code   string    `json:"code"`
+	GUID   string    `json:"guid,omitempty"`
+	Status string    `json:"status"`
+	Placed string    `json:"placed"`
+	Total  *OCCPrice `json:"total,omitempty"`
+}
+
+// OCCOrderHistoryList represents the order history response
+type OCCOrderHistoryList struct {
+	Orders     []OCCOrderHistory `json:"orders"`
+	Pagination OCCPagination     `json:"pagination"`
+}
+
+// occTimeLayout is the timestamp format used by OCC (ISO-8601 without colon in the offset)
+const occTimeLayout = "2006-01-02T15:04:05-0700"
+
+// Transformation methods: OCC → Domain models
+
+func (a *SAPCommerceAdapter) transformProductSearchPage(page OCCProductSearchPage, filters models.ProductFilters) *models.ProductList {
+	products := make([]models.Product, 0, len(page.Products))
+
+	for _, occProduct := range page.Products {
+		product := a.transformProduct(occProduct)
+
+		// OCC search has no price range facet by default, so apply it here
+		if filters.MinPrice != nil && product.Price.Amount < *filters.MinPrice {
+			continue
+		}
+		if filters.MaxPrice != nil && product.Price.Amount > *filters.MaxPrice {
+			continue
+		}
+
+		products = append(products, *product)
+	}
+
+	offset := page.Pagination.CurrentPage * page.Pagination.PageSize
+
+	return &models.ProductList{
+		Products: products,
+		Total:    page.Pagination.TotalResults,
+		Limit:    page.Pagination.PageSize,
+		Offset:   offset,
+		HasMore:  page.Pagination.CurrentPage+1 < page.Pagination.TotalPages,
+	}
+}
+
+func (a *SAPCommerceAdapter) transformProduct(occProduct OCCProduct) *models.Product {
+	product := &models.Product{
+		ID:          occProduct.Code,
+		SKU:         occProduct.Code,
+		Name:        occProduct.Name,
+		Description: occProduct.Description,
+		Images:      make([]models.ProductImage, 0),
+		Variants:    make([]models.ProductVariant, 0),
+		Metadata:    make(map[string]interface{}),
+	}
+
+	if product.Description == "" {
+		product.Description = occProduct.Summary
+	}
+
+	if occProduct.Price != nil {
+		product.Price = a.transformPrice(*occProduct.Price)
+	}
+
+	if len(occProduct.Categories) > 0 {
+		product.Category = occProduct.Categories[0].Code
+	}
+
+	// OCC returns one entry per image format; keep the "product" format only
+	position := 0
+	for _, img := range occProduct.Images {
+		if img.Format != "" && img.Format != "product" {
+			continue
+		}
+		product.Images = append(product.Images, models.ProductImage{
+			URL:       a.absoluteMediaURL(img.URL),
+			AltText:   img.AltText,
+			IsPrimary: img.ImageType == "PRIMARY",
+			Position:  position,
+		})
+		position++
+	}
+
+	for _, option := range occProduct.VariantOptions {
+		variant := models.ProductVariant{
+			ID:         option.Code,
+			SKU:        option.Code,
+			Attributes: make(map[string]string),
+		}
+		if option.PriceData != nil {
+			variant.Price = a.transformPrice(*option.PriceData)
+		}
+		names := make([]string, 0, len(option.VariantOptionQualifiers))
+		for _, q := range option.VariantOptionQualifiers {
+			variant.Attributes[q.Qualifier] = q.Value
+			names = append(names, q.Value)
+		}
+		variant.Name = strings.Join(names, " / ")
+		if option.Stock != nil {
+			variant.Inventory = a.transformStock(*option.Stock)
+		}
+		product.Variants = append(product.Variants, variant)
+	}
+
+	if occProduct.Stock != nil {
+		product.Inventory = a.transformStock(*occProduct.Stock)
+	}
+
+	product.Metadata["purchasable"] = occProduct.Purchasable
+
+	return product
+}
+
+func (a *SAPCommerceAdapter) transformPrice(occPrice OCCPrice) models.Price {
+	return models.Price{
+		Amount:   occPrice.Value,
+		Currency: occPrice.CurrencyISO,
+	}
+}
+
+func (a *SAPCommerceAdapter) transformStock(occStock OCCStock) *models.InventoryInfo {
+	inventory := &models.InventoryInfo{
+		Available: occStock.StockLevelStatus != "outOfStock",
+	}
+	if occStock.StockLevel != nil {
+		inventory.Quantity = *occStock.StockLevel
+	}
+	return inventory
+}
+
+func (a *SAPCommerceAdapter) transformOrder(occOrder OCCOrder) *models.Order {
+	order := &models.Order{
+		ID:          occOrder.Code,
+		OrderNumber: occOrder.Code,
+		StoreID:     a.config.StoreID,
+		Status:      a.mapOCCOrderStatus(occOrder.Status),
+		LineItems:   make([]models.OrderLineItem, 0, len(occOrder.Entries)),
+		Metadata:    make(map[string]interface{}),
+	}
+
+	if occOrder.User != nil {
+		order.CustomerID = occOrder.User.UID
+	}
+	if occOrder.GUID != "" {
+		order.Metadata["guid"] = occOrder.GUID
+	}
+	if occOrder.SubTotal != nil {
+		order.Subtotal = a.transformPrice(*occOrder.SubTotal)
+	}
+	if occOrder.TotalTax != nil {
+		order.Tax = a.transformPrice(*occOrder.TotalTax)
+	}
+	if occOrder.DeliveryCost != nil {
+		order.Shipping = a.transformPrice(*occOrder.DeliveryCost)
+	}
+	if occOrder.TotalPriceWithTax != nil {
+		order.Total = a.transformPrice(*occOrder.TotalPriceWithTax)
+	}
+
+	if createdAt, err := time.Parse(occTimeLayout, occOrder.Created); err == nil {
+		order.CreatedAt = createdAt
+	}
+
+	for _, entry := range occOrder.Entries {
+		lineItem := models.OrderLineItem{
+			ID:         fmt.Sprint(entry.EntryNumber),
+			ProductID:  entry.Product.Code,
+			SKU:        entry.Product.Code,
+			Name:       entry.Product.Name,
+			Quantity:   entry.Quantity,
+			UnitPrice:  a.transformPrice(entry.BasePrice),
+			TotalPrice: a.transformPrice(entry.TotalPrice),
+		}
+		if len(entry.Product.Images) > 0 {
+			lineItem.ImageURL = a.absoluteMediaURL(entry.Product.Images[0].URL)
+		}
+		order.LineItems = append(order.LineItems, lineItem)
+	}
+
+	if occOrder.DeliveryAddress != nil {
+		order.ShippingAddress = a.transformAddress(*occOrder.DeliveryAddress)
+	}
+
+	return order
+}
+
+func (a *SAPCommerceAdapter) transformOrderHistoryList(history OCCOrderHistoryList, customerID string, limit, offset int) *models.OrderList {
+	orders := make([]models.Order, 0, len(history.Orders))
+
+	for _, summary := range history.Orders {
+		order := models.Order{
+			ID:          summary.Code,
+			OrderNumber: summary.Code,
+			CustomerID:  customerID,
+			StoreID:     a.config.StoreID,
+			Status:      a.mapOCCOrderStatus(summary.Status),
+			LineItems:   make([]models.OrderLineItem, 0),
+		}
+		if summary.Total != nil {
+			order.Total = a.transformPrice(*summary.Total)
+		}
+		if placed, err := time.Parse(occTimeLayout, summary.Placed); err == nil {
+			order.CreatedAt = placed
+		}
+		orders = append(orders, order)
+	}
+
+	return &models.OrderList{
+		Orders:  orders,
+		Total:   history.Pagination.TotalResults,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: history.Pagination.CurrentPage+1 < history.Pagination.TotalPages,
+	}
+}
+
+func (a *SAPCommerceAdapter) transformAddress(occAddr OCCAddress) *models.Address {
+	address := &models.Address{
+		FirstName:  occAddr.FirstName,
+		LastName:   occAddr.LastName,
+		Company:    occAddr.CompanyName,
+		Address1:   occAddr.Line1,
+		Address2:   occAddr.Line2,
+		City:       occAddr.Town,
+		PostalCode: occAddr.PostalCode,
+		Phone:      occAddr.Phone,
+	}
+	if occAddr.Country != nil {
+		address.Country = occAddr.Country.ISOCode
+	}
+	if occAddr.Region != nil {
+		address.State = occAddr.Region.ISOCodeShort
+	}
+	return address
+}
+
+func (a *SAPCommerceAdapter) mapOCCOrderStatus(occStatus string) models.OrderStatus {
+	// Map OCC order status to domain status
+	statusMap := map[string]models.OrderStatus{
+		"CREATED":          models.OrderStatusPending,
+		"CHECKED_VALID":    models.OrderStatusProcessing,
+		"PROCESSING":       models.OrderStatusProcessing,
+		"PAYMENT_CAPTURED": models.OrderStatusPaid,
+		"READY":            models.OrderStatusPaid,
+		"READY_FOR_PICKUP": models.OrderStatusPaid,
+		"SHIPPED":          models.OrderStatusShipped,
+		"PICKUP_COMPLETE":  models.OrderStatusDelivered,
+		"COMPLETED":        models.OrderStatusDelivered,
+		"CANCELLING":       models.OrderStatusCancelled,
+		"CANCELLED":        models.OrderStatusCancelled,
+	}
+
+	if status, ok := statusMap[strings.ToUpper(occStatus)]; ok {
+		return status
+	}
+
+	return models.OrderStatusPending
+}
+
+// Transformation methods: Domain → OCC models
+
+func (a *SAPCommerceAdapter) transformCartEntry(item models.OrderLineItem) map[string]interface{} {
+	code := item.SKU
+	if item.VariantID != "" {
+		code = item.VariantID
+	}
+
+	entry := map[string]interface{}{
+		"product":  map[string]interface{}{"code": code},
+		"quantity": item.Quantity,
+	}
+
+	// Scan & Go orders are picked up in the store the customer is shopping in
+	if a.pointOfService != "" {
+		entry["deliveryPointOfService"] = map[string]interface{}{"name": a.pointOfService}
+	}
+
+	return entry
+}
+
+func (a *SAPCommerceAdapter) absoluteMediaURL(mediaURL string) string {
+	if mediaURL == "" || strings.HasPrefix(mediaURL, "http://") || strings.HasPrefix(mediaURL, "https://") {
+		return mediaURL
+	}
+	return a.baseURL + mediaURL
+}
@@ -0,0 +1,326 @@
+// Package cart implements server-side carts whose prices and stock are checked against the store's retail connector.
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/productcache"
+	"github.com/google/uuid"
+)
+
+// MaxLineQuantity is the largest quantity a single cart line may hold
+const MaxLineQuantity = 99
+
+// saveAttempts bounds the read-modify-write retries when a cart is changed concurrently
+const saveAttempts = 3
+
+var (
+	// ErrInvalidQuantity is returned for quantities outside 1..MaxLineQuantity
+	ErrInvalidQuantity = fmt.Errorf("quantity must be between 1 and %d", MaxLineQuantity)
+
+	// ErrUnavailable is returned when a product cannot be supplied in the requested quantity
+	ErrUnavailable = errors.New("product is not available in the requested quantity")
+
+	// ErrEmpty is returned when an empty cart is converted
+	ErrEmpty = errors.New("cart is empty")
+)
+
+// Store persists carts
+// Get returns ports.ErrNotFound when the customer has no cart in the store. Save and Delete
+// return ports.ErrConflict when the stored version differs from cart.Version; Save increments
+// cart.Version on success.
+type Store interface {
+	Get(ctx context.Context, tenantID, storeID, customerID string) (*models.Cart, error)
+	Save(ctx context.Context, cart *models.Cart) error
+	Delete(ctx context.Context, cart *models.Cart) error
+}
+
+// LineChange is a cart line whose price changed when the cart was converted
+type LineChange struct {
+	LineID        string       `json:"lineId"`
+	ProductID     string       `json:"productId"`
+	Name          string       `json:"name"`
+	PreviousPrice models.Price `json:"previousPrice"`
+	Price         models.Price `json:"price"`
+}
+
+// Service manages carts
+// Every read and change re-validates all lines through the retail connector, so prices held
+// by the client are never trusted
+type Service struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewService creates a cart service; carts expire ttl after their last change
+func NewService(store Store, ttl time.Duration) *Service {
+	return &Service{store: store, ttl: ttl}
+}
+
+// Get returns the customer's cart with current prices and stock
+// A customer without a cart gets an empty one, which is not stored
+func (s *Service) Get(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID string) (*models.Cart, error) {
+	cart, err := s.load(ctx, tenantID, storeID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := validate(ctx, retail, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// AddLine adds a product to the cart
+// A product already in the cart with the same variant gets the quantity added to its line
+func (s *Service) AddLine(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID, productID, variantID string, quantity int) (*models.Cart, error) {
+	if quantity < 1 || quantity > MaxLineQuantity {
+		return nil, ErrInvalidQuantity
+	}
+
+	return s.update(ctx, retail, tenantID, storeID, customerID, func(cart *models.Cart) (string, error) {
+		for i := range cart.Lines {
+			line := &cart.Lines[i]
+			if line.ProductID == productID && line.VariantID == variantID {
+				if line.Quantity+quantity > MaxLineQuantity {
+					return "", ErrInvalidQuantity
+				}
+				line.Quantity += quantity
+				return line.ID, nil
+			}
+		}
+
+		line := models.CartLine{
+			ID:        uuid.NewString(),
+			ProductID: productID,
+			VariantID: variantID,
+			Quantity:  quantity,
+			AddedAt:   time.Now().UTC(),
+		}
+		cart.Lines = append(cart.Lines, line)
+		return line.ID, nil
+	})
+}
+
+// UpdateLine sets the quantity of a cart line
+func (s *Service) UpdateLine(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID, lineID string, quantity int) (*models.Cart, error) {
+	if quantity < 1 || quantity > MaxLineQuantity {
+		return nil, ErrInvalidQuantity
+	}
+
+	return s.update(ctx, retail, tenantID, storeID, customerID, func(cart *models.Cart) (string, error) {
+		for i := range cart.Lines {
+			if cart.Lines[i].ID == lineID {
+				cart.Lines[i].Quantity = quantity
+				return lineID, nil
+			}
+		}
+		return "", fmt.Errorf("cart line %s: %w", lineID, ports.ErrNotFound)
+	})
+}
+
+// RemoveLine removes a line from the cart; removing a line that is not there is not an error
+func (s *Service) RemoveLine(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID, lineID string) (*models.Cart, error) {
+	return s.update(ctx, retail, tenantID, storeID, customerID, func(cart *models.Cart) (string, error) {
+		lines := cart.Lines[:0]
+		for _, line := range cart.Lines {
+			if line.ID != lineID {
+				lines = append(lines, line)
+			}
+		}
+		cart.Lines = lines
+		return "", nil
+	})
+}
+
+// Clear deletes the customer's cart
+func (s *Service) Clear(ctx context.Context, tenantID, storeID, customerID string) error {
+	for attempt := 0; attempt < saveAttempts; attempt++ {
+		cart, err := s.store.Get(ctx, tenantID, storeID, customerID)
+		if errors.Is(err, ports.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = s.store.Delete(ctx, cart)
+		if errors.Is(err, ports.ErrConflict) || errors.Is(err, ports.ErrNotFound) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed to clear cart: %w", ports.ErrConflict)
+}
+
+// Prepare re-validates the cart before it is converted into a checkout session or an order
+// It fails with ErrEmpty for an empty cart and with ErrUnavailable when a line cannot be
+// supplied; the returned cart then shows which lines are affected. Price changes since the
+// cart was last stored are returned alongside the cart.
+func (s *Service) Prepare(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID string) (*models.Cart, []LineChange, error) {
+	cart, err := s.load(ctx, tenantID, storeID, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cart.Lines) == 0 {
+		return cart, nil, ErrEmpty
+	}
+
+	previous := make(map[string]models.Price, len(cart.Lines))
+	for _, line := range cart.Lines {
+		previous[line.ID] = line.UnitPrice
+	}
+
+	if _, err := validate(ctx, retail, cart); err != nil {
+		return nil, nil, err
+	}
+	if len(cart.UnavailableLines()) > 0 {
+		return cart, nil, ErrUnavailable
+	}
+
+	changes := []LineChange{}
+	for _, line := range cart.Lines {
+		if old := previous[line.ID]; old.Amount != line.UnitPrice.Amount || old.Currency != line.UnitPrice.Currency {
+			changes = append(changes, LineChange{
+				LineID:        line.ID,
+				ProductID:     line.ProductID,
+				Name:          line.Name,
+				PreviousPrice: old,
+				Price:         line.UnitPrice,
+			})
+		}
+	}
+
+	return cart, changes, nil
+}
+
+// Complete deletes a converted cart
+// A cart changed after Prepare is kept, so lines added in the meantime are not lost
+func (s *Service) Complete(ctx context.Context, cart *models.Cart) error {
+	err := s.store.Delete(ctx, cart)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// load returns the stored cart or a new empty one
+func (s *Service) load(ctx context.Context, tenantID, storeID, customerID string) (*models.Cart, error) {
+	cart, err := s.store.Get(ctx, tenantID, storeID, customerID)
+	if errors.Is(err, ports.ErrNotFound) {
+		now := time.Now().UTC()
+		return &models.Cart{
+			ID:         uuid.NewString(),
+			TenantID:   tenantID,
+			StoreID:    storeID,
+			CustomerID: customerID,
+			Lines:      []models.CartLine{},
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			ExpiresAt:  now.Add(s.ttl),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// update applies a change to the cart, re-validates it and stores it
+// mutate returns the ID of the line it added or changed; that line must be available
+func (s *Service) update(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID, customerID string, mutate func(*models.Cart) (string, error)) (*models.Cart, error) {
+	for attempt := 0; attempt < saveAttempts; attempt++ {
+		cart, err := s.load(ctx, tenantID, storeID, customerID)
+		if err != nil {
+			return nil, err
+		}
+
+		lineID, err := mutate(cart)
+		if err != nil {
+			return nil, err
+		}
+
+		missing, err := validate(ctx, retail, cart)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range cart.Lines {
+			if line.ID != lineID {
+				continue
+			}
+			if missing[line.ProductID] {
+				return nil, fmt.Errorf("product %s: %w", line.ProductID, ports.ErrNotFound)
+			}
+			if !line.Available {
+				return nil, ErrUnavailable
+			}
+		}
+
+		now := time.Now().UTC()
+		cart.UpdatedAt = now
+		cart.ExpiresAt = now.Add(s.ttl)
+
+		err = s.store.Save(ctx, cart)
+		if errors.Is(err, ports.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return cart, nil
+	}
+
+	return nil, fmt.Errorf("failed to update cart: %w", ports.ErrConflict)
+}
+
+// validate refreshes every line from the retail connector and recalculates the totals
+// Lines whose product no longer exists are marked unavailable and reported in the returned set;
+// any other connector error aborts validation
+func validate(ctx context.Context, retail ports.IRetailConnector, cart *models.Cart) (map[string]bool, error) {
+	// Lines are checked against current stock, so cached products must be recent
+	ctx = productcache.WithDataType(ctx, productcache.DataAvailability)
+	products := make(map[string]*models.Product)
+	missing := make(map[string]bool)
+
+	for i := range cart.Lines {
+		line := &cart.Lines[i]
+
+		product, seen := products[line.ProductID]
+		if !seen {
+			var err error
+			product, err = retail.GetProduct(ctx, line.ProductID)
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return nil, fmt.Errorf("failed to get product %s: %w", line.ProductID, err)
+			}
+			products[line.ProductID] = product
+		}
+
+		if product == nil {
+			missing[line.ProductID] = true
+			line.Available = false
+			continue
+		}
+
+		line.SKU = product.SKU
+		line.Name = product.Name
+		line.Category = product.Category
+		line.UnitPrice = product.Price
+		line.ImageURL = ""
+		for _, image := range product.Images {
+			if image.IsPrimary || line.ImageURL == "" {
+				line.ImageURL = image.URL
+			}
+		}
+
+		inventory := product.InventoryFor(line.VariantID)
+		line.Available = inventory == nil || inventory.CanFulfil(line.Quantity)
+	}
+
+	cart.ValidatedAt = time.Now().UTC()
+	cart.Recalculate()
+	return missing, nil
+}
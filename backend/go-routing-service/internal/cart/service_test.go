@@ -0,0 +1,259 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a versioned in-memory Store
+type memoryStore struct {
+	mu    sync.Mutex
+	carts map[string]models.Cart
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{carts: make(map[string]models.Cart)}
+}
+
+func (s *memoryStore) key(tenantID, storeID, customerID string) string {
+	return tenantID + ":" + storeID + ":" + customerID
+}
+
+func (s *memoryStore) Get(ctx context.Context, tenantID, storeID, customerID string) (*models.Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cart, ok := s.carts[s.key(tenantID, storeID, customerID)]
+	if !ok {
+		return nil, ports.ErrNotFound
+	}
+	cart.Lines = append([]models.CartLine(nil), cart.Lines...)
+	return &cart, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, cart *models.Cart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.key(cart.TenantID, cart.StoreID, cart.CustomerID)
+	if s.carts[key].Version != cart.Version {
+		return ports.ErrConflict
+	}
+	cart.Version++
+	stored := *cart
+	stored.Lines = append([]models.CartLine(nil), cart.Lines...)
+	s.carts[key] = stored
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, cart *models.Cart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.key(cart.TenantID, cart.StoreID, cart.CustomerID)
+	stored, ok := s.carts[key]
+	if !ok {
+		return ports.ErrNotFound
+	}
+	if stored.Version != cart.Version {
+		return ports.ErrConflict
+	}
+	delete(s.carts, key)
+	return nil
+}
+
+// fakeRetail serves products from a map; only GetProduct is used by the service
+type fakeRetail struct {
+	ports.IRetailConnector
+	products map[string]*models.Product
+	err      error
+}
+
+func (f *fakeRetail) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	product, ok := f.products[productID]
+	if !ok {
+		return nil, fmt.Errorf("product %s: %w", productID, ports.ErrNotFound)
+	}
+	clone := *product
+	return &clone, nil
+}
+
+func newFakeRetail() *fakeRetail {
+	return &fakeRetail{products: map[string]*models.Product{
+		"1001": {
+			ID:        "1001",
+			SKU:       "BILLY-WHITE-001",
+			Name:      "BILLY Bookcase",
+			Price:     models.Price{Amount: 79.99, Currency: "USD"},
+			Inventory: &models.InventoryInfo{Available: true, Quantity: 5},
+		},
+		"1002": {
+			ID:    "1002",
+			SKU:   "KALLAX-001",
+			Name:  "KALLAX Shelf unit",
+			Price: models.Price{Amount: 59.99, Currency: "USD"},
+		},
+	}}
+}
+
+func TestService_AddLineMergesAndTotals(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newMemoryStore(), time.Hour)
+	retail := newFakeRetail()
+
+	_, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 1)
+	require.NoError(t, err)
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1002", "", 1)
+	require.NoError(t, err)
+	cart, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 2)
+	require.NoError(t, err)
+
+	require.Len(t, cart.Lines, 2)
+	assert.Equal(t, 3, cart.Lines[0].Quantity)
+	assert.Equal(t, "BILLY Bookcase", cart.Lines[0].Name)
+	assert.Equal(t, 4, cart.ItemCount)
+	assert.InDelta(t, 3*79.99+59.99, cart.Subtotal.Amount, 0.001)
+	assert.Equal(t, "USD", cart.Subtotal.Currency)
+	assert.Equal(t, int64(3), cart.Version)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), cart.ExpiresAt, time.Minute)
+}
+
+func TestService_AddLineChecksProduct(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newMemoryStore(), time.Hour)
+	retail := newFakeRetail()
+
+	_, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "missing", "", 1)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	// Only 5 in stock
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 6)
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 0)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	retail.err = errors.New("backend down")
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1002", "", 1)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ports.ErrNotFound)
+}
+
+func TestService_GetRepricesLines(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newMemoryStore(), time.Hour)
+	retail := newFakeRetail()
+
+	_, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 2)
+	require.NoError(t, err)
+
+	retail.products["1001"].Price.Amount = 69.99
+	cart, err := service.Get(ctx, retail, "tenant", "store", "customer")
+	require.NoError(t, err)
+	assert.Equal(t, 69.99, cart.Lines[0].UnitPrice.Amount)
+	assert.InDelta(t, 139.98, cart.Subtotal.Amount, 0.001)
+
+	empty, err := service.Get(ctx, retail, "tenant", "store", "someone-else")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Lines)
+	assert.Equal(t, int64(0), empty.Version)
+}
+
+func TestService_UpdateAndRemoveLine(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newMemoryStore(), time.Hour)
+	retail := newFakeRetail()
+
+	cart, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1002", "", 1)
+	require.NoError(t, err)
+	lineID := cart.Lines[0].ID
+
+	cart, err = service.UpdateLine(ctx, retail, "tenant", "store", "customer", lineID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, cart.Lines[0].Quantity)
+
+	_, err = service.UpdateLine(ctx, retail, "tenant", "store", "customer", "unknown", 1)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	cart, err = service.RemoveLine(ctx, retail, "tenant", "store", "customer", lineID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Lines)
+
+	// Removing again is not an error
+	_, err = service.RemoveLine(ctx, retail, "tenant", "store", "customer", lineID)
+	assert.NoError(t, err)
+}
+
+func TestService_PrepareAndComplete(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	service := NewService(store, time.Hour)
+	retail := newFakeRetail()
+
+	_, _, err := service.Prepare(ctx, retail, "tenant", "store", "customer")
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 2)
+	require.NoError(t, err)
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1002", "", 1)
+	require.NoError(t, err)
+
+	retail.products["1002"].Price.Amount = 49.99
+	cart, changes, err := service.Prepare(ctx, retail, "tenant", "store", "customer")
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 59.99, changes[0].PreviousPrice.Amount)
+	assert.Equal(t, 49.99, changes[0].Price.Amount)
+
+	require.NoError(t, service.Complete(ctx, cart))
+	_, err = store.Get(ctx, "tenant", "store", "customer")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
+
+func TestService_PrepareRejectsUnavailableLines(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(newMemoryStore(), time.Hour)
+	retail := newFakeRetail()
+
+	_, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 2)
+	require.NoError(t, err)
+
+	retail.products["1001"].Inventory = &models.InventoryInfo{Available: false}
+	cart, _, err := service.Prepare(ctx, retail, "tenant", "store", "customer")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	require.Len(t, cart.UnavailableLines(), 1)
+	assert.Equal(t, 0, cart.ItemCount)
+}
+
+func TestService_CompleteKeepsChangedCart(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	service := NewService(store, time.Hour)
+	retail := newFakeRetail()
+
+	_, err := service.AddLine(ctx, retail, "tenant", "store", "customer", "1001", "", 1)
+	require.NoError(t, err)
+	cart, _, err := service.Prepare(ctx, retail, "tenant", "store", "customer")
+	require.NoError(t, err)
+
+	// A line added while the order is being placed
+	_, err = service.AddLine(ctx, retail, "tenant", "store", "customer", "1002", "", 1)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.Complete(ctx, cart), ports.ErrConflict)
+	stored, err := store.Get(ctx, "tenant", "store", "customer")
+	require.NoError(t, err)
+	assert.Len(t, stored.Lines, 2)
+}
@@ -0,0 +1,156 @@
+package checkout
+
+import (
+	"context"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// EventOutbox records domain events together with the state changes that cause them
+type EventOutbox interface {
+	Append(ctx context.Context, events ...models.DomainEvent) error
+	Atomically(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// checkoutStatusEvents maps the session statuses that are published to their event types
+var checkoutStatusEvents = map[string]string{
+	models.CheckoutStatusPaid:      models.EventCheckoutPaid,
+	models.CheckoutStatusCompleted: models.EventCheckoutCompleted,
+	models.CheckoutStatusCancelled: models.EventCheckoutCancelled,
+	models.CheckoutStatusExpired:   models.EventCheckoutExpired,
+}
+
+// checkoutEventData is the payload of checkout events
+type checkoutEventData struct {
+	SessionID   string  `json:"sessionId"`
+	CustomerID  string  `json:"customerId"`
+	Status      string  `json:"status"`
+	Total       float64 `json:"total"`
+	Currency    string  `json:"currency"`
+	PaymentID   string  `json:"paymentId,omitempty"`
+	OrderID     string  `json:"orderId,omitempty"`
+	OrderNumber string  `json:"orderNumber,omitempty"`
+}
+
+// orderEventData is the payload of the OrderCreated event of a session's backend order
+type orderEventData struct {
+	OrderID     string  `json:"orderId"`
+	OrderNumber string  `json:"orderNumber,omitempty"`
+	CustomerID  string  `json:"customerId"`
+	SessionID   string  `json:"sessionId"`
+	Total       float64 `json:"total"`
+	Currency    string  `json:"currency"`
+}
+
+// sessionState is what a session's events are derived from
+type sessionState struct {
+	status      string
+	orderStatus string
+}
+
+// stateOf captures the state of a session before it is changed
+func stateOf(session *models.CheckoutSession) sessionState {
+	state := sessionState{status: session.Status}
+	if session.Order != nil {
+		state.orderStatus = session.Order.Status
+	}
+	return state
+}
+
+// RecordEvents makes the sessions append domain events to outbox with every change
+// It must be called before the sessions are used.
+func (s *Sessions) RecordEvents(outbox EventOutbox) {
+	s.outbox = outbox
+}
+
+// create stores a new session with its CheckoutStarted event
+func (s *Sessions) create(ctx context.Context, session *models.CheckoutSession) error {
+	if s.outbox == nil {
+		return s.store.Create(ctx, session)
+	}
+	return s.outbox.Atomically(ctx, func(ctx context.Context) error {
+		if err := s.store.Create(ctx, session); err != nil {
+			return err
+		}
+		return s.appendEvents(ctx, sessionState{}, session)
+	})
+}
+
+// update stores a changed session with the events of its change from previous
+func (s *Sessions) update(ctx context.Context, session *models.CheckoutSession, previous sessionState) error {
+	if s.outbox == nil {
+		return s.store.Update(ctx, session)
+	}
+
+	// The session's version is only advanced once the transaction commits
+	version := session.Version
+	err := s.outbox.Atomically(ctx, func(ctx context.Context) error {
+		session.Version = version
+		if err := s.store.Update(ctx, session); err != nil {
+			return err
+		}
+		return s.appendEvents(ctx, previous, session)
+	})
+	if err != nil {
+		session.Version = version
+	}
+	return err
+}
+
+// appendEvents appends the events of a session's change from previous
+func (s *Sessions) appendEvents(ctx context.Context, previous sessionState, session *models.CheckoutSession) error {
+	data := checkoutEventData{
+		SessionID:  session.ID,
+		CustomerID: session.CustomerID,
+		Status:     session.Status,
+		Total:      session.Total,
+		Currency:   session.Currency,
+	}
+	if session.Payment != nil {
+		data.PaymentID = session.Payment.PaymentID
+	}
+	if session.Order != nil {
+		data.OrderID = session.Order.OrderID
+		data.OrderNumber = session.Order.OrderNumber
+	}
+
+	var events []models.DomainEvent
+	add := func(eventType, aggregateType, aggregateID string, payload interface{}) error {
+		event, err := models.NewDomainEvent(eventType, aggregateType, aggregateID, session.TenantID, session.StoreID, payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+		return nil
+	}
+
+	if previous.status == "" {
+		if err := add(models.EventCheckoutStarted, models.AggregateCheckoutSession, session.ID, data); err != nil {
+			return err
+		}
+	} else if eventType, ok := checkoutStatusEvents[session.Status]; ok && session.Status != previous.status {
+		if err := add(eventType, models.AggregateCheckoutSession, session.ID, data); err != nil {
+			return err
+		}
+	}
+
+	current := stateOf(session)
+	if current.orderStatus == models.CheckoutOrderSubmitted && previous.orderStatus != models.CheckoutOrderSubmitted {
+		order := orderEventData{
+			OrderID:     session.Order.OrderID,
+			OrderNumber: session.Order.OrderNumber,
+			CustomerID:  session.CustomerID,
+			SessionID:   session.ID,
+			Total:       session.Total,
+			Currency:    session.Currency,
+		}
+		if err := add(models.EventOrderCreated, models.AggregateOrder, session.Order.OrderID, order); err != nil {
+			return err
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	return s.outbox.Append(ctx, events...)
+}
@@ -0,0 +1,87 @@
+package checkout
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pendingEvents(t *testing.T, store *outbox.MemoryStore) []models.DomainEvent {
+	t.Helper()
+	entries, err := store.ListPending(context.Background(), 100)
+	require.NoError(t, err)
+	events := make([]models.DomainEvent, 0, len(entries))
+	for _, entry := range entries {
+		events = append(events, entry.Event)
+	}
+	return events
+}
+
+func TestSessions_RecordsDomainEvents(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	events := outbox.NewMemoryStore()
+	sessions.RecordEvents(events)
+	ctx := context.Background()
+	retail := &orderRetail{}
+
+	session := newPaidSession(t, sessions)
+	_, err := sessions.SubmitOrder(ctx, "tenant", session.ID, retail.resolve)
+	require.NoError(t, err)
+	_, err = sessions.Verify(ctx, "tenant", "store", session.QRToken, "staff-1")
+	require.NoError(t, err)
+
+	recorded := pendingEvents(t, events)
+	types := make([]string, 0, len(recorded))
+	for _, event := range recorded {
+		types = append(types, event.Type)
+	}
+	// Scanning at the gate is not published
+	assert.Equal(t, []string{
+		models.EventCheckoutStarted,
+		models.EventCheckoutPaid,
+		models.EventOrderCreated,
+		models.EventCheckoutCompleted,
+	}, types)
+
+	paid := recorded[1]
+	assert.Equal(t, models.AggregateCheckoutSession, paid.AggregateType)
+	assert.Equal(t, session.ID, paid.AggregateID)
+	assert.Equal(t, "tenant", paid.TenantID)
+	assert.Equal(t, "store", paid.StoreID)
+
+	created := recorded[2]
+	assert.Equal(t, models.AggregateOrder, created.AggregateType)
+	assert.Equal(t, "order-1", created.AggregateID)
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(created.Data, &data))
+	assert.Equal(t, "ORD-0001", data["orderNumber"])
+	assert.Equal(t, session.ID, data["sessionId"])
+	assert.Equal(t, "customer", data["customerId"])
+}
+
+func TestSessions_RecordsNoEventsForUnchangedSessions(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	events := outbox.NewMemoryStore()
+	sessions.RecordEvents(events)
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCancelled)
+	require.NoError(t, err)
+
+	// Repeated and rejected transitions are not published again
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCancelled)
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusPaid)
+	require.Error(t, err)
+
+	recorded := pendingEvents(t, events)
+	require.Len(t, recorded, 2)
+	assert.Equal(t, models.EventCheckoutCancelled, recorded[1].Type)
+}
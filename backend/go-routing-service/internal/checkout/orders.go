@@ -0,0 +1,160 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+const (
+	// MaxOrderAttempts is how often a paid session is submitted before its order is marked failed
+	MaxOrderAttempts = 8
+
+	// orderClaim is how long a submitter holds a session before another one may take over
+	orderClaim = 2 * time.Minute
+
+	// orderRetryBase and orderRetryMax bound the exponential backoff between attempts
+	orderRetryBase = 30 * time.Second
+	orderRetryMax  = 30 * time.Minute
+)
+
+// RetailResolver returns the retail connector of a store
+type RetailResolver func(ctx context.Context, tenantID, storeID string) (ports.IRetailConnector, error)
+
+// SubmitOrder submits a paid session to its store's retail connector and records the order
+// Each attempt first claims the session, so concurrent submitters do not create the order twice.
+// A session whose order was already submitted, or is claimed by another submitter, is returned
+// unchanged. A failed attempt is retried later with backoff until MaxOrderAttempts is reached.
+func (s *Sessions) SubmitOrder(ctx context.Context, tenantID, sessionID string, resolve RetailResolver) (*models.CheckoutSession, error) {
+	claimed := false
+	session, err := s.modify(ctx, tenantID, sessionID, func(session *models.CheckoutSession, now time.Time) (bool, error) {
+		order := session.Order
+		if order == nil {
+			return false, fmt.Errorf("checkout session %s is %s, not paid: %w", sessionID, session.Status, ports.ErrInvalidTransition)
+		}
+		switch {
+		case order.Status == models.CheckoutOrderSubmitted, order.Status == models.CheckoutOrderFailed:
+			return false, nil
+		case order.Status == models.CheckoutOrderSubmitting && now.Before(order.NextAttemptAt):
+			return false, nil
+		}
+
+		order.Status = models.CheckoutOrderSubmitting
+		order.Attempts++
+		order.NextAttemptAt = now.Add(orderClaim)
+		claimed = true
+		return true, nil
+	})
+	if err != nil || !claimed {
+		return session, err
+	}
+	attempt := session.Order.Attempts
+
+	var created *models.Order
+	retail, submitErr := resolve(ctx, tenantID, session.StoreID)
+	if submitErr == nil {
+		created, submitErr = retail.CreateOrder(ctx, session.OrderRequest())
+	}
+
+	session, err = s.modify(ctx, tenantID, sessionID, func(session *models.CheckoutSession, now time.Time) (bool, error) {
+		order := session.Order
+		if submitErr == nil {
+			// Record the order even if another submitter has taken over in the meantime
+			if order.Status == models.CheckoutOrderSubmitted {
+				return false, nil
+			}
+			order.Status = models.CheckoutOrderSubmitted
+			order.OrderID = created.ID
+			order.OrderNumber = created.OrderNumber
+			order.LastError = ""
+			order.SubmittedAt = &now
+			return true, nil
+		}
+
+		if order.Status != models.CheckoutOrderSubmitting || order.Attempts != attempt {
+			return false, nil
+		}
+		order.LastError = submitErr.Error()
+		if order.Attempts >= MaxOrderAttempts {
+			order.Status = models.CheckoutOrderFailed
+		} else {
+			order.Status = models.CheckoutOrderPending
+			order.NextAttemptAt = now.Add(orderRetryDelay(order.Attempts))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if submitErr != nil {
+		return nil, fmt.Errorf("failed to submit order for checkout session %s (attempt %d): %w", sessionID, attempt, submitErr)
+	}
+	return session, nil
+}
+
+// SubmitDueOrders submits up to limit sessions whose order submission is due
+// It returns how many orders were submitted and how many attempts failed
+func (s *Sessions) SubmitDueOrders(ctx context.Context, resolve RetailResolver, limit int) (int, int, error) {
+	due, err := s.store.ListDueOrders(ctx, s.now().UTC(), limit)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	submitted, failed := 0, 0
+	for _, session := range due {
+		updated, err := s.SubmitOrder(ctx, session.TenantID, session.ID, resolve)
+		if err != nil {
+			failed++
+			continue
+		}
+		if updated.Order != nil && updated.Order.Status == models.CheckoutOrderSubmitted {
+			submitted++
+		}
+	}
+	return submitted, failed, nil
+}
+
+// modify applies a change to a session and stores it, retrying on concurrent modification
+// mutate reports whether it changed the session; unchanged sessions are returned without a write
+func (s *Sessions) modify(ctx context.Context, tenantID, sessionID string, mutate func(*models.CheckoutSession, time.Time) (bool, error)) (*models.CheckoutSession, error) {
+	for attempt := 0; attempt < transitionAttempts; attempt++ {
+		session, err := s.store.Get(ctx, tenantID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		previous := stateOf(session)
+		changed, err := mutate(session, s.now().UTC())
+		if err != nil || !changed {
+			return session, err
+		}
+
+		err = s.update(ctx, session, previous)
+		if errors.Is(err, ports.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.changed(ctx, session)
+		return session, nil
+	}
+
+	return nil, fmt.Errorf("failed to update checkout session %s: %w", sessionID, ports.ErrConflict)
+}
+
+// orderRetryDelay returns the wait before the next attempt after the given number of attempts
+func orderRetryDelay(attempts int) time.Duration {
+	delay := orderRetryBase
+	for i := 1; i < attempts && delay < orderRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > orderRetryMax {
+		delay = orderRetryMax
+	}
+	return delay
+}
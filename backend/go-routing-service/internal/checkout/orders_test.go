@@ -0,0 +1,157 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderRetail records submitted orders and fails the first failures calls
+type orderRetail struct {
+	ports.IRetailConnector
+	failures int
+	requests []models.OrderRequest
+}
+
+func (r *orderRetail) CreateOrder(ctx context.Context, orderReq models.OrderRequest) (*models.Order, error) {
+	r.requests = append(r.requests, orderReq)
+	if r.failures > 0 {
+		r.failures--
+		return nil, errors.New("backend unavailable")
+	}
+	n := len(r.requests)
+	return &models.Order{ID: fmt.Sprintf("order-%d", n), OrderNumber: fmt.Sprintf("ORD-%04d", n)}, nil
+}
+
+func (r *orderRetail) resolve(ctx context.Context, tenantID, storeID string) (ports.IRetailConnector, error) {
+	return r, nil
+}
+
+func TestSubmitOrder_IsIdempotent(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+	retail := &orderRetail{}
+	paid := newPaidSession(t, sessions)
+
+	session, err := sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+	require.NoError(t, err)
+	require.NotNil(t, session.Order)
+	assert.Equal(t, models.CheckoutOrderSubmitted, session.Order.Status)
+	assert.Equal(t, "order-1", session.Order.OrderID)
+	assert.Equal(t, "ORD-0001", session.Order.OrderNumber)
+
+	again, err := sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", again.Order.OrderID)
+	require.Len(t, retail.requests, 1)
+
+	req := retail.requests[0]
+	assert.Equal(t, "store", req.StoreID)
+	assert.Equal(t, paid.ID, req.Metadata["idempotencyKey"])
+	require.Len(t, req.LineItems, 1)
+	assert.Equal(t, 2, req.LineItems[0].Quantity)
+}
+
+func TestSubmitOrder_RequiresPayment(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+	retail := &orderRetail{}
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	_, err = sessions.SubmitOrder(ctx, "tenant", session.ID, retail.resolve)
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+	assert.Empty(t, retail.requests)
+}
+
+func TestSubmitOrder_RetriesWithBackoff(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+	retail := &orderRetail{failures: 1}
+	paid := newPaidSession(t, sessions)
+
+	_, err := sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+	assert.Error(t, err)
+
+	session, err := sessions.Get(ctx, "tenant", paid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutOrderPending, session.Order.Status)
+	assert.Equal(t, "backend unavailable", session.Order.LastError)
+	assert.Equal(t, now.Add(orderRetryBase), session.Order.NextAttemptAt)
+
+	// Not due yet
+	submitted, failed, err := sessions.SubmitDueOrders(ctx, retail.resolve, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, submitted+failed)
+
+	*now = now.Add(orderRetryBase)
+	submitted, failed, err = sessions.SubmitDueOrders(ctx, retail.resolve, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, submitted)
+	assert.Equal(t, 0, failed)
+
+	session, err = sessions.Get(ctx, "tenant", paid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutOrderSubmitted, session.Order.Status)
+	assert.Equal(t, 2, session.Order.Attempts)
+	assert.Empty(t, session.Order.LastError)
+}
+
+func TestSubmitOrder_GivesUp(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+	retail := &orderRetail{failures: MaxOrderAttempts}
+	paid := newPaidSession(t, sessions)
+
+	for i := 0; i < MaxOrderAttempts; i++ {
+		_, err := sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+		assert.Error(t, err)
+		*now = now.Add(orderRetryMax)
+	}
+
+	session, err := sessions.Get(ctx, "tenant", paid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutOrderFailed, session.Order.Status)
+
+	_, err = sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+	require.NoError(t, err)
+	assert.Len(t, retail.requests, MaxOrderAttempts)
+}
+
+func TestSubmitOrder_RespectsClaims(t *testing.T) {
+	sessions, store, now := newTestSessions()
+	ctx := context.Background()
+	retail := &orderRetail{}
+	paid := newPaidSession(t, sessions)
+
+	// Another submitter claimed the session and has not finished
+	claimed := store.sessions[paid.ID]
+	claimed.Order = &models.CheckoutOrder{Status: models.CheckoutOrderSubmitting, Attempts: 1, NextAttemptAt: now.Add(orderClaim)}
+	store.sessions[paid.ID] = claimed
+
+	session, err := sessions.SubmitOrder(ctx, "tenant", paid.ID, retail.resolve)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutOrderSubmitting, session.Order.Status)
+	assert.Empty(t, retail.requests)
+
+	// The claim lapses, so the session is taken over
+	*now = now.Add(orderClaim)
+	submitted, _, err := sessions.SubmitDueOrders(ctx, retail.resolve, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, submitted)
+}
+
+func TestOrderRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, orderRetryDelay(1))
+	assert.Equal(t, time.Minute, orderRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, orderRetryDelay(4))
+	assert.Equal(t, orderRetryMax, orderRetryDelay(20))
+}
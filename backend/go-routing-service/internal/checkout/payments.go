@@ -0,0 +1,97 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// ErrPaymentRejected is returned when a payment holding the customer's money cannot pay its
+// checkout session; the caller should void or refund it
+var ErrPaymentRejected = errors.New("payment rejected")
+
+// RecordPayment records the state of a session's in-app payment as reported by the payment provider
+// A captured payment of the session total pays an open session, from pending as well as from
+// scanned_at_gate. An authorized or captured payment that cannot pay the session, because its
+// amount or currency differs, the session is closed, or another payment already paid it, fails
+// with ErrPaymentRejected. Reports that are older than the recorded state are ignored, so
+// notifications may arrive more than once and out of order.
+func (s *Sessions) RecordPayment(ctx context.Context, tenantID, storeID, sessionID string, payment *models.Payment) (*models.CheckoutSession, error) {
+	return s.modify(ctx, tenantID, sessionID, func(session *models.CheckoutSession, now time.Time) (bool, error) {
+		if session.StoreID != storeID || payment.Reference != session.ID {
+			return false, fmt.Errorf("checkout session %s: %w", sessionID, ports.ErrNotFound)
+		}
+
+		current := session.Payment
+		samePayment := current != nil && current.PaymentID == payment.ID
+		if samePayment {
+			if !models.CanTransitionPaymentStatus(current.Status, payment.Status) {
+				return false, nil
+			}
+			if current.Status == payment.Status && current.RefundedAmount == payment.RefundedAmount {
+				return false, nil
+			}
+		}
+
+		// A new authorization, and any capture, must be able to pay the session; later reports
+		// of the payment that paid it, such as refunds, are only recorded
+		paidBySelf := samePayment && (session.Status == models.CheckoutStatusPaid || session.Status == models.CheckoutStatusCompleted)
+		holdsMoney := payment.Status == models.PaymentStatusAuthorized || payment.Status == models.PaymentStatusCaptured
+		if holdsMoney && !paidBySelf && (!samePayment || payment.Status == models.PaymentStatusCaptured) {
+			if err := s.checkPayment(session, payment, now); err != nil {
+				return false, err
+			}
+		}
+		if !holdsMoney && !samePayment && current != nil && current.Status == models.PaymentStatusCaptured {
+			// A failed attempt must not hide the payment that paid the session
+			return false, nil
+		}
+
+		amount := payment.Amount
+		if payment.Status == models.PaymentStatusCaptured || payment.Status == models.PaymentStatusRefunded {
+			amount = payment.CapturedAmount
+		}
+		session.Payment = &models.CheckoutPayment{
+			PaymentID:      payment.ID,
+			Status:         payment.Status,
+			Amount:         amount,
+			RefundedAmount: payment.RefundedAmount,
+			Currency:       payment.Currency,
+			DeclineReason:  payment.DeclineReason,
+			UpdatedAt:      now,
+		}
+		session.UpdatedAt = now
+
+		if payment.Status == models.PaymentStatusCaptured && session.Status != models.CheckoutStatusPaid &&
+			models.CanTransitionCheckoutStatus(session.Status, models.CheckoutStatusPaid) {
+			session.SetStatus(models.CheckoutStatusPaid, now)
+			session.PurgeAt = now.Add(s.retention)
+		}
+		return true, nil
+	})
+}
+
+// checkPayment checks that a new authorized or captured payment can pay the session
+func (s *Sessions) checkPayment(session *models.CheckoutSession, payment *models.Payment, now time.Time) error {
+	if !models.IsOpenCheckoutStatus(session.Status) || session.IsExpired(now) {
+		return fmt.Errorf("checkout session %s is %s: %w", session.ID, session.Status, ErrPaymentRejected)
+	}
+	if current := session.Payment; current != nil && current.Status == models.PaymentStatusCaptured {
+		return fmt.Errorf("checkout session %s was already paid by payment %s: %w", session.ID, current.PaymentID, ErrPaymentRejected)
+	}
+
+	amount := payment.Amount
+	if payment.Status == models.PaymentStatusCaptured {
+		amount = payment.CapturedAmount
+	}
+	if models.RoundAmount(amount) != session.Total || !strings.EqualFold(payment.Currency, session.Currency) {
+		return fmt.Errorf("payment of %.2f %s does not match checkout session total %.2f %s: %w",
+			amount, payment.Currency, session.Total, session.Currency, ErrPaymentRejected)
+	}
+	return nil
+}
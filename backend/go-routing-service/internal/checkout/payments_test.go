@@ -0,0 +1,125 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPayment(sessionID, paymentID, status string, amount float64) *models.Payment {
+	payment := &models.Payment{ID: paymentID, Reference: sessionID, Status: status, Amount: amount, Currency: "USD"}
+	if status == models.PaymentStatusCaptured || status == models.PaymentStatusRefunded {
+		payment.CapturedAmount = amount
+	}
+	return payment
+}
+
+func TestSessions_CapturedPaymentPaysSession(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	session, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusAuthorized, 100))
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusPending, session.Status)
+	assert.Equal(t, models.PaymentStatusAuthorized, session.Payment.Status)
+
+	session, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusCaptured, 100))
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusPaid, session.Status, "an in-app payment does not need a gate scan")
+	assert.Equal(t, models.PaymentStatusCaptured, session.Payment.Status)
+	require.NotNil(t, session.Order)
+	assert.Equal(t, models.CheckoutOrderPending, session.Order.Status)
+
+	// Duplicated and late notifications change nothing
+	version := session.Version
+	session, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusCaptured, 100))
+	require.NoError(t, err)
+	session, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusAuthorized, 100))
+	require.NoError(t, err)
+	assert.Equal(t, version, session.Version)
+	assert.Equal(t, models.PaymentStatusCaptured, session.Payment.Status)
+
+	// A later refund is recorded without touching the session status
+	refunded := testPayment(session.ID, "pay_1", models.PaymentStatusRefunded, 100)
+	refunded.RefundedAmount = 100
+	session, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, refunded)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusPaid, session.Status)
+	assert.Equal(t, models.PaymentStatusRefunded, session.Payment.Status)
+}
+
+func TestSessions_RejectsPaymentsThatCannotPay(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_short", models.PaymentStatusCaptured, 99.99))
+	assert.ErrorIs(t, err, ErrPaymentRejected)
+
+	euro := testPayment(session.ID, "pay_euro", models.PaymentStatusCaptured, 100)
+	euro.Currency = "EUR"
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, euro)
+	assert.ErrorIs(t, err, ErrPaymentRejected)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusCaptured, 100))
+	require.NoError(t, err)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_2", models.PaymentStatusCaptured, 100))
+	assert.ErrorIs(t, err, ErrPaymentRejected, "a session is paid only once")
+
+	// A declined retry does not hide the payment that paid the session
+	read, err := sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_3", models.PaymentStatusDeclined, 100))
+	require.NoError(t, err)
+	assert.Equal(t, "pay_1", read.Payment.PaymentID)
+
+	expiring, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	*now = now.Add(16 * time.Minute)
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", expiring.ID, testPayment(expiring.ID, "pay_4", models.PaymentStatusCaptured, 100))
+	assert.ErrorIs(t, err, ErrPaymentRejected)
+}
+
+func TestSessions_RejectsCaptureAfterCancellation(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusAuthorized, 100))
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCancelled)
+	require.NoError(t, err)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusCaptured, 100))
+	assert.ErrorIs(t, err, ErrPaymentRejected)
+
+	read, err := sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusVoided, 100))
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusCancelled, read.Status)
+	assert.Equal(t, models.PaymentStatusVoided, read.Payment.Status)
+}
+
+func TestSessions_RecordPaymentChecksStore(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "other-store", session.ID, testPayment(session.ID, "pay_1", models.PaymentStatusCaptured, 100))
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	_, err = sessions.RecordPayment(ctx, "tenant", "store", session.ID, testPayment("session-other", "pay_1", models.PaymentStatusCaptured, 100))
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
@@ -0,0 +1,146 @@
+// Package checkout prices checkout sessions on the server from the store's retail connector.
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/productcache"
+	"github.com/amicis/go-routing-service/internal/promotions"
+)
+
+// MaxLineQuantity is the largest quantity a single checkout line may hold
+const MaxLineQuantity = 99
+
+var (
+	// ErrInvalidItem is returned for items without a product reference or with a quantity outside 1..MaxLineQuantity
+	ErrInvalidItem = errors.New("invalid checkout item")
+
+	// ErrMixedCurrency is returned when the products of one checkout are priced in different currencies
+	ErrMixedCurrency = errors.New("products are priced in different currencies")
+)
+
+// Item is a product the client wants to check out
+// Only the product reference and quantity are taken from the client; a missing quantity counts as 1
+type Item struct {
+	ProductID string `json:"productId"`
+	VariantID string `json:"variantId,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Quote is the server-side price breakdown of a checkout
+type Quote struct {
+	Lines         []models.CheckoutLine    `json:"lines"`
+	Subtotal      models.Price             `json:"subtotal"`
+	Discounts     []models.AppliedDiscount `json:"discounts"`
+	DiscountTotal models.Price             `json:"discountTotal"`
+	Total         models.Price             `json:"total"`
+}
+
+// Pricer prices checkouts from the retail connector and validates discount codes
+type Pricer struct {
+	promotions *promotions.Engine
+}
+
+// NewPricer creates a pricer that validates discount codes with the given engine
+func NewPricer(engine *promotions.Engine) *Pricer {
+	return &Pricer{promotions: engine}
+}
+
+// Quote prices every item by SKU (or product ID when no SKU is given), multiplies by quantity
+// and applies the discount codes
+// Unknown products fail with ports.ErrNotFound and rejected codes with promotions.ErrInvalidCode
+func (p *Pricer) Quote(ctx context.Context, retail ports.IRetailConnector, tenantID, storeID string, items []Item, codes []string) (*Quote, error) {
+	quote := &Quote{
+		Lines:     make([]models.CheckoutLine, 0, len(items)),
+		Discounts: []models.AppliedDiscount{},
+	}
+
+	var subtotal float64
+	for _, item := range items {
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		if quantity < 0 || quantity > MaxLineQuantity || (item.SKU == "" && item.ProductID == "") {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidItem, itemRef(item))
+		}
+
+		product, err := lookupProduct(ctx, retail, item)
+		if err != nil {
+			return nil, err
+		}
+
+		if quote.Subtotal.Currency == "" {
+			quote.Subtotal.Currency = product.Price.Currency
+		} else if product.Price.Currency != quote.Subtotal.Currency {
+			return nil, ErrMixedCurrency
+		}
+
+		productID := item.ProductID
+		if productID == "" {
+			productID = product.ID
+		}
+
+		lineTotal := models.RoundAmount(product.Price.Amount * float64(quantity))
+		subtotal += lineTotal
+
+		quote.Lines = append(quote.Lines, models.CheckoutLine{
+			ProductID: productID,
+			VariantID: item.VariantID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+			Quantity:  quantity,
+			UnitPrice: product.Price,
+			LineTotal: models.Price{Amount: lineTotal, Currency: product.Price.Currency},
+		})
+	}
+	quote.Subtotal.Amount = models.RoundAmount(subtotal)
+
+	discounts, err := p.promotions.Apply(ctx, tenantID, storeID, quote.Subtotal, codes)
+	if err != nil {
+		return nil, err
+	}
+	quote.Discounts = discounts
+
+	var discountTotal float64
+	for _, discount := range discounts {
+		discountTotal += discount.Amount.Amount
+	}
+	quote.DiscountTotal = models.Price{Amount: models.RoundAmount(discountTotal), Currency: quote.Subtotal.Currency}
+	quote.Total = models.Price{Amount: models.RoundAmount(quote.Subtotal.Amount - quote.DiscountTotal.Amount), Currency: quote.Subtotal.Currency}
+
+	return quote, nil
+}
+
+// lookupProduct reads an item's product by SKU, falling back to the product ID
+func lookupProduct(ctx context.Context, retail ports.IRetailConnector, item Item) (*models.Product, error) {
+	// Sessions charge the price read here, so cached products must be within the price TTL
+	ctx = productcache.WithDataType(ctx, productcache.DataPrice)
+	var product *models.Product
+	var err error
+	if item.SKU != "" {
+		product, err = retail.GetProductBySKU(ctx, item.SKU)
+	} else {
+		product, err = retail.GetProduct(ctx, item.ProductID)
+	}
+	if errors.Is(err, ports.ErrNotFound) || (err == nil && product == nil) {
+		return nil, fmt.Errorf("product %s: %w", itemRef(item), ports.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to price product %s: %w", itemRef(item), err)
+	}
+	return product, nil
+}
+
+// itemRef names an item in errors
+func itemRef(item Item) string {
+	if item.SKU != "" {
+		return item.SKU
+	}
+	return item.ProductID
+}
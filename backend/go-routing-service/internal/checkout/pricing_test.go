@@ -0,0 +1,92 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/promotions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRetail serves products by SKU and ID; only the product lookups are used by the pricer
+type fakeRetail struct {
+	ports.IRetailConnector
+	products []models.Product
+}
+
+func (f *fakeRetail) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	for _, product := range f.products {
+		if product.ID == productID {
+			return &product, nil
+		}
+	}
+	return nil, fmt.Errorf("product %s: %w", productID, ports.ErrNotFound)
+}
+
+func (f *fakeRetail) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	for _, product := range f.products {
+		if product.SKU == sku {
+			return &product, nil
+		}
+	}
+	return nil, fmt.Errorf("product %s: %w", sku, ports.ErrNotFound)
+}
+
+// noPromotions knows a single 10% code
+type noPromotions struct{}
+
+func (noPromotions) FindPromotion(ctx context.Context, tenantID, code string) (*models.Promotion, error) {
+	if code == "SAVE10" {
+		return &models.Promotion{Code: code, Type: models.PromotionTypePercentage, Value: 10, Active: true}, nil
+	}
+	return nil, ports.ErrNotFound
+}
+
+func newTestPricer() (*Pricer, *fakeRetail) {
+	retail := &fakeRetail{products: []models.Product{
+		{ID: "1001", SKU: "BILLY-WHITE-001", Name: "BILLY Bookcase", Price: models.Price{Amount: 79.99, Currency: "USD"}},
+		{ID: "1002", SKU: "KALLAX-001", Name: "KALLAX Shelf unit", Price: models.Price{Amount: 59.99, Currency: "USD"}},
+		{ID: "2001", SKU: "EUR-001", Name: "Imported", Price: models.Price{Amount: 10, Currency: "EUR"}},
+	}}
+	return NewPricer(promotions.NewEngine(noPromotions{})), retail
+}
+
+func TestPricer_RepricesAndMultipliesQuantity(t *testing.T) {
+	pricer, retail := newTestPricer()
+
+	quote, err := pricer.Quote(context.Background(), retail, "tenant", "store", []Item{
+		{ProductID: "1001", SKU: "BILLY-WHITE-001", Quantity: 3},
+		{ProductID: "1002"},
+	}, []string{"save10"})
+	require.NoError(t, err)
+
+	require.Len(t, quote.Lines, 2)
+	assert.Equal(t, 239.97, quote.Lines[0].LineTotal.Amount)
+	assert.Equal(t, 1, quote.Lines[1].Quantity)
+	assert.Equal(t, 299.96, quote.Subtotal.Amount)
+	assert.Equal(t, "USD", quote.Subtotal.Currency)
+	require.Len(t, quote.Discounts, 1)
+	assert.Equal(t, 30.0, quote.DiscountTotal.Amount)
+	assert.Equal(t, 269.96, quote.Total.Amount)
+}
+
+func TestPricer_Rejects(t *testing.T) {
+	pricer, retail := newTestPricer()
+	ctx := context.Background()
+
+	_, err := pricer.Quote(ctx, retail, "tenant", "store", []Item{{SKU: "UNKNOWN"}}, nil)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+
+	_, err = pricer.Quote(ctx, retail, "tenant", "store", []Item{{ProductID: "1001", Quantity: -1}}, nil)
+	assert.ErrorIs(t, err, ErrInvalidItem)
+
+	_, err = pricer.Quote(ctx, retail, "tenant", "store", []Item{{ProductID: "1001"}, {ProductID: "2001"}}, nil)
+	assert.ErrorIs(t, err, ErrMixedCurrency)
+
+	_, err = pricer.Quote(ctx, retail, "tenant", "store", []Item{{ProductID: "1001"}}, []string{"FREE"})
+	assert.ErrorIs(t, err, promotions.ErrInvalidCode)
+}
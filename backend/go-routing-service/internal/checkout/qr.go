@@ -0,0 +1,76 @@
+package checkout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/signing"
+)
+
+var (
+	// ErrInvalidQRToken is returned for QR tokens that are malformed or not signed by a known key
+	ErrInvalidQRToken = errors.New("invalid QR token")
+
+	// ErrQRTokenExpired is returned for QR tokens past their expiry time
+	ErrQRTokenExpired = errors.New("QR token has expired")
+)
+
+// QRClaims is the payload of a checkout QR token
+// Keys are kept short because the token is rendered as a QR code
+type QRClaims struct {
+	SessionID string  `json:"sid"`
+	TenantID  string  `json:"tid"`
+	StoreID   string  `json:"sto"`
+	Total     float64 `json:"tot"`
+	Currency  string  `json:"cur"`
+	ExpiresAt int64   `json:"exp"`
+}
+
+// QRTokens issues and checks the signed tokens shown as QR codes for checkout sessions
+// A token stays valid for grace after the session's payment deadline, so a customer who paid
+// just before the deadline can still leave the store.
+type QRTokens struct {
+	keys  *signing.Keyring
+	grace time.Duration
+}
+
+// NewQRTokens creates a QR token issuer on the given keyring
+func NewQRTokens(keys *signing.Keyring, grace time.Duration) *QRTokens {
+	return &QRTokens{keys: keys, grace: grace}
+}
+
+// Issue returns the signed QR token for a session
+func (q *QRTokens) Issue(session *models.CheckoutSession) (string, error) {
+	payload, err := json.Marshal(QRClaims{
+		SessionID: session.ID,
+		TenantID:  session.TenantID,
+		StoreID:   session.StoreID,
+		Total:     session.Total,
+		Currency:  session.Currency,
+		ExpiresAt: session.ExpiresAt.Add(q.grace).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR token: %w", err)
+	}
+	return q.keys.Sign(payload), nil
+}
+
+// Parse checks a QR token's signature and expiry and returns its claims
+func (q *QRTokens) Parse(token string, now time.Time) (*QRClaims, error) {
+	payload, _, err := q.keys.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQRToken, err)
+	}
+
+	var claims QRClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.SessionID == "" || claims.TenantID == "" {
+		return nil, ErrInvalidQRToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrQRTokenExpired
+	}
+	return &claims, nil
+}
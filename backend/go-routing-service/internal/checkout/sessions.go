@@ -0,0 +1,211 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+)
+
+// ErrAlreadyVerified is returned when a QR token is presented at the exit gate a second time
+var ErrAlreadyVerified = errors.New("checkout session has already been verified")
+
+// transitionAttempts bounds the read-modify-write retries when a session is changed concurrently
+const transitionAttempts = 3
+
+// SessionStore persists checkout sessions
+// Get returns ports.ErrNotFound for unknown sessions. Update returns ports.ErrConflict when the
+// stored version differs from session.Version and increments session.Version on success.
+// ListDueOrders returns sessions of any tenant whose order submission is pending, or claimed
+// with a lapsed claim, and due at now.
+type SessionStore interface {
+	Create(ctx context.Context, session *models.CheckoutSession) error
+	Get(ctx context.Context, tenantID, sessionID string) (*models.CheckoutSession, error)
+	Update(ctx context.Context, session *models.CheckoutSession) error
+	ListDueOrders(ctx context.Context, now time.Time, limit int) ([]*models.CheckoutSession, error)
+}
+
+// Sessions manages the lifecycle of checkout sessions
+// Sessions must be paid within ttl of their creation. Stored sessions are kept for retention
+// after they expire or reach a final status, then removed by the store.
+type Sessions struct {
+	store     SessionStore
+	qr        *QRTokens
+	ttl       time.Duration
+	retention time.Duration
+	now       func() time.Time
+	onChange  func(ctx context.Context, session *models.CheckoutSession)
+	outbox    EventOutbox
+}
+
+// NewSessions creates a session manager on the given store; qr signs the sessions' QR tokens
+func NewSessions(store SessionStore, qr *QRTokens, ttl, retention time.Duration) *Sessions {
+	return &Sessions{store: store, qr: qr, ttl: ttl, retention: retention, now: time.Now}
+}
+
+// OnChange registers a function called with every session after it was created or changed
+// It is called synchronously and must not block; it is not safe to call OnChange concurrently
+// with other methods
+func (s *Sessions) OnChange(fn func(ctx context.Context, session *models.CheckoutSession)) {
+	s.onChange = fn
+}
+
+// changed reports a stored change to the OnChange function
+func (s *Sessions) changed(ctx context.Context, session *models.CheckoutSession) {
+	if s.onChange != nil {
+		s.onChange(ctx, session)
+	}
+}
+
+// Create stores a new pending session for a quote
+func (s *Sessions) Create(ctx context.Context, tenantID, storeID, customerID string, quote *Quote) (*models.CheckoutSession, error) {
+	now := s.now().UTC()
+	session := &models.CheckoutSession{
+		ID:            "session-" + uuid.NewString(),
+		TenantID:      tenantID,
+		StoreID:       storeID,
+		CustomerID:    customerID,
+		Lines:         quote.Lines,
+		Discounts:     quote.Discounts,
+		Subtotal:      quote.Subtotal.Amount,
+		DiscountTotal: quote.DiscountTotal.Amount,
+		Total:         quote.Total.Amount,
+		Currency:      quote.Total.Currency,
+		Status:        models.CheckoutStatusPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		ExpiresAt:     now.Add(s.ttl),
+		PurgeAt:       now.Add(s.ttl + s.retention),
+	}
+
+	token, err := s.qr.Issue(session)
+	if err != nil {
+		return nil, err
+	}
+	session.QRToken = token
+
+	if err := s.create(ctx, session); err != nil {
+		return nil, err
+	}
+	s.changed(ctx, session)
+	return session, nil
+}
+
+// Get returns a session; an open session past its expiry time is moved to expired first
+func (s *Sessions) Get(ctx context.Context, tenantID, sessionID string) (*models.CheckoutSession, error) {
+	session, err := s.store.Get(ctx, tenantID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsExpired(s.now()) {
+		return session, nil
+	}
+	return s.Transition(ctx, tenantID, sessionID, models.CheckoutStatusExpired)
+}
+
+// Transition moves a session to a new status
+// Moves not allowed from the current status fail with ports.ErrInvalidTransition; an open
+// session past its expiry time is expired and can then only be read. Repeating the current
+// status returns the session unchanged.
+func (s *Sessions) Transition(ctx context.Context, tenantID, sessionID, status string) (*models.CheckoutSession, error) {
+	for attempt := 0; attempt < transitionAttempts; attempt++ {
+		session, err := s.store.Get(ctx, tenantID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		now := s.now().UTC()
+		target := status
+		if session.IsExpired(now) {
+			target = models.CheckoutStatusExpired
+		}
+
+		if session.Status == target {
+			if target != status {
+				return nil, fmt.Errorf("checkout session %s has expired: %w", sessionID, ports.ErrInvalidTransition)
+			}
+			return session, nil
+		}
+		if !models.CanTransitionCheckoutStatus(session.Status, target) {
+			return nil, fmt.Errorf("checkout session %s cannot move from %s to %s: %w", sessionID, session.Status, target, ports.ErrInvalidTransition)
+		}
+
+		previous := stateOf(session)
+		session.SetStatus(target, now)
+		if !models.IsOpenCheckoutStatus(target) {
+			session.PurgeAt = now.Add(s.retention)
+		}
+
+		err = s.update(ctx, session, previous)
+		if errors.Is(err, ports.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.changed(ctx, session)
+
+		if target != status {
+			return nil, fmt.Errorf("checkout session %s has expired: %w", sessionID, ports.ErrInvalidTransition)
+		}
+		return session, nil
+	}
+
+	return nil, fmt.Errorf("failed to update checkout session %s: %w", sessionID, ports.ErrConflict)
+}
+
+// Verify checks a QR token presented at the exit gate and completes its paid session
+// The token must be signed by a known key, unexpired, issued to the tenant and, when storeID
+// is given, to that store. It must also be the session's current token. A session can be
+// verified once; presenting the token again fails with ErrAlreadyVerified.
+func (s *Sessions) Verify(ctx context.Context, tenantID, storeID, token, verifiedBy string) (*models.CheckoutSession, error) {
+	claims, err := s.qr.Parse(token, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.TenantID != tenantID {
+		return nil, ErrInvalidQRToken
+	}
+	if storeID != "" && claims.StoreID != storeID {
+		return nil, fmt.Errorf("%w: issued for store %s", ErrInvalidQRToken, claims.StoreID)
+	}
+
+	for attempt := 0; attempt < transitionAttempts; attempt++ {
+		session, err := s.store.Get(ctx, tenantID, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if session.QRToken != token {
+			return nil, ErrInvalidQRToken
+		}
+		if session.VerifiedAt != nil {
+			return session, ErrAlreadyVerified
+		}
+		if session.Status != models.CheckoutStatusPaid {
+			return session, fmt.Errorf("checkout session %s is %s, not paid: %w", session.ID, session.Status, ports.ErrInvalidTransition)
+		}
+
+		now := s.now().UTC()
+		previous := stateOf(session)
+		session.SetStatus(models.CheckoutStatusCompleted, now)
+		session.VerifiedAt = &now
+		session.VerifiedBy = verifiedBy
+		session.PurgeAt = now.Add(s.retention)
+
+		err = s.update(ctx, session, previous)
+		if errors.Is(err, ports.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.changed(ctx, session)
+		return session, nil
+	}
+
+	return nil, fmt.Errorf("failed to verify checkout session %s: %w", claims.SessionID, ports.ErrConflict)
+}
@@ -0,0 +1,293 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySessionStore is a versioned in-memory SessionStore
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]models.CheckoutSession
+	// conflicts makes the next Update calls fail as if another writer got there first
+	conflicts int
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]models.CheckoutSession)}
+}
+
+func (s *memorySessionStore) Create(ctx context.Context, session *models.CheckoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.Version = 1
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *memorySessionStore) Get(ctx context.Context, tenantID, sessionID string) (*models.CheckoutSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.TenantID != tenantID {
+		return nil, ports.ErrNotFound
+	}
+	return cloneSession(session), nil
+}
+
+func (s *memorySessionStore) Update(ctx context.Context, session *models.CheckoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conflicts > 0 {
+		s.conflicts--
+		return ports.ErrConflict
+	}
+	if s.sessions[session.ID].Version != session.Version {
+		return ports.ErrConflict
+	}
+	session.Version++
+	s.sessions[session.ID] = *cloneSession(*session)
+	return nil
+}
+
+func (s *memorySessionStore) ListDueOrders(ctx context.Context, now time.Time, limit int) ([]*models.CheckoutSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := []*models.CheckoutSession{}
+	for _, session := range s.sessions {
+		order := session.Order
+		if order == nil || order.NextAttemptAt.After(now) {
+			continue
+		}
+		if order.Status == models.CheckoutOrderPending || order.Status == models.CheckoutOrderSubmitting {
+			due = append(due, cloneSession(session))
+		}
+	}
+	return due, nil
+}
+
+// cloneSession copies a session so that the store does not share its order with callers
+func cloneSession(session models.CheckoutSession) *models.CheckoutSession {
+	if session.Order != nil {
+		order := *session.Order
+		session.Order = &order
+	}
+	if session.Payment != nil {
+		payment := *session.Payment
+		session.Payment = &payment
+	}
+	return &session
+}
+
+func newTestSessions() (*Sessions, *memorySessionStore, *time.Time) {
+	store := newMemorySessionStore()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	keys, _ := signing.NewKeyring("k1", map[string][]byte{"k1": []byte("qr-secret")})
+	sessions := NewSessions(store, NewQRTokens(keys, time.Hour), 15*time.Minute, 24*time.Hour)
+	sessions.now = func() time.Time { return now }
+	return sessions, store, &now
+}
+
+func testQuote() *Quote {
+	return &Quote{
+		Lines:     []models.CheckoutLine{{ProductID: "1001", Quantity: 2}},
+		Discounts: []models.AppliedDiscount{},
+		Subtotal:  models.Price{Amount: 100, Currency: "USD"},
+		Total:     models.Price{Amount: 100, Currency: "USD"},
+	}
+}
+
+func TestSessions_HappyPath(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusPending, session.Status)
+	assert.Equal(t, 100.0, session.Total)
+	assert.Equal(t, "USD", session.Currency)
+
+	for _, status := range []string{models.CheckoutStatusScanned, models.CheckoutStatusPaid, models.CheckoutStatusCompleted} {
+		session, err = sessions.Transition(ctx, "tenant", session.ID, status)
+		require.NoError(t, err, status)
+		assert.Equal(t, status, session.Status)
+	}
+	assert.NotNil(t, session.ScannedAt)
+	assert.NotNil(t, session.PaidAt)
+	assert.NotNil(t, session.CompletedAt)
+
+	// Repeating the current status is harmless
+	again, err := sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCompleted)
+	require.NoError(t, err)
+	assert.Equal(t, session.Version, again.Version)
+}
+
+func TestSessions_RejectsInvalidTransitions(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCompleted)
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCancelled)
+	require.NoError(t, err)
+
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+	_, err = sessions.Transition(ctx, "other-tenant", session.ID, models.CheckoutStatusCancelled)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
+
+func TestSessions_Expiry(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	*now = now.Add(16 * time.Minute)
+
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+	read, err := sessions.Get(ctx, "tenant", session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusExpired, read.Status)
+	assert.Equal(t, now.Add(24*time.Hour), read.PurgeAt)
+}
+
+func TestSessions_PaidSessionDoesNotExpire(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusPaid)
+	require.NoError(t, err)
+
+	*now = now.Add(time.Hour)
+
+	completed, err := sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCompleted)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusCompleted, completed.Status)
+}
+
+func TestSessions_RetriesConflicts(t *testing.T) {
+	sessions, store, _ := newTestSessions()
+	ctx := context.Background()
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	store.conflicts = 2
+	updated, err := sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCancelled)
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusCancelled, updated.Status)
+
+	other, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+
+	store.conflicts = transitionAttempts
+	_, err = sessions.Transition(ctx, "tenant", other.ID, models.CheckoutStatusCancelled)
+	assert.ErrorIs(t, err, ports.ErrConflict)
+}
+
+func newPaidSession(t *testing.T, sessions *Sessions) *models.CheckoutSession {
+	ctx := context.Background()
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusPaid)
+	require.NoError(t, err)
+	return session
+}
+
+func TestSessions_VerifyOnce(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+	session := newPaidSession(t, sessions)
+	assert.True(t, strings.HasPrefix(session.QRToken, "k1."))
+
+	verified, err := sessions.Verify(ctx, "tenant", "store", session.QRToken, "staff-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.CheckoutStatusCompleted, verified.Status)
+	assert.Equal(t, "staff-1", verified.VerifiedBy)
+	require.NotNil(t, verified.VerifiedAt)
+
+	// Replaying the token is rejected
+	_, err = sessions.Verify(ctx, "tenant", "store", session.QRToken, "staff-2")
+	assert.ErrorIs(t, err, ErrAlreadyVerified)
+}
+
+func TestSessions_VerifyRejects(t *testing.T) {
+	sessions, _, now := newTestSessions()
+	ctx := context.Background()
+
+	unpaid, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	_, err = sessions.Verify(ctx, "tenant", "", unpaid.QRToken, "staff")
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition)
+
+	paid := newPaidSession(t, sessions)
+
+	_, err = sessions.Verify(ctx, "other-tenant", "", paid.QRToken, "staff")
+	assert.ErrorIs(t, err, ErrInvalidQRToken)
+
+	_, err = sessions.Verify(ctx, "tenant", "other-store", paid.QRToken, "staff")
+	assert.ErrorIs(t, err, ErrInvalidQRToken)
+
+	forgedKeys, _ := signing.NewKeyring("k1", map[string][]byte{"k1": []byte("guessed")})
+	forged, err := NewQRTokens(forgedKeys, time.Hour).Issue(paid)
+	require.NoError(t, err)
+	_, err = sessions.Verify(ctx, "tenant", "", forged, "staff")
+	assert.ErrorIs(t, err, ErrInvalidQRToken)
+
+	// Tokens outlive the payment deadline by the grace period only
+	*now = now.Add(15*time.Minute + time.Hour)
+	_, err = sessions.Verify(ctx, "tenant", "", paid.QRToken, "staff")
+	assert.ErrorIs(t, err, ErrQRTokenExpired)
+}
+
+func TestSessions_ReportsChanges(t *testing.T) {
+	sessions, _, _ := newTestSessions()
+	ctx := context.Background()
+
+	var seen []string
+	sessions.OnChange(func(ctx context.Context, session *models.CheckoutSession) {
+		seen = append(seen, fmt.Sprintf("%s@%d", session.Status, session.Version))
+	})
+
+	session, err := sessions.Create(ctx, "tenant", "store", "customer", testQuote())
+	require.NoError(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	require.NoError(t, err)
+
+	// Rejected and repeated transitions change nothing
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusCompleted)
+	require.Error(t, err)
+	_, err = sessions.Transition(ctx, "tenant", session.ID, models.CheckoutStatusScanned)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pending@1", "scanned_at_gate@2"}, seen)
+}
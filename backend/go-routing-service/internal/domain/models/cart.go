@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Cart is a customer's basket in a store
+// There is at most one cart per customer per store; it expires after a period without changes
+type Cart struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenantId"`
+	StoreID     string     `json:"storeId"`
+	CustomerID  string     `json:"customerId"`
+	Lines       []CartLine `json:"lines"`
+	Subtotal    Price      `json:"subtotal"`
+	ItemCount   int        `json:"itemCount"`
+	Version     int64      `json:"version"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	ValidatedAt time.Time  `json:"validatedAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+}
+
+// CartLine is a product in a cart
+// Name, SKU, price and availability are refreshed from the retail connector whenever the cart is read or changed
+type CartLine struct {
+	ID         string    `json:"id"`
+	ProductID  string    `json:"productId"`
+	VariantID  string    `json:"variantId,omitempty"`
+	SKU        string    `json:"sku"`
+	Name       string    `json:"name"`
+	Category   string    `json:"category,omitempty"`
+	ImageURL   string    `json:"imageUrl,omitempty"`
+	Quantity   int       `json:"quantity"`
+	UnitPrice  Price     `json:"unitPrice"`
+	TotalPrice Price     `json:"totalPrice"`
+	Available  bool      `json:"available"`
+	AddedAt    time.Time `json:"addedAt"`
+}
+
+// AddCartLineRequest represents a request to add a product to a cart
+type AddCartLineRequest struct {
+	StoreID   string `json:"storeId"`
+	ProductID string `json:"productId"`
+	VariantID string `json:"variantId,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// UpdateCartLineRequest represents a request to change the quantity of a cart line
+type UpdateCartLineRequest struct {
+	StoreID  string `json:"storeId"`
+	Quantity int    `json:"quantity"`
+}
+
+// Recalculate updates line totals, the subtotal and the item count
+// Unavailable lines stay in the cart but are not counted
+func (c *Cart) Recalculate() {
+	c.Subtotal = Price{}
+	c.ItemCount = 0
+	for i := range c.Lines {
+		line := &c.Lines[i]
+		line.TotalPrice = Price{
+			Amount:   line.UnitPrice.Amount * float64(line.Quantity),
+			Currency: line.UnitPrice.Currency,
+		}
+		if !line.Available {
+			continue
+		}
+		c.Subtotal.Amount += line.TotalPrice.Amount
+		if c.Subtotal.Currency == "" {
+			c.Subtotal.Currency = line.UnitPrice.Currency
+		}
+		c.ItemCount += line.Quantity
+	}
+}
+
+// UnavailableLines returns the lines that cannot be fulfilled
+func (c *Cart) UnavailableLines() []CartLine {
+	lines := []CartLine{}
+	for _, line := range c.Lines {
+		if !line.Available {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// OrderLineItem converts a cart line into an order line
+func (l CartLine) OrderLineItem() OrderLineItem {
+	return OrderLineItem{
+		ID:         l.ID,
+		ProductID:  l.ProductID,
+		VariantID:  l.VariantID,
+		SKU:        l.SKU,
+		Name:       l.Name,
+		Quantity:   l.Quantity,
+		UnitPrice:  l.UnitPrice,
+		TotalPrice: l.TotalPrice,
+		ImageURL:   l.ImageURL,
+		Category:   l.Category,
+	}
+}
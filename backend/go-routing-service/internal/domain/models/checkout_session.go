@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Checkout session statuses
+// A session moves pending → scanned_at_gate → paid → completed, or straight from pending to paid
+// when the customer pays in the app. An unpaid session can be cancelled, and expires when it is
+// not paid before ExpiresAt.
+const (
+	CheckoutStatusPending   = "pending"
+	CheckoutStatusScanned   = "scanned_at_gate"
+	CheckoutStatusPaid      = "paid"
+	CheckoutStatusCompleted = "completed"
+	CheckoutStatusCancelled = "cancelled"
+	CheckoutStatusExpired   = "expired"
+)
+
+// Order submission statuses of a paid checkout session
+// Submission moves pending → submitting → submitted; a failed attempt goes back to pending until
+// the attempts run out, and then to failed.
+const (
+	CheckoutOrderPending    = "pending"
+	CheckoutOrderSubmitting = "submitting"
+	CheckoutOrderSubmitted  = "submitted"
+	CheckoutOrderFailed     = "failed"
+)
+
+// checkoutTransitions lists the statuses each status may move to
+var checkoutTransitions = map[string][]string{
+	CheckoutStatusPending:   {CheckoutStatusScanned, CheckoutStatusPaid, CheckoutStatusCancelled, CheckoutStatusExpired},
+	CheckoutStatusScanned:   {CheckoutStatusPaid, CheckoutStatusCancelled, CheckoutStatusExpired},
+	CheckoutStatusPaid:      {CheckoutStatusCompleted},
+	CheckoutStatusCompleted: {},
+	CheckoutStatusCancelled: {},
+	CheckoutStatusExpired:   {},
+}
+
+// CheckoutSession is a server-priced checkout waiting to be paid at the store
+type CheckoutSession struct {
+	ID            string            `json:"sessionId"`
+	TenantID      string            `json:"tenantId"`
+	StoreID       string            `json:"storeId"`
+	CustomerID    string            `json:"customerId"`
+	Lines         []CheckoutLine    `json:"lines"`
+	Discounts     []AppliedDiscount `json:"discounts"`
+	Subtotal      float64           `json:"subtotal"`
+	DiscountTotal float64           `json:"discountTotal"`
+	Total         float64           `json:"total"`
+	Currency      string            `json:"currency"`
+	QRToken       string            `json:"qrToken"`
+	Status        string            `json:"status"`
+	Version       int64             `json:"version"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+	ExpiresAt     time.Time         `json:"expiresAt"`
+	ScannedAt     *time.Time        `json:"scannedAt,omitempty"`
+	PaidAt        *time.Time        `json:"paidAt,omitempty"`
+	CompletedAt   *time.Time        `json:"completedAt,omitempty"`
+	CancelledAt   *time.Time        `json:"cancelledAt,omitempty"`
+	VerifiedAt    *time.Time        `json:"verifiedAt,omitempty"` // When staff checked the QR token at the exit gate
+	VerifiedBy    string            `json:"verifiedBy,omitempty"`
+	Payment       *CheckoutPayment  `json:"payment,omitempty"` // Set once the customer pays in the app
+	Order         *CheckoutOrder    `json:"order,omitempty"`   // Set once the session is paid
+	PurgeAt       time.Time         `json:"-"`                 // When the stored session is removed by the TTL index
+}
+
+// CheckoutPayment is the in-app payment of a checkout session as last reported by the payment provider
+type CheckoutPayment struct {
+	PaymentID      string    `json:"paymentId"`
+	Status         string    `json:"status"`
+	Amount         float64   `json:"amount"`
+	RefundedAmount float64   `json:"refundedAmount,omitempty"`
+	Currency       string    `json:"currency"`
+	DeclineReason  string    `json:"declineReason,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// CheckoutOrder tracks the submission of a paid checkout session to the retail backend
+type CheckoutOrder struct {
+	Status        string     `json:"status"`
+	OrderID       string     `json:"orderId,omitempty"`
+	OrderNumber   string     `json:"orderNumber,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"lastError,omitempty"`
+	NextAttemptAt time.Time  `json:"-"` // When a pending submission is due, or a submitting one's claim lapses
+	SubmittedAt   *time.Time `json:"submittedAt,omitempty"`
+}
+
+// IsValidCheckoutStatus reports whether status is a known checkout session status
+func IsValidCheckoutStatus(status string) bool {
+	_, ok := checkoutTransitions[status]
+	return ok
+}
+
+// IsOpenCheckoutStatus reports whether a session in this status can still expire
+func IsOpenCheckoutStatus(status string) bool {
+	return status == CheckoutStatusPending || status == CheckoutStatusScanned
+}
+
+// CanTransitionCheckoutStatus reports whether a session may move from one status to another
+// Setting the current status again is allowed so that retried updates are harmless
+func CanTransitionCheckoutStatus(from, to string) bool {
+	if from == to {
+		return IsValidCheckoutStatus(to)
+	}
+	for _, next := range checkoutTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired reports whether an open session has passed its expiry time
+func (s *CheckoutSession) IsExpired(now time.Time) bool {
+	return IsOpenCheckoutStatus(s.Status) && !now.Before(s.ExpiresAt)
+}
+
+// SetStatus moves the session to a new status and records when it happened
+// It does not check the transition; see CanTransitionCheckoutStatus
+func (s *CheckoutSession) SetStatus(status string, now time.Time) {
+	s.Status = status
+	s.UpdatedAt = now
+
+	at := now
+	switch status {
+	case CheckoutStatusScanned:
+		s.ScannedAt = &at
+	case CheckoutStatusPaid:
+		s.PaidAt = &at
+		if s.Order == nil {
+			s.Order = &CheckoutOrder{Status: CheckoutOrderPending, NextAttemptAt: now}
+		}
+	case CheckoutStatusCompleted:
+		s.CompletedAt = &at
+	case CheckoutStatusCancelled:
+		s.CancelledAt = &at
+	}
+}
+
+// OrderRequest builds the backend order for the session
+// The session ID is the idempotency key: the retail connectors send it with the order as an
+// Idempotency-Key header, and D365 also as the order's ChannelReferenceId. Only a backend or
+// gateway that honours the key drops resubmissions.
+func (s *CheckoutSession) OrderRequest() OrderRequest {
+	req := OrderRequest{
+		StoreID:   s.StoreID,
+		LineItems: make([]OrderLineItem, 0, len(s.Lines)),
+		Metadata: map[string]interface{}{
+			"customerId":        s.CustomerID,
+			"checkoutSessionId": s.ID,
+			"idempotencyKey":    s.ID,
+			"discounts":         s.Discounts,
+			"discountTotal":     s.DiscountTotal,
+			"total":             s.Total,
+			"currency":          s.Currency,
+		},
+	}
+	for i, line := range s.Lines {
+		req.LineItems = append(req.LineItems, OrderLineItem{
+			ID:         fmt.Sprintf("%s-%d", s.ID, i+1),
+			ProductID:  line.ProductID,
+			VariantID:  line.VariantID,
+			SKU:        line.SKU,
+			Name:       line.Name,
+			Quantity:   line.Quantity,
+			UnitPrice:  line.UnitPrice,
+			TotalPrice: line.LineTotal,
+		})
+	}
+	return req
+}
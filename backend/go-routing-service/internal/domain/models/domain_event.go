@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Aggregates that domain events are published for
+// Events of one aggregate are delivered in the order they were recorded.
+const (
+	AggregateOrder           = "order"
+	AggregateCheckoutSession = "checkout_session"
+	AggregateWishlist        = "wishlist"
+	AggregateProduct         = "product"
+)
+
+// Domain event types
+const (
+	EventOrderCreated       = "OrderCreated"
+	EventOrderStatusChanged = "OrderStatusChanged"
+
+	EventCheckoutStarted   = "CheckoutStarted"
+	EventCheckoutPaid      = "CheckoutPaid"
+	EventCheckoutCompleted = "CheckoutCompleted"
+	EventCheckoutCancelled = "CheckoutCancelled"
+	EventCheckoutExpired   = "CheckoutExpired"
+
+	EventWishlistCreated     = "WishlistCreated"
+	EventWishlistUpdated     = "WishlistUpdated"
+	EventWishlistDeleted     = "WishlistDeleted"
+	EventWishlistItemAdded   = "WishlistItemAdded"
+	EventWishlistItemUpdated = "WishlistItemUpdated"
+	EventWishlistItemRemoved = "WishlistItemRemoved"
+
+	EventProductPriceChanged     = "ProductPriceChanged"
+	EventProductInventoryChanged = "ProductInventoryChanged"
+)
+
+// domainEventTypes are the event types that can be subscribed to
+var domainEventTypes = map[string]bool{
+	EventOrderCreated:        true,
+	EventOrderStatusChanged:  true,
+	EventCheckoutStarted:     true,
+	EventCheckoutPaid:        true,
+	EventCheckoutCompleted:   true,
+	EventCheckoutCancelled:   true,
+	EventCheckoutExpired:     true,
+	EventWishlistCreated:     true,
+	EventWishlistUpdated:     true,
+	EventWishlistDeleted:     true,
+	EventWishlistItemAdded:   true,
+	EventWishlistItemUpdated: true,
+	EventWishlistItemRemoved: true,
+
+	EventProductPriceChanged:     true,
+	EventProductInventoryChanged: true,
+}
+
+// IsDomainEventType reports whether eventType is a known domain event type
+func IsDomainEventType(eventType string) bool {
+	return domainEventTypes[eventType]
+}
+
+// DomainEvent is a fact about a state change, published to other services through the outbox
+type DomainEvent struct {
+	ID            string          `json:"eventId"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	TenantID      string          `json:"tenantId"`
+	StoreID       string          `json:"storeId,omitempty"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Data          json.RawMessage `json:"data,omitempty"`
+}
+
+// NewDomainEvent creates an event with a new ID; data is encoded as the event's JSON payload
+func NewDomainEvent(eventType, aggregateType, aggregateID, tenantID, storeID string, data interface{}) (DomainEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return DomainEvent{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return DomainEvent{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		TenantID:      tenantID,
+		StoreID:       storeID,
+		OccurredAt:    time.Now().UTC(),
+		Data:          payload,
+	}, nil
+}
+
+// AggregateKey identifies the aggregate of an event across tenants
+func (e DomainEvent) AggregateKey() string {
+	return e.TenantID + "/" + e.AggregateType + "/" + e.AggregateID
+}
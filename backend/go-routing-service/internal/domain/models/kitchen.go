@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Kitchen ticket statuses shared by all kitchen connectors
+// A ticket moves received → preparing → ready → served, and can be cancelled while active
+const (
+	KitchenStatusReceived  = "received"
+	KitchenStatusPreparing = "preparing"
+	KitchenStatusReady     = "ready"
+	KitchenStatusServed    = "served"
+	KitchenStatusCancelled = "cancelled"
+)
+
+// kitchenTransitions lists the statuses each status may move to
+var kitchenTransitions = map[string][]string{
+	KitchenStatusReceived:  {KitchenStatusPreparing, KitchenStatusReady, KitchenStatusCancelled},
+	KitchenStatusPreparing: {KitchenStatusReady, KitchenStatusCancelled},
+	KitchenStatusReady:     {KitchenStatusServed, KitchenStatusCancelled},
+	KitchenStatusServed:    {},
+	KitchenStatusCancelled: {},
+}
+
+// kitchenCategories are the line item categories prepared by the kitchen
+var kitchenCategories = map[string]bool{
+	"food":       true,
+	"restaurant": true,
+	"bistro":     true,
+}
+
+// KitchenTicket represents an order as seen by the kitchen
+type KitchenTicket struct {
+	OrderID   string          `json:"orderId"`
+	StoreID   string          `json:"storeId"`
+	Status    string          `json:"status"`
+	Items     []OrderLineItem `json:"items,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// IsValidKitchenStatus reports whether status is a known kitchen status
+func IsValidKitchenStatus(status string) bool {
+	_, ok := kitchenTransitions[status]
+	return ok
+}
+
+// IsActiveKitchenStatus reports whether a ticket in this status is still being worked on
+func IsActiveKitchenStatus(status string) bool {
+	return len(kitchenTransitions[status]) > 0
+}
+
+// CanTransitionKitchenStatus reports whether a ticket may move from one status to another
+// Setting the current status again is allowed so that retried updates are harmless
+func CanTransitionKitchenStatus(from, to string) bool {
+	if from == to {
+		return IsValidKitchenStatus(to)
+	}
+	for _, next := range kitchenTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKitchenItem reports whether a line item is prepared by the kitchen
+func IsKitchenItem(item OrderLineItem) bool {
+	return kitchenCategories[item.Category]
+}
+
+// KitchenOrderRequest represents a request to send an order to the kitchen
+type KitchenOrderRequest struct {
+	StoreID string          `json:"storeId"`
+	OrderID string          `json:"orderId"`
+	Items   []OrderLineItem `json:"items"`
+}
+
+// KitchenStatusUpdateRequest represents a request to change the kitchen status of an order
+type KitchenStatusUpdateRequest struct {
+	StoreID string `json:"storeId"`
+	Status  string `json:"status"`
+}
+
+// KitchenOrderStatus is the kitchen status of a single order
+type KitchenOrderStatus struct {
+	OrderID string `json:"orderId"`
+	StoreID string `json:"storeId"`
+	Status  string `json:"status"`
+}
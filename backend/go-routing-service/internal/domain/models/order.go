@@ -26,15 +26,50 @@ type Order struct {
 type OrderStatus string
 
 const (
-	OrderStatusPending    OrderStatus = "pending"
-	OrderStatusProcessing OrderStatus = "processing"
-	OrderStatusPaid       OrderStatus = "paid"
-	OrderStatusShipped    OrderStatus = "shipped"
-	OrderStatusDelivered  OrderStatus = "delivered"
-	OrderStatusCancelled  OrderStatus = "cancelled"
-	OrderStatusRefunded   OrderStatus = "refunded"
+	OrderStatusPending        OrderStatus = "pending"
+	OrderStatusProcessing     OrderStatus = "processing"
+	OrderStatusPaid           OrderStatus = "paid"
+	OrderStatusShipped        OrderStatus = "shipped"
+	OrderStatusReadyForPickup OrderStatus = "ready_for_pickup"
+	OrderStatusDelivered      OrderStatus = "delivered"
+	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusRefunded       OrderStatus = "refunded"
 )
 
+// orderTransitions lists the statuses each order status may move to
+// An order can be cancelled until it ships or is delivered, so an uncollected pickup order can
+// still be cancelled; a delivered order can only be refunded
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:        {OrderStatusProcessing, OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusProcessing:     {OrderStatusPaid, OrderStatusShipped, OrderStatusReadyForPickup, OrderStatusCancelled},
+	OrderStatusPaid:           {OrderStatusProcessing, OrderStatusShipped, OrderStatusReadyForPickup, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusShipped:        {OrderStatusDelivered},
+	OrderStatusReadyForPickup: {OrderStatusDelivered, OrderStatusCancelled},
+	OrderStatusDelivered:      {OrderStatusRefunded},
+	OrderStatusCancelled:      {},
+	OrderStatusRefunded:       {},
+}
+
+// IsValidOrderStatus reports whether status is a known order status
+func IsValidOrderStatus(status OrderStatus) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransitionOrderStatus reports whether an order may move from one status to another
+// Setting the current status again is allowed so that retried updates are harmless
+func CanTransitionOrderStatus(from, to OrderStatus) bool {
+	if from == to {
+		return IsValidOrderStatus(to)
+	}
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 // OrderLineItem represents a single product in an order
 type OrderLineItem struct {
 	ID         string  `json:"id"`
@@ -46,6 +81,7 @@ type OrderLineItem struct {
 	UnitPrice  Price   `json:"unitPrice"`
 	TotalPrice Price   `json:"totalPrice"`
 	ImageURL   string  `json:"imageUrl,omitempty"`
+	Category   string  `json:"category,omitempty"`
 }
 
 // Address represents a physical address
@@ -81,6 +117,13 @@ type OrderRequest struct {
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// IdempotencyKey returns the key that identifies resubmissions of the same order, or "" when there is none
+// It is read from the "idempotencyKey" metadata entry.
+func (r OrderRequest) IdempotencyKey() string {
+	key, _ := r.Metadata["idempotencyKey"].(string)
+	return key
+}
+
 // OrderList represents paginated order results
 type OrderList struct {
 	Orders  []Order `json:"orders"`
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+		allowed  bool
+	}{
+		{OrderStatusPending, OrderStatusCancelled, true},
+		{OrderStatusProcessing, OrderStatusCancelled, true},
+		{OrderStatusPaid, OrderStatusCancelled, true},
+		{OrderStatusShipped, OrderStatusCancelled, false},
+		{OrderStatusDelivered, OrderStatusCancelled, false},
+		{OrderStatusRefunded, OrderStatusCancelled, false},
+		{OrderStatusCancelled, OrderStatusCancelled, true},
+		{OrderStatusCancelled, OrderStatusPending, false},
+		{OrderStatusShipped, OrderStatusDelivered, true},
+		{OrderStatusPaid, OrderStatusReadyForPickup, true},
+		{OrderStatusReadyForPickup, OrderStatusDelivered, true},
+		{OrderStatusReadyForPickup, OrderStatusCancelled, true},
+		{OrderStatusShipped, OrderStatusReadyForPickup, false},
+		{OrderStatusDelivered, OrderStatusRefunded, true},
+		{OrderStatus("unknown"), OrderStatusCancelled, false},
+		{OrderStatus("unknown"), OrderStatus("unknown"), false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, CanTransitionOrderStatus(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// Payment statuses shared by all payment connectors
+// A payment moves pending → authorized → captured → refunded. An authorization can be voided
+// instead of captured, and the provider can decline a payment that is pending. A partial refund
+// keeps the payment captured.
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusRefunded   = "refunded"
+	PaymentStatusVoided     = "voided"
+	PaymentStatusDeclined   = "declined"
+)
+
+// paymentTransitions lists the statuses each status may move to
+var paymentTransitions = map[string][]string{
+	PaymentStatusPending:    {PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusDeclined},
+	PaymentStatusAuthorized: {PaymentStatusCaptured, PaymentStatusVoided},
+	PaymentStatusCaptured:   {PaymentStatusRefunded},
+	PaymentStatusRefunded:   {},
+	PaymentStatusVoided:     {},
+	PaymentStatusDeclined:   {},
+}
+
+// PaymentRequest is a request to authorize a payment
+type PaymentRequest struct {
+	Reference    string            `json:"reference"` // The merchant's reference, e.g. a checkout session ID
+	Amount       float64           `json:"amount"`
+	Currency     string            `json:"currency"`
+	PaymentToken string            `json:"paymentToken"` // Tokenized payment method from the provider's client SDK
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// Payment is a payment as reported by a payment provider
+type Payment struct {
+	ID             string    `json:"paymentId"`
+	Reference      string    `json:"reference"`
+	Status         string    `json:"status"`
+	Amount         float64   `json:"amount"` // The authorized amount
+	CapturedAmount float64   `json:"capturedAmount"`
+	RefundedAmount float64   `json:"refundedAmount"`
+	Currency       string    `json:"currency"`
+	DeclineReason  string    `json:"declineReason,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// PaymentEvent is a payment provider notification about a change to a payment
+type PaymentEvent struct {
+	ID      string  `json:"eventId"`
+	Type    string  `json:"type"`
+	Payment Payment `json:"payment"`
+}
+
+// IsValidPaymentStatus reports whether status is a known payment status
+func IsValidPaymentStatus(status string) bool {
+	_, ok := paymentTransitions[status]
+	return ok
+}
+
+// CanTransitionPaymentStatus reports whether a payment may move from one status to another
+// Setting the current status again is allowed so that retried updates are harmless
+func CanTransitionPaymentStatus(from, to string) bool {
+	if from == to {
+		return IsValidPaymentStatus(to)
+	}
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // Product represents a canonical retail product across all backends
 // This is the domain model that all adapters must transform to/from
@@ -72,3 +75,30 @@ type ProductList struct {
 	Offset     int       `json:"offset"`
 	HasMore    bool      `json:"hasMore"`
 }
+
+// InventoryFor returns the stock of a product variant
+// The product's own stock is used when the variant is not listed or has no stock information
+func (p *Product) InventoryFor(variantID string) *InventoryInfo {
+	if variantID != "" {
+		for _, variant := range p.Variants {
+			if variant.ID == variantID && variant.Inventory != nil {
+				return variant.Inventory
+			}
+		}
+	}
+	return p.Inventory
+}
+
+// CanFulfil reports whether the stock covers the quantity
+// Backends that only report availability (Quantity 0) are trusted for any quantity
+func (i *InventoryInfo) CanFulfil(quantity int) bool {
+	if !i.Available {
+		return false
+	}
+	return i.Quantity <= 0 || quantity <= i.Quantity
+}
+
+// RoundAmount rounds a money amount to cents
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
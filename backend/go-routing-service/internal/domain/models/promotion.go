@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Promotion types
+const (
+	PromotionTypePercentage  = "percentage"
+	PromotionTypeFixedAmount = "fixed_amount"
+)
+
+// Promotion is a discount code defined by a tenant
+// An empty StoreID makes the code valid in all of the tenant's stores
+type Promotion struct {
+	Code        string     `json:"code"`
+	TenantID    string     `json:"tenantId"`
+	StoreID     string     `json:"storeId,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Type        string     `json:"type"`
+	Value       float64    `json:"value"`              // Percent for percentage, amount for fixed_amount
+	Currency    string     `json:"currency,omitempty"` // Required for fixed_amount
+	MinSubtotal float64    `json:"minSubtotal,omitempty"`
+	StartsAt    *time.Time `json:"startsAt,omitempty"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+	Active      bool       `json:"active"`
+}
+
+// AppliedDiscount is a validated discount code and the amount it takes off
+type AppliedDiscount struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Amount      Price  `json:"amount"`
+}
+
+// CheckoutLine is a server-priced line of a checkout session
+type CheckoutLine struct {
+	ProductID string `json:"productId"`
+	VariantID string `json:"variantId,omitempty"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Price  `json:"unitPrice"`
+	LineTotal Price  `json:"lineTotal"`
+}
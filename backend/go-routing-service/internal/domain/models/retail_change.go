@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Retail change types pushed by retail backends
+const (
+	RetailChangeOrderStatus = "order_status"
+	RetailChangePrice       = "price"
+	RetailChangeInventory   = "inventory"
+)
+
+// RetailChange is a change a retail backend notified the gateway about
+// Order status changes carry the order; price and inventory changes carry the product.
+type RetailChange struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	OrderID     string      `json:"orderId,omitempty"`
+	OrderNumber string      `json:"orderNumber,omitempty"`
+	OrderStatus OrderStatus `json:"orderStatus,omitempty"`
+	ProductID   string      `json:"productId,omitempty"`
+	SKU         string      `json:"sku,omitempty"`
+	OccurredAt  time.Time   `json:"occurredAt"`
+}
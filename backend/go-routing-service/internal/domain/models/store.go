@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Store is a tenant's store in the store registry, with the backend requests for it are routed to
+// StoreIDs are only unique within a tenant.
+type Store struct {
+	StoreID        string                 `json:"storeId"`
+	TenantID       string                 `json:"tenantId"`
+	Name           string                 `json:"name"`
+	BackendURL     string                 `json:"backendUrl"`
+	BackendContext map[string]interface{} `json:"backendContext,omitempty"`
+	Location       *StoreLocation         `json:"location,omitempty"`
+	Status         string                 `json:"status,omitempty"` // "busy", "normal", "closingSoon"
+	ClosingTime    time.Time              `json:"closingTime,omitempty"`
+	Address        string                 `json:"address,omitempty"`
+	City           string                 `json:"city,omitempty"`
+	State          string                 `json:"state,omitempty"`
+}
+
+// StoreLocation is the position of a store
+type StoreLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
@@ -31,11 +31,24 @@ type WishlistItem struct {
 }
 
 // AddWishlistItemRequest represents a request to add an item
+// SKU, Name, Price and ImageURL are filled in by the service from the retail connector
+// so that adapters can store a snapshot of the product at the time it was saved
 type AddWishlistItemRequest struct {
 	ProductID string `json:"productId"`
 	VariantID string `json:"variantId,omitempty"`
 	Quantity  int    `json:"quantity"`
 	Notes     string `json:"notes,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Price     Price  `json:"price,omitempty"`
+	ImageURL  string `json:"imageUrl,omitempty"`
+}
+
+// WishlistUpdate represents a partial update of a wishlist
+// Nil fields are left unchanged
+type WishlistUpdate struct {
+	Name     *string `json:"name,omitempty"`
+	IsPublic *bool   `json:"isPublic,omitempty"`
 }
 
 // WishlistList represents paginated wishlist results
@@ -46,3 +59,62 @@ type WishlistList struct {
 	Offset    int        `json:"offset"`
 	HasMore   bool       `json:"hasMore"`
 }
+
+// WishlistShare is a revocable share link for a wishlist
+type WishlistShare struct {
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenantId"`
+	StoreID    string     `json:"storeId"`
+	WishlistID string     `json:"wishlistId"`
+	CustomerID string     `json:"customerId"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// PublicWishlist is the read-only view of a shared wishlist
+// It carries no customer data; prices and stock are read live from the retail connector
+type PublicWishlist struct {
+	Name      string               `json:"name"`
+	StoreID   string               `json:"storeId"`
+	Items     []PublicWishlistItem `json:"items"`
+	Count     int                  `json:"count"`
+	ExpiresAt time.Time            `json:"expiresAt"`
+}
+
+// PublicWishlistItem is an item of a shared wishlist
+// Live is false when the retail connector could not be reached and the saved snapshot is shown
+type PublicWishlistItem struct {
+	ProductID string         `json:"productId"`
+	VariantID string         `json:"variantId,omitempty"`
+	SKU       string         `json:"sku"`
+	Name      string         `json:"name"`
+	ImageURL  string         `json:"imageUrl,omitempty"`
+	Quantity  int            `json:"quantity"`
+	Price     Price          `json:"price"`
+	Inventory *InventoryInfo `json:"inventory,omitempty"`
+	Live      bool           `json:"live"`
+}
+
+// Reasons a wishlist item cannot be checked out
+const (
+	WishlistItemNotFound   = "not_found"
+	WishlistItemOutOfStock = "out_of_stock"
+)
+
+// WishlistCheckoutChange is a wishlist item whose price changed since it was saved
+type WishlistCheckoutChange struct {
+	ItemID        string `json:"itemId"`
+	ProductID     string `json:"productId"`
+	Name          string `json:"name"`
+	PreviousPrice Price  `json:"previousPrice"`
+	Price         Price  `json:"price"`
+}
+
+// WishlistCheckoutUnavailable is a wishlist item left out of a checkout session
+type WishlistCheckoutUnavailable struct {
+	ItemID    string `json:"itemId"`
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
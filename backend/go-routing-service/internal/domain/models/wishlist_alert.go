@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Wishlist alert types
+const (
+	WishlistAlertPriceDrop   = "price_drop"
+	WishlistAlertBackInStock = "back_in_stock"
+)
+
+// WishlistAlert is a price or stock change on a wishlist item, raised by the wishlist alert job
+type WishlistAlert struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	TenantID      string    `json:"tenantId"`
+	StoreID       string    `json:"storeId"`
+	CustomerID    string    `json:"customerId"`
+	WishlistID    string    `json:"wishlistId"`
+	ItemID        string    `json:"itemId"`
+	ProductID     string    `json:"productId"`
+	VariantID     string    `json:"variantId,omitempty"`
+	Name          string    `json:"name"`
+	Price         Price     `json:"price"`
+	PreviousPrice *Price    `json:"previousPrice,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// WishlistItemState is the price and stock of a wishlist item as last seen by the alert job
+// Available is nil while stock has never been observed
+type WishlistItemState struct {
+	Price     Price     `json:"price"`
+	Available *bool     `json:"available,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// DetectWishlistAlerts compares the previous and current state of an item and returns the alert
+// types raised, together with the state to remember for the next check.
+// A price drop is a lower amount in the same currency. When no previous amount is known, the
+// retailer's CompareAtPrice is used as the reference instead. Back in stock needs an observed
+// out-of-stock state first, so the first check of an item never raises it.
+func DetectWishlistAlerts(previous, current WishlistItemState) ([]string, WishlistItemState) {
+	var alerts []string
+	next := previous
+	next.CheckedAt = current.CheckedAt
+
+	if current.Price.Amount > 0 {
+		reference := previous.Price.Amount
+		if reference <= 0 && current.Price.CompareAtPrice != nil {
+			reference = *current.Price.CompareAtPrice
+		}
+		sameCurrency := previous.Price.Currency == "" || previous.Price.Currency == current.Price.Currency
+		if sameCurrency && current.Price.Amount < reference {
+			alerts = append(alerts, WishlistAlertPriceDrop)
+		}
+		next.Price = current.Price
+	}
+
+	if current.Available != nil {
+		if *current.Available && previous.Available != nil && !*previous.Available {
+			alerts = append(alerts, WishlistAlertBackInStock)
+		}
+		available := *current.Available
+		next.Available = &available
+	}
+
+	return alerts, next
+}
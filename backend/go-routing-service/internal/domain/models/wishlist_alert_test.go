@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectWishlistAlerts_PriceDrop(t *testing.T) {
+	previous := WishlistItemState{Price: Price{Amount: 99.99, Currency: "USD"}}
+	now := time.Now()
+
+	alerts, next := DetectWishlistAlerts(previous, WishlistItemState{Price: Price{Amount: 79.99, Currency: "USD"}, CheckedAt: now})
+	assert.Equal(t, []string{WishlistAlertPriceDrop}, alerts)
+	assert.Equal(t, 79.99, next.Price.Amount)
+	assert.Equal(t, now, next.CheckedAt)
+
+	// The same price again is not a new drop
+	alerts, _ = DetectWishlistAlerts(next, WishlistItemState{Price: Price{Amount: 79.99, Currency: "USD"}})
+	assert.Empty(t, alerts)
+
+	// A lower amount in another currency is not comparable
+	alerts, _ = DetectWishlistAlerts(previous, WishlistItemState{Price: Price{Amount: 50, Currency: "EUR"}})
+	assert.Empty(t, alerts)
+}
+
+func TestDetectWishlistAlerts_CompareAtPriceWithoutSnapshot(t *testing.T) {
+	compareAt := 120.0
+
+	alerts, next := DetectWishlistAlerts(WishlistItemState{}, WishlistItemState{
+		Price: Price{Amount: 99.99, Currency: "USD", CompareAtPrice: &compareAt},
+	})
+	assert.Equal(t, []string{WishlistAlertPriceDrop}, alerts)
+	assert.Equal(t, 99.99, next.Price.Amount)
+}
+
+func TestDetectWishlistAlerts_BackInStock(t *testing.T) {
+	inStock, outOfStock := true, false
+	price := Price{Amount: 10, Currency: "USD"}
+
+	// Stock is unknown on the first check
+	alerts, next := DetectWishlistAlerts(WishlistItemState{Price: price}, WishlistItemState{Price: price, Available: &inStock})
+	assert.Empty(t, alerts)
+	assert.True(t, *next.Available)
+
+	alerts, next = DetectWishlistAlerts(next, WishlistItemState{Price: price, Available: &outOfStock})
+	assert.Empty(t, alerts)
+
+	// Unknown stock keeps the last observation
+	alerts, next = DetectWishlistAlerts(next, WishlistItemState{Price: price})
+	assert.Empty(t, alerts)
+	assert.False(t, *next.Available)
+
+	alerts, _ = DetectWishlistAlerts(next, WishlistItemState{Price: price, Available: &inStock})
+	assert.Equal(t, []string{WishlistAlertBackInStock}, alerts)
+}
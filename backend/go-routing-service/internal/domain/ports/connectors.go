@@ -2,9 +2,24 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"net/http"
+
 	"github.com/amicis/go-routing-service/internal/domain/models"
 )
 
+// ErrNotFound is wrapped by connectors when the requested entity does not exist in the backend
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalidTransition is wrapped by connectors when a status change is not allowed from the current status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrConflict is wrapped by stores when an entity was changed by someone else since it was read
+var ErrConflict = errors.New("concurrent modification")
+
+// ErrInvalidSignature is wrapped by connectors when a webhook notification fails authentication
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
 // IConnector is the base interface for all backend connectors
 // All domain-specific connectors must implement this interface
 type IConnector interface {
@@ -64,8 +79,24 @@ type IRetailConnector interface {
 	UpdateOrderStatus(ctx context.Context, orderID string, status models.OrderStatus) error
 }
 
+// IRetailChangeNotifier is implemented by retail connectors whose backend pushes change
+// notifications to the gateway
+// Implementations: D365CommerceAdapter
+type IRetailChangeNotifier interface {
+	// ParseChangeNotification authenticates a backend notification and decodes the changes it reports
+	// Notifications that fail authentication wrap ErrInvalidSignature
+	ParseChangeNotification(ctx context.Context, header http.Header, body []byte) ([]models.RetailChange, error)
+}
+
+// IRetailCacheInvalidator is implemented by retail connectors that cache backend data
+// The gateway calls it when the backend reports that a product's price or stock changed
+type IRetailCacheInvalidator interface {
+	// InvalidateProduct drops cached data of the product with the given ID or SKU
+	InvalidateProduct(ctx context.Context, productID, sku string) error
+}
+
 // IWishlistConnector defines operations for wishlist backends
-// Implementations: D365WishlistAdapter, CustomWishlistAdapter
+// Implementations: D365WishlistAdapter, MongoWishlistAdapter
 type IWishlistConnector interface {
 	IConnector
 	
@@ -78,6 +109,9 @@ type IWishlistConnector interface {
 	// CreateWishlist creates a new wishlist
 	CreateWishlist(ctx context.Context, customerID, name string, isPublic bool) (*models.Wishlist, error)
 	
+	// UpdateWishlist renames a wishlist or changes its visibility; nil fields are left unchanged
+	UpdateWishlist(ctx context.Context, wishlistID string, update models.WishlistUpdate) (*models.Wishlist, error)
+	
 	// AddItem adds an item to a wishlist
 	AddItem(ctx context.Context, wishlistID string, item models.AddWishlistItemRequest) (*models.WishlistItem, error)
 	
@@ -92,7 +126,7 @@ type IWishlistConnector interface {
 }
 
 // IKitchenConnector defines operations for kitchen management backends
-// Implementations: PureKDSAdapter, CustomKitchenAdapter
+// Implementations: PureKDSAdapter, InMemoryKitchenAdapter
 type IKitchenConnector interface {
 	IConnector
 	
@@ -102,13 +136,39 @@ type IKitchenConnector interface {
 	// GetOrderStatus retrieves the kitchen status of an order
 	GetOrderStatus(ctx context.Context, orderID string) (string, error)
 	
-	// UpdateOrderStatus updates the kitchen status
+	// UpdateOrderStatus updates the kitchen status (see models.KitchenStatus*)
 	UpdateOrderStatus(ctx context.Context, orderID string, status string) error
 	
-	// GetActiveOrders retrieves all active orders in the kitchen
+	// GetActiveOrders retrieves the IDs of all active orders in the kitchen, oldest first
 	GetActiveOrders(ctx context.Context) ([]string, error)
 }
 
+// IPaymentConnector defines operations for payment service providers
+// Implementations: PSPAdapter, FakePaymentAdapter
+type IPaymentConnector interface {
+	IConnector
+	
+	// Authorize reserves an amount on the customer's payment method
+	// A declined payment is returned with status declined, not as an error
+	Authorize(ctx context.Context, req models.PaymentRequest) (*models.Payment, error)
+	
+	// Capture collects up to the authorized amount; capturing a captured payment again is a no-op
+	Capture(ctx context.Context, paymentID string, amount float64) (*models.Payment, error)
+	
+	// Void releases an authorization that was not captured
+	Void(ctx context.Context, paymentID string) (*models.Payment, error)
+	
+	// Refund returns part or all of the captured amount
+	Refund(ctx context.Context, paymentID string, amount float64) (*models.Payment, error)
+	
+	// GetPayment retrieves a payment and its current status
+	GetPayment(ctx context.Context, paymentID string) (*models.Payment, error)
+	
+	// ParseWebhook authenticates a provider notification and decodes the payment it reports
+	// Notifications that fail authentication wrap ErrInvalidSignature
+	ParseWebhook(ctx context.Context, header http.Header, body []byte) (*models.PaymentEvent, error)
+}
+
 // ConnectorMetadata represents runtime information about a connector
 type ConnectorMetadata struct {
 	Domain      string            `json:"domain"`
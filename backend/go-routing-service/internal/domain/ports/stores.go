@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// ErrTenantRequired is returned by store repositories when a lookup names no tenant
+var ErrTenantRequired = errors.New("tenant is required")
+
+// IStoreRepository reads the store registry on behalf of one tenant at a time
+// Every lookup is filtered by tenantID: a store of another tenant is reported as ErrNotFound,
+// even when it has the same storeId, and an empty tenantID is rejected with ErrTenantRequired.
+type IStoreRepository interface {
+	// GetStore returns a store of the tenant
+	GetStore(ctx context.Context, tenantID, storeID string) (*models.Store, error)
+
+	// ListStores returns all stores of the tenant
+	ListStores(ctx context.Context, tenantID string) ([]models.Store, error)
+}
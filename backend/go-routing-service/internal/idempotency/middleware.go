@@ -0,0 +1,216 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// KeyHeader carries the client's idempotency key
+	KeyHeader = "Idempotency-Key"
+
+	// ReplayedHeader is set to "true" on replayed responses
+	ReplayedHeader = "Idempotent-Replayed"
+
+	// maxKeyLength bounds the length of a client key
+	maxKeyLength = 255
+
+	// maxBodyBytes bounds the size of a request body that is hashed
+	maxBodyBytes = 1 << 20
+
+	// defaultLockTTL is how long a claim blocks duplicates if the replica running the request dies
+	defaultLockTTL = 2 * time.Minute
+
+	// defaultWait is how long a duplicate waits for the first request's response
+	defaultWait = 30 * time.Second
+
+	// defaultPollInterval is how often a waiting duplicate checks for the response
+	defaultPollInterval = 100 * time.Millisecond
+)
+
+// Middleware makes mutating requests that carry an Idempotency-Key replay-safe
+type Middleware struct {
+	store        Store
+	ttl          time.Duration
+	scope        func(r *http.Request) string
+	lockTTL      time.Duration
+	wait         time.Duration
+	pollInterval time.Duration
+}
+
+// NewMiddleware creates a middleware keeping responses for ttl
+// scope namespaces keys per caller, e.g. by tenant and user, so that clients cannot replay
+// each other's responses.
+func NewMiddleware(store Store, ttl time.Duration, scope func(r *http.Request) string) *Middleware {
+	return &Middleware{
+		store:        store,
+		ttl:          ttl,
+		scope:        scope,
+		lockTTL:      defaultLockTTL,
+		wait:         defaultWait,
+		pollInterval: defaultPollInterval,
+	}
+}
+
+// Handler wraps next
+// Safe methods and requests without a key pass through. Responses with a 5xx status are not
+// stored, so a retry of a failed request runs it again.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientKey := r.Header.Get(KeyHeader)
+		if clientKey == "" || !isMutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(clientKey) > maxKeyLength {
+			http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		key := m.scope(r) + ":" + clientKey
+		requestHash := hashRequest(r, body)
+		deadline := time.Now().Add(m.wait)
+
+		for {
+			existing, err := m.store.Claim(ctx, key, requestHash, m.lockTTL)
+			if err != nil {
+				log.Error().Err(err).Str("store", m.store.Name()).Msg("Failed to claim idempotency key")
+				http.Error(w, "Idempotency store unavailable", http.StatusServiceUnavailable)
+				return
+			}
+
+			switch {
+			case existing == nil:
+				m.run(w, r, next, key, requestHash)
+				return
+			case existing.RequestHash != requestHash:
+				http.Error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+				return
+			case existing.Response != nil:
+				replay(w, existing.Response)
+				return
+			case time.Now().After(deadline):
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+				return
+			}
+
+			// The first request is still running
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(m.pollInterval):
+			}
+		}
+	})
+}
+
+// run serves the request holding the key and stores its response
+func (m *Middleware) run(w http.ResponseWriter, r *http.Request, next http.Handler, key, requestHash string) {
+	recorder := &responseRecorder{ResponseWriter: w}
+	completed := false
+
+	// Release the key if the handler fails or panics so that a retry can run it again
+	defer func() {
+		if completed {
+			return
+		}
+		if err := m.store.Release(context.WithoutCancel(r.Context()), key); err != nil {
+			log.Error().Err(err).Str("store", m.store.Name()).Msg("Failed to release idempotency key")
+		}
+	}()
+
+	next.ServeHTTP(recorder, r)
+
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	if recorder.status >= http.StatusInternalServerError {
+		return
+	}
+
+	record := Record{
+		RequestHash: requestHash,
+		Response: &Response{
+			Status: recorder.status,
+			Header: recorder.header,
+			Body:   recorder.body.Bytes(),
+		},
+	}
+	if err := m.store.Complete(context.WithoutCancel(r.Context()), key, record, m.ttl); err != nil {
+		log.Error().Err(err).Str("store", m.store.Name()).Msg("Failed to store idempotent response")
+		return
+	}
+	completed = true
+}
+
+// replay writes a stored response, keeping headers already set on this response
+func replay(w http.ResponseWriter, response *Response) {
+	for name, values := range response.Header {
+		if w.Header().Get(name) == "" {
+			w.Header()[name] = values
+		}
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+// isMutating reports whether requests with method change state
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hashRequest identifies a request by method, path, query and body
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status and the headers set so far
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status != 0 {
+		return
+	}
+	rr.status = status
+	rr.header = rr.ResponseWriter.Header().Clone()
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
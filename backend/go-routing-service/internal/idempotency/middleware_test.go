@@ -0,0 +1,210 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler creates orders with increasing IDs
+func countingHandler(calls *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"order-` + strconv.Itoa(int(n)) + `"}`))
+	})
+}
+
+func newTestMiddleware(store Store) *Middleware {
+	return NewMiddleware(store, time.Hour, func(r *http.Request) string {
+		return r.Header.Get("X-User")
+	})
+}
+
+func send(handler http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/commerce/orders", strings.NewReader(body))
+	req.Header.Set("X-User", "tenant:user-1")
+	if key != "" {
+		req.Header.Set(KeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_ReplaysResponse(t *testing.T) {
+	var calls int32
+	handler := newTestMiddleware(NewMemoryStore()).Handler(countingHandler(&calls))
+
+	first := send(handler, http.MethodPost, "key-1", `{"storeId":"s1"}`)
+	second := send(handler, http.MethodPost, "key-1", `{"storeId":"s1"}`)
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+
+	// Another key runs the request again
+	third := send(handler, http.MethodPost, "key-2", `{"storeId":"s1"}`)
+	assert.Equal(t, int32(2), calls)
+	assert.NotEqual(t, first.Body.String(), third.Body.String())
+}
+
+func TestMiddleware_RejectsKeyReuseWithDifferentRequest(t *testing.T) {
+	var calls int32
+	handler := newTestMiddleware(NewMemoryStore()).Handler(countingHandler(&calls))
+
+	send(handler, http.MethodPost, "key-1", `{"storeId":"s1"}`)
+	rec := send(handler, http.MethodPost, "key-1", `{"storeId":"s2"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestMiddleware_ScopesKeysPerCaller(t *testing.T) {
+	var calls int32
+	handler := newTestMiddleware(NewMemoryStore()).Handler(countingHandler(&calls))
+
+	send(handler, http.MethodPost, "key-1", `{}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/commerce/orders", strings.NewReader(`{}`))
+	req.Header.Set("X-User", "tenant:user-2")
+	req.Header.Set(KeyHeader, "key-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, int32(2), calls)
+	assert.Empty(t, rec.Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_PassesThroughWithoutKey(t *testing.T) {
+	var calls int32
+	handler := newTestMiddleware(NewMemoryStore()).Handler(countingHandler(&calls))
+
+	send(handler, http.MethodPost, "", `{}`)
+	send(handler, http.MethodPost, "", `{}`)
+	send(handler, http.MethodGet, "key-1", "")
+	send(handler, http.MethodGet, "key-1", "")
+
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestMiddleware_DoesNotStoreServerErrors(t *testing.T) {
+	var calls int32
+	handler := newTestMiddleware(NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			http.Error(w, "backend unavailable", http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	assert.Equal(t, http.StatusBadGateway, send(handler, http.MethodPost, "key-1", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, send(handler, http.MethodPost, "key-1", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, send(handler, http.MethodPost, "key-1", `{}`).Code)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestMiddleware_ReleasesKeyOnPanic(t *testing.T) {
+	store := NewMemoryStore()
+	handler := newTestMiddleware(store).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}))
+
+	assert.Panics(t, func() { send(handler, http.MethodPost, "key-1", `{}`) })
+
+	existing, err := store.Claim(t.Context(), "tenant:user-1:key-1", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "the key must be free for a retry")
+}
+
+func TestMiddleware_ConcurrentDuplicatesWaitForFirstRequest(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := newTestMiddleware(NewMemoryStore()).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"order-1"}`))
+	}))
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0] = send(handler, http.MethodPost, "key-1", `{}`)
+	}()
+	<-started
+
+	for i := 1; i < len(responses); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = send(handler, http.MethodPost, "key-1", `{}`)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	for _, rec := range responses {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, `{"id":"order-1"}`, rec.Body.String())
+	}
+}
+
+func TestMiddleware_GivesUpWaitingForSlowRequest(t *testing.T) {
+	store := NewMemoryStore()
+	middleware := newTestMiddleware(store)
+	middleware.wait = 20 * time.Millisecond
+	middleware.pollInterval = 5 * time.Millisecond
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	// Another replica is still running the request
+	_, err := store.Claim(t.Context(), "tenant:user-1:key-1", hashRequest(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/orders", nil), []byte(`{}`)), time.Minute)
+	require.NoError(t, err)
+
+	rec := send(handler, http.MethodPost, "key-1", `{}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestMemoryStore_ExpiresRecords(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	existing, err := store.Claim(t.Context(), "key", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	require.NoError(t, store.Complete(t.Context(), "key", Record{RequestHash: "hash", Response: &Response{Status: http.StatusCreated}}, time.Hour))
+
+	now = now.Add(59 * time.Minute)
+	existing, err = store.Claim(t.Context(), "key", "hash", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, http.StatusCreated, existing.Response.Status)
+
+	now = now.Add(2 * time.Minute)
+	existing, err = store.Claim(t.Context(), "key", "hash", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
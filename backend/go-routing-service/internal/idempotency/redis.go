@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// claimAttempts bounds how often Claim retries when the existing record expires while being read
+const claimAttempts = 3
+
+// RedisStore keeps records in Redis, so that retries reaching another replica are recognised
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a store on the given client; prefix namespaces its keys
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Name returns "redis"
+func (s *RedisStore) Name() string {
+	return "redis"
+}
+
+// Claim reserves key with SET NX, or reads the record holding it
+func (s *RedisStore) Claim(ctx context.Context, key, requestHash string, lockTTL time.Duration) (*Record, error) {
+	data, err := json.Marshal(Record{RequestHash: requestHash})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < claimAttempts; attempt++ {
+		claimed, err := s.client.SetNX(ctx, s.prefix+key, data, lockTTL).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		existing, err := s.client.Get(ctx, s.prefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			// The record expired or was released in between; try to claim it again
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+
+		var record Record
+		if err := json.Unmarshal(existing, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		return &record, nil
+	}
+
+	return nil, fmt.Errorf("failed to claim idempotency key after %d attempts", claimAttempts)
+}
+
+// Complete overwrites the claim with the record for ttl
+func (s *RedisStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release deletes the key
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
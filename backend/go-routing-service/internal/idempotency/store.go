@@ -0,0 +1,109 @@
+// Package idempotency lets clients retry mutating requests safely with an Idempotency-Key header.
+//
+// The first request with a key claims it and runs; its response is stored under the key and
+// replayed to later requests with the same key and the same body. Duplicates that arrive while
+// the first request is still running wait for its response. Reusing a key for a different
+// request is rejected.
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is a stored response, replayed to retries of the request that produced it
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Record is the state of an idempotency key
+type Record struct {
+	// RequestHash identifies the request that claimed the key
+	RequestHash string `json:"requestHash"`
+
+	// Response is nil while the request that claimed the key is still running
+	Response *Response `json:"response,omitempty"`
+}
+
+// Store keeps idempotency records
+type Store interface {
+	// Claim reserves key for the request with the given hash until lockTTL passes
+	// It returns nil when the caller now holds the key, or the key's existing record otherwise.
+	Claim(ctx context.Context, key, requestHash string, lockTTL time.Duration) (*Record, error)
+
+	// Complete stores the response of the request holding key for ttl
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release gives up a claim without storing a response, so that a retry runs the request again
+	Release(ctx context.Context, key string) error
+
+	// Name identifies the store in logs
+	Name() string
+}
+
+// memoryEntry is a record with its expiry
+type memoryEntry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// MemoryStore keeps records in process; it suits tests and single-replica development
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Name returns "memory"
+func (s *MemoryStore) Name() string {
+	return "memory"
+}
+
+// Claim reserves key unless an unexpired record holds it
+func (s *MemoryStore) Claim(ctx context.Context, key, requestHash string, lockTTL time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		record := entry.record
+		return &record, nil
+	}
+
+	s.entries[key] = memoryEntry{record: Record{RequestHash: requestHash}, expiresAt: now.Add(lockTTL)}
+	return nil, nil
+}
+
+// Complete stores the record for ttl, dropping expired records on the way
+func (s *MemoryStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.entries[key] = memoryEntry{record: record, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Release removes the key's record
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
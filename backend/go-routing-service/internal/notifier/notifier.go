@@ -0,0 +1,106 @@
+// Package notifier delivers wishlist alerts to customers' notification channels.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Notifier delivers a wishlist alert
+// Implementations must be safe for concurrent use
+type Notifier interface {
+	// Notify delivers one alert; an error means the alert was not delivered
+	Notify(ctx context.Context, alert models.WishlistAlert) error
+
+	// Name identifies the notifier in logs
+	Name() string
+}
+
+// LogNotifier writes alerts to the service log
+// It is the default when no webhook is configured
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the alert
+func (n *LogNotifier) Notify(ctx context.Context, alert models.WishlistAlert) error {
+	log.Info().
+		Str("alertId", alert.ID).
+		Str("type", alert.Type).
+		Str("tenantId", alert.TenantID).
+		Str("storeId", alert.StoreID).
+		Str("customerId", alert.CustomerID).
+		Str("wishlistId", alert.WishlistID).
+		Str("productId", alert.ProductID).
+		Float64("price", alert.Price.Amount).
+		Msg("Wishlist alert")
+	return nil
+}
+
+// Name identifies the notifier in logs
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+// WebhookNotifier posts alerts as JSON to a webhook URL
+// Any 2xx response counts as delivered
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &WebhookNotifier{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Notify posts the alert to the webhook
+func (n *WebhookNotifier) Notify(ctx context.Context, alert models.WishlistAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal wishlist alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Alert-Id", alert.ID)
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Name identifies the notifier in logs
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_PostsAlert(t *testing.T) {
+	var received models.WishlistAlert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "alert-1", r.Header.Get("X-Alert-Id"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, 0)
+	err := n.Notify(context.Background(), models.WishlistAlert{
+		ID:        "alert-1",
+		Type:      models.WishlistAlertPriceDrop,
+		ProductID: "12345",
+		Price:     models.Price{Amount: 79.99, Currency: "USD"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.WishlistAlertPriceDrop, received.Type)
+	assert.Equal(t, "12345", received.ProductID)
+	assert.Equal(t, 79.99, received.Price.Amount)
+}
+
+func TestWebhookNotifier_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL, 0).Notify(context.Background(), models.WishlistAlert{ID: "alert-1"})
+	assert.ErrorContains(t, err, "503")
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// Bus delivers published events to other services
+type Bus interface {
+	// Publish returns once the bus has accepted the event
+	Publish(ctx context.Context, event models.DomainEvent) error
+
+	// Name identifies the bus in logs
+	Name() string
+
+	// Close releases the bus's connections
+	Close() error
+}
+
+// Handler consumes events delivered by a MemoryBus
+type Handler func(ctx context.Context, event models.DomainEvent) error
+
+// MemoryBus delivers events to handlers in the same process
+// Publish calls the handlers synchronously and fails when any of them fails, so the relay
+// publishes the event again.
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewMemoryBus creates an in-process bus
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+// Name returns "memory"
+func (b *MemoryBus) Name() string {
+	return "memory"
+}
+
+// Subscribe registers a handler for every event
+func (b *MemoryBus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish calls every handler with the event
+func (b *MemoryBus) Publish(ctx context.Context, event models.DomainEvent) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("failed to handle %s event %s: %w", event.Type, event.ID, err)
+		}
+	}
+	return nil
+}
+
+// Close does nothing
+func (b *MemoryBus) Close() error {
+	return nil
+}
@@ -0,0 +1,151 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// natsDuplicateWindow is how long JetStream drops a republished event with the same ID
+	natsDuplicateWindow = 10 * time.Minute
+
+	// natsRedeliveryDelay is how long a consumer waits before an event its handler failed is redelivered
+	natsRedeliveryDelay = 5 * time.Second
+)
+
+// NATSBus publishes events to a NATS JetStream stream
+// Each event goes to <prefix>.<tenantId>.<aggregateType>.<type> and carries its ID as the
+// Nats-Msg-Id header, so JetStream drops events the relay publishes again within the
+// duplicate window. Publish waits for the stream's acknowledgement.
+type NATSBus struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	stream string
+	prefix string
+}
+
+// NewNATSBus connects to url and creates or updates the stream capturing prefix.>
+func NewNATSBus(ctx context.Context, url, stream, prefix string) (*NATSBus, error) {
+	conn, err := nats.Connect(url, nats.Name("go-routing-service"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:       stream,
+		Subjects:   []string{prefix + ".>"},
+		Storage:    jetstream.FileStorage,
+		Duplicates: natsDuplicateWindow,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", stream, err)
+	}
+
+	return &NATSBus{conn: conn, js: js, stream: stream, prefix: prefix}, nil
+}
+
+// Name returns "nats"
+func (b *NATSBus) Name() string {
+	return "nats"
+}
+
+// Subject returns the subject an event is published to
+func (b *NATSBus) Subject(event models.DomainEvent) string {
+	return strings.Join([]string{
+		b.prefix,
+		subjectToken(event.TenantID),
+		subjectToken(event.AggregateType),
+		subjectToken(event.Type),
+	}, ".")
+}
+
+// Publish sends the event and waits for JetStream to store it
+func (b *NATSBus) Publish(ctx context.Context, event models.DomainEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(b.Subject(event))
+	msg.Data = data
+	msg.Header.Set(jetstream.MsgIDHeader, event.ID)
+
+	if _, err := b.js.PublishMsg(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// Consume delivers events published from now on to handler through the durable consumer
+// named durable, until ctx is done
+// Replicas consuming with the same name share the events. An event is redelivered when handler
+// fails or the replica stops before handling it.
+func (b *NATSBus) Consume(ctx context.Context, durable string, handler Handler) error {
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, b.stream, jetstream.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: b.prefix + ".>",
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", durable, err)
+	}
+
+	consuming, err := consumer.Consume(func(msg jetstream.Msg) {
+		var event models.DomainEvent
+		if err := json.Unmarshal(msg.Data(), &event); err != nil {
+			log.Error().Err(err).Str("consumer", durable).Str("subject", msg.Subject()).Msg("Dropping undecodable event")
+			msg.Term()
+			return
+		}
+		if err := handler(ctx, event); err != nil {
+			log.Warn().Err(err).Str("consumer", durable).Str("eventId", event.ID).Msg("Failed to handle event, will redeliver")
+			msg.NakWithDelay(natsRedeliveryDelay)
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", durable, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		consuming.Stop()
+	}()
+	return nil
+}
+
+// Close drains the connection
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
+
+// subjectToken makes a value safe to use as one token of a subject
+func subjectToken(value string) string {
+	if value == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, value)
+}
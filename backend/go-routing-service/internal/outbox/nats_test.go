@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNATSBus_PublishesToJetStream runs against a local server started with
+//
+//	docker compose up -d nats
+//	NATS_URL=nats://localhost:4222 go test ./internal/outbox
+func TestNATSBus_PublishesToJetStream(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := "TEST_" + uuid.NewString()[:8]
+	prefix := "test." + stream
+	bus, err := NewNATSBus(ctx, url, stream, prefix)
+	require.NoError(t, err)
+	defer bus.Close()
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+	defer js.DeleteStream(context.Background(), stream)
+
+	created := testEvent(t, models.EventOrderCreated, "order-1")
+	cancelled := testEvent(t, models.EventOrderStatusChanged, "order-1")
+	require.NoError(t, bus.Publish(ctx, created))
+	require.NoError(t, bus.Publish(ctx, created), "a republished event is acknowledged")
+	require.NoError(t, bus.Publish(ctx, cancelled))
+
+	consumer, err := js.OrderedConsumer(ctx, stream, jetstream.OrderedConsumerConfig{})
+	require.NoError(t, err)
+	batch, err := consumer.Fetch(3, jetstream.FetchMaxWait(time.Second))
+	require.NoError(t, err)
+
+	var received []models.DomainEvent
+	for msg := range batch.Messages() {
+		var event models.DomainEvent
+		require.NoError(t, json.Unmarshal(msg.Data(), &event))
+		assert.Equal(t, bus.Subject(event), msg.Subject())
+		received = append(received, event)
+	}
+	require.Len(t, received, 2, "the duplicate is dropped by the stream")
+	assert.Equal(t, created.ID, received[0].ID)
+	assert.Equal(t, cancelled.ID, received[1].ID)
+}
+
+func TestNATSBus_ConsumesPublishedEvents(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := "TEST_" + uuid.NewString()[:8]
+	bus, err := NewNATSBus(ctx, url, stream, "test."+stream)
+	require.NoError(t, err)
+	defer bus.Close()
+	defer bus.js.DeleteStream(context.Background(), stream)
+
+	received := make(chan models.DomainEvent, 2)
+	failed := false
+	require.NoError(t, bus.Consume(ctx, "test", func(ctx context.Context, event models.DomainEvent) error {
+		// The first delivery fails and is redelivered
+		if !failed {
+			failed = true
+			return errors.New("consumer unavailable")
+		}
+		received <- event
+		return nil
+	}))
+
+	event := testEvent(t, models.EventOrderCreated, "order-1")
+	require.NoError(t, bus.Publish(ctx, event))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, event.ID, got.ID)
+	case <-ctx.Done():
+		t.Fatal("event was not consumed")
+	}
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// relayBatch is how many pending events the relay reads at a time
+	relayBatch = 100
+
+	// relayLease is how long a relay stays the only publisher after each run
+	// It must exceed the time needed to publish a batch.
+	relayLease = 30 * time.Second
+)
+
+// Relay publishes pending outbox events to a bus
+// Only the relay holding the store's lease publishes, so that events of one aggregate are
+// not published concurrently by several replicas.
+type Relay struct {
+	store Store
+	bus   Bus
+	owner string
+	batch int
+	lease time.Duration
+	now   func() time.Time
+}
+
+// NewRelay creates a relay; owner identifies this replica in the lease
+func NewRelay(store Store, bus Bus, owner string) *Relay {
+	return &Relay{store: store, bus: bus, owner: owner, batch: relayBatch, lease: relayLease, now: time.Now}
+}
+
+// RelayOnce publishes a batch of pending events and returns how many were published
+// An event that fails to publish is retried on the next run; later events of its aggregate
+// wait for it.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	leader, err := r.store.AcquireLease(ctx, r.owner, r.now(), r.lease)
+	if err != nil || !leader {
+		return 0, err
+	}
+
+	entries, err := r.store.ListPending(ctx, r.batch)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool)
+	for _, entry := range entries {
+		event := entry.Event
+		key := event.AggregateKey()
+		if blocked[key] {
+			continue
+		}
+
+		if err := r.bus.Publish(ctx, event); err != nil {
+			blocked[key] = true
+			log.Warn().
+				Err(err).
+				Str("bus", r.bus.Name()).
+				Str("eventId", event.ID).
+				Str("eventType", event.Type).
+				Int("attempts", entry.Attempts+1).
+				Msg("Failed to publish domain event")
+			if err := r.store.MarkFailed(ctx, event.ID, err); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		// An event published but not marked is published again on the next run
+		if err := r.store.MarkPublished(ctx, event.ID, r.now()); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// Run relays events every interval until ctx is done
+// A full batch is followed by the next one at once, so that a backlog drains quickly.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Str("bus", r.bus.Name()).Msg("Outbox relay failed")
+		}
+		if err == nil && published == r.batch && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(t *testing.T, eventType, aggregateID string) models.DomainEvent {
+	t.Helper()
+	event, err := models.NewDomainEvent(eventType, models.AggregateOrder, aggregateID, "ikea", "ikea-seattle", map[string]string{"orderId": aggregateID})
+	require.NoError(t, err)
+	return event
+}
+
+// recordingBus collects published events and fails the ones listed in fail
+type recordingBus struct {
+	*MemoryBus
+	published []models.DomainEvent
+	fail      map[string]bool
+}
+
+func newRecordingBus() *recordingBus {
+	bus := &recordingBus{MemoryBus: NewMemoryBus(), fail: make(map[string]bool)}
+	bus.Subscribe(func(ctx context.Context, event models.DomainEvent) error {
+		if bus.fail[event.ID] {
+			return errors.New("broker unavailable")
+		}
+		bus.published = append(bus.published, event)
+		return nil
+	})
+	return bus
+}
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	store := NewMemoryStore()
+	bus := newRecordingBus()
+	relay := NewRelay(store, bus, "replica-1")
+	ctx := context.Background()
+
+	created := testEvent(t, models.EventOrderCreated, "order-1")
+	cancelled := testEvent(t, models.EventOrderStatusChanged, "order-1")
+	require.NoError(t, store.Append(ctx, created, cancelled))
+
+	published, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	require.Len(t, bus.published, 2)
+	assert.Equal(t, created.ID, bus.published[0].ID)
+	assert.Equal(t, cancelled.ID, bus.published[1].ID)
+
+	pending, err := store.ListPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelay_FailedEventHoldsBackItsAggregate(t *testing.T) {
+	store := NewMemoryStore()
+	bus := newRecordingBus()
+	relay := NewRelay(store, bus, "replica-1")
+	ctx := context.Background()
+
+	first := testEvent(t, models.EventOrderCreated, "order-1")
+	second := testEvent(t, models.EventOrderStatusChanged, "order-1")
+	other := testEvent(t, models.EventOrderCreated, "order-2")
+	require.NoError(t, store.Append(ctx, first, second, other))
+	bus.fail[first.ID] = true
+
+	published, err := relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	require.Len(t, bus.published, 1)
+	assert.Equal(t, other.ID, bus.published[0].ID, "other aggregates are not held back")
+
+	pending, err := store.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "failed to handle OrderCreated event "+first.ID+": broker unavailable", pending[0].LastError)
+
+	// Once the bus recovers the held back events follow in order
+	delete(bus.fail, first.ID)
+	published, err = relay.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{other.ID, first.ID, second.ID}, []string{bus.published[0].ID, bus.published[1].ID, bus.published[2].ID})
+}
+
+func TestRelay_OnlyLeaseHolderPublishes(t *testing.T) {
+	store := NewMemoryStore()
+	bus := newRecordingBus()
+	now := time.Now()
+	first := NewRelay(store, bus, "replica-1")
+	first.now = func() time.Time { return now }
+	second := NewRelay(store, bus, "replica-2")
+	second.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := first.RelayOnce(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Append(ctx, testEvent(t, models.EventOrderCreated, "order-1")))
+	published, err := second.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	// The lease passes on once its holder stops renewing it
+	now = now.Add(relayLease + time.Second)
+	published, err = second.RelayOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+}
+
+func TestNATSBus_SubjectTokens(t *testing.T) {
+	bus := &NATSBus{prefix: "amicis.events"}
+	event := testEvent(t, models.EventOrderCreated, "order-1")
+	event.TenantID = "ikea.us"
+
+	assert.Equal(t, "amicis.events.ikea_us.order.OrderCreated", bus.Subject(event))
+}
@@ -0,0 +1,130 @@
+// Package outbox publishes domain events reliably through a transactional outbox.
+//
+// State changes append their events to an outbox store in the same logical operation, inside a
+// transaction where the store supports one. A relay then publishes pending events to a bus and
+// marks them published. Delivery is at least once: an event whose publication was not recorded
+// is published again, so consumers should deduplicate by event ID. Events of one aggregate are
+// published in the order they were appended; a failing event holds back the later events of its
+// aggregate until it is published.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+)
+
+// Entry is a pending event with its delivery attempts
+type Entry struct {
+	Event     models.DomainEvent
+	Attempts  int
+	LastError string
+}
+
+// Store keeps events until the relay has published them
+type Store interface {
+	// Append adds events to the outbox; inside Atomically they are written with the state change
+	Append(ctx context.Context, events ...models.DomainEvent) error
+
+	// Atomically runs fn so that the writes it makes through ctx, to the outbox and to other
+	// stores on the same database, are committed together
+	Atomically(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// ListPending returns unpublished events in the order they were appended
+	ListPending(ctx context.Context, limit int) ([]Entry, error)
+
+	// MarkPublished records that an event was published
+	MarkPublished(ctx context.Context, eventID string, at time.Time) error
+
+	// MarkFailed records a failed publication of an event
+	MarkFailed(ctx context.Context, eventID string, cause error) error
+
+	// AcquireLease makes owner the only relay for ttl, unless another owner holds an unexpired lease
+	AcquireLease(ctx context.Context, owner string, now time.Time, ttl time.Duration) (bool, error)
+}
+
+// MemoryStore keeps events in process; it suits tests and single-replica development
+// Atomically does not roll back events appended before fn fails.
+type MemoryStore struct {
+	mu          sync.Mutex
+	entries     []Entry
+	leaseOwner  string
+	leaseExpiry time.Time
+}
+
+// NewMemoryStore creates an in-process outbox store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append adds events in order
+func (s *MemoryStore) Append(ctx context.Context, events ...models.DomainEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		s.entries = append(s.entries, Entry{Event: event})
+	}
+	return nil
+}
+
+// Atomically runs fn
+func (s *MemoryStore) Atomically(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// ListPending returns up to limit pending events, oldest first
+func (s *MemoryStore) ListPending(ctx context.Context, limit int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit > len(s.entries) {
+		limit = len(s.entries)
+	}
+	return append([]Entry(nil), s.entries[:limit]...), nil
+}
+
+// MarkPublished removes a published event
+func (s *MemoryStore) MarkPublished(ctx context.Context, eventID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, entry := range s.entries {
+		if entry.Event.ID == eventID {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox event %s not found", eventID)
+}
+
+// MarkFailed counts a failed attempt
+func (s *MemoryStore) MarkFailed(ctx context.Context, eventID string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].Event.ID == eventID {
+			s.entries[i].Attempts++
+			s.entries[i].LastError = cause.Error()
+			return nil
+		}
+	}
+	return fmt.Errorf("outbox event %s not found", eventID)
+}
+
+// AcquireLease grants the lease to owner when it is free, expired or already owner's
+func (s *MemoryStore) AcquireLease(ctx context.Context, owner string, now time.Time, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.leaseOwner != owner && now.Before(s.leaseExpiry) {
+		return false, nil
+	}
+	s.leaseOwner = owner
+	s.leaseExpiry = now.Add(ttl)
+	return true, nil
+}
@@ -0,0 +1,122 @@
+package productcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Product data a caller relies on; each has its own TTL
+const (
+	// DataProduct is catalog data: names, images and variants, with price and stock shown for information
+	DataProduct = "product"
+
+	// DataPrice is the price a customer is charged
+	DataPrice = "price"
+
+	// DataAvailability is the stock a sale is checked against
+	DataAvailability = "availability"
+)
+
+type dataTypeKey struct{}
+
+// WithDataType returns a context whose product lookups need the given data to be fresh
+func WithDataType(ctx context.Context, dataType string) context.Context {
+	return context.WithValue(ctx, dataTypeKey{}, dataType)
+}
+
+// dataTypeFrom returns the data type of a lookup; lookups that state none read catalog data
+func dataTypeFrom(ctx context.Context) string {
+	if dataType, ok := ctx.Value(dataTypeKey{}).(string); ok {
+		return dataType
+	}
+	return DataProduct
+}
+
+// Config controls how long a store's products are cached
+type Config struct {
+	// Enabled turns caching off for the store when false
+	Enabled bool
+
+	// ProductTTL, PriceTTL and AvailabilityTTL are how long an entry is fresh for each data type
+	ProductTTL      time.Duration
+	PriceTTL        time.Duration
+	AvailabilityTTL time.Duration
+
+	// NotFoundTTL is how long a product the backend does not know is reported missing without asking again
+	NotFoundTTL time.Duration
+
+	// StaleWhileRevalidate is how long past its TTL an entry is still served while it is refreshed
+	StaleWhileRevalidate time.Duration
+}
+
+// DefaultConfig returns the TTLs used when a connector configures none
+func DefaultConfig() Config {
+	return Config{
+		Enabled:              true,
+		ProductTTL:           10 * time.Minute,
+		PriceTTL:             5 * time.Minute,
+		AvailabilityTTL:      10 * time.Second,
+		NotFoundTTL:          time.Minute,
+		StaleWhileRevalidate: 30 * time.Second,
+	}
+}
+
+// ConfigFrom reads the "cache" object of a connector config, e.g.
+// {"cache": {"priceTtl": "15m", "availabilityTtl": "5s"}}
+// Durations use Go syntax; missing or invalid values keep their defaults.
+func ConfigFrom(connectorConfig map[string]interface{}) Config {
+	config := DefaultConfig()
+	settings, ok := connectorConfig["cache"].(map[string]interface{})
+	if !ok {
+		return config
+	}
+
+	if enabled, ok := settings["enabled"].(bool); ok {
+		config.Enabled = enabled
+	}
+	readDuration(settings, "productTtl", &config.ProductTTL)
+	readDuration(settings, "priceTtl", &config.PriceTTL)
+	readDuration(settings, "availabilityTtl", &config.AvailabilityTTL)
+	readDuration(settings, "notFoundTtl", &config.NotFoundTTL)
+	readDuration(settings, "staleWhileRevalidate", &config.StaleWhileRevalidate)
+	return config
+}
+
+func readDuration(settings map[string]interface{}, name string, target *time.Duration) {
+	value, ok := settings[name]
+	if !ok {
+		return
+	}
+	text, _ := value.(string)
+	duration, err := time.ParseDuration(text)
+	if err != nil || duration < 0 {
+		log.Warn().Interface("value", value).Str("setting", name).Msg("Invalid product cache setting, using default")
+		return
+	}
+	*target = duration
+}
+
+// ttl returns how long an entry is fresh for a data type
+func (c Config) ttl(dataType string) time.Duration {
+	switch dataType {
+	case DataPrice:
+		return c.PriceTTL
+	case DataAvailability:
+		return c.AvailabilityTTL
+	default:
+		return c.ProductTTL
+	}
+}
+
+// retention is how long the store keeps an entry: it must still be there for the data type with the longest TTL
+func (c Config) retention() time.Duration {
+	longest := c.ProductTTL
+	for _, ttl := range []time.Duration{c.PriceTTL, c.AvailabilityTTL} {
+		if ttl > longest {
+			longest = ttl
+		}
+	}
+	return longest + c.StaleWhileRevalidate
+}
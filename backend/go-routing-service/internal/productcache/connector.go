@@ -0,0 +1,340 @@
+package productcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+// fetchTimeout bounds a backend fetch; fetches are shared by callers, so they do not use any caller's deadline
+const fetchTimeout = 10 * time.Second
+
+// Lookup results reported to the Observer
+const (
+	ResultHit   = "hit"
+	ResultStale = "stale"
+	ResultMiss  = "miss"
+)
+
+// Observer is told the data type and result of every cached lookup, e.g. to count hits and misses
+type Observer func(dataType, result string)
+
+// entry is a cached lookup result
+type entry struct {
+	Product   *models.Product     `json:"product,omitempty"`
+	List      *models.ProductList `json:"list,omitempty"`
+	NotFound  bool                `json:"notFound,omitempty"`
+	FetchedAt time.Time           `json:"fetchedAt"`
+}
+
+// Connector is an IRetailConnector that caches the product lookups of another
+// Orders and the connector lifecycle go straight to the wrapped connector.
+type Connector struct {
+	ports.IRetailConnector
+	store    Store
+	config   Config
+	scope    string
+	observe  Observer
+	requests singleflight.Group
+	now      func() time.Time
+}
+
+// NewConnector wraps a store's retail connector; observe may be nil
+func NewConnector(connector ports.IRetailConnector, store Store, tenantID, storeID string, config Config, observe Observer) *Connector {
+	if observe == nil {
+		observe = func(string, string) {}
+	}
+	return &Connector{
+		IRetailConnector: connector,
+		store:            store,
+		config:           config,
+		scope:            tenantID + ":" + storeID + ":",
+		observe:          observe,
+		now:              time.Now,
+	}
+}
+
+// Unwrap returns the wrapped connector, e.g. to reach interfaces the cache does not implement
+func (c *Connector) Unwrap() ports.IRetailConnector {
+	return c.IRetailConnector
+}
+
+// GetProduct reads a product by ID through the cache
+func (c *Connector) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	cached, err := c.lookup(ctx, c.productKey(productID), func(ctx context.Context) (*models.Product, *models.ProductList, error) {
+		product, err := c.IRetailConnector.GetProduct(ctx, productID)
+		return product, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached.NotFound {
+		return nil, fmt.Errorf("product %s: %w", productID, ports.ErrNotFound)
+	}
+	return cached.Product, nil
+}
+
+// GetProductBySKU reads a product by SKU through the cache
+func (c *Connector) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	cached, err := c.lookup(ctx, c.skuKey(sku), func(ctx context.Context) (*models.Product, *models.ProductList, error) {
+		product, err := c.IRetailConnector.GetProductBySKU(ctx, sku)
+		return product, nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached.NotFound {
+		return nil, fmt.Errorf("product with SKU %s: %w", sku, ports.ErrNotFound)
+	}
+	return cached.Product, nil
+}
+
+// GetProducts reads a product list through the cache
+// A search for a single SKU shares the entry of GetProductBySKU; other lists are cached
+// until any product of the store is invalidated.
+func (c *Connector) GetProducts(ctx context.Context, filters models.ProductFilters) (*models.ProductList, error) {
+	if isSKULookup(filters) {
+		cached, err := c.lookup(ctx, c.skuKey(filters.SKUs[0]), func(ctx context.Context) (*models.Product, *models.ProductList, error) {
+			list, err := c.IRetailConnector.GetProducts(ctx, filters)
+			if err != nil || len(list.Products) == 0 {
+				return nil, nil, err
+			}
+			return &list.Products[0], nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		list := &models.ProductList{Products: []models.Product{}, Limit: filters.Limit}
+		if !cached.NotFound {
+			list.Products = append(list.Products, *cached.Product)
+			list.Total = 1
+		}
+		return list, nil
+	}
+
+	key, err := c.listKey(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := c.lookup(ctx, key, func(ctx context.Context) (*models.Product, *models.ProductList, error) {
+		list, err := c.IRetailConnector.GetProducts(ctx, filters)
+		return nil, list, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached.NotFound {
+		return &models.ProductList{Products: []models.Product{}, Limit: filters.Limit, Offset: filters.Offset}, nil
+	}
+	return cached.List, nil
+}
+
+// InvalidateProduct drops the cached product with the given ID or SKU and every cached product list
+// It implements ports.IRetailCacheInvalidator.
+func (c *Connector) InvalidateProduct(ctx context.Context, productID, sku string) error {
+	keys := make(map[string]bool)
+	if productID != "" {
+		keys[c.productKey(productID)] = true
+	}
+	if sku != "" {
+		keys[c.skuKey(sku)] = true
+	}
+
+	// An entry read by ID also sits under its SKU, and the other way round
+	for key := range keys {
+		if cached, err := c.read(ctx, key); err == nil && cached != nil && cached.Product != nil {
+			for _, alias := range c.productKeys(cached.Product) {
+				keys[alias] = true
+			}
+		}
+	}
+
+	deleted := make([]string, 0, len(keys))
+	for key := range keys {
+		deleted = append(deleted, key)
+	}
+	if err := c.store.Delete(ctx, deleted...); err != nil {
+		return err
+	}
+	return c.store.Set(ctx, c.listVersionKey(), []byte(strconv.FormatInt(c.now().UnixNano(), 10)), 0)
+}
+
+// lookup returns the entry of key, fetching it from the backend when it is missing or too old
+// for the caller's data type. Entries within the stale-while-revalidate window are returned
+// while a refresh runs in the background, except for prices a customer is charged. Concurrent
+// fetches of a key share one backend call.
+func (c *Connector) lookup(ctx context.Context, key string, fetch func(context.Context) (*models.Product, *models.ProductList, error)) (*entry, error) {
+	dataType := dataTypeFrom(ctx)
+	if !c.config.Enabled {
+		product, list, err := fetch(ctx)
+		if isNotFound(product, list, err) {
+			return &entry{NotFound: true}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &entry{Product: product, List: list}, nil
+	}
+
+	cached, err := c.read(ctx, key)
+	if err != nil {
+		log.Warn().Err(err).Str("store", c.store.Name()).Str("key", key).Msg("Failed to read product cache, asking the backend")
+	}
+	if cached != nil {
+		age := c.now().Sub(cached.FetchedAt)
+		ttl := c.config.ttl(dataType)
+		if cached.NotFound {
+			ttl = c.config.NotFoundTTL
+		}
+		switch {
+		case age < ttl:
+			c.observe(dataType, ResultHit)
+			return cached, nil
+		case !cached.NotFound && dataType != DataPrice && age < ttl+c.config.StaleWhileRevalidate:
+			c.observe(dataType, ResultStale)
+			c.refresh(ctx, key, fetch)
+			return cached, nil
+		}
+	}
+
+	c.observe(dataType, ResultMiss)
+
+	// The fetch outlives a caller that gives up, so the callers sharing it still get a result
+	done := c.requests.DoChan(key, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+		return c.load(fetchCtx, key, fetch)
+	})
+	select {
+	case result := <-done:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return decode(result.Val.([]byte))
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh replaces a stale entry in the background unless a fetch of it is already running
+func (c *Connector) refresh(ctx context.Context, key string, fetch func(context.Context) (*models.Product, *models.ProductList, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+	go func() {
+		defer cancel()
+		if _, err, _ := c.requests.Do(key, func() (interface{}, error) {
+			return c.load(ctx, key, fetch)
+		}); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to refresh cached product")
+		}
+	}()
+}
+
+// load fetches an entry from the backend and stores it; backend errors other than not found are not cached
+// The encoded entry is returned so that callers sharing the fetch each decode their own copy.
+func (c *Connector) load(ctx context.Context, key string, fetch func(context.Context) (*models.Product, *models.ProductList, error)) ([]byte, error) {
+	product, list, err := fetch(ctx)
+	fetched := &entry{Product: product, List: list, FetchedAt: c.now()}
+	retention := c.config.retention()
+	if isNotFound(product, list, err) {
+		fetched = &entry{NotFound: true, FetchedAt: c.now()}
+		retention = c.config.NotFoundTTL
+	} else if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	// A product is also stored under its ID and SKU, so either lookup finds it
+	keys := []string{key}
+	if product != nil {
+		for _, alias := range c.productKeys(product) {
+			if alias != key {
+				keys = append(keys, alias)
+			}
+		}
+	}
+	for _, k := range keys {
+		if err := c.store.Set(ctx, k, encoded, retention); err != nil {
+			log.Warn().Err(err).Str("store", c.store.Name()).Str("key", k).Msg("Failed to write product cache")
+		}
+	}
+	return encoded, nil
+}
+
+// read returns the entry of key, or nil when there is none
+func (c *Connector) read(ctx context.Context, key string) (*entry, error) {
+	encoded, err := c.store.Get(ctx, key)
+	if err != nil || encoded == nil {
+		return nil, err
+	}
+	return decode(encoded)
+}
+
+func decode(encoded []byte) (*entry, error) {
+	var cached entry
+	if err := json.Unmarshal(encoded, &cached); err != nil {
+		return nil, fmt.Errorf("failed to decode cached product: %w", err)
+	}
+	return &cached, nil
+}
+
+// isNotFound reports whether a fetch found nothing; backends either return ErrNotFound or no result
+func isNotFound(product *models.Product, list *models.ProductList, err error) bool {
+	if err != nil {
+		return errors.Is(err, ports.ErrNotFound)
+	}
+	return product == nil && list == nil
+}
+
+// isSKULookup reports whether a product search only asks for one SKU
+func isSKULookup(filters models.ProductFilters) bool {
+	return len(filters.SKUs) == 1 && filters.Category == "" && filters.SearchTerm == "" &&
+		filters.MinPrice == nil && filters.MaxPrice == nil && filters.InStock == nil && filters.Offset == 0
+}
+
+func (c *Connector) productKey(productID string) string {
+	return c.scope + "id:" + productID
+}
+
+func (c *Connector) skuKey(sku string) string {
+	return c.scope + "sku:" + sku
+}
+
+func (c *Connector) productKeys(product *models.Product) []string {
+	keys := []string{c.productKey(product.ID)}
+	if product.SKU != "" {
+		keys = append(keys, c.skuKey(product.SKU))
+	}
+	return keys
+}
+
+func (c *Connector) listVersionKey() string {
+	return c.scope + "lists"
+}
+
+// listKey identifies a product search within the current list version of the store
+func (c *Connector) listKey(ctx context.Context, filters models.ProductFilters) (string, error) {
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return "", err
+	}
+	version, err := c.store.Get(ctx, c.listVersionKey())
+	if err != nil {
+		log.Warn().Err(err).Str("store", c.store.Name()).Msg("Failed to read product list version")
+	}
+	hash := sha256.Sum256(encoded)
+	return fmt.Sprintf("%slist:%s:%s", c.scope, version, hex.EncodeToString(hash[:])), nil
+}
@@ -0,0 +1,312 @@
+package productcache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRetail serves products from a map and counts backend calls
+type fakeRetail struct {
+	ports.IRetailConnector
+	mu       sync.Mutex
+	products map[string]*models.Product
+	calls    int
+}
+
+func (f *fakeRetail) find(match func(*models.Product) bool) *models.Product {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	for _, product := range f.products {
+		if match(product) {
+			clone := *product
+			return &clone
+		}
+	}
+	return nil
+}
+
+func (f *fakeRetail) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	if product := f.find(func(p *models.Product) bool { return p.ID == productID }); product != nil {
+		return product, nil
+	}
+	return nil, fmt.Errorf("product %s: %w", productID, ports.ErrNotFound)
+}
+
+func (f *fakeRetail) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	if product := f.find(func(p *models.Product) bool { return strings.EqualFold(p.SKU, sku) }); product != nil {
+		return product, nil
+	}
+	return nil, fmt.Errorf("product %s: %w", sku, ports.ErrNotFound)
+}
+
+func (f *fakeRetail) GetProducts(ctx context.Context, filters models.ProductFilters) (*models.ProductList, error) {
+	list := &models.ProductList{Products: []models.Product{}}
+	if product := f.find(func(p *models.Product) bool { return len(filters.SKUs) == 0 || p.SKU == filters.SKUs[0] }); product != nil {
+		list.Products = append(list.Products, *product)
+		list.Total = 1
+	}
+	return list, nil
+}
+
+func (f *fakeRetail) setPrice(productID string, amount float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.products[productID].Price.Amount = amount
+}
+
+func (f *fakeRetail) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+type fixture struct {
+	retail  *fakeRetail
+	cache   *Connector
+	now     time.Time
+	results map[string]int
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{
+		retail: &fakeRetail{products: map[string]*models.Product{
+			"1001": {ID: "1001", SKU: "BILLY-WHITE-001", Name: "BILLY Bookcase", Price: models.Price{Amount: 79.99, Currency: "USD"}},
+		}},
+		now:     time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		results: make(map[string]int),
+	}
+	var mu sync.Mutex
+	store := NewMemoryStore()
+	store.now = func() time.Time { return f.now }
+	f.cache = NewConnector(f.retail, store, "ikea", "ikea-seattle", DefaultConfig(), func(dataType, result string) {
+		mu.Lock()
+		defer mu.Unlock()
+		f.results[dataType+"/"+result]++
+	})
+	f.cache.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestConnector_TTLDependsOnDataType(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	product, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 79.99, product.Price.Amount)
+
+	// The entry read by ID is also found by SKU
+	_, err = f.cache.GetProductBySKU(ctx, "BILLY-WHITE-001")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.retail.callCount())
+
+	f.now = f.now.Add(time.Minute)
+	_, err = f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	_, err = f.cache.GetProduct(WithDataType(ctx, DataPrice), "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.retail.callCount(), "a minute old entry is fresh for catalog and price reads")
+
+	_, err = f.cache.GetProduct(WithDataType(ctx, DataAvailability), "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.retail.callCount(), "availability checks need data younger than 10 seconds")
+
+	assert.Equal(t, 1, f.results["product/miss"])
+	assert.Equal(t, 2, f.results["product/hit"])
+	assert.Equal(t, 1, f.results["price/hit"])
+	assert.Equal(t, 1, f.results["availability/miss"])
+}
+
+func TestConnector_ServesStaleEntriesWhileRefreshing(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+
+	f.retail.setPrice("1001", 69.99)
+	f.now = f.now.Add(10*time.Minute + 10*time.Second)
+
+	product, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 79.99, product.Price.Amount, "the stale entry is served at once")
+	assert.Equal(t, 1, f.results["product/stale"])
+
+	assert.Eventually(t, func() bool {
+		product, err := f.cache.GetProduct(ctx, "1001")
+		return err == nil && product.Price.Amount == 69.99
+	}, time.Second, 10*time.Millisecond, "the entry is refreshed in the background")
+
+	// Past the stale window the caller waits for the backend
+	f.retail.setPrice("1001", 59.99)
+	f.now = f.now.Add(20 * time.Minute)
+	product, err = f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 59.99, product.Price.Amount)
+}
+
+func TestConnector_NeverServesStalePrices(t *testing.T) {
+	f := newFixture(t)
+	ctx := WithDataType(context.Background(), DataPrice)
+
+	_, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+
+	f.retail.setPrice("1001", 69.99)
+	f.now = f.now.Add(5*time.Minute + 10*time.Second)
+
+	product, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 69.99, product.Price.Amount, "a price past its TTL is fetched before it is charged")
+	assert.Zero(t, f.results["price/stale"])
+	assert.Equal(t, 2, f.results["price/miss"])
+}
+
+func TestConnector_CachesMissingProducts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := f.cache.GetProductBySKU(ctx, "UNKNOWN-SKU")
+		assert.ErrorIs(t, err, ports.ErrNotFound)
+	}
+	assert.Equal(t, 1, f.retail.callCount())
+
+	list, err := f.cache.GetProducts(ctx, models.ProductFilters{SKUs: []string{"UNKNOWN-SKU"}})
+	require.NoError(t, err)
+	assert.Empty(t, list.Products, "a single SKU search shares the lookup by SKU")
+	assert.Equal(t, 1, f.retail.callCount())
+
+	f.now = f.now.Add(2 * time.Minute)
+	_, err = f.cache.GetProductBySKU(ctx, "UNKNOWN-SKU")
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+	assert.Equal(t, 2, f.retail.callCount(), "missing products are asked for again after notFoundTtl")
+}
+
+func TestConnector_KeepsRequestedKey(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	// The backend matches SKUs regardless of case, so the requested SKU differs from the product's
+	for i := 0; i < 3; i++ {
+		product, err := f.cache.GetProductBySKU(ctx, "billy-white-001")
+		require.NoError(t, err)
+		assert.Equal(t, "1001", product.ID)
+	}
+	_, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.retail.callCount(), "the entry is stored under the requested key and the product's keys")
+}
+
+func TestConnector_InvalidateProduct(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	_, err = f.cache.GetProducts(ctx, models.ProductFilters{Category: "bookcases"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.retail.callCount())
+
+	f.retail.setPrice("1001", 69.99)
+	require.NoError(t, f.cache.InvalidateProduct(ctx, "", "BILLY-WHITE-001"))
+
+	product, err := f.cache.GetProduct(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, 69.99, product.Price.Amount, "invalidating by SKU drops the entry read by ID")
+
+	list, err := f.cache.GetProducts(ctx, models.ProductFilters{Category: "bookcases"})
+	require.NoError(t, err)
+	assert.Equal(t, 69.99, list.Products[0].Price.Amount, "product lists are dropped as well")
+	assert.Equal(t, 4, f.retail.callCount())
+}
+
+func TestConnector_CoalescesConcurrentMisses(t *testing.T) {
+	f := newFixture(t)
+	block := make(chan struct{})
+	slow := &slowRetail{fakeRetail: f.retail, block: block}
+	f.cache.IRetailConnector = slow
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := f.cache.GetProduct(context.Background(), "1001")
+			assert.NoError(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(block)
+	wg.Wait()
+
+	assert.Equal(t, 1, f.retail.callCount())
+}
+
+func TestConnector_FetchOutlivesCancelledCaller(t *testing.T) {
+	f := newFixture(t)
+	block := make(chan struct{})
+	f.cache.IRetailConnector = &slowRetail{fakeRetail: f.retail, block: block}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := f.cache.GetProduct(ctx, "1001")
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled, "a caller that gives up returns at once")
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := f.cache.GetProduct(context.Background(), "1001")
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(block)
+	assert.NoError(t, <-second, "the shared fetch is not cancelled with the caller that started it")
+	assert.Equal(t, 1, f.retail.callCount())
+}
+
+// slowRetail holds product reads until block is closed
+type slowRetail struct {
+	*fakeRetail
+	block chan struct{}
+}
+
+func (s *slowRetail) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	<-s.block
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.fakeRetail.GetProduct(ctx, productID)
+}
+
+func TestConfigFrom(t *testing.T) {
+	config := ConfigFrom(map[string]interface{}{
+		"cache": map[string]interface{}{
+			"priceTtl":        "15m",
+			"availabilityTtl": "5s",
+			"notFoundTtl":     "soon",
+		},
+	})
+	assert.True(t, config.Enabled)
+	assert.Equal(t, 15*time.Minute, config.PriceTTL)
+	assert.Equal(t, 5*time.Second, config.AvailabilityTTL)
+	assert.Equal(t, DefaultConfig().NotFoundTTL, config.NotFoundTTL, "invalid durations keep their default")
+	assert.Equal(t, DefaultConfig().ProductTTL, config.ProductTTL)
+
+	assert.False(t, ConfigFrom(map[string]interface{}{"cache": map[string]interface{}{"enabled": false}}).Enabled)
+}
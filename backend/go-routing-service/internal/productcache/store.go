@@ -0,0 +1,93 @@
+// Package productcache caches the product lookups of retail connectors.
+//
+// Connector wraps an IRetailConnector and reads products through a Store keyed by tenant,
+// store and SKU or product ID. Callers state which product data they rely on with
+// WithDataType: catalog reads accept older entries than price or availability checks, each
+// data type having its own TTL. Entries somewhat past their TTL are served while a background
+// refresh replaces them, products the backend does not know are cached briefly as well, and
+// InvalidateProduct drops a product when its backend reports a change.
+package productcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps encoded cache entries
+type Store interface {
+	// Get returns the value of key, or nil when there is none
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key; a ttl of 0 keeps it until it is deleted or overwritten
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the keys
+	Delete(ctx context.Context, keys ...string) error
+
+	// Name identifies the store in logs
+	Name() string
+}
+
+// memoryEntry is a value with its expiry; a zero expiry never passes
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore keeps entries in process; it suits tests and single-replica development
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Name returns "memory"
+func (s *MemoryStore) Name() string {
+	return "memory"
+}
+
+// Get returns the value of key unless it expired
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !entry.expiresAt.IsZero() && !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	return entry.value, nil
+}
+
+// Set stores value under key
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = s.now().Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+// Delete removes the keys
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
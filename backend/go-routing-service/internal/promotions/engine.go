@@ -0,0 +1,133 @@
+// Package promotions validates discount codes and computes the discounts they grant.
+package promotions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// ErrInvalidCode is wrapped by CodeError for every rejected discount code
+var ErrInvalidCode = errors.New("invalid discount code")
+
+// CodeError explains why a discount code was rejected
+type CodeError struct {
+	Code   string
+	Reason string
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("discount code %s: %s", e.Code, e.Reason)
+}
+
+// Unwrap lets errors.Is match ErrInvalidCode
+func (e *CodeError) Unwrap() error {
+	return ErrInvalidCode
+}
+
+// Source looks up a tenant's promotion by its normalized code
+// It returns ports.ErrNotFound for unknown codes
+type Source interface {
+	FindPromotion(ctx context.Context, tenantID, code string) (*models.Promotion, error)
+}
+
+// Engine validates discount codes against the tenant's promotions
+type Engine struct {
+	source Source
+	now    func() time.Time
+}
+
+// NewEngine creates a promotion engine on the given source
+func NewEngine(source Source) *Engine {
+	return &Engine{source: source, now: time.Now}
+}
+
+// NormalizeCode trims and upper-cases a discount code; codes are stored normalized
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Apply validates the codes for a subtotal and returns the discounts they grant
+// Every code is checked; the first invalid one fails the whole call with a *CodeError.
+// Percentages apply to the subtotal, not to an already discounted amount, and the total
+// discount never exceeds the subtotal. Repeated codes count once.
+func (e *Engine) Apply(ctx context.Context, tenantID, storeID string, subtotal models.Price, codes []string) ([]models.AppliedDiscount, error) {
+	applied := []models.AppliedDiscount{}
+	seen := make(map[string]bool)
+	remaining := subtotal.Amount
+
+	for _, raw := range codes {
+		code := NormalizeCode(raw)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		promotion, err := e.source.FindPromotion(ctx, tenantID, code)
+		if errors.Is(err, ports.ErrNotFound) {
+			return nil, &CodeError{Code: code, Reason: "unknown code"}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up discount code %s: %w", code, err)
+		}
+
+		amount, err := e.discount(promotion, storeID, subtotal)
+		if err != nil {
+			return nil, err
+		}
+		if amount > remaining {
+			amount = remaining
+		}
+		remaining = models.RoundAmount(remaining - amount)
+
+		applied = append(applied, models.AppliedDiscount{
+			Code:        code,
+			Description: promotion.Description,
+			Amount:      models.Price{Amount: amount, Currency: subtotal.Currency},
+		})
+	}
+
+	return applied, nil
+}
+
+// discount checks that a promotion applies and returns its amount for the subtotal
+func (e *Engine) discount(promotion *models.Promotion, storeID string, subtotal models.Price) (float64, error) {
+	code := promotion.Code
+	now := e.now()
+
+	switch {
+	case !promotion.Active:
+		return 0, &CodeError{Code: code, Reason: "code is not active"}
+	case promotion.StartsAt != nil && now.Before(*promotion.StartsAt):
+		return 0, &CodeError{Code: code, Reason: "code is not valid yet"}
+	case promotion.EndsAt != nil && !now.Before(*promotion.EndsAt):
+		return 0, &CodeError{Code: code, Reason: "code has expired"}
+	case promotion.StoreID != "" && promotion.StoreID != storeID:
+		return 0, &CodeError{Code: code, Reason: "code is not valid in this store"}
+	case subtotal.Amount < promotion.MinSubtotal:
+		return 0, &CodeError{Code: code, Reason: fmt.Sprintf("subtotal must be at least %.2f", promotion.MinSubtotal)}
+	}
+
+	switch promotion.Type {
+	case models.PromotionTypePercentage:
+		if promotion.Value <= 0 || promotion.Value > 100 {
+			return 0, &CodeError{Code: code, Reason: "promotion is misconfigured"}
+		}
+		return models.RoundAmount(subtotal.Amount * promotion.Value / 100), nil
+	case models.PromotionTypeFixedAmount:
+		if promotion.Value <= 0 {
+			return 0, &CodeError{Code: code, Reason: "promotion is misconfigured"}
+		}
+		if promotion.Currency != subtotal.Currency {
+			return 0, &CodeError{Code: code, Reason: fmt.Sprintf("code is only valid for %s", promotion.Currency)}
+		}
+		return models.RoundAmount(promotion.Value), nil
+	default:
+		return 0, &CodeError{Code: code, Reason: "promotion is misconfigured"}
+	}
+}
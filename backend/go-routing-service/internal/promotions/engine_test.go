@@ -0,0 +1,81 @@
+package promotions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapSource serves promotions from a map keyed by code
+type mapSource map[string]models.Promotion
+
+func (s mapSource) FindPromotion(ctx context.Context, tenantID, code string) (*models.Promotion, error) {
+	promotion, ok := s[code]
+	if !ok || promotion.TenantID != tenantID {
+		return nil, ports.ErrNotFound
+	}
+	return &promotion, nil
+}
+
+func newTestEngine() *Engine {
+	past := time.Now().Add(-time.Hour)
+	return NewEngine(mapSource{
+		"SAVE10":   {Code: "SAVE10", TenantID: "tenant", Type: models.PromotionTypePercentage, Value: 10, Active: true},
+		"FIVEOFF":  {Code: "FIVEOFF", TenantID: "tenant", Type: models.PromotionTypeFixedAmount, Value: 5, Currency: "USD", Active: true},
+		"BIGSPEND": {Code: "BIGSPEND", TenantID: "tenant", Type: models.PromotionTypeFixedAmount, Value: 500, Currency: "USD", MinSubtotal: 100, Active: true},
+		"OLD":      {Code: "OLD", TenantID: "tenant", Type: models.PromotionTypePercentage, Value: 50, EndsAt: &past, Active: true},
+		"OFF":      {Code: "OFF", TenantID: "tenant", Type: models.PromotionTypePercentage, Value: 50},
+		"STORE2":   {Code: "STORE2", TenantID: "tenant", StoreID: "store-2", Type: models.PromotionTypePercentage, Value: 5, Active: true},
+	})
+}
+
+func TestEngine_AppliesCodes(t *testing.T) {
+	engine := newTestEngine()
+	subtotal := models.Price{Amount: 80, Currency: "USD"}
+
+	applied, err := engine.Apply(context.Background(), "tenant", "store-1", subtotal, []string{" save10 ", "FIVEOFF", "SAVE10"})
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "SAVE10", applied[0].Code)
+	assert.Equal(t, 8.0, applied[0].Amount.Amount)
+	assert.Equal(t, "USD", applied[0].Amount.Currency)
+	assert.Equal(t, 5.0, applied[1].Amount.Amount)
+}
+
+func TestEngine_CapsDiscountAtSubtotal(t *testing.T) {
+	applied, err := newTestEngine().Apply(context.Background(), "tenant", "store-1", models.Price{Amount: 120, Currency: "USD"}, []string{"BIGSPEND"})
+	require.NoError(t, err)
+	assert.Equal(t, 120.0, applied[0].Amount.Amount)
+}
+
+func TestEngine_RejectsInvalidCodes(t *testing.T) {
+	engine := newTestEngine()
+	subtotal := models.Price{Amount: 80, Currency: "USD"}
+
+	for code, reason := range map[string]string{
+		"NOPE":     "unknown code",
+		"OLD":      "code has expired",
+		"OFF":      "code is not active",
+		"STORE2":   "code is not valid in this store",
+		"BIGSPEND": "subtotal must be at least 100.00",
+	} {
+		_, err := engine.Apply(context.Background(), "tenant", "store-1", subtotal, []string{"SAVE10", code})
+		assert.ErrorIs(t, err, ErrInvalidCode, code)
+
+		var codeErr *CodeError
+		require.ErrorAs(t, err, &codeErr, code)
+		assert.Equal(t, code, codeErr.Code)
+		assert.Equal(t, reason, codeErr.Reason)
+	}
+
+	_, err := engine.Apply(context.Background(), "tenant", "store-1", models.Price{Amount: 80, Currency: "EUR"}, []string{"FIVEOFF"})
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = engine.Apply(context.Background(), "other-tenant", "store-1", subtotal, []string{"SAVE10"})
+	assert.ErrorIs(t, err, ErrInvalidCode)
+}
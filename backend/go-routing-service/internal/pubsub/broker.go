@@ -0,0 +1,82 @@
+// Package pubsub fans messages out to subscribers of a topic, in process or across replicas through Redis.
+//
+// Delivery is at most once: a subscriber that is slow, or not connected when a message is
+// published, misses it. Subscribers that need every change should re-read the current state
+// after subscribing and periodically.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is how many undelivered messages a subscriber may hold before new ones are dropped
+const subscriberBuffer = 16
+
+// Broker publishes messages to topics and delivers them to the topics' subscribers
+type Broker interface {
+	// Publish sends a message to the current subscribers of a topic
+	Publish(ctx context.Context, topic string, message []byte) error
+
+	// Subscribe returns a channel of the topic's messages; it is closed when ctx is done
+	Subscribe(ctx context.Context, topic string) (<-chan []byte, error)
+
+	// Name identifies the broker in logs
+	Name() string
+}
+
+// MemoryBroker delivers messages to subscribers in the same process
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string]map[chan []byte]struct{}
+}
+
+// NewMemoryBroker creates an in-process broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{topics: make(map[string]map[chan []byte]struct{})}
+}
+
+// Name returns "memory"
+func (b *MemoryBroker) Name() string {
+	return "memory"
+}
+
+// Publish sends a message to the topic's subscribers, skipping those whose buffer is full
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.topics[topic] {
+		select {
+		case ch <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber until ctx is done
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string) (<-chan []byte, error) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[chan []byte]struct{})
+	}
+	b.topics[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.topics[topic], ch)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
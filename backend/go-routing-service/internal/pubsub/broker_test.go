@@ -0,0 +1,76 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan []byte) string {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return string(msg)
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return ""
+	}
+}
+
+func TestMemoryBroker_FansOut(t *testing.T) {
+	broker := NewMemoryBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := broker.Subscribe(ctx, "checkout:t1:s1")
+	require.NoError(t, err)
+	second, err := broker.Subscribe(ctx, "checkout:t1:s1")
+	require.NoError(t, err)
+	other, err := broker.Subscribe(ctx, "checkout:t1:s2")
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(ctx, "checkout:t1:s1", []byte("paid")))
+
+	assert.Equal(t, "paid", receive(t, first))
+	assert.Equal(t, "paid", receive(t, second))
+	select {
+	case msg := <-other:
+		t.Fatalf("unexpected message %q on another topic", msg)
+	default:
+	}
+}
+
+func TestMemoryBroker_UnsubscribesOnCancel(t *testing.T) {
+	broker := NewMemoryBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := broker.Subscribe(ctx, "topic")
+	require.NoError(t, err)
+	cancel()
+
+	// The channel is closed once the subscription is removed
+	for range ch {
+	}
+	broker.mu.Lock()
+	assert.Empty(t, broker.topics)
+	broker.mu.Unlock()
+
+	assert.NoError(t, broker.Publish(context.Background(), "topic", []byte("ignored")))
+}
+
+func TestMemoryBroker_DropsWhenSubscriberIsSlow(t *testing.T) {
+	broker := NewMemoryBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := broker.Subscribe(ctx, "topic")
+	require.NoError(t, err)
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		require.NoError(t, broker.Publish(ctx, "topic", []byte("update")))
+	}
+	assert.Len(t, ch, subscriberBuffer)
+}
@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBroker delivers messages through Redis pub/sub, so subscribers on every replica receive them
+type RedisBroker struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisBroker creates a broker on the given client; prefix namespaces its channels
+func NewRedisBroker(client redis.UniversalClient, prefix string) *RedisBroker {
+	return &RedisBroker{client: client, prefix: prefix}
+}
+
+// Name returns "redis"
+func (b *RedisBroker) Name() string {
+	return "redis"
+}
+
+// Publish sends a message to the topic's channel
+func (b *RedisBroker) Publish(ctx context.Context, topic string, message []byte) error {
+	if err := b.client.Publish(ctx, b.prefix+topic, message).Err(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Subscribe subscribes to the topic's channel until ctx is done
+// Messages are dropped while the subscriber's buffer is full
+func (b *RedisBroker) Subscribe(ctx context.Context, topic string) (<-chan []byte, error) {
+	sub := b.client.Subscribe(ctx, b.prefix+topic)
+
+	// Wait for the subscription to be confirmed so that no message published after
+	// Subscribe returns is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	ch := make(chan []byte, subscriberBuffer)
+	go func() {
+		defer close(ch)
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- []byte(msg.Payload):
+				default:
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
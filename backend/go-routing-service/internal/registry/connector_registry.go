@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/amicis/go-routing-service/internal/domain/ports"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -26,6 +28,9 @@ type ConnectorRegistry struct {
 	stopCleanup       chan bool
 }
 
+// ErrConnectorNotFound is returned when no connector document exists for a tenant, store and domain
+var ErrConnectorNotFound = errors.New("connector not found")
+
 // ConnectorFactory is a function that creates a new connector instance
 type ConnectorFactory func(config ports.ConnectorConfig) (ports.IConnector, error)
 
@@ -200,6 +205,19 @@ func (r *ConnectorRegistry) ListConnectors(ctx context.Context, tenantID, storeI
 	return metadataList, nil
 }
 
+// AddConnector caches an initialized connector for a tenant, store and domain, so that it is
+// resolved without a connector document, e.g. for connectors set up in code
+// Like any cached connector it is evicted after an hour without use.
+func (r *ConnectorRegistry) AddConnector(tenantID, storeID, domain string, connector ports.IConnector) {
+	cacheKey := r.getCacheKey(tenantID, storeID, domain)
+
+	r.cacheMutex.Lock()
+	defer r.cacheMutex.Unlock()
+
+	r.cache[cacheKey] = connector
+	r.lastAccessTimes[cacheKey] = time.Now()
+}
+
 // InvalidateCache removes a connector from the cache
 func (r *ConnectorRegistry) InvalidateCache(tenantID, storeID, domain string) {
 	cacheKey := r.getCacheKey(tenantID, storeID, domain)
@@ -254,7 +272,7 @@ func (r *ConnectorRegistry) loadConnectorConfig(ctx context.Context, tenantID, s
 	err := r.connectorsDB.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("connector not found for tenantId=%s, storeId=%s, domain=%s", tenantID, storeID, domain)
+			return nil, fmt.Errorf("%w for tenantId=%s, storeId=%s, domain=%s", ErrConnectorNotFound, tenantID, storeID, domain)
 		}
 		return nil, err
 	}
@@ -266,7 +284,7 @@ func (r *ConnectorRegistry) loadConnectorConfig(ctx context.Context, tenantID, s
 		URL:      doc.URL,
 		Adapter:  doc.Adapter,
 		Version:  doc.Version,
-		Config:   doc.Config,
+		Config:   normalizeConfig(doc.Config),
 		Enabled:  doc.Enabled,
 		Timeout:  doc.Timeout,
 	}
@@ -344,3 +362,41 @@ func (r *ConnectorRegistry) performCleanup() {
 		r.cacheMutex.Unlock()
 	}
 }
+
+// normalizeConfig converts nested BSON documents and arrays in a connector config
+// into plain maps and slices so adapters can treat the config like decoded JSON
+func normalizeConfig(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	normalized := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		normalized[key] = normalizeValue(value)
+	}
+	return normalized
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.D:
+		normalized := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			normalized[elem.Key] = normalizeValue(elem.Value)
+		}
+		return normalized
+	case primitive.M:
+		return normalizeConfig(v)
+	case map[string]interface{}:
+		return normalizeConfig(v)
+	case primitive.A:
+		return normalizeValue([]interface{}(v))
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, elem := range v {
+			normalized[i] = normalizeValue(elem)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
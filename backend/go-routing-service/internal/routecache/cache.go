@@ -0,0 +1,175 @@
+package routecache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+// How a value was served, as reported in the X-Cache header
+const (
+	StatusHit   = "HIT"
+	StatusMiss  = "MISS"
+	StatusStale = "STALE"
+)
+
+// Loader reads the JSON-encoded value of a key from the backend
+type Loader func(ctx context.Context) ([]byte, error)
+
+// Config controls how long routing data is cached
+type Config struct {
+	// SoftTTL is how long an entry is served without reloading it
+	SoftTTL time.Duration
+
+	// HardTTL is how long an entry is served at all; past SoftTTL it is refreshed in the background
+	HardTTL time.Duration
+
+	// StaleTTL is how long past HardTTL an entry is kept as the last known value
+	StaleTTL time.Duration
+
+	// LoadTimeout bounds a load, which outlives the request that started it when others share it
+	LoadTimeout time.Duration
+
+	// ServeStale reports whether a load error allows serving the last known value; nil never does
+	ServeStale func(err error) bool
+}
+
+// DefaultConfig returns the TTLs used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		SoftTTL:     50 * time.Minute,
+		HardTTL:     time.Hour,
+		StaleTTL:    24 * time.Hour,
+		LoadTimeout: 10 * time.Second,
+	}
+}
+
+// entry is a cached value with the time it was loaded
+type entry struct {
+	Value     json.RawMessage `json:"value"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+}
+
+// Cache reads routing data through a Store
+type Cache struct {
+	store    Store
+	config   Config
+	requests singleflight.Group
+	now      func() time.Time
+
+	// generations counts the invalidations of each key; a load only stores its value if the
+	// generation it started under is still current, and mu orders that check against Invalidate
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+// New creates a cache on the given store
+func New(store Store, config Config) *Cache {
+	return &Cache{store: store, config: config, now: time.Now, generations: make(map[string]uint64)}
+}
+
+// Get returns the value of key and how it was served, loading it when it is missing or past its
+// hard TTL. Errors of load are returned as they are unless ServeStale allows the last known value.
+func (c *Cache) Get(ctx context.Context, key string, load Loader) ([]byte, string, error) {
+	cached, err := c.read(ctx, key)
+	if err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("Failed to read route cache, loading from the backend")
+	}
+	if cached != nil {
+		age := c.now().Sub(cached.FetchedAt)
+		switch {
+		case age < c.config.SoftTTL:
+			return cached.Value, StatusHit, nil
+		case age < c.config.HardTTL:
+			c.refresh(ctx, key, load)
+			return cached.Value, StatusHit, nil
+		}
+	}
+
+	value, err := c.load(ctx, key, load)
+	if err != nil {
+		if cached != nil && c.config.ServeStale != nil && c.config.ServeStale(err) {
+			log.Warn().Err(err).Str("key", key).Time("fetchedAt", cached.FetchedAt).Msg("Serving last known route")
+			return cached.Value, StatusStale, nil
+		}
+		return nil, "", err
+	}
+	return value, StatusMiss, nil
+}
+
+// Invalidate drops the entry of key, including its last known value, so that the next Get loads it
+// Loads of key already running in this process still answer their callers but no longer store
+// what they read, since it may predate the change.
+func (c *Cache) Invalidate(ctx context.Context, key string) error {
+	c.mu.Lock()
+	c.generations[key]++
+	c.mu.Unlock()
+
+	c.requests.Forget(key)
+	return c.store.Delete(ctx, key)
+}
+
+// refresh reloads an entry in the background unless a load of it is already running
+func (c *Cache) refresh(ctx context.Context, key string, load Loader) {
+	go func() {
+		if _, err := c.load(ctx, key, load); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to refresh cached route")
+		}
+	}()
+}
+
+// load reads key from the backend and stores it; concurrent loads of a key share one call
+// The load is detached from the caller's cancellation since other callers may be waiting on it.
+func (c *Cache) load(ctx context.Context, key string, load Loader) ([]byte, error) {
+	value, err, _ := c.requests.Do(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.LoadTimeout)
+		defer cancel()
+
+		c.mu.Lock()
+		generation := c.generations[key]
+		c.mu.Unlock()
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(entry{Value: value, FetchedAt: c.now()})
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.generations[key] != generation {
+			log.Debug().Str("key", key).Msg("Route was invalidated while loading, not caching it")
+			return value, nil
+		}
+		if err := c.store.Set(ctx, key, encoded, c.config.HardTTL+c.config.StaleTTL); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to write route cache")
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]byte), nil
+}
+
+// read returns the entry of key, or nil when there is none
+func (c *Cache) read(ctx context.Context, key string) (*entry, error) {
+	encoded, err := c.store.Get(ctx, key)
+	if err != nil || encoded == nil {
+		return nil, err
+	}
+	var cached entry
+	if err := json.Unmarshal(encoded, &cached); err != nil {
+		return nil, fmt.Errorf("failed to decode cached route: %w", err)
+	}
+	return &cached, nil
+}
@@ -0,0 +1,206 @@
+package routecache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("circuit is open")
+
+type fixture struct {
+	store *MemoryStore
+	cache *Cache
+	now   time.Time
+	loads atomic.Int32
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{store: NewMemoryStore(), now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	f.store.now = func() time.Time { return f.now }
+
+	config := DefaultConfig()
+	config.ServeStale = func(err error) bool { return errors.Is(err, errUnavailable) }
+	f.cache = New(f.store, config)
+	f.cache.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *fixture) loader(value string, err error) Loader {
+	return func(ctx context.Context) ([]byte, error) {
+		f.loads.Add(1)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(value), nil
+	}
+}
+
+func TestGetLoadsOnMissAndHitsAfterwards(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+	assert.JSONEq(t, `{"v":1}`, string(value))
+
+	value, status, err = f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusHit, status)
+	assert.JSONEq(t, `{"v":1}`, string(value))
+	assert.EqualValues(t, 1, f.loads.Load())
+}
+
+func TestGetCoalescesConcurrentLoads(t *testing.T) {
+	f := newFixture(t)
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]byte, error) {
+		f.loads.Add(1)
+		<-release
+		return []byte(`{"v":1}`), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _, err := f.cache.Get(context.Background(), "store:1", load)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"v":1}`, string(value))
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, f.loads.Load())
+}
+
+func TestGetRefreshesInBackgroundPastSoftTTL(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, _, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(55 * time.Minute)
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusHit, status)
+	assert.JSONEq(t, `{"v":1}`, string(value))
+
+	assert.Eventually(t, func() bool {
+		value, _, _ := f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+		return string(value) == `{"v":2}`
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGetReloadsPastHardTTL(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, _, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(2 * time.Hour)
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+	assert.JSONEq(t, `{"v":2}`, string(value))
+}
+
+func TestGetServesLastKnownValueWhenBackendUnavailable(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, _, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(3 * time.Hour)
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader("", errUnavailable))
+	require.NoError(t, err)
+	assert.Equal(t, StatusStale, status)
+	assert.JSONEq(t, `{"v":1}`, string(value))
+
+	// Other errors, e.g. a store that no longer exists, are not hidden by the last known value
+	notFound := errors.New("not found")
+	_, _, err = f.cache.Get(ctx, "store:1", f.loader("", notFound))
+	assert.ErrorIs(t, err, notFound)
+}
+
+func TestGetFailsWithoutLastKnownValue(t *testing.T) {
+	f := newFixture(t)
+
+	_, _, err := f.cache.Get(context.Background(), "store:1", f.loader("", errUnavailable))
+	assert.ErrorIs(t, err, errUnavailable)
+}
+
+func TestGetForgetsLastKnownValueAfterStaleTTL(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, _, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(26 * time.Hour)
+	_, _, err = f.cache.Get(ctx, "store:1", f.loader("", errUnavailable))
+	assert.ErrorIs(t, err, errUnavailable)
+}
+
+func TestInvalidateDropsEntry(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, _, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":1}`, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, f.cache.Invalidate(ctx, "store:1"))
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+	assert.JSONEq(t, `{"v":2}`, string(value))
+
+	// An invalidated store has no last known value to fall back to
+	require.NoError(t, f.cache.Invalidate(ctx, "store:1"))
+	_, _, err = f.cache.Get(ctx, "store:1", f.loader("", errUnavailable))
+	assert.ErrorIs(t, err, errUnavailable)
+}
+
+func TestInvalidateDuringLoadDoesNotStoreOldValue(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-release
+		return []byte(`{"v":1}`), nil
+	}
+
+	done := make(chan []byte)
+	go func() {
+		value, _, err := f.cache.Get(ctx, "store:1", slow)
+		assert.NoError(t, err)
+		done <- value
+	}()
+
+	<-started
+	require.NoError(t, f.cache.Invalidate(ctx, "store:1"))
+	close(release)
+
+	// The caller that asked before the change still gets its answer, but it is not cached
+	assert.JSONEq(t, `{"v":1}`, string(<-done))
+	encoded, err := f.store.Get(ctx, "store:1")
+	require.NoError(t, err)
+	assert.Nil(t, encoded)
+
+	value, status, err := f.cache.Get(ctx, "store:1", f.loader(`{"v":2}`, nil))
+	require.NoError(t, err)
+	assert.Equal(t, StatusMiss, status)
+	assert.JSONEq(t, `{"v":2}`, string(value))
+}
@@ -0,0 +1,81 @@
+// Package routecache caches the routing data of stores.
+//
+// Cache reads entries through a Store with a soft and a hard TTL. Entries younger than the
+// soft TTL are served as they are; entries between the soft and the hard TTL are served while
+// a background refresh replaces them, so that busy keys are reloaded before they expire.
+// Concurrent loads of a key share one call to the backend. Entries are kept past their hard
+// TTL as the last known value, which is served when the backend is unavailable. Invalidate
+// drops an entry when its source changes.
+package routecache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps encoded cache entries
+type Store interface {
+	// Get returns the value of key, or nil when there is none
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the keys
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// memoryEntry is a value with its expiry
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore keeps entries in process; it suits tests and single-replica development
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Get returns the value of key unless it expired
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	return entry.value, nil
+}
+
+// Set stores value under key
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{value: value, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Delete removes the keys
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownKey is returned when a token names a key the keyring does not hold
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Keyring signs tokens with its active key and verifies tokens signed with any of its keys
+// Its tokens are prefixed with the key ID ("<kid>.<payload>.<signature>"), so a key can be
+// rotated by adding a new active key and keeping the old one until its tokens have expired.
+type Keyring struct {
+	activeID string
+	signers  map[string]*Signer
+}
+
+// NewKeyring creates a keyring that signs with the key named activeID
+func NewKeyring(activeID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", activeID)
+	}
+
+	signers := make(map[string]*Signer, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ".") {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("key %q is empty", id)
+		}
+		signers[id] = NewSigner(key)
+	}
+
+	return &Keyring{activeID: activeID, signers: signers}, nil
+}
+
+// ParseKeys reads keys written as "kid:secret" pairs separated by commas
+// The first key is returned as the active one
+func ParseKeys(spec string) (string, map[string][]byte, error) {
+	var activeID string
+	keys := make(map[string][]byte)
+
+	for i, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			// The entry is not echoed, since it may hold a secret
+			return "", nil, fmt.Errorf("invalid key entry %d, expected kid:secret", i+1)
+		}
+		if _, dup := keys[id]; dup {
+			return "", nil, fmt.Errorf("duplicate key ID %q", id)
+		}
+		if activeID == "" {
+			activeID = id
+		}
+		keys[id] = []byte(secret)
+	}
+
+	if activeID == "" {
+		return "", nil, errors.New("no keys given")
+	}
+	return activeID, keys, nil
+}
+
+// ActiveKeyID returns the ID of the key new tokens are signed with
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Sign returns a URL-safe token carrying payload, signed with the active key
+func (k *Keyring) Sign(payload []byte) string {
+	return k.activeID + "." + k.signers[k.activeID].Sign(payload)
+}
+
+// Verify checks the token signature and returns its payload and the ID of the key that signed it
+func (k *Keyring) Verify(token string) ([]byte, string, error) {
+	id, signed, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, "", ErrMalformedToken
+	}
+
+	signer, ok := k.signers[id]
+	if !ok {
+		return nil, "", ErrUnknownKey
+	}
+
+	payload, err := signer.Verify(signed)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, id, nil
+}
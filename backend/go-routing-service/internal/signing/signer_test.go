@@ -0,0 +1,83 @@
+package signing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner([]byte("share-secret"))
+
+	token := signer.Sign([]byte(`{"wishlistId":"abc"}`))
+	assert.NotContains(t, token, "=", "tokens are unpadded")
+	assert.NotContains(t, token, "/")
+
+	payload, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, `{"wishlistId":"abc"}`, string(payload))
+}
+
+func TestSigner_RejectsTampering(t *testing.T) {
+	signer := NewSigner([]byte("share-secret"))
+	token := signer.Sign([]byte(`{"wishlistId":"abc"}`))
+
+	forged := NewSigner([]byte("other-secret")).Sign([]byte(`{"wishlistId":"abc"}`))
+	_, err := signer.Verify(forged)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Swap in a different payload but keep the original signature
+	_, signature, _ := strings.Cut(token, ".")
+	_, err = signer.Verify(encoding.EncodeToString([]byte(`{"wishlistId":"xyz"}`)) + "." + signature)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	for _, malformed := range []string{"", "no-dot", "a.b.c", "!!.??"} {
+		_, err = signer.Verify(malformed)
+		assert.Error(t, err, malformed)
+	}
+}
+
+func TestKeyring_RotatesKeys(t *testing.T) {
+	old, err := NewKeyring("k1", map[string][]byte{"k1": []byte("old-secret")})
+	require.NoError(t, err)
+	oldToken := old.Sign([]byte("payload"))
+	assert.True(t, strings.HasPrefix(oldToken, "k1."))
+
+	rotated, err := NewKeyring("k2", map[string][]byte{"k1": []byte("old-secret"), "k2": []byte("new-secret")})
+	require.NoError(t, err)
+
+	payload, keyID, err := rotated.Verify(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+	assert.Equal(t, "k1", keyID)
+
+	newToken := rotated.Sign([]byte("payload"))
+	_, keyID, err = rotated.Verify(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyID)
+
+	_, _, err = old.Verify(newToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// A token cannot be moved to another key by editing its key ID
+	_, _, err = rotated.Verify("k2" + strings.TrimPrefix(oldToken, "k1"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestParseKeys(t *testing.T) {
+	activeID, keys, err := ParseKeys(" k2:new-secret , k1:old:secret ")
+	require.NoError(t, err)
+	assert.Equal(t, "k2", activeID)
+	assert.Equal(t, []byte("new-secret"), keys["k2"])
+	assert.Equal(t, []byte("old:secret"), keys["k1"])
+
+	for _, spec := range []string{"", "no-key-id", "k1:a,k1:b", ":secret"} {
+		_, _, err := ParseKeys(spec)
+		assert.Error(t, err, spec)
+	}
+
+	_, err = NewKeyring("k1", map[string][]byte{"k.1": []byte("secret"), "k1": []byte("secret")})
+	assert.Error(t, err)
+}
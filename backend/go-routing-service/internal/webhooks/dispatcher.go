@@ -0,0 +1,224 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// deliveryLease is how long a claimed delivery is left to its worker
+// It must exceed the time all retries of a delivery can take.
+const deliveryLease = 2 * time.Minute
+
+// RetryFunc runs operation until it succeeds, it returns an error that IsRetryable rejects,
+// or the retries are exhausted
+type RetryFunc func(ctx context.Context, operation func() error) error
+
+// permanentError is a response that retrying will not change, e.g. 404 or 410
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// IsRetryable reports whether a failed attempt should be retried
+// Network errors, timeouts, 408, 429 and 5xx responses are retried; other 4xx responses are not.
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	return !errors.As(err, &permanent)
+}
+
+// Dispatcher turns domain events into deliveries and sends them
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	retry  RetryFunc
+	lease  time.Duration
+	now    func() time.Time
+}
+
+// NewDispatcher creates a dispatcher sending deliveries with client and retrying them with retry
+func NewDispatcher(store Store, client *http.Client, retry RetryFunc) *Dispatcher {
+	return &Dispatcher{store: store, client: client, retry: retry, lease: deliveryLease, now: time.Now}
+}
+
+// Handle enqueues a delivery of the event for every active subscription of its tenant that
+// matches the event type
+// Delivery IDs are derived from the event and subscription, so an event handled again is not
+// delivered twice.
+func (d *Dispatcher) Handle(ctx context.Context, event models.DomainEvent) error {
+	subscriptions, err := d.store.ListSubscriptions(ctx, event.TenantID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !subscription.Matches(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+			}
+		}
+
+		now := d.now().UTC()
+		delivery := &models.WebhookDelivery{
+			ID:             uuid.NewSHA1(uuid.NameSpaceURL, []byte(event.ID+"/"+subscription.ID)).String(),
+			SubscriptionID: subscription.ID,
+			TenantID:       event.TenantID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			NextAttemptAt:  now,
+		}
+		if err := d.store.EnqueueDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay sends a failed delivery again with the subscription's current URL and secret
+func (d *Dispatcher) Replay(ctx context.Context, tenantID, deliveryID string) (*models.WebhookDelivery, error) {
+	delivery, err := d.store.GetDelivery(ctx, tenantID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != models.WebhookDeliveryFailed {
+		return nil, fmt.Errorf("webhook delivery %s is %s, only failed deliveries can be replayed: %w", deliveryID, delivery.Status, ports.ErrInvalidTransition)
+	}
+
+	now := d.now().UTC()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.UpdatedAt = now
+	delivery.NextAttemptAt = now
+	if err := d.store.SaveDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DeliverNext claims a due delivery and sends it; it reports whether one was due
+func (d *Dispatcher) DeliverNext(ctx context.Context) (bool, error) {
+	delivery, err := d.store.ClaimDelivery(ctx, d.now().UTC(), d.lease)
+	if err != nil || delivery == nil {
+		return false, err
+	}
+	return true, d.deliver(ctx, delivery)
+}
+
+// Run sends due deliveries with workers concurrent workers until ctx is cancelled
+// An idle worker looks for due deliveries every interval.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				found, err := d.DeliverNext(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("Webhook dispatcher failed to deliver")
+				}
+				if found && ctx.Err() == nil {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+}
+
+// deliver sends a claimed delivery with retries and records the outcome
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	subscription, err := d.store.GetSubscription(ctx, delivery.TenantID, delivery.SubscriptionID)
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		err = errors.New("subscription was deleted")
+	case err != nil:
+		return err
+	case !subscription.Active:
+		err = errors.New("subscription is inactive")
+	default:
+		err = d.retry(ctx, func() error {
+			delivery.Attempts++
+			status, err := d.send(ctx, subscription, delivery)
+			delivery.ResponseStatus = status
+			return err
+		})
+	}
+
+	now := d.now().UTC()
+	delivery.UpdatedAt = now
+	if err != nil {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+		log.Warn().
+			Err(err).
+			Str("tenantId", delivery.TenantID).
+			Str("subscriptionId", delivery.SubscriptionID).
+			Str("deliveryId", delivery.ID).
+			Int("attempts", delivery.Attempts).
+			Msg("Webhook delivery failed, moved to dead letters")
+	} else {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	}
+
+	// The outcome is recorded even when ctx was cancelled during the attempts
+	return d.store.SaveDelivery(context.WithoutCancel(ctx), delivery)
+}
+
+// send makes one attempt and returns the response status, or 0 when there was no response
+func (d *Dispatcher) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, &permanentError{fmt.Errorf("invalid webhook request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "amicis-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, delivery.Payload, d.now()))
+
+	resp, err := d.client.Do(req)
+	if errors.Is(err, ErrForbiddenAddress) {
+		return 0, &permanentError{fmt.Errorf("webhook request failed: %w", err)}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	default:
+		return resp.StatusCode, &permanentError{fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)}
+	}
+}
@@ -0,0 +1,192 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retryTimes retries retryable errors up to attempts times without waiting
+func retryTimes(attempts int) RetryFunc {
+	return func(ctx context.Context, operation func() error) error {
+		var err error
+		for i := 0; i < attempts; i++ {
+			if err = operation(); err == nil || !IsRetryable(err) {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func testSubscription(t *testing.T, store Store, url string, eventTypes ...string) *models.WebhookSubscription {
+	t.Helper()
+	subscription := &models.WebhookSubscription{
+		ID:         "sub-" + url,
+		TenantID:   "ikea",
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     "whsec_test",
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	require.NoError(t, store.CreateSubscription(context.Background(), subscription))
+	return subscription
+}
+
+func testEvent(t *testing.T, eventType string) models.DomainEvent {
+	t.Helper()
+	event, err := models.NewDomainEvent(eventType, models.AggregateOrder, "order-1", "ikea", "ikea-seattle", map[string]string{"orderId": "order-1"})
+	require.NoError(t, err)
+	return event
+}
+
+func TestDispatcher_HandleEnqueuesMatchingSubscriptions(t *testing.T) {
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(store, http.DefaultClient, retryTimes(1))
+	ctx := context.Background()
+
+	orders := testSubscription(t, store, "https://example.com/orders", models.EventOrderCreated)
+	testSubscription(t, store, "https://example.com/checkout", models.EventCheckoutPaid)
+	inactive := testSubscription(t, store, "https://example.com/inactive", models.EventOrderCreated)
+	inactive.Active = false
+	require.NoError(t, store.UpdateSubscription(ctx, inactive))
+
+	event := testEvent(t, models.EventOrderCreated)
+	require.NoError(t, dispatcher.Handle(ctx, event))
+	require.NoError(t, dispatcher.Handle(ctx, event), "an event handled again is not delivered twice")
+
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{TenantID: "ikea"})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, orders.ID, deliveries[0].SubscriptionID)
+	assert.Equal(t, event.ID, deliveries[0].EventID)
+	assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(store, server.Client(), retryTimes(3))
+	ctx := context.Background()
+
+	testSubscription(t, store, server.URL, models.EventOrderCreated)
+	event := testEvent(t, models.EventOrderCreated)
+	require.NoError(t, dispatcher.Handle(ctx, event))
+
+	found, err := dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	require.NotNil(t, received)
+	assert.Equal(t, models.EventOrderCreated, received.Header.Get(EventHeader))
+	assert.NoError(t, Verify("whsec_test", received.Header.Get(SignatureHeader), body, time.Now(), time.Minute))
+	assert.Contains(t, string(body), event.ID)
+
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{TenantID: "ikea"})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, received.Header.Get(DeliveryHeader), deliveries[0].ID)
+	assert.Equal(t, models.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+
+	found, err = dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, found, "delivered deliveries are not sent again")
+}
+
+func TestDispatcher_FailedDeliveriesAreDeadLetteredAndReplayed(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(store, server.Client(), retryTimes(3))
+	ctx := context.Background()
+
+	testSubscription(t, store, server.URL, models.EventOrderCreated)
+	require.NoError(t, dispatcher.Handle(ctx, testEvent(t, models.EventOrderCreated)))
+
+	_, err := dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	deadLetters, err := store.ListDeliveries(ctx, DeliveryFilter{TenantID: "ikea", Status: models.WebhookDeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	delivery := deadLetters[0]
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	assert.Equal(t, "webhook endpoint returned 503", delivery.LastError)
+
+	// Once the endpoint recovers the delivery can be replayed
+	status = http.StatusOK
+	_, err = dispatcher.Replay(ctx, "ikea", delivery.ID)
+	require.NoError(t, err)
+	_, err = dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+
+	replayed, err := store.GetDelivery(ctx, "ikea", delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryDelivered, replayed.Status)
+	assert.Equal(t, 4, replayed.Attempts)
+
+	_, err = dispatcher.Replay(ctx, "ikea", delivery.ID)
+	assert.ErrorIs(t, err, ports.ErrInvalidTransition, "delivered deliveries are not replayed")
+	_, err = dispatcher.Replay(ctx, "other-tenant", delivery.ID)
+	assert.ErrorIs(t, err, ports.ErrNotFound)
+}
+
+func TestDispatcher_ClientErrorsAreNotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(store, server.Client(), retryTimes(3))
+	ctx := context.Background()
+
+	testSubscription(t, store, server.URL, models.EventOrderCreated)
+	require.NoError(t, dispatcher.Handle(ctx, testEvent(t, models.EventOrderCreated)))
+
+	_, err := dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestVerify_RejectsTamperedAndStaleDeliveries(t *testing.T) {
+	body := []byte(`{"eventId":"event-1"}`)
+	now := time.Now()
+	header := Sign("whsec_test", body, now)
+
+	assert.NoError(t, Verify("whsec_test", header, body, now, time.Minute))
+	assert.ErrorIs(t, Verify("whsec_other", header, body, now, time.Minute), ports.ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", header, []byte(`{"eventId":"event-2"}`), now, time.Minute), ports.ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", header, body, now.Add(2*time.Minute), time.Minute), ports.ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", "v1=abc", body, now, time.Minute), ports.ErrInvalidSignature)
+}
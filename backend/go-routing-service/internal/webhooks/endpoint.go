@@ -0,0 +1,88 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhook endpoints on loopback, link-local or private addresses
+var ErrForbiddenAddress = errors.New("webhook endpoint address is not public")
+
+// IsForbiddenIP reports whether ip is a loopback, link-local, private or unspecified address
+// Deliveries are sent from inside the cluster, so these addresses would reach internal services.
+func IsForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// ValidateEndpoint checks that rawURL is an absolute https URL whose host only resolves to public addresses
+// The addresses of a host can change after it was checked, so NewHTTPClient checks them again
+// on every connection.
+func ValidateEndpoint(ctx context.Context, resolver *net.Resolver, rawURL string) error {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Hostname() == "" {
+		return errors.New("url must be an absolute https URL")
+	}
+
+	host := endpoint.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if IsForbiddenIP(ip) {
+			return fmt.Errorf("url host %s: %w", host, ErrForbiddenAddress)
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("url host %s cannot be resolved", host)
+	}
+	for _, addr := range addrs {
+		if IsForbiddenIP(addr.IP) {
+			return fmt.Errorf("url host %s resolves to %s: %w", host, addr.IP, ErrForbiddenAddress)
+		}
+	}
+	return nil
+}
+
+// NewHTTPClient creates the client deliveries are sent with
+// It refuses to connect to forbidden addresses, also after redirects, and does not use a proxy,
+// so the address it checks is the one it connects to.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: dialControl}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to %s: %w", req.URL.Redacted(), ErrForbiddenAddress)
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// dialControl rejects connections to forbidden addresses once the host has been resolved
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || IsForbiddenIP(ip) {
+		return fmt.Errorf("dial %s: %w", address, ErrForbiddenAddress)
+	}
+	return nil
+}
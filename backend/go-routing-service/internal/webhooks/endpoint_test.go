@@ -0,0 +1,67 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, ValidateEndpoint(ctx, net.DefaultResolver, "https://203.0.113.10/hooks"))
+
+	for _, rawURL := range []string{"http://203.0.113.10/hooks", "ftp://example.com", "/hooks", "https://"} {
+		assert.Error(t, ValidateEndpoint(ctx, net.DefaultResolver, rawURL), rawURL)
+	}
+
+	for _, rawURL := range []string{
+		"https://127.0.0.1/hooks",
+		"https://[::1]/hooks",
+		"https://localhost/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hooks",
+		"https://172.16.0.1/hooks",
+		"https://192.168.1.1/hooks",
+		"https://[fd00::1]/hooks",
+		"https://0.0.0.0/hooks",
+	} {
+		assert.ErrorIs(t, ValidateEndpoint(ctx, net.DefaultResolver, rawURL), ErrForbiddenAddress, rawURL)
+	}
+}
+
+func TestNewHTTPClient_RefusesForbiddenAddresses(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// A subscription saved while its host was public can later resolve to an internal address
+	store := NewMemoryStore()
+	dispatcher := NewDispatcher(store, NewHTTPClient(time.Second), retryTimes(3))
+	ctx := context.Background()
+
+	testSubscription(t, store, server.URL, models.EventOrderCreated)
+	require.NoError(t, dispatcher.Handle(ctx, testEvent(t, models.EventOrderCreated)))
+
+	found, err := dispatcher.DeliverNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Zero(t, calls)
+
+	deliveries, err := store.ListDeliveries(ctx, DeliveryFilter{TenantID: "ikea"})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts, "a forbidden address is not retried")
+	assert.Contains(t, deliveries[0].LastError, ErrForbiddenAddress.Error())
+}
@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// Headers sent with every delivery
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+	SignatureHeader = "Amicis-Signature"
+
+	// EventHeader carries the domain event type
+	EventHeader = "Amicis-Event"
+
+	// DeliveryHeader carries the delivery ID, which stays the same across retries and replays
+	DeliveryHeader = "Amicis-Delivery"
+)
+
+// NewSecret returns a random signing secret for a subscription
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// Sign returns the signature header value of a delivery body sent at the given time
+func Sign(secret string, body []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, signatureMAC(secret, timestamp, body))
+}
+
+// Verify checks a signature header against the body, as a receiving endpoint would
+// Deliveries signed more than tolerance away from now are rejected as possible replays.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("malformed %s header: %w", SignatureHeader, ports.ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed %s timestamp: %w", SignatureHeader, ports.ErrInvalidSignature)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook timestamp is outside the tolerance of %s: %w", tolerance, ports.ErrInvalidSignature)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(signatureMAC(secret, timestamp, body))) {
+		return fmt.Errorf("webhook signature does not match: %w", ports.ErrInvalidSignature)
+	}
+	return nil
+}
+
+func signatureMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,216 @@
+// Package webhooks pushes domain events to endpoints registered by tenants.
+//
+// Events reaching a Dispatcher become one delivery per matching subscription. Deliveries are
+// signed with the subscription's secret and retried with exponential backoff; a delivery that
+// exhausts its retries is kept as failed, forming the tenant's dead-letter list, until it is
+// replayed. Every delivery stays queryable in the subscription's delivery log.
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+)
+
+// DeliveryFilter selects deliveries of a tenant; empty fields match every delivery
+type DeliveryFilter struct {
+	TenantID       string
+	SubscriptionID string
+	Status         string
+	Limit          int
+}
+
+// Store keeps subscriptions and their deliveries
+// Get, update and delete methods return ports.ErrNotFound for unknown IDs or IDs of another tenant.
+type Store interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, tenantID, subscriptionID string) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, tenantID, subscriptionID string) error
+
+	// EnqueueDelivery stores a new delivery; a delivery with the same ID is left unchanged
+	EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	// ClaimDelivery returns a pending delivery of any tenant due at now and pushes its next
+	// attempt back by lease, so other workers skip it; it returns nil when none is due
+	ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+
+	SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, tenantID, deliveryID string) (*models.WebhookDelivery, error)
+
+	// ListDeliveries returns matching deliveries, newest first
+	ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error)
+}
+
+// MemoryStore keeps subscriptions and deliveries in process; it suits tests and single-replica development
+type MemoryStore struct {
+	mu            sync.Mutex
+	subscriptions map[string]models.WebhookSubscription
+	deliveries    map[string]models.WebhookDelivery
+}
+
+// NewMemoryStore creates an in-process webhook store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		subscriptions: make(map[string]models.WebhookSubscription),
+		deliveries:    make(map[string]models.WebhookDelivery),
+	}
+}
+
+// CreateSubscription stores a new subscription
+func (s *MemoryStore) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[subscription.ID] = copySubscription(*subscription)
+	return nil
+}
+
+// GetSubscription returns a subscription of the tenant
+func (s *MemoryStore) GetSubscription(ctx context.Context, tenantID, subscriptionID string) (*models.WebhookSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, ok := s.subscriptions[subscriptionID]
+	if !ok || subscription.TenantID != tenantID {
+		return nil, fmt.Errorf("webhook subscription %s: %w", subscriptionID, ports.ErrNotFound)
+	}
+	subscription = copySubscription(subscription)
+	return &subscription, nil
+}
+
+// ListSubscriptions returns the subscriptions of the tenant, oldest first
+func (s *MemoryStore) ListSubscriptions(ctx context.Context, tenantID string) ([]models.WebhookSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscriptions := []models.WebhookSubscription{}
+	for _, subscription := range s.subscriptions {
+		if subscription.TenantID == tenantID {
+			subscriptions = append(subscriptions, copySubscription(subscription))
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+// UpdateSubscription replaces a subscription of the tenant
+func (s *MemoryStore) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.subscriptions[subscription.ID]
+	if !ok || stored.TenantID != subscription.TenantID {
+		return fmt.Errorf("webhook subscription %s: %w", subscription.ID, ports.ErrNotFound)
+	}
+	s.subscriptions[subscription.ID] = copySubscription(*subscription)
+	return nil
+}
+
+// DeleteSubscription removes a subscription of the tenant; its deliveries are kept
+func (s *MemoryStore) DeleteSubscription(ctx context.Context, tenantID, subscriptionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, ok := s.subscriptions[subscriptionID]
+	if !ok || subscription.TenantID != tenantID {
+		return fmt.Errorf("webhook subscription %s: %w", subscriptionID, ports.ErrNotFound)
+	}
+	delete(s.subscriptions, subscriptionID)
+	return nil
+}
+
+// EnqueueDelivery stores a delivery unless one with its ID exists
+func (s *MemoryStore) EnqueueDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		s.deliveries[delivery.ID] = *delivery
+	}
+	return nil
+}
+
+// ClaimDelivery claims the longest-due pending delivery
+func (s *MemoryStore) ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due *models.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || delivery.NextAttemptAt.Before(due.NextAttemptAt) {
+			delivery := delivery
+			due = &delivery
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+
+	due.NextAttemptAt = now.Add(lease)
+	s.deliveries[due.ID] = *due
+	return due, nil
+}
+
+// SaveDelivery replaces a delivery
+func (s *MemoryStore) SaveDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		return fmt.Errorf("webhook delivery %s: %w", delivery.ID, ports.ErrNotFound)
+	}
+	s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+// GetDelivery returns a delivery of the tenant
+func (s *MemoryStore) GetDelivery(ctx context.Context, tenantID, deliveryID string) (*models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[deliveryID]
+	if !ok || delivery.TenantID != tenantID {
+		return nil, fmt.Errorf("webhook delivery %s: %w", deliveryID, ports.ErrNotFound)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries returns matching deliveries, newest first
+func (s *MemoryStore) ListDeliveries(ctx context.Context, filter DeliveryFilter) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.TenantID != filter.TenantID ||
+			(filter.SubscriptionID != "" && delivery.SubscriptionID != filter.SubscriptionID) ||
+			(filter.Status != "" && delivery.Status != filter.Status) {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+	return deliveries, nil
+}
+
+func copySubscription(subscription models.WebhookSubscription) models.WebhookSubscription {
+	subscription.EventTypes = append([]string(nil), subscription.EventTypes...)
+	return subscription
+}
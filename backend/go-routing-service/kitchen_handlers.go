@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/registry"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Kitchen handlers - routed through the "kitchen" connector domain
+
+// getKitchenConnector resolves the kitchen connector for a store
+func (app *App) getKitchenConnector(ctx context.Context, tenantID, storeID string) (ports.IKitchenConnector, error) {
+	connector, err := app.connectorRegistry.GetConnector(ctx, tenantID, storeID, "kitchen")
+	if err != nil {
+		return nil, err
+	}
+
+	kitchenConnector, ok := connector.(ports.IKitchenConnector)
+	if !ok {
+		return nil, fmt.Errorf("connector %s does not implement IKitchenConnector", connector.GetAdapterType())
+	}
+
+	return kitchenConnector, nil
+}
+
+// forwardToKitchen submits the food lines of a new order to the store's kitchen
+// It is best effort: stores without a kitchen connector are skipped and failures are only logged,
+// so a kitchen outage never blocks order creation
+func (app *App) forwardToKitchen(ctx context.Context, tenantID, storeID, orderID string, lineItems []models.OrderLineItem) {
+	var kitchenItems []models.OrderLineItem
+	for _, item := range lineItems {
+		if models.IsKitchenItem(item) {
+			kitchenItems = append(kitchenItems, item)
+		}
+	}
+	if len(kitchenItems) == 0 {
+		return
+	}
+
+	correlationID := GetCorrelationID(ctx)
+
+	kitchenConnector, err := app.getKitchenConnector(ctx, tenantID, storeID)
+	if errors.Is(err, registry.ErrConnectorNotFound) {
+		log.Debug().
+			Str("correlationId", correlationID).
+			Str("storeId", storeID).
+			Msg("Store has no kitchen connector, food lines not forwarded")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("correlationId", correlationID).Str("orderId", orderID).Msg("Failed to get kitchen connector")
+		return
+	}
+
+	if err := kitchenConnector.SubmitOrder(ctx, orderID, kitchenItems); err != nil {
+		log.Error().
+			Err(err).
+			Str("correlationId", correlationID).
+			Str("adapter", kitchenConnector.GetAdapterType()).
+			Str("orderId", orderID).
+			Msg("Failed to forward order to kitchen")
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("storeId", storeID).
+		Str("orderId", orderID).
+		Int("kitchenItemCount", len(kitchenItems)).
+		Msg("Order forwarded to kitchen")
+}
+
+// writeKitchenError maps kitchen connector errors to HTTP responses
+func writeKitchenError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		http.Error(w, "Kitchen order not found", http.StatusNotFound)
+	case errors.Is(err, ports.ErrInvalidTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusBadGateway)
+	}
+}
+
+// kitchenSubmitOrderHandler handles POST /api/v1/kitchen/orders
+func (app *App) kitchenSubmitOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req models.KitchenOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.StoreID == "" || req.OrderID == "" || len(req.Items) == 0 {
+		http.Error(w, "storeId, orderId and items are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("orderId", req.OrderID).
+		Int("itemCount", len(req.Items)).
+		Msg("Kitchen submit order request")
+
+	kitchenConnector, err := app.getKitchenConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get kitchen connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := kitchenConnector.SubmitOrder(ctx, req.OrderID, req.Items); err != nil {
+		log.Error().Err(err).Str("adapter", kitchenConnector.GetAdapterType()).Msg("Failed to submit order to kitchen")
+		writeKitchenError(w, err, "submit order to kitchen")
+		return
+	}
+
+	status, err := kitchenConnector.GetOrderStatus(ctx, req.OrderID)
+	if err != nil {
+		log.Warn().Err(err).Str("orderId", req.OrderID).Msg("Failed to read kitchen status after submit")
+		status = models.KitchenStatusReceived
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(models.KitchenOrderStatus{
+		OrderID: req.OrderID,
+		StoreID: req.StoreID,
+		Status:  status,
+	})
+}
+
+// kitchenActiveOrdersHandler handles GET /api/v1/kitchen/orders
+func (app *App) kitchenActiveOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get storeId from query params
+	storeID := r.URL.Query().Get("storeId")
+	if storeID == "" {
+		http.Error(w, "storeId query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Msg("Kitchen active orders request")
+
+	kitchenConnector, err := app.getKitchenConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get kitchen connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	orderIDs, err := kitchenConnector.GetActiveOrders(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", kitchenConnector.GetAdapterType()).Msg("Failed to get active kitchen orders")
+		writeKitchenError(w, err, "get active kitchen orders")
+		return
+	}
+
+	orders := make([]models.KitchenOrderStatus, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		status, err := kitchenConnector.GetOrderStatus(ctx, orderID)
+		if errors.Is(err, ports.ErrNotFound) {
+			// Finished between the two calls
+			continue
+		}
+		if err != nil {
+			log.Error().Err(err).Str("orderId", orderID).Msg("Failed to get kitchen order status")
+			writeKitchenError(w, err, "get active kitchen orders")
+			return
+		}
+		if !models.IsActiveKitchenStatus(status) {
+			continue
+		}
+		orders = append(orders, models.KitchenOrderStatus{OrderID: orderID, StoreID: storeID, Status: status})
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"orders": orders,
+		"count":  len(orders),
+	})
+}
+
+// kitchenOrderStatusHandler handles GET /api/v1/kitchen/orders/{orderId}
+func (app *App) kitchenOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params
+	orderID := chi.URLParam(r, "orderId")
+	storeID := r.URL.Query().Get("storeId")
+
+	if orderID == "" || storeID == "" {
+		http.Error(w, "orderId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("orderId", orderID).
+		Msg("Kitchen order status request")
+
+	// Staff follow any ticket; a customer only the ticket of their own order
+	if !claims.HasRole(staffRoles...) {
+		retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get retail connector")
+			http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		if _, err := getCustomerOrder(ctx, retailConnector, claims.Sub, orderID); err != nil {
+			if !errors.Is(err, ports.ErrNotFound) {
+				log.Error().Err(err).Str("orderId", orderID).Msg("Failed to get order from connector")
+			}
+			writeKitchenError(w, err, "get kitchen order status")
+			return
+		}
+	}
+
+	kitchenConnector, err := app.getKitchenConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get kitchen connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	status, err := kitchenConnector.GetOrderStatus(ctx, orderID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", kitchenConnector.GetAdapterType()).Msg("Failed to get kitchen order status")
+		}
+		writeKitchenError(w, err, "get kitchen order status")
+		return
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(models.KitchenOrderStatus{
+		OrderID: orderID,
+		StoreID: storeID,
+		Status:  status,
+	})
+}
+
+// kitchenUpdateOrderStatusHandler handles PATCH /api/v1/kitchen/orders/{orderId}
+func (app *App) kitchenUpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	orderID := chi.URLParam(r, "orderId")
+
+	// Parse request body
+	var req models.KitchenStatusUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if orderID == "" || req.StoreID == "" || req.Status == "" {
+		http.Error(w, "orderId, storeId and status are required", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidKitchenStatus(req.Status) {
+		http.Error(w, fmt.Sprintf("Unknown kitchen status %q", req.Status), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("orderId", orderID).
+		Str("status", req.Status).
+		Msg("Kitchen update order status request")
+
+	kitchenConnector, err := app.getKitchenConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get kitchen connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := kitchenConnector.UpdateOrderStatus(ctx, orderID, req.Status); err != nil {
+		if !errors.Is(err, ports.ErrNotFound) && !errors.Is(err, ports.ErrInvalidTransition) {
+			log.Error().Err(err).Str("adapter", kitchenConnector.GetAdapterType()).Msg("Failed to update kitchen order status")
+		}
+		writeKitchenError(w, err, "update kitchen order status")
+		return
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(models.KitchenOrderStatus{
+		OrderID: orderID,
+		StoreID: req.StoreID,
+		Status:  req.Status,
+	})
+}
@@ -4,11 +4,28 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"time"
 
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/adapters/mongodb"
+	"github.com/amicis/go-routing-service/internal/cart"
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/idempotency"
+	"github.com/amicis/go-routing-service/internal/notifier"
+	"github.com/amicis/go-routing-service/internal/outbox"
+	"github.com/amicis/go-routing-service/internal/productcache"
+	"github.com/amicis/go-routing-service/internal/promotions"
+	"github.com/amicis/go-routing-service/internal/pubsub"
 	"github.com/amicis/go-routing-service/internal/registry"
+	"github.com/amicis/go-routing-service/internal/routecache"
+	"github.com/amicis/go-routing-service/internal/signing"
+	"github.com/amicis/go-routing-service/internal/webhooks"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,7 +33,6 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -47,6 +63,7 @@ type Location struct {
 type RedisClient interface {
 	Get(ctx context.Context, key string) *redis.StringCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
 	Ping(ctx context.Context) *redis.StatusCmd
 }
 
@@ -62,6 +79,24 @@ type App struct {
 	mongoClient        *mongo.Client
 	redisClient        RedisClient
 	storesDB           *mongo.Collection
+	stores             ports.IStoreRepository
+	wishlistsDB        *mongo.Collection
+	kitchenStore       *memory.KitchenStore
+	fakePayments       *memory.PaymentStore
+	wishlistShares     *mongodb.WishlistShareStore
+	shareSigner        *signing.Signer
+	wishlistAlerts     *mongodb.WishlistAlertStore
+	alertNotifier      notifier.Notifier
+	carts              *cart.Service
+	checkoutPricer     *checkout.Pricer
+	checkoutSessions   *checkout.Sessions
+	eventBroker        pubsub.Broker
+	outbox             *mongodb.OutboxStore
+	eventBus           outbox.Bus
+	webhookStore       webhooks.Store
+	webhooks           *webhooks.Dispatcher
+	productCache       productcache.Store
+	routeCache         *routecache.Cache
 	circuitBreakers    *CircuitBreakerWrapper
 	connectorRegistry  *registry.ConnectorRegistry
 }
@@ -99,6 +134,13 @@ var (
 		},
 	)
 	
+	cacheStaleTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "cache_stale_total",
+			Help: "Total number of last known routes served while the database was unavailable",
+		},
+	)
+	
 	dbQueriesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "db_queries_total",
@@ -106,6 +148,14 @@ var (
 		},
 		[]string{"collection", "operation"},
 	)
+	
+	productCacheLookupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "product_cache_lookups_total",
+			Help: "Total number of product cache lookups by data type and result (hit, stale, miss)",
+		},
+		[]string{"data_type", "result"},
+	)
 )
 
 func init() {
@@ -114,7 +164,9 @@ func init() {
 	prometheus.MustRegister(httpRequestDuration)
 	prometheus.MustRegister(cacheHitsTotal)
 	prometheus.MustRegister(cacheMissesTotal)
+	prometheus.MustRegister(cacheStaleTotal)
 	prometheus.MustRegister(dbQueriesTotal)
+	prometheus.MustRegister(productCacheLookupsTotal)
 }
 
 func main() {
@@ -176,19 +228,103 @@ func main() {
 		mongoClient:     mongoClient,
 		redisClient:     redisClient,
 		storesDB:        mongoClient.Database(dbName).Collection("stores"),
+		stores:          mongodb.NewStoreRepository(mongoClient.Database(dbName).Collection("stores")),
+		wishlistsDB:     mongoClient.Database(dbName).Collection("wishlists"),
+		kitchenStore:    memory.NewKitchenStore(),
+		fakePayments:    memory.NewPaymentStore(),
+		wishlistShares:  mongodb.NewWishlistShareStore(mongoClient.Database(dbName).Collection("wishlist_shares")),
+		shareSigner:     newWishlistShareSigner(),
+		wishlistAlerts:  mongodb.NewWishlistAlertStore(mongoClient.Database(dbName).Collection("wishlist_alerts"), mongoClient.Database(dbName).Collection("wishlist_item_states")),
+		alertNotifier:   newWishlistAlertNotifier(),
+		checkoutPricer:  checkout.NewPricer(promotions.NewEngine(mongodb.NewPromotionStore(mongoClient.Database(dbName).Collection("promotions")))),
 		circuitBreakers: NewCircuitBreakerWrapper(),
 	}
 	
+	// Store routing data is cached with soft and hard TTLs and served stale while MongoDB is unavailable
+	app.routeCache = app.newRouteCache()
+	app.startStoreChangeWatcher(ctx)
+	
+	// Carts expire through a TTL index on the carts collection
+	cartStore := mongodb.NewCartStore(mongoClient.Database(dbName).Collection("carts"))
+	if err := cartStore.EnsureIndexes(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to create cart indexes")
+	}
+	app.carts = cart.NewService(cartStore, cartTTL())
+	
+	// Checkout sessions are removed through a TTL index once their retention has passed
+	sessionStore := mongodb.NewCheckoutSessionStore(mongoClient.Database(dbName).Collection("checkout_sessions"))
+	if err := sessionStore.EnsureIndexes(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to create checkout session indexes")
+	}
+	app.checkoutSessions = checkout.NewSessions(sessionStore, newCheckoutQRTokens(), checkoutSessionTTL, checkoutSessionRetention)
+	
+	// Session changes are pushed to clients streaming the session's events
+	app.eventBroker = newEventBroker(redisClient)
+	app.checkoutSessions.OnChange(app.publishCheckoutSession)
+	
+	// Domain events are written to the outbox with the changes that cause them and relayed to the event bus
+	app.outbox = mongodb.NewOutboxStore(mongoClient.Database(dbName).Collection("outbox"), mongoClient.Database(dbName).Collection("outbox_leases"), outboxRetention)
+	if err := app.outbox.EnsureIndexes(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to create outbox indexes")
+	}
+	if err := app.outbox.DetectTransactions(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to detect MongoDB transactions, outbox writes are not transactional")
+	}
+	app.checkoutSessions.RecordEvents(app.outbox)
+	eventBus, err := newEventBus(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize event bus")
+	}
+	app.eventBus = eventBus
+	defer app.eventBus.Close()
+	
+	// Tenants' webhook subscriptions receive the events they subscribed to
+	webhookStore := mongodb.NewWebhookStore(mongoClient.Database(dbName).Collection("webhook_subscriptions"), mongoClient.Database(dbName).Collection("webhook_deliveries"), webhookDeliveryRetention)
+	if err := webhookStore.EnsureIndexes(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to create webhook indexes")
+	}
+	app.webhookStore = webhookStore
+	app.webhooks = newWebhookDispatcher(webhookStore)
+	
+	// Give wishlists written before named wishlists a name and visibility
+	if migrated, err := mongodb.MigrateLegacyWishlists(ctx, app.wishlistsDB); err != nil {
+		log.Warn().Err(err).Msg("Failed to migrate legacy wishlists")
+	} else if migrated > 0 {
+		log.Info().Int64("count", migrated).Msg("Migrated legacy wishlists to default lists")
+	}
+	
+	// Product lookups of retail connectors are cached with per-store TTLs
+	app.productCache = newProductCacheStore(redisClient)
+	
 	// Initialize connector registry with adapter factories
 	if err := app.initializeConnectorRegistry(dbName); err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize connector registry")
 	}
 	defer app.connectorRegistry.Close()
 	
+	// Re-price wishlist items in the background for price-drop and back-in-stock alerts
+	app.startWishlistAlertJob(ctx, wishlistAlertInterval())
+	
+	// Retry order submissions of paid checkout sessions that failed or were interrupted
+	app.startCheckoutOrderJob(ctx, checkoutOrderInterval())
+	
+	// Publish domain events recorded in the outbox
+	app.startOutboxRelay(ctx, outboxRelayInterval())
+	
+	// Push relayed events to tenant webhooks
+	app.startWebhookDispatcher(ctx, webhookWorkers())
+	
 	// Initialize rate limiter (100 requests per second, burst of 200)
 	rateLimiter := NewRateLimiter(100, 200)
 	rateLimiter.Cleanup(5 * time.Minute)
 	
+	// Shared wishlist links are unauthenticated, so they get a stricter per-IP limit
+	publicRateLimiter := NewRateLimiter(5, 20)
+	publicRateLimiter.Cleanup(5 * time.Minute)
+	
+	// Retries of mutating commerce requests with the same Idempotency-Key replay the first response
+	idempotencyMiddleware := idempotency.NewMiddleware(newIdempotencyStore(redisClient), idempotencyTTL(), idempotencyScope)
+	
 	// Get port from environment variable, default to 8080
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -211,6 +347,18 @@ func main() {
 	r.Group(func(r chi.Router) {
 		r.Get("/health", app.healthHandler)
 		r.Handle("/metrics", promhttp.Handler())
+		
+		// Payment provider notifications (authenticated by the store's payment connector)
+		r.Post("/api/v1/webhooks/payments/{tenantId}/{storeId}", app.paymentWebhookHandler)
+		
+		// Retail backend change notifications (authenticated by the store's retail connector)
+		r.Post("/api/v1/webhooks/retail/{tenantId}/{storeId}", app.retailChangeNotificationHandler)
+	})
+	
+	// Shared wishlists (signed share token, no JWT)
+	r.Group(func(r chi.Router) {
+		r.Use(RateLimitMiddleware(publicRateLimiter))
+		r.Get("/api/v1/public/wishlists/{token}", app.publicSharedWishlistHandler)
 	})
 
 	// Protected routes (JWT required)
@@ -218,34 +366,7 @@ func main() {
 		r.Use(JWTMiddleware)
 		r.Use(RateLimitMiddleware(rateLimiter)) // Apply rate limiting to protected routes
 		r.Route("/api/v1", func(r chi.Router) {
-			// Legacy routes
-			r.Get("/route", app.routeHandler)
-			r.Get("/stores", app.storesListHandler)
-			
-			// Store details for mobile app
-			r.Get("/stores/{storeId}", app.storeDetailsHandler)
-			
-			// Commerce gateway routes (multi-domain connector framework)
-			r.Route("/commerce", func(r chi.Router) {
-				r.Get("/products", app.commerceProductsHandler)
-				r.Get("/products/{productId}", app.commerceProductHandler)
-				r.Get("/products/by-article/{articleNumber}", app.productByArticleHandler)
-				r.Post("/orders", app.commerceOrdersHandler)
-				r.Get("/connectors", app.commerceConnectorsHandler)
-				
-				// IKEA Scan & Go mobile endpoints
-				r.Get("/availability/{storeId}/{articleNumber}", app.productAvailabilityHandler)
-				r.Get("/pricing/{storeId}/{articleNumber}", app.productPricingHandler)
-				
-				// Checkout sessions
-				r.Post("/checkout/sessions", app.createCheckoutSessionHandler)
-				r.Get("/checkout/sessions/{sessionId}/status", app.getCheckoutSessionStatusHandler)
-				
-				// Wishlist routes
-				r.Get("/wishlist", app.commerceWishlistHandler)
-				r.Post("/wishlist/items", app.commerceAddToWishlistHandler)
-				r.Delete("/wishlist/items/{itemId}", app.commerceRemoveFromWishlistHandler)
-			})
+			app.apiRoutes(r, idempotencyMiddleware.Handler)
 		})
 	})
 
@@ -257,6 +378,109 @@ func main() {
 	}
 }
 
+// apiRoutes registers the protected /api/v1 routes; JWTMiddleware must run before them
+// Mutating commerce routes go through idempotent.
+func (app *App) apiRoutes(r chi.Router, idempotent func(http.Handler) http.Handler) {
+	// Legacy routes
+	r.Get("/route", app.routeHandler)
+	r.Get("/stores", app.storesListHandler)
+	
+	// Store details for mobile app
+	r.Get("/stores/{storeId}", app.storeDetailsHandler)
+	
+	// Commerce gateway routes (multi-domain connector framework)
+	r.Route("/commerce", func(r chi.Router) {
+		r.Use(idempotent)
+		
+		r.Get("/products", app.commerceProductsHandler)
+		r.Get("/products/{productId}", app.commerceProductHandler)
+		r.Get("/products/by-article/{articleNumber}", app.productByArticleHandler)
+		r.Post("/orders", app.commerceOrdersHandler)
+		r.Get("/orders", app.commerceListOrdersHandler)
+		r.Get("/orders/{orderId}", app.commerceGetOrderHandler)
+		r.Post("/orders/{orderId}/cancel", app.commerceCancelOrderHandler)
+		r.Get("/orders/{orderId}/events", app.commerceOrderEventsHandler)
+		r.Get("/connectors", app.commerceConnectorsHandler)
+		
+		// IKEA Scan & Go mobile endpoints
+		r.Get("/availability/{storeId}/{articleNumber}", app.productAvailabilityHandler)
+		r.Get("/pricing/{storeId}/{articleNumber}", app.productPricingHandler)
+		
+		// Checkout sessions
+		r.Post("/checkout/sessions", app.createCheckoutSessionHandler)
+		r.Get("/checkout/sessions/{sessionId}/status", app.getCheckoutSessionStatusHandler)
+		r.Get("/checkout/sessions/{sessionId}/events", app.checkoutSessionEventsHandler)
+		r.Post("/checkout/sessions/{sessionId}/payment", app.checkoutPaymentHandler)
+		r.Post("/checkout/sessions/{sessionId}/cancel", app.checkoutSessionTransitionHandler(models.CheckoutStatusCancelled))
+		r.With(RequireRole(staffRoles...)).Post("/checkout/sessions/{sessionId}/scan", app.checkoutSessionTransitionHandler(models.CheckoutStatusScanned))
+		r.With(RequireRole(staffRoles...)).Post("/checkout/sessions/{sessionId}/pay", app.checkoutSessionTransitionHandler(models.CheckoutStatusPaid))
+		r.With(RequireRole(staffRoles...)).Post("/checkout/sessions/{sessionId}/complete", app.checkoutSessionTransitionHandler(models.CheckoutStatusCompleted))
+		r.With(RequireRole(staffRoles...)).Post("/checkout/verify", app.verifyCheckoutHandler)
+		
+		// Cart routes
+		r.Get("/cart", app.commerceGetCartHandler)
+		r.Delete("/cart", app.commerceClearCartHandler)
+		r.Post("/cart/lines", app.commerceAddCartLineHandler)
+		r.Patch("/cart/lines/{lineId}", app.commerceUpdateCartLineHandler)
+		r.Delete("/cart/lines/{lineId}", app.commerceRemoveCartLineHandler)
+		r.Post("/cart/checkout", app.commerceCartCheckoutHandler)
+		r.Post("/cart/order", app.commerceCartOrderHandler)
+		
+		// Wishlist routes
+		r.Get("/wishlist", app.commerceWishlistHandler)
+		r.Post("/wishlist/items", app.commerceAddToWishlistHandler)
+		r.Delete("/wishlist/items/{itemId}", app.commerceRemoveFromWishlistHandler)
+		r.Patch("/wishlist/items/{itemId}", app.commerceUpdateWishlistItemDefaultHandler)
+		r.Get("/wishlist/alerts", app.commerceWishlistAlertsHandler)
+		
+		// Named wishlists
+		r.Get("/wishlists", app.commerceListWishlistsHandler)
+		r.Post("/wishlists", app.commerceCreateWishlistHandler)
+		r.Get("/wishlists/{wishlistId}", app.commerceGetWishlistByIDHandler)
+		r.Patch("/wishlists/{wishlistId}", app.commerceUpdateWishlistHandler)
+		r.Delete("/wishlists/{wishlistId}", app.commerceDeleteWishlistHandler)
+		r.Post("/wishlists/{wishlistId}/items", app.commerceAddWishlistItemHandler)
+		r.Patch("/wishlists/{wishlistId}/items/{itemId}", app.commerceUpdateWishlistItemHandler)
+		r.Delete("/wishlists/{wishlistId}/items/{itemId}", app.commerceRemoveWishlistItemHandler)
+		r.Post("/wishlists/{wishlistId}/checkout", app.commerceWishlistCheckoutHandler)
+		
+		// Wishlist share links
+		r.Post("/wishlists/{wishlistId}/shares", app.commerceCreateWishlistShareHandler)
+		r.Get("/wishlists/{wishlistId}/shares", app.commerceListWishlistSharesHandler)
+		r.Delete("/wishlists/{wishlistId}/shares/{shareId}", app.commerceRevokeWishlistShareHandler)
+	})
+	
+	// Tenant administration
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(RequireRole(adminRoles...))
+		
+		// Webhook subscriptions, delivery logs and dead letters
+		r.Post("/webhooks", app.adminCreateWebhookHandler)
+		r.Get("/webhooks", app.adminListWebhooksHandler)
+		r.Get("/webhooks/dead-letters", app.adminWebhookDeadLettersHandler)
+		r.Post("/webhooks/deliveries/{deliveryId}/replay", app.adminReplayWebhookDeliveryHandler)
+		r.Get("/webhooks/{subscriptionId}", app.adminGetWebhookHandler)
+		r.Patch("/webhooks/{subscriptionId}", app.adminUpdateWebhookHandler)
+		r.Delete("/webhooks/{subscriptionId}", app.adminDeleteWebhookHandler)
+		r.Get("/webhooks/{subscriptionId}/deliveries", app.adminWebhookDeliveriesHandler)
+		
+		// Product cache
+		r.Post("/product-cache/invalidate", app.adminInvalidateProductCacheHandler)
+		
+		// Route cache
+		r.Post("/route-cache/invalidate", app.adminInvalidateRouteCacheHandler)
+	})
+	
+	// Kitchen display system routes (restaurant orders); tickets are sent and worked by staff,
+	// customers may only follow the ticket of their own order
+	r.Route("/kitchen", func(r chi.Router) {
+		r.With(RequireRole(staffRoles...)).Post("/orders", app.kitchenSubmitOrderHandler)
+		r.With(RequireRole(staffRoles...)).Get("/orders", app.kitchenActiveOrdersHandler)
+		r.Get("/orders/{orderId}", app.kitchenOrderStatusHandler)
+		r.With(RequireRole(staffRoles...)).Patch("/orders/{orderId}", app.kitchenUpdateOrderStatusHandler)
+	})
+}
+
 // healthHandler handles GET /health requests with dependency checks
 func (app *App) healthHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
@@ -304,15 +528,18 @@ func (app *App) routeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	correlationID := GetCorrelationID(ctx)
 	
-	// Get authenticated user from context
+	// Stores are only resolved within the caller's tenant
 	user, ok := GetUserFromContext(ctx)
-	if ok {
-		log.Debug().
-			Str("correlationId", correlationID).
-			Str("user", user.Sub).
-			Str("tenant", user.TenantID).
-			Msg("Processing route request for authenticated user")
+	if !ok || user.TenantID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
+	tenantID := user.TenantID
+	log.Debug().
+		Str("correlationId", correlationID).
+		Str("user", user.Sub).
+		Str("tenant", tenantID).
+		Msg("Processing route request for authenticated user")
 	
 	// Get storeId from query parameter
 	storeID := r.URL.Query().Get("storeId")
@@ -322,66 +549,17 @@ func (app *App) routeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Try Redis cache first with circuit breaker
-	cacheKey := "store:" + storeID
-	var cachedData string
-	var cacheErr error
-	
-	result, err := app.circuitBreakers.ExecuteWithBreaker(
-		app.circuitBreakers.RedisBreaker,
-		func() (interface{}, error) {
-			return app.redisClient.Get(ctx, cacheKey).Result()
-		},
-	)
-	
-	if err == nil && result != nil {
-		cachedData = result.(string)
-		cacheErr = nil
-	} else {
-		cacheErr = err
-	}
-	
-	if cacheErr == nil {
-		// Cache hit - return cached data
-		cacheHitsTotal.Inc()
-		var response RouteResponse
-		if err := json.Unmarshal([]byte(cachedData), &response); err == nil {
-			log.Info().
-				Str("correlationId", correlationID).
-				Str("storeId", storeID).
-				Msg("Cache hit")
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-Cache", "HIT")
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(response)
-			return
-		}
-	}
-
-	// Cache miss - query MongoDB with circuit breaker
-	cacheMissesTotal.Inc()
-	dbQueriesTotal.WithLabelValues("stores", "findOne").Inc()
-	log.Info().
-		Str("correlationId", correlationID).
-		Str("storeId", storeID).
-		Msg("Cache miss, querying database")
-	
-	var store Store
-	filter := bson.M{"storeId": storeID}
-	
-	dbResult, err := app.circuitBreakers.ExecuteWithBreaker(
-		app.circuitBreakers.MongoBreaker,
-		func() (interface{}, error) {
-			var s Store
-			err := app.storesDB.FindOne(ctx, filter).Decode(&s)
-			return s, err
-		},
-	)
-	
+	// Read through the route cache; concurrent misses for a store share one database query
+	cacheKey := routeCacheKey(tenantID, storeID)
+	responseJSON, cacheStatus, err := app.routeCache.Get(ctx, cacheKey, func(ctx context.Context) ([]byte, error) {
+		return app.loadRoute(ctx, tenantID, storeID)
+	})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		// A store of another tenant is reported exactly like one that does not exist
+		if errors.Is(err, ports.ErrNotFound) {
 			log.Warn().
 				Str("correlationId", correlationID).
+				Str("tenant", tenantID).
 				Str("storeId", storeID).
 				Msg("Store not found")
 			http.Error(w, "Store not found", http.StatusNotFound)
@@ -395,34 +573,55 @@ func (app *App) routeHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	
-	store = dbResult.(Store)
 
-	// Build response
-	response := RouteResponse{
-		StoreID:        store.StoreID,
-		BackendURL:     store.BackendURL,
-		BackendContext: store.BackendContext,
+	switch cacheStatus {
+	case routecache.StatusHit:
+		cacheHitsTotal.Inc()
+	case routecache.StatusStale:
+		cacheStaleTotal.Inc()
+	default:
+		cacheMissesTotal.Inc()
 	}
-
-	// Cache the result in Redis (TTL: 1 hour) - with circuit breaker
-	responseJSON, _ := json.Marshal(response)
-	_, _ = app.circuitBreakers.ExecuteWithBreaker(
-		app.circuitBreakers.RedisBreaker,
-		func() (interface{}, error) {
-			return nil, app.redisClient.Set(ctx, cacheKey, responseJSON, 1*time.Hour).Err()
-		},
-	)
-
 	log.Info().
 		Str("correlationId", correlationID).
 		Str("storeId", storeID).
-		Msg("Store data cached successfully")
+		Str("cache", cacheStatus).
+		Msg("Route resolved")
 
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("X-Cache", "MISS")
+	w.Header().Set("X-Cache", cacheStatus)
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	w.Write(responseJSON)
+}
+
+// loadRoute reads the routing data of a tenant's store from the store repository with circuit breaker
+func (app *App) loadRoute(ctx context.Context, tenantID, storeID string) ([]byte, error) {
+	dbQueriesTotal.WithLabelValues("stores", "findOne").Inc()
+
+	dbResult, err := app.circuitBreakers.ExecuteWithBreaker(
+		app.circuitBreakers.MongoBreaker,
+		func() (interface{}, error) {
+			store, err := app.stores.GetStore(ctx, tenantID, storeID)
+			if errors.Is(err, ports.ErrNotFound) {
+				// A missing store is an answer, not a failure of the database
+				return (*models.Store)(nil), nil
+			}
+			return store, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	store := dbResult.(*models.Store)
+	if store == nil {
+		return nil, fmt.Errorf("store %s: %w", storeID, ports.ErrNotFound)
+	}
+
+	return json.Marshal(RouteResponse{
+		StoreID:        store.StoreID,
+		BackendURL:     store.BackendURL,
+		BackendContext: store.BackendContext,
+	})
 }
 
 // StoreListItem represents a store in the list response
@@ -437,41 +636,26 @@ func (app *App) storesListHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	correlationID := GetCorrelationID(ctx)
 	
-	// Get authenticated user from context
+	// Get authenticated user from context; stores are only listed for the caller's tenant
 	user, ok := GetUserFromContext(ctx)
-	var tenantID string
-	if ok {
-		tenantID = user.TenantID
-		log.Debug().
-			Str("correlationId", correlationID).
-			Str("user", user.Sub).
-			Str("tenant", tenantID).
-			Msg("Fetching stores for authenticated user")
-	}
-
-	// Build filter - if tenant ID is available, filter by it
-	filter := bson.M{}
-	if tenantID != "" {
-		filter["tenantId"] = tenantID
+	if !ok || user.TenantID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
+	tenantID := user.TenantID
+	log.Debug().
+		Str("correlationId", correlationID).
+		Str("user", user.Sub).
+		Str("tenant", tenantID).
+		Msg("Fetching stores for authenticated user")
 
-	// Query MongoDB for stores with circuit breaker
+	// Query the store repository with circuit breaker
 	dbQueriesTotal.WithLabelValues("stores", "find").Inc()
 	
 	result, err := app.circuitBreakers.ExecuteWithBreaker(
 		app.circuitBreakers.MongoBreaker,
 		func() (interface{}, error) {
-			cursor, err := app.storesDB.Find(ctx, filter)
-			if err != nil {
-				return nil, err
-			}
-			defer cursor.Close(ctx)
-
-			var stores []Store
-			if err := cursor.All(ctx, &stores); err != nil {
-				return nil, err
-			}
-			return stores, nil
+			return app.stores.ListStores(ctx, tenantID)
 		},
 	)
 	
@@ -484,7 +668,7 @@ func (app *App) storesListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	stores := result.([]Store)
+	stores := result.([]models.Store)
 
 	// Build response with minimal data
 	storeList := make([]StoreListItem, 0, len(stores))
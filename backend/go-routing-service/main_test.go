@@ -3,11 +3,23 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/amicis/go-routing-service/internal/adapters/conformance"
+	"github.com/amicis/go-routing-service/internal/adapters/memory"
+	"github.com/amicis/go-routing-service/internal/cart"
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/pubsub"
+	"github.com/amicis/go-routing-service/internal/registry"
+	"github.com/amicis/go-routing-service/internal/signing"
 	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
@@ -44,6 +56,23 @@ func (m *MockRedisClient) Set(ctx context.Context, key string, value interface{}
 	return cmd
 }
 
+func (m *MockRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	if m.error != nil {
+		cmd.SetErr(m.error)
+		return cmd
+	}
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := m.data[key]; ok {
+			delete(m.data, key)
+			deleted++
+		}
+	}
+	cmd.SetVal(deleted)
+	return cmd
+}
+
 func (m *MockRedisClient) Ping(ctx context.Context) *redis.StatusCmd {
 	cmd := redis.NewStatusCmd(ctx)
 	if m.error != nil {
@@ -54,6 +83,256 @@ func (m *MockRedisClient) Ping(ctx context.Context) *redis.StatusCmd {
 	return cmd
 }
 
+// withTenant authenticates a request as a user of the tenant
+func withTenant(req *http.Request, tenantID string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), UserContextKey, &JWTClaims{Sub: "user-1", TenantID: tenantID}))
+}
+
+// newStoreTestApp creates an app whose stores are the conformance fixture shared by two tenants
+func newStoreTestApp() *App {
+	app := &App{
+		redisClient:     &MockRedisClient{data: make(map[string]string)},
+		stores:          memory.NewStoreRepository(conformance.StoreRepositoryFixture...),
+		circuitBreakers: NewCircuitBreakerWrapper(),
+	}
+	app.routeCache = app.newRouteCache()
+	return app
+}
+
+// asUser authenticates a request as a user of the "ikea" tenant with the given roles
+func asUser(req *http.Request, sub string, roles ...string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), UserContextKey, &JWTClaims{Sub: sub, TenantID: "ikea", Roles: roles}))
+}
+
+// newAPITestRouter mounts the protected API routes of app as JWTMiddleware would leave them
+func newAPITestRouter(app *App) chi.Router {
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		app.apiRoutes(r, func(next http.Handler) http.Handler { return next })
+	})
+	return r
+}
+
+// newConnectorTestApp creates an app whose connector registry holds the given connectors of store IKEA001
+func newConnectorTestApp(t *testing.T, connectors map[string]ports.IConnector) *App {
+	t.Helper()
+	app := &App{
+		redisClient:       &MockRedisClient{data: make(map[string]string)},
+		circuitBreakers:   NewCircuitBreakerWrapper(),
+		connectorRegistry: registry.NewConnectorRegistry(nil),
+	}
+	t.Cleanup(app.connectorRegistry.Close)
+	for domain, connector := range connectors {
+		app.connectorRegistry.AddConnector("ikea", "IKEA001", domain, connector)
+	}
+	return app
+}
+
+// fakeRetailConnector serves products and orders from maps; other retail calls are not used by the tests
+type fakeRetailConnector struct {
+	ports.IRetailConnector
+	products map[string]*models.Product
+	orders   map[string]*models.Order
+}
+
+func newFakeRetailConnector() *fakeRetailConnector {
+	return &fakeRetailConnector{products: map[string]*models.Product{
+		"1001": {
+			ID:        "1001",
+			SKU:       "BILLY-WHITE-001",
+			Name:      "BILLY Bookcase",
+			Price:     models.Price{Amount: 79.99, Currency: "USD"},
+			Inventory: &models.InventoryInfo{Available: true, Quantity: 5},
+		},
+	}, orders: map[string]*models.Order{
+		"ORD-1": {ID: "ORD-1", CustomerID: "customer-1", StoreID: "IKEA001", Status: models.OrderStatusPending},
+		"ORD-2": {ID: "ORD-2", StoreID: "IKEA001", Status: models.OrderStatusPending},
+	}}
+}
+
+func (f *fakeRetailConnector) GetDomain() string                     { return "retail" }
+func (f *fakeRetailConnector) GetAdapterType() string                { return "FakeRetailConnector" }
+func (f *fakeRetailConnector) HealthCheck(ctx context.Context) error { return nil }
+func (f *fakeRetailConnector) Close() error                          { return nil }
+
+func (f *fakeRetailConnector) GetProduct(ctx context.Context, productID string) (*models.Product, error) {
+	product, ok := f.products[productID]
+	if !ok {
+		return nil, fmt.Errorf("product %s: %w", productID, ports.ErrNotFound)
+	}
+	clone := *product
+	return &clone, nil
+}
+
+func (f *fakeRetailConnector) GetOrder(ctx context.Context, orderID string) (*models.Order, error) {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order %s: %w", orderID, ports.ErrNotFound)
+	}
+	clone := *order
+	return &clone, nil
+}
+
+func (f *fakeRetailConnector) UpdateOrderStatus(ctx context.Context, orderID string, status models.OrderStatus) error {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order %s: %w", orderID, ports.ErrNotFound)
+	}
+	order.Status = status
+	return nil
+}
+
+// fakeWishlistConnector serves wishlists from a slice, oldest first; writes are not used by the tests
+type fakeWishlistConnector struct {
+	ports.IWishlistConnector
+	wishlists []models.Wishlist
+}
+
+// newFakeWishlistConnector holds customer-1's default list WL-1 with item ITEM-1 and a second list WL-2
+func newFakeWishlistConnector() *fakeWishlistConnector {
+	item := models.WishlistItem{ID: "ITEM-1", ProductID: "1001", SKU: "BILLY-WHITE-001", Name: "BILLY Bookcase", Quantity: 1}
+	return &fakeWishlistConnector{wishlists: []models.Wishlist{
+		{ID: "WL-1", CustomerID: "customer-1", TenantID: "ikea", StoreID: "IKEA001", Name: "My Wishlist", Items: []models.WishlistItem{item}},
+		{ID: "WL-2", CustomerID: "customer-1", TenantID: "ikea", StoreID: "IKEA001", Name: "Kids room", Items: []models.WishlistItem{}},
+	}}
+}
+
+func (f *fakeWishlistConnector) GetDomain() string                     { return "wishlist" }
+func (f *fakeWishlistConnector) GetAdapterType() string                { return "FakeWishlistConnector" }
+func (f *fakeWishlistConnector) HealthCheck(ctx context.Context) error { return nil }
+func (f *fakeWishlistConnector) Close() error                          { return nil }
+
+func (f *fakeWishlistConnector) GetWishlists(ctx context.Context, customerID string) (*models.WishlistList, error) {
+	list := &models.WishlistList{Wishlists: []models.Wishlist{}}
+	for _, wishlist := range f.wishlists {
+		if wishlist.CustomerID == customerID {
+			list.Wishlists = append(list.Wishlists, wishlist)
+		}
+	}
+	list.Total = len(list.Wishlists)
+	return list, nil
+}
+
+func (f *fakeWishlistConnector) GetWishlist(ctx context.Context, wishlistID string) (*models.Wishlist, error) {
+	for _, wishlist := range f.wishlists {
+		if wishlist.ID == wishlistID {
+			return &wishlist, nil
+		}
+	}
+	return nil, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+}
+
+// memoryCartStore is a versioned in-memory cart.Store
+type memoryCartStore struct {
+	mu    sync.Mutex
+	carts map[string]models.Cart
+}
+
+func (s *memoryCartStore) key(tenantID, storeID, customerID string) string {
+	return tenantID + ":" + storeID + ":" + customerID
+}
+
+func (s *memoryCartStore) Get(ctx context.Context, tenantID, storeID, customerID string) (*models.Cart, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.carts[s.key(tenantID, storeID, customerID)]
+	if !ok {
+		return nil, ports.ErrNotFound
+	}
+	stored.Lines = append([]models.CartLine(nil), stored.Lines...)
+	return &stored, nil
+}
+
+func (s *memoryCartStore) Save(ctx context.Context, customerCart *models.Cart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.key(customerCart.TenantID, customerCart.StoreID, customerCart.CustomerID)
+	if s.carts[key].Version != customerCart.Version {
+		return ports.ErrConflict
+	}
+	customerCart.Version++
+	stored := *customerCart
+	stored.Lines = append([]models.CartLine(nil), customerCart.Lines...)
+	s.carts[key] = stored
+	return nil
+}
+
+func (s *memoryCartStore) Delete(ctx context.Context, customerCart *models.Cart) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.key(customerCart.TenantID, customerCart.StoreID, customerCart.CustomerID)
+	if _, ok := s.carts[key]; !ok {
+		return ports.ErrNotFound
+	}
+	delete(s.carts, key)
+	return nil
+}
+
+// memorySessionStore is a versioned in-memory checkout.SessionStore
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]models.CheckoutSession
+}
+
+func (s *memorySessionStore) Create(ctx context.Context, session *models.CheckoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session.Version = 1
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *memorySessionStore) Get(ctx context.Context, tenantID, sessionID string) (*models.CheckoutSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.TenantID != tenantID {
+		return nil, ports.ErrNotFound
+	}
+	return &session, nil
+}
+
+func (s *memorySessionStore) Update(ctx context.Context, session *models.CheckoutSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[session.ID].Version != session.Version {
+		return ports.ErrConflict
+	}
+	session.Version++
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+func (s *memorySessionStore) ListDueOrders(ctx context.Context, now time.Time, limit int) ([]*models.CheckoutSession, error) {
+	return nil, nil
+}
+
+// newCheckoutTestApp creates an app with in-memory checkout sessions and one pending session of customer-1
+func newCheckoutTestApp(t *testing.T) (*App, *models.CheckoutSession) {
+	t.Helper()
+	keys, err := signing.NewKeyring("k1", map[string][]byte{"k1": []byte("qr-secret")})
+	require.NoError(t, err)
+
+	app := newConnectorTestApp(t, nil)
+	app.eventBroker = pubsub.NewMemoryBroker()
+	app.checkoutSessions = checkout.NewSessions(&memorySessionStore{sessions: make(map[string]models.CheckoutSession)},
+		checkout.NewQRTokens(keys, time.Hour), checkoutSessionTTL, checkoutSessionRetention)
+
+	session, err := app.checkoutSessions.Create(context.Background(), "ikea", "IKEA001", "customer-1", &checkout.Quote{
+		Lines:    []models.CheckoutLine{{ProductID: "1001", Quantity: 1}},
+		Subtotal: models.Price{Amount: 79.99, Currency: "USD"},
+		Total:    models.Price{Amount: 79.99, Currency: "USD"},
+	})
+	require.NoError(t, err)
+	return app, session
+}
+
 // TestHealthHandler_AllHealthy tests health endpoint when all dependencies are healthy
 func TestHealthHandler_AllHealthy(t *testing.T) {
 	// Setup
@@ -63,7 +342,8 @@ func TestHealthHandler_AllHealthy(t *testing.T) {
 
 	// Create test app (MongoDB connection optional for this test)
 	app := &App{
-		redisClient: mockRedis,
+		redisClient:     mockRedis,
+		circuitBreakers: NewCircuitBreakerWrapper(),
 		// In real test, would use testcontainers or mock for MongoDB
 	}
 
@@ -94,7 +374,8 @@ func TestHealthHandler_RedisUnhealthy(t *testing.T) {
 	}
 
 	app := &App{
-		redisClient: mockRedis,
+		redisClient:     mockRedis,
+		circuitBreakers: NewCircuitBreakerWrapper(),
 	}
 
 	// Create request
@@ -119,11 +400,12 @@ func TestHealthHandler_RedisUnhealthy(t *testing.T) {
 func TestRouteHandler_MissingStoreID(t *testing.T) {
 	// Setup
 	app := &App{
-		redisClient: &MockRedisClient{data: make(map[string]string)},
+		redisClient:     &MockRedisClient{data: make(map[string]string)},
+		circuitBreakers: NewCircuitBreakerWrapper(),
 	}
 
 	// Create request without storeId
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/route", nil)
+	req := withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/route", nil), "ikea")
 	w := httptest.NewRecorder()
 
 	// Execute
@@ -146,21 +428,29 @@ func TestRouteHandler_CacheHit(t *testing.T) {
 		},
 	}
 	
-	cachedData, err := json.Marshal(testStore)
+	routeData, err := json.Marshal(testStore)
+	require.NoError(t, err)
+	cachedData, err := json.Marshal(map[string]interface{}{
+		"value":     json.RawMessage(routeData),
+		"fetchedAt": time.Now(),
+	})
 	require.NoError(t, err)
 
 	mockRedis := &MockRedisClient{
 		data: map[string]string{
-			"store:IKEA001": string(cachedData),
+			"amicis:routes:v1:ikea:IKEA001": string(cachedData),
 		},
 	}
 
 	app := &App{
-		redisClient: mockRedis,
+		redisClient:     mockRedis,
+		circuitBreakers: NewCircuitBreakerWrapper(),
 	}
+	app.routeCache = app.newRouteCache()
 
 	// Create request
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/route?storeId=IKEA001", nil)
+	req = withTenant(req, "ikea")
 	w := httptest.NewRecorder()
 
 	// Execute
@@ -168,6 +458,7 @@ func TestRouteHandler_CacheHit(t *testing.T) {
 
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
 	
 	var response RouteResponse
 	err = json.NewDecoder(w.Body).Decode(&response)
@@ -178,6 +469,359 @@ func TestRouteHandler_CacheHit(t *testing.T) {
 	assert.Equal(t, "EU", response.BackendContext["region"])
 }
 
+// TestAdminInvalidateRouteCache tests that invalidation only drops the caller's tenant's route
+func TestAdminInvalidateRouteCache(t *testing.T) {
+	mockRedis := &MockRedisClient{data: map[string]string{
+		"amicis:routes:v1:ikea:IKEA001":  `{"value":{},"fetchedAt":"2026-01-01T00:00:00Z"}`,
+		"amicis:routes:v1:other:IKEA001": `{"value":{},"fetchedAt":"2026-01-01T00:00:00Z"}`,
+	}}
+	app := &App{
+		redisClient:     mockRedis,
+		circuitBreakers: NewCircuitBreakerWrapper(),
+	}
+	app.routeCache = app.newRouteCache()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/route-cache/invalidate", strings.NewReader(`{"storeId":"IKEA001"}`))
+	req = withTenant(req, "ikea")
+	w := httptest.NewRecorder()
+
+	app.adminInvalidateRouteCacheHandler(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.NotContains(t, mockRedis.data, "amicis:routes:v1:ikea:IKEA001")
+	assert.Contains(t, mockRedis.data, "amicis:routes:v1:other:IKEA001")
+}
+
+// TestRouteHandler_TenantIsolation tests that a tenant only resolves its own stores, cached or not
+func TestRouteHandler_TenantIsolation(t *testing.T) {
+	app := newStoreTestApp()
+	route := func(tenantID, storeID string) *httptest.ResponseRecorder {
+		req := withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/route?storeId="+storeID, nil), tenantID)
+		w := httptest.NewRecorder()
+		app.routeHandler(w, req)
+		return w
+	}
+
+	// Both tenants have a store "shared"; each is routed to its own backend, also once cached
+	for i := 0; i < 2; i++ {
+		for tenantID, backendURL := range map[string]string{"tenant-a": "https://a.example.com", "tenant-b": "https://b.example.com"} {
+			w := route(tenantID, "shared")
+			require.Equal(t, http.StatusOK, w.Code)
+			var response RouteResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+			assert.Equal(t, backendURL, response.BackendURL)
+		}
+	}
+
+	// Another tenant's store does not exist for the caller
+	w := route("tenant-a", "b-only")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), "b-only.example.com")
+
+	// Requests without a tenant are rejected
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/route?storeId=shared", nil)
+	w = httptest.NewRecorder()
+	app.routeHandler(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// TestStoresListHandler_TenantIsolation tests that the store list only holds the caller's stores
+func TestStoresListHandler_TenantIsolation(t *testing.T) {
+	app := newStoreTestApp()
+
+	req := withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/stores", nil), "tenant-a")
+	w := httptest.NewRecorder()
+	app.storesListHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var stores []StoreListItem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&stores))
+	require.Len(t, stores, 2)
+	for _, store := range stores {
+		assert.NotContains(t, store.BackendURL, "b.example.com")
+		assert.NotContains(t, store.BackendURL, "b-only.example.com")
+	}
+
+	// Without a tenant nothing is listed
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/stores", nil)
+	w = httptest.NewRecorder()
+	app.storesListHandler(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// TestStoreDetailsHandler_TenantIsolation tests that store details of another tenant are not found
+func TestStoreDetailsHandler_TenantIsolation(t *testing.T) {
+	app := newStoreTestApp()
+	r := chi.NewRouter()
+	r.Get("/api/v1/stores/{storeId}", app.storeDetailsHandler)
+
+	req := withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/stores/b-only", nil), "tenant-a")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/stores/shared", nil), "tenant-b")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var details StoreDetails
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&details))
+	assert.Equal(t, "B shared", details.Name)
+}
+
+// TestKitchenRoutes_StaffOnly tests that only staff send, see and update kitchen tickets
+// and that a customer can only follow the ticket of their own order
+func TestKitchenRoutes_StaffOnly(t *testing.T) {
+	config := ports.ConnectorConfig{TenantID: "ikea", StoreID: "IKEA001", Domain: "kitchen", Adapter: "InMemoryKitchenAdapter", Enabled: true}
+	kitchen, err := memory.NewInMemoryKitchenAdapter(config, memory.NewKitchenStore())
+	require.NoError(t, err)
+	require.NoError(t, kitchen.Initialize(context.Background(), config))
+	router := newAPITestRouter(newConnectorTestApp(t, map[string]ports.IConnector{"kitchen": kitchen, "retail": newFakeRetailConnector()}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	submit := `{"storeId":"IKEA001","orderId":"ORD-1","items":[{"id":"1","productId":"70001","name":"Meatballs","quantity":1,"category":"restaurant"}]}`
+
+	// Customers cannot put tickets on the queue
+	w := serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/kitchen/orders", strings.NewReader(submit)), "customer-1"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/kitchen/orders", strings.NewReader(submit)), "staff-1", "staff"))
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	// ORD-1 belongs to customer-1; other customers cannot tell the ticket exists
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/kitchen/orders/ORD-1?storeId=IKEA001", nil), "customer-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/kitchen/orders/ORD-1?storeId=IKEA001", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The queue and status changes are for staff
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/kitchen/orders?storeId=IKEA001", nil), "customer-1"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/kitchen/orders/ORD-1?storeId=IKEA001", strings.NewReader(`{"storeId":"IKEA001","status":"preparing"}`)), "customer-1"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/kitchen/orders?storeId=IKEA001", nil), "staff-1", "staff"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ORD-1")
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/kitchen/orders/ORD-1?storeId=IKEA001", nil), "staff-1", "staff"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/kitchen/orders/ORD-1?storeId=IKEA001", strings.NewReader(`{"storeId":"IKEA001","status":"preparing"}`)), "staff-1", "staff"))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestCartRoutes_OwnCartOnly tests that a customer cannot read or change another customer's cart
+func TestCartRoutes_OwnCartOnly(t *testing.T) {
+	app := newConnectorTestApp(t, map[string]ports.IConnector{"retail": newFakeRetailConnector()})
+	app.carts = cart.NewService(&memoryCartStore{carts: make(map[string]models.Cart)}, time.Hour)
+	router := newAPITestRouter(app)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	readCart := func(sub string) models.Cart {
+		w := serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/cart?storeId=IKEA001", nil), sub))
+		require.Equal(t, http.StatusOK, w.Code)
+		var customerCart models.Cart
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&customerCart))
+		return customerCart
+	}
+
+	w := serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/cart/lines",
+		strings.NewReader(`{"storeId":"IKEA001","productId":"1001","quantity":2}`)), "customer-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	owned := readCart("customer-1")
+	require.Len(t, owned.Lines, 1)
+	assert.Equal(t, "customer-1", owned.CustomerID)
+	lineID := owned.Lines[0].ID
+
+	// A customerId naming another customer is ignored; customer-2 only ever sees their own cart
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/cart?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	require.Equal(t, http.StatusOK, w.Code)
+	var other models.Cart
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&other))
+	assert.Equal(t, "customer-2", other.CustomerID)
+	assert.Empty(t, other.Lines)
+
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/commerce/cart/lines/"+lineID,
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1","quantity":5}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/commerce/cart/lines/"+lineID+"?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/commerce/cart?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/cart/checkout",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1"}`)), "customer-2"))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/cart/order",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1"}`)), "customer-2"))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	owned = readCart("customer-1")
+	require.Len(t, owned.Lines, 1)
+	assert.Equal(t, 2, owned.Lines[0].Quantity)
+}
+
+// TestCheckoutSessionRoutes_OwnSessionOnly tests that a customer cannot read, pay or move another customer's session
+func TestCheckoutSessionRoutes_OwnSessionOnly(t *testing.T) {
+	app, session := newCheckoutTestApp(t)
+	router := newAPITestRouter(app)
+	base := "/api/v1/commerce/checkout/sessions/" + session.ID
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Another customer gets no status, QR token or event stream, and cannot pay or cancel
+	w := serve(asUser(httptest.NewRequest(http.MethodGet, base+"/status", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), session.QRToken)
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, base+"/events", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, base+"/payment", strings.NewReader(`{"paymentToken":"tok_visa"}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, base+"/cancel", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Gate and till moves are for staff, even on the customer's own session
+	for _, action := range []string{"scan", "pay", "complete"} {
+		w = serve(asUser(httptest.NewRequest(http.MethodPost, base+"/"+action, nil), "customer-1"))
+		assert.Equal(t, http.StatusForbidden, w.Code, action)
+	}
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, base+"/scan", nil), "staff-1", "staff"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, base+"/status", nil), "customer-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), session.QRToken)
+	assert.Contains(t, w.Body.String(), models.CheckoutStatusScanned)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, base+"/cancel", nil), "customer-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestOrderRoutes_OwnOrderOnly tests that a customer cannot read or cancel another customer's order
+func TestOrderRoutes_OwnOrderOnly(t *testing.T) {
+	retail := newFakeRetailConnector()
+	router := newAPITestRouter(newConnectorTestApp(t, map[string]ports.IConnector{"retail": retail}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/orders/ORD-1?storeId=IKEA001", nil), "customer-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ORD-1")
+
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/orders/ORD-1?storeId=IKEA001", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/orders/ORD-1/cancel?storeId=IKEA001", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.OrderStatusPending, retail.orders["ORD-1"].Status)
+
+	// An order without a customer belongs to nobody
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/orders/ORD-2?storeId=IKEA001", nil), "customer-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/orders/ORD-2/cancel?storeId=IKEA001", nil), "customer-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.OrderStatusPending, retail.orders["ORD-2"].Status)
+}
+
+// TestDefaultWishlistRoutes_OwnWishlistOnly tests that a customer cannot read or change another customer's default wishlist
+func TestDefaultWishlistRoutes_OwnWishlistOnly(t *testing.T) {
+	wishlists := newFakeWishlistConnector()
+	router := newAPITestRouter(newConnectorTestApp(t, map[string]ports.IConnector{"wishlist": wishlists}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/wishlist?storeId=IKEA001", nil), "customer-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ITEM-1")
+
+	// A customerId naming another customer is ignored; customer-2 has no wishlist of their own
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/wishlist?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "ITEM-1")
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/commerce/wishlist/items/ITEM-1",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1","quantity":5}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/commerce/wishlist/items/ITEM-1?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, wishlists.wishlists[0].Items, 1)
+	assert.Equal(t, 1, wishlists.wishlists[0].Items[0].Quantity)
+}
+
+// TestNamedWishlistRoutes_OwnWishlistOnly tests that a customer cannot read or change another customer's named wishlist
+func TestNamedWishlistRoutes_OwnWishlistOnly(t *testing.T) {
+	wishlists := newFakeWishlistConnector()
+	router := newAPITestRouter(newConnectorTestApp(t, map[string]ports.IConnector{"wishlist": wishlists}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/wishlists/WL-1?storeId=IKEA001", nil), "customer-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "ITEM-1")
+
+	// A customerId naming another customer is ignored; customer-2 has no wishlists of their own
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/wishlists?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.WishlistList
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Empty(t, list.Wishlists)
+
+	w = serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/commerce/wishlists/WL-1?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/commerce/wishlists/WL-1",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1","name":"Mine now"}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/commerce/wishlists/WL-1?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPost, "/api/v1/commerce/wishlists/WL-1/items",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1","productId":"1001"}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodPatch, "/api/v1/commerce/wishlists/WL-1/items/ITEM-1",
+		strings.NewReader(`{"storeId":"IKEA001","customerId":"customer-1","quantity":5}`)), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(asUser(httptest.NewRequest(http.MethodDelete, "/api/v1/commerce/wishlists/WL-1/items/ITEM-1?storeId=IKEA001&customerId=customer-1", nil), "customer-2"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	assert.Equal(t, "My Wishlist", wishlists.wishlists[0].Name)
+	require.Len(t, wishlists.wishlists[0].Items, 1)
+	assert.Equal(t, 1, wishlists.wishlists[0].Items[0].Quantity)
+}
+
+// TestNewEventBus_NoFallback tests that a configured event bus that cannot be used fails instead of falling back to memory
+func TestNewEventBus_NoFallback(t *testing.T) {
+	t.Setenv("EVENT_BUS", "")
+	bus, err := newEventBus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "memory", bus.Name())
+
+	t.Setenv("EVENT_BUS", "nats")
+	t.Setenv("NATS_URL", "nats://127.0.0.1:1")
+	_, err = newEventBus(context.Background())
+	assert.Error(t, err)
+
+	t.Setenv("EVENT_BUS", "kafka")
+	_, err = newEventBus(context.Background())
+	assert.Error(t, err)
+}
+
 // TestRouteHandler_InvalidStoreID tests route endpoint with non-existent store
 func TestRouteHandler_InvalidStoreID(t *testing.T) {
 	// This test requires MongoDB integration test
@@ -188,7 +832,8 @@ func TestRouteHandler_InvalidStoreID(t *testing.T) {
 // TestRouterSetup tests that all routes are properly configured
 func TestRouterSetup(t *testing.T) {
 	app := &App{
-		redisClient: &MockRedisClient{data: make(map[string]string)},
+		redisClient:     &MockRedisClient{data: make(map[string]string)},
+		circuitBreakers: NewCircuitBreakerWrapper(),
 	}
 
 	r := chi.NewRouter()
@@ -204,7 +849,7 @@ func TestRouterSetup(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	// Test route endpoint (should fail without storeId)
-	req = httptest.NewRequest(http.MethodGet, "/api/v1/route", nil)
+	req = withTenant(httptest.NewRequest(http.MethodGet, "/api/v1/route", nil), "ikea")
 	w = httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultOrderLimit is the page size of the order history when none is requested
+	defaultOrderLimit = 20
+
+	// maxOrderLimit bounds the page size of the order history
+	maxOrderLimit = 100
+)
+
+// writeOrderError maps retail connector order errors to HTTP responses
+func writeOrderError(w http.ResponseWriter, err error, action string) {
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusInternalServerError)
+}
+
+// getCustomerOrder reads an order and checks that it belongs to the customer
+// Orders of other customers are reported as not found so that order IDs cannot be probed.
+// An order without a customer cannot be attributed, so it is reported as not found too.
+func getCustomerOrder(ctx context.Context, retailConnector ports.IRetailConnector, customerID, orderID string) (*models.Order, error) {
+	order, err := retailConnector.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID == "" || order.CustomerID != customerID {
+		return nil, fmt.Errorf("order %s: %w", orderID, ports.ErrNotFound)
+	}
+	return order, nil
+}
+
+// commerceListOrdersHandler handles GET /api/v1/commerce/orders
+// It returns the order history of the customer in the JWT, newest first as the backend sorts it
+func (app *App) commerceListOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	storeID := r.URL.Query().Get("storeId")
+	if storeID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultOrderLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l <= 0 || l > maxOrderLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxOrderLimit), http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	offset := 0
+	if value := r.URL.Query().Get("offset"); value != "" {
+		o, err := strconv.Atoi(value)
+		if err != nil || o < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		offset = o
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Commerce list orders request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	orders, err := retailConnector.GetOrders(ctx, claims.Sub, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get orders from connector")
+		http.Error(w, "Failed to get orders", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(orders)
+}
+
+// commerceGetOrderHandler handles GET /api/v1/commerce/orders/{orderId}
+func (app *App) commerceGetOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	orderID := chi.URLParam(r, "orderId")
+	storeID := r.URL.Query().Get("storeId")
+	if orderID == "" || storeID == "" {
+		http.Error(w, "orderId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("orderId", orderID).
+		Msg("Commerce get order request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	order, err := getCustomerOrder(ctx, retailConnector, claims.Sub, orderID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("orderId", orderID).Msg("Failed to get order from connector")
+		}
+		writeOrderError(w, err, "get order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(order)
+}
+
+// commerceCancelOrderHandler handles POST /api/v1/commerce/orders/{orderId}/cancel
+// An order can be cancelled by its customer until it ships. Cancelling a cancelled order
+// returns it unchanged so that retries are harmless.
+func (app *App) commerceCancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	orderID := chi.URLParam(r, "orderId")
+	storeID := r.URL.Query().Get("storeId")
+	if orderID == "" || storeID == "" {
+		http.Error(w, "orderId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("orderId", orderID).
+		Msg("Commerce cancel order request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	order, err := getCustomerOrder(ctx, retailConnector, claims.Sub, orderID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("orderId", orderID).Msg("Failed to get order from connector")
+		}
+		writeOrderError(w, err, "get order")
+		return
+	}
+
+	if !models.CanTransitionOrderStatus(order.Status, models.OrderStatusCancelled) {
+		http.Error(w, fmt.Sprintf("order %s is %s and can no longer be cancelled", order.ID, order.Status), http.StatusConflict)
+		return
+	}
+
+	if order.Status != models.OrderStatusCancelled {
+		if err := retailConnector.UpdateOrderStatus(ctx, order.ID, models.OrderStatusCancelled); err != nil {
+			log.Error().Err(err).Str("orderId", order.ID).Msg("Failed to cancel order via connector")
+			writeOrderError(w, err, "cancel order")
+			return
+		}
+		previous := order.Status
+		order.Status = models.OrderStatusCancelled
+		order.UpdatedAt = time.Now()
+		app.recordEvent(ctx, models.EventOrderStatusChanged, models.AggregateOrder, order.ID, claims.TenantID, storeID, map[string]interface{}{
+			"orderId":        order.ID,
+			"orderNumber":    order.OrderNumber,
+			"customerId":     claims.Sub,
+			"previousStatus": previous,
+			"status":         order.Status,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(order)
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("orderId", order.ID).
+		Msg("Commerce order cancelled successfully")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/productcache"
+	"github.com/amicis/go-routing-service/internal/registry"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// newProductCacheStore selects the product cache from PRODUCT_CACHE
+// "redis" (the default) shares entries across replicas; "memory" keeps them in this process
+// and only suits a single replica; "off" sends every product lookup to the backend
+func newProductCacheStore(redisClient redis.UniversalClient) productcache.Store {
+	switch os.Getenv("PRODUCT_CACHE") {
+	case "off":
+		return nil
+	case "memory":
+		return productcache.NewMemoryStore()
+	case "", "redis":
+		return productcache.NewRedisStore(redisClient, "amicis:products:")
+	default:
+		log.Warn().Str("value", os.Getenv("PRODUCT_CACHE")).Msg("Unknown PRODUCT_CACHE, using redis")
+		return productcache.NewRedisStore(redisClient, "amicis:products:")
+	}
+}
+
+// observeProductCache counts product cache lookups per data type
+func observeProductCache(dataType, result string) {
+	productCacheLookupsTotal.WithLabelValues(dataType, result).Inc()
+}
+
+// cachedRetailFactory wraps the retail connectors a factory creates with the product cache
+// TTLs come from the "cache" object of the connector config.
+func (app *App) cachedRetailFactory(factory registry.ConnectorFactory) registry.ConnectorFactory {
+	return func(config ports.ConnectorConfig) (ports.IConnector, error) {
+		connector, err := factory(config)
+		if err != nil || app.productCache == nil {
+			return connector, err
+		}
+
+		retailConnector, ok := connector.(ports.IRetailConnector)
+		if !ok {
+			return connector, nil
+		}
+		return productcache.NewConnector(retailConnector, app.productCache, config.TenantID, config.StoreID, productcache.ConfigFrom(config.Config), observeProductCache), nil
+	}
+}
+
+// productCacheInvalidationRequest names the product to drop from a store's cache
+type productCacheInvalidationRequest struct {
+	StoreID   string `json:"storeId"`
+	ProductID string `json:"productId"`
+	SKU       string `json:"sku"`
+}
+
+// adminInvalidateProductCacheHandler handles POST /api/v1/admin/product-cache/invalidate
+// It drops a product from the cache of one of the tenant's stores, e.g. after a correction in
+// a backend that does not send change notifications.
+func (app *App) adminInvalidateProductCacheHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req productCacheInvalidationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.StoreID == "" || (req.ProductID == "" && req.SKU == "") {
+		http.Error(w, "storeId and productId or sku are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("productId", req.ProductID).
+		Str("sku", req.SKU).
+		Msg("Admin invalidate product cache request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	invalidator, ok := retailConnector.(ports.IRetailCacheInvalidator)
+	if !ok {
+		http.Error(w, "Products of this store are not cached", http.StatusNotFound)
+		return
+	}
+	if err := invalidator.InvalidateProduct(ctx, req.ProductID, req.SKU); err != nil {
+		log.Error().Err(err).Msg("Failed to invalidate product cache")
+		http.Error(w, "Failed to invalidate product cache", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// retailNotificationMaxBytes bounds the size of a retail backend change notification
+const retailNotificationMaxBytes = 256 << 10
+
+// orderTopic is the event topic of an order's status changes
+func orderTopic(tenantID, orderID string) string {
+	return fmt.Sprintf("order:%s:%s", tenantID, orderID)
+}
+
+// orderStreamDone reports whether an order in this status will not change any more as far as the app is concerned
+func orderStreamDone(status models.OrderStatus) bool {
+	switch status {
+	case models.OrderStatusDelivered, models.OrderStatusCancelled, models.OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// retailChangeNotifier returns the change notifier of a retail connector, looking through
+// decorators such as the product cache
+func retailChangeNotifier(connector ports.IRetailConnector) (ports.IRetailChangeNotifier, bool) {
+	for {
+		if notifier, ok := connector.(ports.IRetailChangeNotifier); ok {
+			return notifier, true
+		}
+		wrapper, ok := connector.(interface{ Unwrap() ports.IRetailConnector })
+		if !ok {
+			return nil, false
+		}
+		connector = wrapper.Unwrap()
+	}
+}
+
+// retailChangeNotificationHandler handles POST /api/v1/webhooks/retail/{tenantId}/{storeId}
+// Retail backends push order status, price and inventory changes here; the store's retail
+// connector authenticates and decodes the notification. Order status changes are recorded as
+// domain events and streamed to the app; price and inventory changes drop cached product data.
+func (app *App) retailChangeNotificationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	tenantID := chi.URLParam(r, "tenantId")
+	storeID := chi.URLParam(r, "storeId")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, retailNotificationMaxBytes))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	retailConnector, err := app.getRetailConnector(ctx, tenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Str("tenantId", tenantID).Str("storeId", storeID).Msg("Failed to get retail connector for change notification")
+		http.Error(w, "Connector not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	notifier, ok := retailChangeNotifier(retailConnector)
+	if !ok {
+		http.Error(w, "Change notifications are not supported for this store", http.StatusNotFound)
+		return
+	}
+
+	changes, err := notifier.ParseChangeNotification(ctx, r.Header, body)
+	if errors.Is(err, ports.ErrInvalidSignature) {
+		log.Warn().Err(err).Str("correlationId", correlationID).Str("tenantId", tenantID).Str("storeId", storeID).Msg("Rejected retail change notification")
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("correlationId", correlationID).Msg("Invalid retail change notification")
+		http.Error(w, "Invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", tenantID).
+		Str("storeId", storeID).
+		Int("changes", len(changes)).
+		Msg("Retail change notification received")
+
+	for _, change := range changes {
+		switch change.Type {
+		case models.RetailChangeOrderStatus:
+			app.applyOrderStatusChange(ctx, tenantID, storeID, change)
+		case models.RetailChangePrice, models.RetailChangeInventory:
+			app.applyProductChange(ctx, retailConnector, tenantID, storeID, change)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{"received": true, "changes": len(changes)})
+}
+
+// applyOrderStatusChange records an order status change reported by the backend and
+// publishes it to the order's event stream
+func (app *App) applyOrderStatusChange(ctx context.Context, tenantID, storeID string, change models.RetailChange) {
+	status := map[string]interface{}{
+		"orderId":     change.OrderID,
+		"orderNumber": change.OrderNumber,
+		"status":      change.OrderStatus,
+		"updatedAt":   change.OccurredAt,
+	}
+	app.recordEvent(ctx, models.EventOrderStatusChanged, models.AggregateOrder, change.OrderID, tenantID, storeID, status)
+
+	message, err := json.Marshal(status)
+	if err != nil {
+		log.Error().Err(err).Str("orderId", change.OrderID).Msg("Failed to encode order event")
+		return
+	}
+	if err := app.eventBroker.Publish(context.WithoutCancel(ctx), orderTopic(tenantID, change.OrderID), message); err != nil {
+		log.Warn().Err(err).Str("orderId", change.OrderID).Msg("Failed to publish order event")
+	}
+}
+
+// applyProductChange drops cached data of a product whose price or stock changed and records the change
+func (app *App) applyProductChange(ctx context.Context, retailConnector ports.IRetailConnector, tenantID, storeID string, change models.RetailChange) {
+	if invalidator, ok := retailConnector.(ports.IRetailCacheInvalidator); ok {
+		if err := invalidator.InvalidateProduct(ctx, change.ProductID, change.SKU); err != nil {
+			log.Warn().Err(err).Str("tenantId", tenantID).Str("storeId", storeID).Str("sku", change.SKU).Msg("Failed to invalidate cached product")
+		}
+	}
+
+	eventType := models.EventProductPriceChanged
+	if change.Type == models.RetailChangeInventory {
+		eventType = models.EventProductInventoryChanged
+	}
+	aggregateID := change.ProductID
+	if aggregateID == "" {
+		aggregateID = change.SKU
+	}
+	app.recordEvent(ctx, eventType, models.AggregateProduct, aggregateID, tenantID, storeID, map[string]interface{}{
+		"productId": change.ProductID,
+		"sku":       change.SKU,
+		"changedAt": change.OccurredAt,
+	})
+}
+
+// commerceOrderEventsHandler handles GET /api/v1/commerce/orders/{orderId}/events
+// It streams the order's status as Server-Sent Events: the current status first, then every
+// change the retail backend notifies, until the order is delivered, cancelled or refunded.
+func (app *App) commerceOrderEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	orderID := chi.URLParam(r, "orderId")
+	storeID := r.URL.Query().Get("storeId")
+	if orderID == "" || storeID == "" {
+		http.Error(w, "orderId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("orderId", orderID).
+		Msg("Commerce order stream request")
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	// Subscribe before reading the order, so that no change falls between the two
+	updates, err := app.eventBroker.Subscribe(ctx, orderTopic(claims.TenantID, orderID))
+	if err != nil {
+		log.Error().Err(err).Str("broker", app.eventBroker.Name()).Msg("Failed to subscribe to order events")
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	order, err := getCustomerOrder(ctx, retailConnector, claims.Sub, orderID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to get order from connector")
+		}
+		writeOrderError(w, err, "get order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	done := false
+
+	send := func(message []byte) error {
+		var state struct {
+			Status models.OrderStatus `json:"status"`
+		}
+		if err := json.Unmarshal(message, &state); err != nil {
+			return nil
+		}
+		done = orderStreamDone(state.Status)
+		if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", message); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	message, err := json.Marshal(map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"status":      order.Status,
+		"updatedAt":   order.UpdatedAt,
+	})
+	if err != nil || send(message) != nil || done {
+		return
+	}
+
+	// Changes only arrive through the broker, so the heartbeat just keeps proxies from closing the stream
+	heartbeat := time.NewTicker(checkoutStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for !done {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-updates:
+			if !ok {
+				return
+			}
+			err = send(message)
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err == nil {
+				err = rc.Flush()
+			}
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn().Err(err).Str("correlationId", correlationID).Str("orderId", orderID).Msg("Order stream closed")
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/routecache"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultRouteCacheVersion namespaces route cache keys unless ROUTE_CACHE_VERSION is set
+const defaultRouteCacheVersion = "v1"
+
+// routeCacheVersion returns the key version from ROUTE_CACHE_VERSION
+// Deploying with a new version starts from an empty cache; entries of the old version expire with their TTL.
+func routeCacheVersion() string {
+	if version := os.Getenv("ROUTE_CACHE_VERSION"); version != "" {
+		return version
+	}
+	return defaultRouteCacheVersion
+}
+
+// routeCacheKey identifies the cached route of a tenant's store
+func routeCacheKey(tenantID, storeID string) string {
+	return tenantID + ":" + storeID
+}
+
+// newRouteCache creates the cache of store routing data
+// Keys are prefixed with amicis:routes:{version}:. ROUTE_CACHE_SOFT_TTL, ROUTE_CACHE_HARD_TTL and ROUTE_CACHE_STALE_TTL override its TTLs.
+func (app *App) newRouteCache() *routecache.Cache {
+	config := routecache.DefaultConfig()
+	readRouteCacheTTL("ROUTE_CACHE_SOFT_TTL", &config.SoftTTL)
+	readRouteCacheTTL("ROUTE_CACHE_HARD_TTL", &config.HardTTL)
+	readRouteCacheTTL("ROUTE_CACHE_STALE_TTL", &config.StaleTTL)
+	if config.SoftTTL > config.HardTTL {
+		log.Warn().Dur("softTtl", config.SoftTTL).Dur("hardTtl", config.HardTTL).Msg("ROUTE_CACHE_SOFT_TTL exceeds ROUTE_CACHE_HARD_TTL, refreshing at the hard TTL")
+		config.SoftTTL = config.HardTTL
+	}
+	config.ServeStale = isBreakerRejection
+	return routecache.New(&redisRouteStore{app: app, prefix: "amicis:routes:" + routeCacheVersion() + ":"}, config)
+}
+
+func readRouteCacheTTL(name string, target *time.Duration) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		log.Warn().Str("value", value).Msgf("Invalid %s, using default", name)
+		return
+	}
+	*target = ttl
+}
+
+// isBreakerRejection reports whether a circuit breaker rejected a call, so that the last known value may be served
+func isBreakerRejection(err error) bool {
+	var rejected *BreakerRejectedError
+	return errors.As(err, &rejected)
+}
+
+// redisRouteStore keeps route cache entries in Redis behind the Redis circuit breaker
+type redisRouteStore struct {
+	app    *App
+	prefix string
+}
+
+// Get returns the value of key, or nil when there is none
+func (s *redisRouteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	result, err := s.app.circuitBreakers.ExecuteWithBreaker(
+		s.app.circuitBreakers.RedisBreaker,
+		func() (interface{}, error) {
+			value, err := s.app.redisClient.Get(ctx, s.prefix+key).Result()
+			if errors.Is(err, redis.Nil) {
+				return "", nil
+			}
+			return value, err
+		},
+	)
+	if err != nil || result.(string) == "" {
+		return nil, err
+	}
+	return []byte(result.(string)), nil
+}
+
+// Set stores value under key
+func (s *redisRouteStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := s.app.circuitBreakers.ExecuteWithBreaker(
+		s.app.circuitBreakers.RedisBreaker,
+		func() (interface{}, error) {
+			return nil, s.app.redisClient.Set(ctx, s.prefix+key, string(value), ttl).Err()
+		},
+	)
+	return err
+}
+
+// Delete removes the keys
+func (s *redisRouteStore) Delete(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	_, err := s.app.circuitBreakers.ExecuteWithBreaker(
+		s.app.circuitBreakers.RedisBreaker,
+		func() (interface{}, error) {
+			return nil, s.app.redisClient.Del(ctx, prefixed...).Err()
+		},
+	)
+	return err
+}
+
+// invalidateRoute drops the cached route of a tenant's store
+func (app *App) invalidateRoute(ctx context.Context, tenantID, storeID string) error {
+	return app.routeCache.Invalidate(ctx, routeCacheKey(tenantID, storeID))
+}
+
+// startStoreChangeWatcher drops the cached routes of stores changed in MongoDB until ctx is cancelled
+// ROUTE_CACHE_CHANGE_STREAM=off disables it where change streams are unavailable; changed routes are
+// then picked up at their soft TTL or through POST /api/v1/admin/route-cache/invalidate.
+func (app *App) startStoreChangeWatcher(ctx context.Context) {
+	if os.Getenv("ROUTE_CACHE_CHANGE_STREAM") == "off" {
+		log.Info().Msg("Store change stream disabled")
+		return
+	}
+
+	log.Info().Msg("Watching store changes to invalidate cached routes")
+	go func() {
+		var resumeToken bson.Raw
+		backoff := time.Second
+		for {
+			err := app.watchStoreChanges(ctx, &resumeToken)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Warn().Err(err).Dur("retryIn", backoff).Msg("Store change stream stopped")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+// storeChange is the part of a change stream event naming the store that changed
+// The document before the change is only present where pre-images are enabled; without it
+// a store whose tenantId or storeId changed, or that was deleted, expires with its TTL.
+type storeChange struct {
+	OperationType            string `bson:"operationType"`
+	FullDocument             *Store `bson:"fullDocument"`
+	FullDocumentBeforeChange *Store `bson:"fullDocumentBeforeChange"`
+}
+
+// watchStoreChanges invalidates the routes of changed stores until the stream fails
+// resumeToken carries the position over to the next watch, so that no change is missed in between.
+func (app *App) watchStoreChanges(ctx context.Context, resumeToken *bson.Raw) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update", "replace", "delete"}}}}},
+	}
+	opts := options.ChangeStream().
+		SetFullDocument(options.UpdateLookup).
+		SetFullDocumentBeforeChange(options.WhenAvailable)
+	if *resumeToken != nil {
+		opts.SetResumeAfter(*resumeToken)
+	}
+
+	stream, err := app.storesDB.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var change storeChange
+		if err := stream.Decode(&change); err != nil {
+			log.Warn().Err(err).Msg("Failed to decode store change")
+		} else {
+			app.invalidateChangedStore(ctx, change)
+		}
+		*resumeToken = stream.ResumeToken()
+	}
+	return stream.Err()
+}
+
+// invalidateChangedStore drops the cached routes of a store before and after a change
+func (app *App) invalidateChangedStore(ctx context.Context, change storeChange) {
+	stores := make([]*Store, 0, 2)
+	for _, store := range []*Store{change.FullDocumentBeforeChange, change.FullDocument} {
+		if store != nil {
+			stores = append(stores, store)
+		}
+	}
+	if len(stores) == 0 {
+		log.Warn().Str("operation", change.OperationType).Msg("Store change without document, cached route expires with its TTL")
+		return
+	}
+
+	for _, store := range stores {
+		if err := app.invalidateRoute(ctx, store.TenantID, store.StoreID); err != nil {
+			log.Error().Err(err).Str("tenantId", store.TenantID).Str("storeId", store.StoreID).Msg("Failed to invalidate cached route")
+			continue
+		}
+		log.Info().Str("tenantId", store.TenantID).Str("storeId", store.StoreID).Str("operation", change.OperationType).Msg("Invalidated cached route")
+	}
+}
+
+// routeCacheInvalidationRequest names the store whose cached route is dropped
+type routeCacheInvalidationRequest struct {
+	StoreID string `json:"storeId"`
+}
+
+// adminInvalidateRouteCacheHandler handles POST /api/v1/admin/route-cache/invalidate
+// It drops the cached route of one of the tenant's stores, e.g. after its backendUrl changed on a
+// deployment without change streams.
+func (app *App) adminInvalidateRouteCacheHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req routeCacheInvalidationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.StoreID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Msg("Admin invalidate route cache request")
+
+	if err := app.invalidateRoute(ctx, claims.TenantID, req.StoreID); err != nil {
+		log.Error().Err(err).Msg("Failed to invalidate route cache")
+		http.Error(w, "Failed to invalidate route cache", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/webhooks"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// adminRoles are the JWT roles allowed to manage a tenant's webhooks
+var adminRoles = []string{"admin"}
+
+const (
+	// defaultWebhookDeliveryLimit is the page size of delivery logs when none is requested
+	defaultWebhookDeliveryLimit = 50
+
+	// maxWebhookDeliveryLimit bounds the page size of delivery logs
+	maxWebhookDeliveryLimit = 200
+)
+
+// webhookSubscriptionRequest is the body of subscription create and update requests
+// Update requests leave nil fields unchanged.
+type webhookSubscriptionRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	Active     *bool    `json:"active"`
+}
+
+// validate checks the fields that are set
+// The URL must be https and must not resolve to loopback, link-local or private addresses.
+func (req *webhookSubscriptionRequest) validate(ctx context.Context) error {
+	if req.URL != nil {
+		if err := webhooks.ValidateEndpoint(ctx, net.DefaultResolver, *req.URL); err != nil {
+			return err
+		}
+	}
+	if req.EventTypes != nil {
+		if len(req.EventTypes) == 0 {
+			return errors.New("eventTypes must not be empty")
+		}
+		for _, eventType := range req.EventTypes {
+			if !models.IsDomainEventType(eventType) {
+				return fmt.Errorf("unknown event type %q", eventType)
+			}
+		}
+	}
+	return nil
+}
+
+// writeWebhookError maps webhook store errors to HTTP responses
+func writeWebhookError(w http.ResponseWriter, err error, action string) {
+	switch {
+	case errors.Is(err, ports.ErrNotFound):
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+	case errors.Is(err, ports.ErrInvalidTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusInternalServerError)
+	}
+}
+
+// webhookDeliveryLimit reads the limit query parameter of delivery logs
+func webhookDeliveryLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultWebhookDeliveryLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > maxWebhookDeliveryLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxWebhookDeliveryLimit)
+	}
+	return limit, nil
+}
+
+// adminCreateWebhookHandler handles POST /api/v1/admin/webhooks
+// The signing secret is generated and returned only in this response.
+func (app *App) adminCreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.URL == nil || req.EventTypes == nil {
+		http.Error(w, "url and eventTypes are required", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("url", *req.URL).
+		Strs("eventTypes", req.EventTypes).
+		Msg("Create webhook subscription request")
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate webhook secret")
+		http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now().UTC()
+	subscription := &models.WebhookSubscription{
+		ID:         "whsub-" + uuid.NewString(),
+		TenantID:   claims.TenantID,
+		URL:        *req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     req.Active == nil || *req.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := app.webhookStore.CreateSubscription(ctx, subscription); err != nil {
+		log.Error().Err(err).Msg("Failed to create webhook subscription")
+		writeWebhookError(w, err, "create webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		*models.WebhookSubscription
+		Secret string `json:"secret"`
+	}{subscription, subscription.Secret})
+}
+
+// adminListWebhooksHandler handles GET /api/v1/admin/webhooks
+func (app *App) adminListWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptions, err := app.webhookStore.ListSubscriptions(ctx, claims.TenantID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list webhook subscriptions")
+		writeWebhookError(w, err, "list webhooks")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"subscriptions": subscriptions,
+		"count":         len(subscriptions),
+	})
+}
+
+// adminGetWebhookHandler handles GET /api/v1/admin/webhooks/{subscriptionId}
+func (app *App) adminGetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscription, err := app.webhookStore.GetSubscription(ctx, claims.TenantID, chi.URLParam(r, "subscriptionId"))
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to get webhook subscription")
+		}
+		writeWebhookError(w, err, "get webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// adminUpdateWebhookHandler handles PATCH /api/v1/admin/webhooks/{subscriptionId}
+func (app *App) adminUpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionID := chi.URLParam(r, "subscriptionId")
+
+	var req webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.URL == nil && req.EventTypes == nil && req.Active == nil {
+		http.Error(w, "url, eventTypes or active is required", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("subscriptionId", subscriptionID).
+		Msg("Update webhook subscription request")
+
+	subscription, err := app.webhookStore.GetSubscription(ctx, claims.TenantID, subscriptionID)
+	if err == nil {
+		if req.URL != nil {
+			subscription.URL = *req.URL
+		}
+		if req.EventTypes != nil {
+			subscription.EventTypes = req.EventTypes
+		}
+		if req.Active != nil {
+			subscription.Active = *req.Active
+		}
+		subscription.UpdatedAt = time.Now().UTC()
+		err = app.webhookStore.UpdateSubscription(ctx, subscription)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to update webhook subscription")
+		}
+		writeWebhookError(w, err, "update webhook")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// adminDeleteWebhookHandler handles DELETE /api/v1/admin/webhooks/{subscriptionId}
+// The subscription's delivery log is kept until its retention passes.
+func (app *App) adminDeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionID := chi.URLParam(r, "subscriptionId")
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("subscriptionId", subscriptionID).
+		Msg("Delete webhook subscription request")
+
+	if err := app.webhookStore.DeleteSubscription(ctx, claims.TenantID, subscriptionID); err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to delete webhook subscription")
+		}
+		writeWebhookError(w, err, "delete webhook")
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminWebhookDeliveriesHandler handles GET /api/v1/admin/webhooks/{subscriptionId}/deliveries
+// The delivery log is newest first and can be filtered by status.
+func (app *App) adminWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionID := chi.URLParam(r, "subscriptionId")
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		http.Error(w, "status must be pending, delivered or failed", http.StatusBadRequest)
+		return
+	}
+	limit, err := webhookDeliveryLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Deliveries of deleted subscriptions are only listed in the dead letters
+	if _, err := app.webhookStore.GetSubscription(ctx, claims.TenantID, subscriptionID); err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Msg("Failed to get webhook subscription")
+		}
+		writeWebhookError(w, err, "list webhook deliveries")
+		return
+	}
+
+	deliveries, err := app.webhookStore.ListDeliveries(ctx, webhooks.DeliveryFilter{
+		TenantID:       claims.TenantID,
+		SubscriptionID: subscriptionID,
+		Status:         status,
+		Limit:          limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list webhook deliveries")
+		writeWebhookError(w, err, "list webhook deliveries")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// adminWebhookDeadLettersHandler handles GET /api/v1/admin/webhooks/dead-letters
+// It lists the failed deliveries of all of the tenant's subscriptions, newest first.
+func (app *App) adminWebhookDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := webhookDeliveryLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := app.webhookStore.ListDeliveries(ctx, webhooks.DeliveryFilter{
+		TenantID: claims.TenantID,
+		Status:   models.WebhookDeliveryFailed,
+		Limit:    limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list webhook dead letters")
+		writeWebhookError(w, err, "list webhook dead letters")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// adminReplayWebhookDeliveryHandler handles POST /api/v1/admin/webhooks/deliveries/{deliveryId}/replay
+// The failed delivery is queued again and sent with the subscription's current URL and secret.
+func (app *App) adminReplayWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	deliveryID := chi.URLParam(r, "deliveryId")
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("deliveryId", deliveryID).
+		Msg("Replay webhook delivery request")
+
+	delivery, err := app.webhooks.Replay(ctx, claims.TenantID, deliveryID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) && !errors.Is(err, ports.ErrInvalidTransition) {
+			log.Error().Err(err).Msg("Failed to replay webhook delivery")
+		}
+		writeWebhookError(w, err, "replay webhook delivery")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
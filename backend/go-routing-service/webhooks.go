@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/outbox"
+	"github.com/amicis/go-routing-service/internal/webhooks"
+	"github.com/rs/zerolog/log"
+)
+
+// Tenant webhooks
+// Domain events relayed from the outbox are pushed to the endpoints tenants subscribe through
+// /api/v1/admin/webhooks. Deliveries are kept in the webhook_deliveries collection as the
+// delivery log; failed ones form the dead-letter list until they are replayed.
+
+const (
+	// webhookDeliveryRetention is how long deliveries, including dead letters, are kept
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+
+	// webhookPollInterval is how often an idle dispatcher worker looks for due deliveries
+	webhookPollInterval = time.Second
+
+	// webhookTimeout bounds a single delivery attempt
+	webhookTimeout = 10 * time.Second
+
+	// defaultWebhookWorkers is how many deliveries a replica sends concurrently
+	defaultWebhookWorkers = 4
+
+	// webhookConsumer is the durable NATS consumer feeding the dispatcher
+	webhookConsumer = "webhooks"
+)
+
+// webhookRetryConfig is the backoff of delivery attempts: 5 attempts over about 15 seconds
+// Attempts that fail with a client error other than 408 or 429 are not retried.
+func webhookRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:     5,
+		InitialDelay:    time.Second,
+		MaxDelay:        30 * time.Second,
+		BackoffFactor:   2.0,
+		RetryableErrors: webhooks.IsRetryable,
+	}
+}
+
+// webhookWorkers reads the number of dispatcher workers from WEBHOOK_WORKERS
+// A value of 0 disables delivery on this replica; deliveries then wait for another replica
+func webhookWorkers() int {
+	value := os.Getenv("WEBHOOK_WORKERS")
+	if value == "" {
+		return defaultWebhookWorkers
+	}
+
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 0 {
+		log.Warn().Str("value", value).Msg("Invalid WEBHOOK_WORKERS, using default")
+		return defaultWebhookWorkers
+	}
+	return workers
+}
+
+// newWebhookDispatcher creates the dispatcher retrying deliveries with RetryWithBackoff
+func newWebhookDispatcher(store webhooks.Store) *webhooks.Dispatcher {
+	retry := func(ctx context.Context, operation func() error) error {
+		return RetryWithBackoff(ctx, webhookRetryConfig(), operation)
+	}
+	return webhooks.NewDispatcher(store, webhooks.NewHTTPClient(webhookTimeout), retry)
+}
+
+// startWebhookDispatcher feeds domain events from the event bus to the dispatcher and sends
+// due deliveries until ctx is cancelled
+func (app *App) startWebhookDispatcher(ctx context.Context, workers int) {
+	switch bus := app.eventBus.(type) {
+	case *outbox.MemoryBus:
+		bus.Subscribe(app.webhooks.Handle)
+	case *outbox.NATSBus:
+		if err := bus.Consume(ctx, webhookConsumer, app.webhooks.Handle); err != nil {
+			log.Error().Err(err).Msg("Failed to consume events for webhooks, no deliveries will be enqueued")
+		}
+	default:
+		log.Warn().Str("bus", app.eventBus.Name()).Msg("Event bus cannot feed webhooks, no deliveries will be enqueued")
+	}
+
+	if workers == 0 {
+		log.Info().Msg("Webhook delivery disabled")
+		return
+	}
+
+	log.Info().Int("workers", workers).Msg("Starting webhook dispatcher")
+	app.webhooks.Run(ctx, webhookPollInterval, workers)
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/adapters/mongodb"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/notifier"
+	"github.com/amicis/go-routing-service/internal/productcache"
+	"github.com/rs/zerolog/log"
+)
+
+// Wishlist alerts
+// A background job re-prices the items of wishlists kept in the wishlists collection through
+// each store's retail connector. Price drops and back-in-stock changes are recorded in the
+// wishlist_alerts collection, delivered through the configured notifier, and served to the
+// mobile app from GET /api/v1/commerce/wishlist/alerts.
+
+const (
+	defaultWishlistAlertInterval = time.Hour
+	defaultWishlistAlertLimit    = 50
+	maxWishlistAlertLimit        = 200
+)
+
+// newWishlistAlertNotifier creates the alert notifier from WISHLIST_ALERTS_WEBHOOK_URL
+// Alerts are only logged when no webhook is configured
+func newWishlistAlertNotifier() notifier.Notifier {
+	if url := os.Getenv("WISHLIST_ALERTS_WEBHOOK_URL"); url != "" {
+		return notifier.NewWebhookNotifier(url, 5*time.Second)
+	}
+	return notifier.NewLogNotifier()
+}
+
+// wishlistAlertInterval reads the job interval from WISHLIST_ALERTS_INTERVAL
+// A value of 0 disables the job
+func wishlistAlertInterval() time.Duration {
+	value := os.Getenv("WISHLIST_ALERTS_INTERVAL")
+	if value == "" {
+		return defaultWishlistAlertInterval
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warn().Str("value", value).Msg("Invalid WISHLIST_ALERTS_INTERVAL, using default")
+		return defaultWishlistAlertInterval
+	}
+	return interval
+}
+
+// startWishlistAlertJob runs checkWishlistAlerts every interval until ctx is cancelled
+func (app *App) startWishlistAlertJob(ctx context.Context, interval time.Duration) {
+	if interval == 0 {
+		log.Info().Msg("Wishlist alert job disabled")
+		return
+	}
+
+	log.Info().Dur("interval", interval).Str("notifier", app.alertNotifier.Name()).Msg("Starting wishlist alert job")
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				app.checkWishlistAlerts(ctx)
+			}
+		}
+	}()
+}
+
+// checkWishlistAlerts checks the wishlists of every store once
+// A failing store is logged and skipped so that it does not hold up the others
+func (app *App) checkWishlistAlerts(ctx context.Context) {
+	start := time.Now()
+
+	stores, err := mongodb.ListWishlistStores(ctx, app.wishlistsDB)
+	if err != nil {
+		log.Error().Err(err).Msg("Wishlist alert job failed to list stores")
+		return
+	}
+
+	alertCount := 0
+	for _, store := range stores {
+		count, err := app.checkStoreWishlistAlerts(ctx, store.TenantID, store.StoreID)
+		alertCount += count
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("tenantId", store.TenantID).
+				Str("storeId", store.StoreID).
+				Msg("Wishlist alert job failed for store")
+		}
+	}
+
+	log.Info().
+		Int("storeCount", len(stores)).
+		Int("alertCount", alertCount).
+		Dur("duration", time.Since(start)).
+		Msg("Wishlist alert job finished")
+}
+
+// checkStoreWishlistAlerts re-prices the wishlist items of one store and returns the number of alerts raised
+func (app *App) checkStoreWishlistAlerts(ctx context.Context, tenantID, storeID string) (int, error) {
+	wishlistConnector, err := app.getWishlistConnector(ctx, tenantID, storeID)
+	if err != nil {
+		return 0, err
+	}
+	if wishlistConnector.GetAdapterType() != "MongoWishlistAdapter" {
+		// The store keeps its wishlists in its commerce backend; documents here are left over
+		return 0, nil
+	}
+
+	connector, err := app.connectorRegistry.GetConnector(ctx, tenantID, storeID, "retail")
+	if err != nil {
+		return 0, err
+	}
+	retailConnector, ok := connector.(ports.IRetailConnector)
+	if !ok {
+		return 0, fmt.Errorf("connector %s does not implement IRetailConnector", connector.GetAdapterType())
+	}
+
+	// Products are looked up once per store run; nil marks a product that could not be read
+	// Back-in-stock alerts need current stock, so cached products must be recent
+	products := make(map[string]*models.Product)
+	lookupCtx := productcache.WithDataType(ctx, productcache.DataAvailability)
+	alertCount := 0
+
+	err = mongodb.ScanStoreWishlists(ctx, app.wishlistsDB, tenantID, storeID, func(wishlist *models.Wishlist) error {
+		for _, item := range wishlist.Items {
+			product, seen := products[item.ProductID]
+			if !seen {
+				var lookupErr error
+				product, lookupErr = retailConnector.GetProduct(lookupCtx, item.ProductID)
+				if lookupErr != nil {
+					if !errors.Is(lookupErr, ports.ErrNotFound) {
+						log.Warn().Err(lookupErr).Str("storeId", storeID).Str("productId", item.ProductID).Msg("Wishlist alert job failed to get product")
+					}
+					product = nil
+				}
+				products[item.ProductID] = product
+			}
+			if product == nil {
+				continue
+			}
+
+			count, err := app.checkWishlistItem(ctx, wishlist, item, product)
+			alertCount += count
+			if err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	})
+
+	return alertCount, err
+}
+
+// checkWishlistItem compares an item with the live product, records and delivers any alerts,
+// and remembers the live state for the next run
+func (app *App) checkWishlistItem(ctx context.Context, wishlist *models.Wishlist, item models.WishlistItem, product *models.Product) (int, error) {
+	previous, err := app.wishlistAlerts.GetItemState(ctx, wishlist.TenantID, wishlist.StoreID, wishlist.ID, item.ID)
+	if err != nil {
+		return 0, err
+	}
+	if previous == nil {
+		// First check: the price saved with the item is the reference
+		previous = &models.WishlistItemState{Price: item.Price}
+	}
+
+	alertTypes, next := models.DetectWishlistAlerts(*previous, liveWishlistItemState(product, item.VariantID))
+
+	for _, alertType := range alertTypes {
+		alert := models.WishlistAlert{
+			Type:       alertType,
+			TenantID:   wishlist.TenantID,
+			StoreID:    wishlist.StoreID,
+			CustomerID: wishlist.CustomerID,
+			WishlistID: wishlist.ID,
+			ItemID:     item.ID,
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Name:       item.Name,
+			Price:      next.Price,
+		}
+		if alertType == models.WishlistAlertPriceDrop && previous.Price.Amount > 0 {
+			previousPrice := previous.Price
+			alert.PreviousPrice = &previousPrice
+		}
+
+		recorded, err := app.wishlistAlerts.Record(ctx, alert)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := app.alertNotifier.Notify(ctx, *recorded); err != nil {
+			log.Error().
+				Err(err).
+				Str("notifier", app.alertNotifier.Name()).
+				Str("alertId", recorded.ID).
+				Msg("Failed to deliver wishlist alert")
+		}
+	}
+
+	if err := app.wishlistAlerts.SaveItemState(ctx, wishlist.TenantID, wishlist.StoreID, wishlist.ID, item.ID, next); err != nil {
+		return len(alertTypes), err
+	}
+
+	return len(alertTypes), nil
+}
+
+// liveWishlistItemState reads the current price and stock of a wishlist item from its product
+// Prices are product level, matching the snapshot saved with the item; stock comes from the
+// item's variant when the product lists it
+func liveWishlistItemState(product *models.Product, variantID string) models.WishlistItemState {
+	state := models.WishlistItemState{
+		Price:     product.Price,
+		CheckedAt: time.Now().UTC(),
+	}
+
+	if inventory := product.InventoryFor(variantID); inventory != nil {
+		available := inventory.Available
+		state.Available = &available
+	}
+
+	return state
+}
+
+// commerceWishlistAlertsHandler handles GET /api/v1/commerce/wishlist/alerts
+func (app *App) commerceWishlistAlertsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get query params
+	storeID := r.URL.Query().Get("storeId")
+	if storeID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	// Customers only see alerts on their own wishlists
+	customerID := claims.Sub
+
+	limit := defaultWishlistAlertLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l <= 0 || l > maxWishlistAlertLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxWishlistAlertLimit), http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		s, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "since must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		since = s
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Msg("Wishlist alerts request")
+
+	alerts, err := app.wishlistAlerts.List(ctx, claims.TenantID, storeID, customerID, since, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list wishlist alerts")
+		http.Error(w, "Failed to get wishlist alerts", http.StatusInternalServerError)
+		return
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/amicis/go-routing-service/internal/checkout"
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// wishlistCheckout is the result of re-resolving wishlist items for checkout
+type wishlistCheckout struct {
+	Items       []models.WishlistItem
+	Changes     []models.WishlistCheckoutChange
+	Unavailable []models.WishlistCheckoutUnavailable
+}
+
+// verifyWishlistItems re-resolves wishlist items through the retail connector
+// Items get the current name, SKU and price of their product; items whose product is gone or
+// out of stock are left out. Any other lookup error fails the whole check, since the session
+// must not be built from unverified prices.
+func verifyWishlistItems(ctx context.Context, retailConnector ports.IRetailConnector, items []models.WishlistItem) (*wishlistCheckout, error) {
+	result := &wishlistCheckout{
+		Items:       []models.WishlistItem{},
+		Changes:     []models.WishlistCheckoutChange{},
+		Unavailable: []models.WishlistCheckoutUnavailable{},
+	}
+	products := make(map[string]*models.Product)
+
+	for _, item := range items {
+		product, seen := products[item.ProductID]
+		if !seen {
+			var err error
+			product, err = retailConnector.GetProduct(ctx, item.ProductID)
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				return nil, fmt.Errorf("failed to get product %s: %w", item.ProductID, err)
+			}
+			products[item.ProductID] = product
+		}
+
+		if product == nil {
+			result.Unavailable = append(result.Unavailable, models.WishlistCheckoutUnavailable{
+				ItemID:    item.ID,
+				ProductID: item.ProductID,
+				Name:      item.Name,
+				Reason:    models.WishlistItemNotFound,
+			})
+			continue
+		}
+
+		live := liveWishlistItemState(product, item.VariantID)
+		if live.Available != nil && !*live.Available {
+			result.Unavailable = append(result.Unavailable, models.WishlistCheckoutUnavailable{
+				ItemID:    item.ID,
+				ProductID: item.ProductID,
+				Name:      product.Name,
+				Reason:    models.WishlistItemOutOfStock,
+			})
+			continue
+		}
+
+		if item.Price.Amount != product.Price.Amount || item.Price.Currency != product.Price.Currency {
+			result.Changes = append(result.Changes, models.WishlistCheckoutChange{
+				ItemID:        item.ID,
+				ProductID:     item.ProductID,
+				Name:          product.Name,
+				PreviousPrice: item.Price,
+				Price:         product.Price,
+			})
+		}
+
+		verified := item
+		verified.SKU = product.SKU
+		verified.Name = product.Name
+		verified.Price = product.Price
+		result.Items = append(result.Items, verified)
+	}
+
+	return result, nil
+}
+
+// commerceWishlistCheckoutHandler handles POST /api/v1/commerce/wishlists/{wishlistId}/checkout
+func (app *App) commerceWishlistCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlistID := chi.URLParam(r, "wishlistId")
+
+	// Only the owner checks a list out, and the session belongs to them; the owner is the JWT subject
+	customerID := claims.Sub
+
+	// Parse request body
+	var req struct {
+		StoreID     string             `json:"storeId"`
+		ItemIDs     []string           `json:"itemIds,omitempty"`
+		Discounts   []checkoutDiscount `json:"discounts,omitempty"`
+		RemoveItems bool               `json:"removeItems,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if wishlistID == "" || req.StoreID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", customerID).
+		Str("wishlistId", wishlistID).
+		Int("requestedItemCount", len(req.ItemIDs)).
+		Msg("Wishlist checkout request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, customerID)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "get wishlist")
+		return
+	}
+
+	// Select the requested items, or the whole list when none are named
+	items := wishlist.Items
+	if len(req.ItemIDs) > 0 {
+		byID := make(map[string]models.WishlistItem, len(wishlist.Items))
+		for _, item := range wishlist.Items {
+			byID[item.ID] = item
+		}
+		items = make([]models.WishlistItem, 0, len(req.ItemIDs))
+		for _, itemID := range req.ItemIDs {
+			item, ok := byID[itemID]
+			if !ok {
+				http.Error(w, fmt.Sprintf("Wishlist item %s not found", itemID), http.StatusNotFound)
+				return
+			}
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		http.Error(w, "Wishlist has no items to check out", http.StatusBadRequest)
+		return
+	}
+
+	retailConnector, err := app.getRetailConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	verified, err := verifyWishlistItems(ctx, retailConnector, items)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", retailConnector.GetAdapterType()).Msg("Failed to verify wishlist items")
+		http.Error(w, "Failed to verify wishlist items", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+
+	if len(verified.Items) == 0 {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":       "No wishlist items are available",
+			"changes":     verified.Changes,
+			"unavailable": verified.Unavailable,
+		})
+		return
+	}
+
+	checkoutItems := make([]checkout.Item, 0, len(verified.Items))
+	for _, item := range verified.Items {
+		checkoutItems = append(checkoutItems, checkout.Item{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	session, err := app.createCheckoutSession(ctx, retailConnector, claims.TenantID, req.StoreID, customerID, checkoutItems, discountCodes(req.Discounts))
+	if err != nil {
+		logCheckoutError(err)
+		writeCheckoutError(w, err)
+		return
+	}
+
+	// Removal is best effort: the session exists, so a failure here must not fail the checkout
+	removedItemIDs := []string{}
+	if req.RemoveItems {
+		for _, item := range verified.Items {
+			err := wishlistConnector.RemoveItem(ctx, wishlist.ID, item.ID)
+			if err != nil && !errors.Is(err, ports.ErrNotFound) {
+				log.Warn().Err(err).Str("wishlistId", wishlist.ID).Str("itemId", item.ID).Msg("Failed to remove checked-out wishlist item")
+				continue
+			}
+			removedItemIDs = append(removedItemIDs, item.ID)
+		}
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("wishlistId", wishlist.ID).
+		Str("sessionId", session.ID).
+		Int("itemCount", len(verified.Items)).
+		Int("changedCount", len(verified.Changes)).
+		Int("unavailableCount", len(verified.Unavailable)).
+		Msg("Wishlist checked out")
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"session":        session,
+		"changes":        verified.Changes,
+		"unavailable":    verified.Unavailable,
+		"removedItemIds": removedItemIDs,
+	})
+}
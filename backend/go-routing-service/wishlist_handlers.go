@@ -0,0 +1,634 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Named wishlist handlers - /api/v1/commerce/wishlists
+// The older /commerce/wishlist routes keep working on the customer's default (oldest) list
+
+// maxWishlistNameLength limits wishlist names to what fits in list headers on every client
+const maxWishlistNameLength = 100
+
+// getCustomerWishlist loads a wishlist and checks that it belongs to the customer
+// Wishlists of other customers are reported as not found so their IDs cannot be probed
+func getCustomerWishlist(ctx context.Context, connector ports.IWishlistConnector, wishlistID, customerID string) (*models.Wishlist, error) {
+	wishlist, err := connector.GetWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+	if wishlist.CustomerID != customerID {
+		return nil, fmt.Errorf("wishlist %s: %w", wishlistID, ports.ErrNotFound)
+	}
+	return wishlist, nil
+}
+
+// normalizeWishlistName trims a wishlist name and checks its length
+func normalizeWishlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name must not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxWishlistNameLength {
+		return "", fmt.Errorf("name must be at most %d characters", maxWishlistNameLength)
+	}
+	return name, nil
+}
+
+// newWishlistItemRequest snapshots product details at the time the item is saved
+func newWishlistItemRequest(product *models.Product, productID, variantID string, quantity int, notes string) models.AddWishlistItemRequest {
+	addReq := models.AddWishlistItemRequest{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		Notes:     notes,
+		SKU:       product.SKU,
+		Name:      product.Name,
+		Price:     product.Price,
+	}
+	for _, image := range product.Images {
+		if image.IsPrimary || addReq.ImageURL == "" {
+			addReq.ImageURL = image.URL
+		}
+	}
+	return addReq
+}
+
+// updateWishlistItem applies a partial quantity/notes change to an item
+// Fields left nil keep their current value
+func updateWishlistItem(ctx context.Context, connector ports.IWishlistConnector, wishlist *models.Wishlist, itemID string, quantity *int, notes *string) (*models.WishlistItem, error) {
+	var current *models.WishlistItem
+	for i := range wishlist.Items {
+		if wishlist.Items[i].ID == itemID {
+			current = &wishlist.Items[i]
+			break
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("wishlist item %s: %w", itemID, ports.ErrNotFound)
+	}
+
+	newQuantity, newNotes := current.Quantity, current.Notes
+	if quantity != nil {
+		newQuantity = *quantity
+	}
+	if notes != nil {
+		newNotes = *notes
+	}
+
+	return connector.UpdateItem(ctx, wishlist.ID, itemID, newQuantity, newNotes)
+}
+
+// writeWishlistError maps wishlist connector errors to HTTP responses
+func writeWishlistError(w http.ResponseWriter, err error, action string) {
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "Wishlist not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, fmt.Sprintf("Failed to %s", action), http.StatusBadGateway)
+}
+
+// commerceListWishlistsHandler handles GET /api/v1/commerce/wishlists
+func (app *App) commerceListWishlistsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get query params
+	storeID := r.URL.Query().Get("storeId")
+	if storeID == "" {
+		http.Error(w, "storeId query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	// Only the caller's own wishlists are listed
+	customerID := claims.Sub
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Msg("List wishlists request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlists, err := wishlistConnector.GetWishlists(ctx, customerID)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to list wishlists")
+		writeWishlistError(w, err, "list wishlists")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(wishlists)
+}
+
+// commerceCreateWishlistHandler handles POST /api/v1/commerce/wishlists
+func (app *App) commerceCreateWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse request body
+	var req struct {
+		StoreID  string `json:"storeId"`
+		Name     string `json:"name"`
+		IsPublic bool   `json:"isPublic"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.StoreID == "" {
+		http.Error(w, "storeId is required", http.StatusBadRequest)
+		return
+	}
+
+	// The wishlist is always created for the caller
+	customerID := claims.Sub
+
+	name, err := normalizeWishlistName(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", customerID).
+		Msg("Create wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := wishlistConnector.CreateWishlist(ctx, customerID, name, req.IsPublic)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to create wishlist")
+		writeWishlistError(w, err, "create wishlist")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistCreated, claims.TenantID, wishlist, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// commerceGetWishlistByIDHandler handles GET /api/v1/commerce/wishlists/{wishlistId}
+func (app *App) commerceGetWishlistByIDHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params
+	wishlistID := chi.URLParam(r, "wishlistId")
+	storeID := r.URL.Query().Get("storeId")
+
+	if wishlistID == "" || storeID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Msg("Get wishlist by ID request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "get wishlist")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// commerceUpdateWishlistHandler handles PATCH /api/v1/commerce/wishlists/{wishlistId}
+func (app *App) commerceUpdateWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlistID := chi.URLParam(r, "wishlistId")
+
+	// Parse request body
+	var req struct {
+		StoreID  string  `json:"storeId"`
+		Name     *string `json:"name,omitempty"`
+		IsPublic *bool   `json:"isPublic,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if wishlistID == "" || req.StoreID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+	if req.Name == nil && req.IsPublic == nil {
+		http.Error(w, "name or isPublic is required", http.StatusBadRequest)
+		return
+	}
+
+	update := models.WishlistUpdate{IsPublic: req.IsPublic}
+	if req.Name != nil {
+		name, err := normalizeWishlistName(*req.Name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		update.Name = &name
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Msg("Update wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	if err == nil {
+		wishlist, err = wishlistConnector.UpdateWishlist(ctx, wishlist.ID, update)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to update wishlist")
+		}
+		writeWishlistError(w, err, "update wishlist")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistUpdated, claims.TenantID, wishlist, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// commerceDeleteWishlistHandler handles DELETE /api/v1/commerce/wishlists/{wishlistId}
+func (app *App) commerceDeleteWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params
+	wishlistID := chi.URLParam(r, "wishlistId")
+	storeID := r.URL.Query().Get("storeId")
+
+	if wishlistID == "" || storeID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Msg("Delete wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	if err == nil {
+		err = wishlistConnector.DeleteWishlist(ctx, wishlist.ID)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to delete wishlist")
+		}
+		writeWishlistError(w, err, "delete wishlist")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistDeleted, claims.TenantID, wishlist, nil)
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commerceAddWishlistItemHandler handles POST /api/v1/commerce/wishlists/{wishlistId}/items
+func (app *App) commerceAddWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlistID := chi.URLParam(r, "wishlistId")
+
+	// Parse request body
+	var req struct {
+		StoreID   string `json:"storeId"`
+		ProductID string `json:"productId"`
+		VariantID string `json:"variantId,omitempty"`
+		Quantity  int    `json:"quantity,omitempty"`
+		Notes     string `json:"notes,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if wishlistID == "" || req.StoreID == "" || req.ProductID == "" {
+		http.Error(w, "wishlistId, storeId, and productId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Str("productId", req.ProductID).
+		Msg("Add to named wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "add to wishlist")
+		return
+	}
+
+	// Get product details from commerce API
+	connector, err := app.connectorRegistry.GetConnector(ctx, claims.TenantID, req.StoreID, "retail")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get retail connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	retailConnector, ok := connector.(ports.IRetailConnector)
+	if !ok {
+		log.Error().Msg("Connector does not implement IRetailConnector")
+		http.Error(w, "Invalid connector type", http.StatusInternalServerError)
+		return
+	}
+
+	product, err := retailConnector.GetProduct(ctx, req.ProductID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get product")
+		http.Error(w, fmt.Sprintf("Product not found: %v", err), http.StatusNotFound)
+		return
+	}
+
+	addReq := newWishlistItemRequest(product, req.ProductID, req.VariantID, req.Quantity, req.Notes)
+
+	item, err := wishlistConnector.AddItem(ctx, wishlist.ID, addReq)
+	if err != nil {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to add item to wishlist")
+		writeWishlistError(w, err, "add to wishlist")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistItemAdded, claims.TenantID, wishlist, item)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wishlistId": wishlist.ID,
+		"item":       item,
+	})
+}
+
+// commerceUpdateWishlistItemHandler handles PATCH /api/v1/commerce/wishlists/{wishlistId}/items/{itemId}
+func (app *App) commerceUpdateWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlistID := chi.URLParam(r, "wishlistId")
+	itemID := chi.URLParam(r, "itemId")
+
+	// Parse request body
+	var req struct {
+		StoreID  string  `json:"storeId"`
+		Quantity *int    `json:"quantity,omitempty"`
+		Notes    *string `json:"notes,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if wishlistID == "" || itemID == "" || req.StoreID == "" {
+		http.Error(w, "wishlistId, itemId, and storeId are required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity == nil && req.Notes == nil {
+		http.Error(w, "quantity or notes is required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		http.Error(w, "quantity must be positive", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Str("itemId", itemID).
+		Msg("Update named wishlist item request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	var item *models.WishlistItem
+	if err == nil {
+		item, err = updateWishlistItem(ctx, wishlistConnector, wishlist, itemID, req.Quantity, req.Notes)
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to update wishlist item")
+		}
+		writeWishlistError(w, err, "update wishlist item")
+		return
+	}
+	app.recordWishlistEvent(ctx, models.EventWishlistItemUpdated, claims.TenantID, wishlist, item)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"wishlistId": wishlist.ID,
+		"item":       item,
+	})
+}
+
+// commerceRemoveWishlistItemHandler handles DELETE /api/v1/commerce/wishlists/{wishlistId}/items/{itemId}
+func (app *App) commerceRemoveWishlistItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params
+	wishlistID := chi.URLParam(r, "wishlistId")
+	itemID := chi.URLParam(r, "itemId")
+	storeID := r.URL.Query().Get("storeId")
+
+	if wishlistID == "" || itemID == "" || storeID == "" {
+		http.Error(w, "wishlistId, itemId, and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", claims.Sub).
+		Str("wishlistId", wishlistID).
+		Str("itemId", itemID).
+		Msg("Remove from named wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, claims.Sub)
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "remove from wishlist")
+		return
+	}
+
+	// Removing an item that is already gone is not an error
+	err = wishlistConnector.RemoveItem(ctx, wishlist.ID, itemID)
+	if err != nil && !errors.Is(err, ports.ErrNotFound) {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to remove item from wishlist")
+		writeWishlistError(w, err, "remove from wishlist")
+		return
+	}
+	if err == nil {
+		app.recordWishlistEvent(ctx, models.EventWishlistItemRemoved, claims.TenantID, wishlist, &models.WishlistItem{ID: itemID})
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
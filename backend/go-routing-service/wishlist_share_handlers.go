@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amicis/go-routing-service/internal/domain/models"
+	"github.com/amicis/go-routing-service/internal/domain/ports"
+	"github.com/amicis/go-routing-service/internal/signing"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// Wishlist share links
+// A share link is a signed token naming a share record in the wishlist_shares collection.
+// The signature keeps tokens unguessable; the record lets owners revoke links before they expire.
+
+const (
+	defaultWishlistShareTTL = 7 * 24 * time.Hour
+	maxWishlistShareTTL     = 30 * 24 * time.Hour
+)
+
+var (
+	errShareInvalid = errors.New("share link is invalid")
+	errShareExpired = errors.New("share link has expired or was revoked")
+)
+
+// wishlistShareClaims is the payload of a share token
+type wishlistShareClaims struct {
+	ShareID    string `json:"sid"`
+	TenantID   string `json:"tid"`
+	StoreID    string `json:"sto"`
+	WishlistID string `json:"wid"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// newWishlistShareSigner creates the share token signer from WISHLIST_SHARE_SECRET
+func newWishlistShareSigner() *signing.Signer {
+	secret := os.Getenv("WISHLIST_SHARE_SECRET")
+	if secret == "" {
+		secret = "development-share-secret-change-in-production"
+		log.Warn().Msg("Using default wishlist share secret - set WISHLIST_SHARE_SECRET environment variable")
+	}
+	return signing.NewSigner([]byte(secret))
+}
+
+// signWishlistShare creates the token for a share record
+func (app *App) signWishlistShare(share *models.WishlistShare) (string, error) {
+	payload, err := json.Marshal(wishlistShareClaims{
+		ShareID:    share.ID,
+		TenantID:   share.TenantID,
+		StoreID:    share.StoreID,
+		WishlistID: share.WishlistID,
+		ExpiresAt:  share.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return app.shareSigner.Sign(payload), nil
+}
+
+// resolveWishlistShare verifies a share token and returns its active share record
+func (app *App) resolveWishlistShare(ctx context.Context, token string) (*models.WishlistShare, error) {
+	payload, err := app.shareSigner.Verify(token)
+	if err != nil {
+		return nil, errShareInvalid
+	}
+
+	var claims wishlistShareClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ShareID == "" {
+		return nil, errShareInvalid
+	}
+
+	now := time.Now()
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, errShareExpired
+	}
+
+	share, err := app.wishlistShares.Get(ctx, claims.ShareID)
+	if errors.Is(err, ports.ErrNotFound) {
+		return nil, errShareInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if share.TenantID != claims.TenantID || share.StoreID != claims.StoreID || share.WishlistID != claims.WishlistID {
+		return nil, errShareInvalid
+	}
+	if share.RevokedAt != nil || !now.Before(share.ExpiresAt) {
+		return nil, errShareExpired
+	}
+
+	return share, nil
+}
+
+// buildPublicWishlist creates the read-only view of a wishlist with live price and stock
+// Items fall back to their saved snapshot when the retail connector is unavailable
+func (app *App) buildPublicWishlist(ctx context.Context, share *models.WishlistShare, wishlist *models.Wishlist) *models.PublicWishlist {
+	var retailConnector ports.IRetailConnector
+	if connector, err := app.connectorRegistry.GetConnector(ctx, share.TenantID, share.StoreID, "retail"); err != nil {
+		log.Warn().Err(err).Str("storeId", share.StoreID).Msg("Retail connector unavailable for shared wishlist, showing saved prices")
+	} else if rc, ok := connector.(ports.IRetailConnector); ok {
+		retailConnector = rc
+	}
+
+	products := make(map[string]*models.Product)
+	items := make([]models.PublicWishlistItem, 0, len(wishlist.Items))
+	for _, saved := range wishlist.Items {
+		item := models.PublicWishlistItem{
+			ProductID: saved.ProductID,
+			VariantID: saved.VariantID,
+			SKU:       saved.SKU,
+			Name:      saved.Name,
+			ImageURL:  saved.ImageURL,
+			Quantity:  saved.Quantity,
+			Price:     saved.Price,
+		}
+
+		if retailConnector != nil {
+			product, seen := products[saved.ProductID]
+			if !seen {
+				var err error
+				if product, err = retailConnector.GetProduct(ctx, saved.ProductID); err != nil {
+					log.Warn().Err(err).Str("productId", saved.ProductID).Msg("Failed to get live product for shared wishlist")
+					product = nil
+				}
+				products[saved.ProductID] = product
+			}
+			if product != nil {
+				item.Price = product.Price
+				item.Inventory = product.Inventory
+				item.Live = true
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return &models.PublicWishlist{
+		Name:      wishlist.Name,
+		StoreID:   wishlist.StoreID,
+		Items:     items,
+		Count:     len(items),
+		ExpiresAt: share.ExpiresAt,
+	}
+}
+
+// commerceCreateWishlistShareHandler handles POST /api/v1/commerce/wishlists/{wishlistId}/shares
+func (app *App) commerceCreateWishlistShareHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	wishlistID := chi.URLParam(r, "wishlistId")
+
+	// Only the owner may share a list; the owner is always the JWT subject
+	customerID := claims.Sub
+
+	// Parse request body
+	var req struct {
+		StoreID        string `json:"storeId"`
+		ExpiresInHours int    `json:"expiresInHours,omitempty"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if wishlistID == "" || req.StoreID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	ttl := defaultWishlistShareTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+		if ttl <= 0 || ttl > maxWishlistShareTTL {
+			http.Error(w, fmt.Sprintf("expiresInHours must be between 1 and %d", int(maxWishlistShareTTL.Hours())), http.StatusBadRequest)
+			return
+		}
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", req.StoreID).
+		Str("customerId", customerID).
+		Str("wishlistId", wishlistID).
+		Msg("Create wishlist share request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, req.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, customerID)
+	if err == nil && !wishlist.IsPublic {
+		// Sharing makes the list public; making it private again disables all of its links
+		public := true
+		wishlist, err = wishlistConnector.UpdateWishlist(ctx, wishlist.ID, models.WishlistUpdate{IsPublic: &public})
+	}
+	if err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to prepare wishlist for sharing")
+		}
+		writeWishlistError(w, err, "share wishlist")
+		return
+	}
+
+	share, err := app.wishlistShares.Create(ctx, claims.TenantID, req.StoreID, wishlist.ID, customerID, time.Now().Add(ttl))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create wishlist share")
+		http.Error(w, "Failed to share wishlist", http.StatusInternalServerError)
+		return
+	}
+
+	token, err := app.signWishlistShare(share)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign wishlist share")
+		http.Error(w, "Failed to share wishlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"share": share,
+		"token": token,
+		"path":  "/api/v1/public/wishlists/" + token,
+	})
+}
+
+// commerceListWishlistSharesHandler handles GET /api/v1/commerce/wishlists/{wishlistId}/shares
+func (app *App) commerceListWishlistSharesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params; only the owner sees a list's share links
+	wishlistID := chi.URLParam(r, "wishlistId")
+	storeID := r.URL.Query().Get("storeId")
+	customerID := claims.Sub
+
+	if wishlistID == "" || storeID == "" {
+		http.Error(w, "wishlistId and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Str("wishlistId", wishlistID).
+		Msg("List wishlist shares request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	if _, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, customerID); err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "list wishlist shares")
+		return
+	}
+
+	shares, err := app.wishlistShares.List(ctx, claims.TenantID, storeID, wishlistID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list wishlist shares")
+		http.Error(w, "Failed to list wishlist shares", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// commerceRevokeWishlistShareHandler handles DELETE /api/v1/commerce/wishlists/{wishlistId}/shares/{shareId}
+func (app *App) commerceRevokeWishlistShareHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	// Get JWT claims
+	claims, ok := GetUserFromContext(ctx)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Get path params and query params; only the owner revokes a list's share links
+	wishlistID := chi.URLParam(r, "wishlistId")
+	shareID := chi.URLParam(r, "shareId")
+	storeID := r.URL.Query().Get("storeId")
+	customerID := claims.Sub
+
+	if wishlistID == "" || shareID == "" || storeID == "" {
+		http.Error(w, "wishlistId, shareId, and storeId are required", http.StatusBadRequest)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", claims.TenantID).
+		Str("storeId", storeID).
+		Str("customerId", customerID).
+		Str("wishlistId", wishlistID).
+		Str("shareId", shareID).
+		Msg("Revoke wishlist share request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, claims.TenantID, storeID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, fmt.Sprintf("Connector not available: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	if _, err := getCustomerWishlist(ctx, wishlistConnector, wishlistID, customerID); err != nil {
+		if !errors.Is(err, ports.ErrNotFound) {
+			log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get wishlist")
+		}
+		writeWishlistError(w, err, "revoke wishlist share")
+		return
+	}
+
+	if err := app.wishlistShares.Revoke(ctx, claims.TenantID, storeID, wishlistID, shareID); err != nil {
+		if errors.Is(err, ports.ErrNotFound) {
+			http.Error(w, "Share not found", http.StatusNotFound)
+			return
+		}
+		log.Error().Err(err).Msg("Failed to revoke wishlist share")
+		http.Error(w, "Failed to revoke wishlist share", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// publicSharedWishlistHandler handles GET /api/v1/public/wishlists/{token}
+// It is served outside the JWT group; the token is the only credential
+func (app *App) publicSharedWishlistHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	correlationID := GetCorrelationID(ctx)
+
+	token := chi.URLParam(r, "token")
+
+	share, err := app.resolveWishlistShare(ctx, token)
+	switch {
+	case errors.Is(err, errShareInvalid):
+		http.Error(w, "Shared wishlist not found", http.StatusNotFound)
+		return
+	case errors.Is(err, errShareExpired):
+		http.Error(w, "Share link has expired or was revoked", http.StatusGone)
+		return
+	case err != nil:
+		log.Error().Err(err).Str("correlationId", correlationID).Msg("Failed to resolve wishlist share")
+		http.Error(w, "Failed to get shared wishlist", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().
+		Str("correlationId", correlationID).
+		Str("tenantId", share.TenantID).
+		Str("storeId", share.StoreID).
+		Str("wishlistId", share.WishlistID).
+		Str("shareId", share.ID).
+		Msg("Shared wishlist request")
+
+	wishlistConnector, err := app.getWishlistConnector(ctx, share.TenantID, share.StoreID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get wishlist connector")
+		http.Error(w, "Shared wishlist not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	wishlist, err := getCustomerWishlist(ctx, wishlistConnector, share.WishlistID, share.CustomerID)
+	if errors.Is(err, ports.ErrNotFound) {
+		http.Error(w, "Shared wishlist not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("adapter", wishlistConnector.GetAdapterType()).Msg("Failed to get shared wishlist")
+		http.Error(w, "Failed to get shared wishlist", http.StatusBadGateway)
+		return
+	}
+	if !wishlist.IsPublic {
+		http.Error(w, "Share link has expired or was revoked", http.StatusGone)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Correlation-ID", correlationID)
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(app.buildPublicWishlist(ctx, share, wishlist))
+}